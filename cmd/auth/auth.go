@@ -33,4 +33,17 @@ func InitCommands() {
 	Cmd.AddCommand(newClientCredentialsCmd())
 	Cmd.AddCommand(newClearClientCredentialsCmd())
 	Cmd.AddCommand(newPrintAccessTokenCmd())
+	Cmd.AddCommand(newWorkloadIdentityCmd())
+	Cmd.AddCommand(newWhoamiCmd())
+}
+
+// allowOnLinux opts a subcommand out of the keyring restriction of the auth command while still running the root
+// command setup (e.g. logging).
+func allowOnLinux(doc *man.Doc) {
+	doc.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if root := cmd.Root(); root.PersistentPreRunE != nil {
+			return root.PersistentPreRunE(cmd, args)
+		}
+		return nil
+	}
 }
@@ -14,7 +14,7 @@ import (
 
 func clientCredentialsRun(cmd *cobra.Command, args []string) {
 	c := cli.New(cmd, args)
-	cp := common.InitProfile(c)
+	cp := common.InitStoredProfile(c)
 
 	var clientID string
 	var clientSecret string
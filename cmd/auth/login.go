@@ -13,8 +13,14 @@ import (
 
 func codeLogin(cmd *cobra.Command, args []string) {
 	c := cli.New(cmd, args)
-	cp := common.InitProfile(c)
-	clientID := c.FlagHelper.GetRequiredString("client-id")
+	cp := common.InitStoredProfile(c)
+	clientID := c.FlagHelper.GetOptionalString("client-id")
+	if clientID == "" {
+		clientID = cp.GetLoginClientID()
+	}
+	if clientID == "" {
+		c.ExitWithError("Flag '--client-id' is required when the profile does not define a login client ID", nil)
+	}
 	port := c.FlagHelper.GetOptionalString("port")
 	tok, err := auth.LoginWithPKCE(
 		cmd.Context(),
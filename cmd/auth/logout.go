@@ -13,7 +13,7 @@ import (
 
 func logout(cmd *cobra.Command, args []string) {
 	c := cli.New(cmd, args)
-	cp := common.InitProfile(c)
+	cp := common.InitStoredProfile(c)
 
 	// we can only revoke access tokens stored for the code login flow, not client credentials
 	creds := cp.GetAuthCredentials()
@@ -20,6 +20,7 @@ func printAccessTokenRun(cmd *cobra.Command, args []string) {
 	switch ac.AuthType {
 	case profiles.AuthTypeClientCredentials:
 	case profiles.AuthTypeAccessToken:
+	case profiles.AuthTypeWorkloadIdentity:
 	default:
 		c.ExitWithError("Invalid auth type", nil)
 	}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/auth"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/spf13/cobra"
+)
+
+type whoamiEntitlement struct {
+	AttributeValueFqn string   `json:"attribute_value_fqn"`
+	Actions           []string `json:"actions"`
+}
+
+type whoamiResult struct {
+	Profile      string              `json:"profile"`
+	Endpoint     string              `json:"endpoint"`
+	AuthType     string              `json:"auth_type"`
+	Token        auth.TokenInfo      `json:"token"`
+	Entitlements []whoamiEntitlement `json:"entitlements,omitempty"`
+}
+
+func whoamiRun(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	cp := common.InitProfile(c)
+	withEntitlements := c.Flags.GetOptionalBool("entitlements")
+	comprehensive := c.Flags.GetOptionalBool("comprehensive-hierarchy")
+
+	tok, err := auth.GetTokenWithProfile(cmd.Context(), cp)
+	if err != nil {
+		c.ExitWithError("Failed to get token", err)
+	}
+
+	info, err := auth.DecodeTokenInfo(tok.AccessToken, time.Now())
+	if err != nil {
+		c.ExitWithError("Failed to decode access token claims", err)
+	}
+
+	result := whoamiResult{
+		Profile:  cp.Name(),
+		Endpoint: cp.GetEndpoint(),
+		AuthType: cp.GetAuthCredentials().AuthType,
+		Token:    info,
+	}
+
+	if withEntitlements {
+		h, err := handlers.New(handlers.WithProfile(cp))
+		if err != nil {
+			c.ExitWithError("Failed to connect to the platform", err)
+		}
+		defer h.Close()
+
+		entitlements, err := h.GetEntitlementsForToken(cmd.Context(), tok.AccessToken, comprehensive)
+		if err != nil {
+			c.ExitWithError("Failed to get entitlements", err)
+		}
+		for _, e := range entitlements {
+			for fqn, actions := range e.GetActionsPerAttributeValueFqn() {
+				names := make([]string, 0, len(actions.GetActions()))
+				for _, a := range actions.GetActions() {
+					names = append(names, a.GetName())
+				}
+				sort.Strings(names)
+				result.Entitlements = append(result.Entitlements, whoamiEntitlement{
+					AttributeValueFqn: fqn,
+					Actions:           names,
+				})
+			}
+		}
+		sort.Slice(result.Entitlements, func(i, j int) bool {
+			return result.Entitlements[i].AttributeValueFqn < result.Entitlements[j].AttributeValueFqn
+		})
+	}
+
+	c.ExitWith(renderWhoami(result, withEntitlements), result, cli.ExitCodeSuccess, os.Stdout)
+}
+
+func renderWhoami(r whoamiResult, withEntitlements bool) string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.Local().Format(time.RFC3339)
+	}
+
+	expiry := formatTime(r.Token.ExpiresAt)
+	if r.Token.ExpiresIn != "" {
+		expiry += " (" + r.Token.ExpiresIn + ")"
+	}
+
+	claims := make([]string, 0, len(r.Token.Claims))
+	for k := range r.Token.Claims {
+		claims = append(claims, k)
+	}
+	sort.Strings(claims)
+
+	t := cli.NewTabular(
+		[]string{"Profile", r.Profile},
+		[]string{"Endpoint", r.Endpoint},
+		[]string{"Auth type", r.AuthType},
+		[]string{"Issuer", r.Token.Issuer},
+		[]string{"Audience", strings.Join(r.Token.Audience, ", ")},
+		[]string{"Subject", r.Token.Subject},
+		[]string{"Client ID", r.Token.ClientID},
+		[]string{"Scopes", strings.Join(r.Token.Scopes, " ")},
+		[]string{"Issued at", formatTime(r.Token.IssuedAt)},
+		[]string{"Expires at", expiry},
+		[]string{"Token health", r.Token.Health},
+		[]string{"Claims", strings.Join(claims, ", ")},
+	)
+	out := t.View()
+
+	if !withEntitlements {
+		return out
+	}
+	if len(r.Entitlements) == 0 {
+		return out + "\n" + cli.WarningMessage("No entitlements resolved for this entity")
+	}
+
+	et := cli.NewTable(
+		table.NewFlexColumn("fqn", "Attribute Value FQN", cli.FlexColumnWidthFour),
+		table.NewFlexColumn("actions", "Actions", cli.FlexColumnWidthTwo),
+	)
+	rows := make([]table.Row, 0, len(r.Entitlements))
+	for _, e := range r.Entitlements {
+		rows = append(rows, table.NewRow(table.RowData{
+			"fqn":     e.AttributeValueFqn,
+			"actions": strings.Join(e.Actions, ", "),
+		}))
+	}
+	et = et.WithRows(rows)
+	return fmt.Sprintf("%s\n%s", out, et.View())
+}
+
+// newWhoamiCmd creates and configures the whoami command.
+func newWhoamiCmd() *cobra.Command {
+	doc := man.Docs.GetCommand("auth/whoami", man.WithRun(whoamiRun))
+	// read-only, so usable wherever the profile credentials are
+	allowOnLinux(doc)
+
+	doc.Flags().BoolP(
+		doc.GetDocFlag("entitlements").Name,
+		doc.GetDocFlag("entitlements").Shorthand,
+		doc.GetDocFlag("entitlements").DefaultAsBool(),
+		doc.GetDocFlag("entitlements").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("comprehensive-hierarchy").Name,
+		doc.GetDocFlag("comprehensive-hierarchy").DefaultAsBool(),
+		doc.GetDocFlag("comprehensive-hierarchy").Description,
+	)
+	return &doc.Command
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/auth"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/otdfctl/pkg/profiles"
+	"github.com/spf13/cobra"
+)
+
+func workloadIdentityRun(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	cp := common.InitStoredProfile(c)
+
+	subjectTokenType := c.FlagHelper.GetOptionalString("subject-token-type")
+	if subjectTokenType == auth.TokenTypeJWT {
+		// default, no need to store it
+		subjectTokenType = ""
+	}
+
+	var scopes []string
+	if cmd.Flags().Changed("scopes") {
+		flagScopes, err := cmd.Flags().GetStringSlice("scopes")
+		if err != nil {
+			c.ExitWithError("Failed to read scopes flag", err)
+		}
+		for _, scope := range flagScopes {
+			scopes = append(scopes, strings.TrimSpace(scope))
+		}
+	}
+
+	creds := profiles.AuthCredentials{
+		AuthType: profiles.AuthTypeWorkloadIdentity,
+		ClientID: c.FlagHelper.GetOptionalString("client-id"),
+		Scopes:   scopes,
+		WorkloadIdentity: &profiles.AuthCredentialsWorkloadIdentity{
+			Grant:            c.FlagHelper.GetOptionalString("grant"),
+			TokenFile:        c.FlagHelper.GetOptionalString("token-file"),
+			TokenEnv:         c.FlagHelper.GetOptionalString("token-env"),
+			SubjectTokenType: subjectTokenType,
+			Audience:         c.FlagHelper.GetOptionalString("audience"),
+		},
+	}
+	if err := auth.ValidateWorkloadIdentity(creds); err != nil {
+		c.ExitWithError("Invalid workload identity configuration", err)
+	}
+
+	// Validate the exchange against an in-memory copy of the profile, so a failure keeps its working credentials
+	candidate, err := profiles.NewOtdfctlProfileStore(profiles.ProfileDriverMemory, &profiles.ProfileConfig{
+		Name:        cp.Name(),
+		Endpoint:    cp.GetEndpoint(),
+		TLSNoVerify: cp.GetTLSNoVerify(),
+	}, true)
+	if err != nil {
+		c.ExitWithError("Failed to initialize in-memory profile", err)
+	}
+	if err := candidate.SetAuthCredentials(creds); err != nil {
+		c.ExitWithError("Failed to set workload identity credentials", err)
+	}
+	if err := auth.ValidateProfileAuthCredentials(cmd.Context(), candidate); err != nil {
+		c.ExitWithError("An error occurred while exchanging the workload identity token", err)
+	}
+
+	if err := cp.SetAuthCredentials(creds); err != nil {
+		c.ExitWithError("Failed to set workload identity credentials", err)
+	}
+
+	c.ExitWithMessage(fmt.Sprintf("Workload identity credentials set for profile [%s]", cp.Name()), cli.ExitCodeSuccess)
+}
+
+// newWorkloadIdentityCmd creates and configures the workload-identity command.
+func newWorkloadIdentityCmd() *cobra.Command {
+	doc := man.Docs.GetCommand("auth/workload-identity",
+		man.WithRun(workloadIdentityRun),
+		man.WithHiddenFlags("with-client-creds", "with-client-creds-file"),
+	)
+	// Workload identity targets CI runners and Kubernetes jobs, and only stores a token location in the
+	// filesystem profile, so the keyring restriction of the parent command does not apply.
+	allowOnLinux(doc)
+
+	doc.Flags().String(
+		doc.GetDocFlag("token-file").Name,
+		doc.GetDocFlag("token-file").Default,
+		doc.GetDocFlag("token-file").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("token-env").Name,
+		doc.GetDocFlag("token-env").Default,
+		doc.GetDocFlag("token-env").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("grant").Name,
+		doc.GetDocFlag("grant").Default,
+		doc.GetDocFlag("grant").Description,
+	)
+	doc.Flags().StringP(
+		doc.GetDocFlag("client-id").Name,
+		doc.GetDocFlag("client-id").Shorthand,
+		doc.GetDocFlag("client-id").Default,
+		doc.GetDocFlag("client-id").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("audience").Name,
+		doc.GetDocFlag("audience").Default,
+		doc.GetDocFlag("audience").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("subject-token-type").Name,
+		doc.GetDocFlag("subject-token-type").Default,
+		doc.GetDocFlag("subject-token-type").Description,
+	)
+	doc.Flags().StringSlice(
+		doc.GetDocFlag("scopes").Name,
+		[]string{},
+		doc.GetDocFlag("scopes").Description,
+	)
+	return &doc.Command
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 
 	"github.com/evertras/bubble-table/table"
 	osprofiles "github.com/jrschumacher/go-osprofiles"
@@ -34,12 +35,70 @@ func applyOutputFormatPreference(c *cli.Cli, store *profiles.OtdfctlProfileStore
 	}
 }
 
+const (
+	profileSourceFlag    = "flag"
+	profileSourceProject = "project"
+	profileSourceDefault = "default"
+)
+
+// loadProjectConfig searches upward from the working directory for a project config file
+func loadProjectConfig() *profiles.ProjectConfig {
+	wd, err := os.Getwd()
+	if err != nil {
+		slog.Debug("Could not determine working directory, skipping project config", "error", err)
+		return nil
+	}
+	pc, err := profiles.FindProjectConfig(wd)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to load %s", profiles.ProjectConfigFileName), err)
+	}
+	if pc != nil {
+		slog.Debug("Found project config", "path", pc.Path())
+	}
+	return pc
+}
+
+// ApplyProjectFlagDefaults applies the flag defaults of the nearest project config to the command. It runs for every
+// command, including those which never load a profile.
+func ApplyProjectFlagDefaults(c *cli.Cli) {
+	applyProjectFlagDefaults(c, loadProjectConfig())
+}
+
+// applyProjectFlagDefaults sets the project config flag values on the command for any allowed flag not explicitly
+// provided
+func applyProjectFlagDefaults(c *cli.Cli, pc *profiles.ProjectConfig) {
+	if pc == nil {
+		return
+	}
+	flags := c.Cmd().Flags()
+	for name, value := range pc.Flags {
+		if !profiles.IsProjectConfigFlagAllowed(name) {
+			continue
+		}
+		f := flags.Lookup(name)
+		if f == nil || f.Changed {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			cli.ExitWithError(fmt.Sprintf("Invalid value for flag '--%s' in %s", name, pc.Path()), err)
+		}
+		slog.Debug("Applied project config flag default", "flag", name, "value", value, "source", pc.Path())
+	}
+}
+
 // InitProfile initializes the profile store and loads the profile specified in the flags
 // if onlyNew is set to true, a new profile will be created and returned
 // returns the profile and the current profile store
+//
+// The profile is selected by the --profile flag, then by the nearest project config file (.otdfctl.yaml), and
+// finally by the global default profile.
 func InitProfile(c *cli.Cli) *profiles.OtdfctlProfileStore {
 	var err error
 	profileName := c.FlagHelper.GetOptionalString("profile")
+	profileSource := profileSourceFlag
+
+	project := loadProjectConfig()
+	applyProjectFlagDefaults(c, project)
 
 	hasKeyringStore, err := osprofiles.HasGlobalStore(config.AppName, osprofiles.WithKeyringStore())
 	if err != nil {
@@ -59,32 +118,66 @@ func InitProfile(c *cli.Cli) *profiles.OtdfctlProfileStore {
 	}
 
 	defaultProfileName := osprofiles.GetGlobalConfig(profiler).GetDefaultProfile()
-	if len(defaultProfileName) == 0 {
-		c.ExitWithWarning(fmt.Sprintf("No default profile set. Use `%s profile create <profile> <endpoint>` to create a default profile.", config.AppName))
-	}
 
-	if profileName == "" {
+	switch {
+	case profileName != "":
+		// explicitly selected with --profile
+	case project != nil && project.Profile != "":
+		profileName = project.Profile
+		profileSource = profileSourceProject
+	default:
 		profileName = defaultProfileName
+		profileSource = profileSourceDefault
 	}
 
-	slog.Debug("Using profile", "profile", profileName)
+	// inline endpoint settings only apply when the profile was not explicitly chosen
+	useProjectEndpoint := profileSource != profileSourceFlag && project.HasInlineEndpoint()
+
+	if len(profileName) == 0 && !useProjectEndpoint {
+		c.ExitWithWarning(fmt.Sprintf("No default profile set. Use `%s profile create <profile> <endpoint>` to create a default profile.", config.AppName))
+	}
 
 	// load profile
-	store, err := profiles.LoadOtdfctlProfileStore(profiles.ProfileDriverFileSystem, profileName)
-	if err != nil {
-		c.ExitWithError(fmt.Sprintf("Failed to load profile: %s", profileName), err)
+	var store *profiles.OtdfctlProfileStore
+	if profileName != "" {
+		store, err = profiles.LoadOtdfctlProfileStore(profiles.ProfileDriverFileSystem, profileName)
+		if err != nil {
+			c.ExitWithError(fmt.Sprintf("Failed to load profile: %s", profileName), err)
+		}
+	}
+
+	if useProjectEndpoint {
+		store, err = profiles.NewProjectProfileStore(store, project)
+		if err != nil {
+			c.ExitWithError(fmt.Sprintf("Failed to apply endpoint settings from %s", project.Path()), err)
+		}
+		slog.Debug("Using project endpoint", "endpoint", store.GetEndpoint(), "source", project.Path())
 	}
 
+	slog.Debug("Using profile", "profile", profileName, "source", profileSource, "project_config", project.Path())
+
 	applyOutputFormatPreference(c, store)
 
 	return store
 }
 
-// instantiates a new handler with authentication via client credentials
-// TODO make this a preRun hook
+// InitStoredProfile initializes the profile like InitProfile, for commands which save credentials to it. The inline
+// endpoint of a project config is an in-memory profile, so saving to it is refused rather than silently discarded.
+func InitStoredProfile(c *cli.Cli) *profiles.OtdfctlProfileStore {
+	cp := InitProfile(c)
+	if path := cp.ProjectConfigPath(); path != "" {
+		c.ExitWithError(fmt.Sprintf(
+			"The endpoint %s is set inline by %s and is not a stored profile, so credentials cannot be saved for it. "+
+				"Pin a profile in that file or select one with --profile.", cp.GetEndpoint(), path), nil)
+	}
+	return cp
+}
+
+// LoadProfile resolves the profile for the command, either from the global host and auth flags as a temporary
+// in-memory profile or from the profile store. The returned bool reports whether the profile is in-memory.
 //
 //nolint:nestif // separate refactor [https://github.com/opentdf/otdfctl/issues/383]
-func NewHandler(c *cli.Cli) handlers.Handler {
+func LoadProfile(c *cli.Cli) (*profiles.OtdfctlProfileStore, bool) {
 	// if global flags are set then validate and create a temporary profile in memory
 	var cp *profiles.OtdfctlProfileStore
 
@@ -125,6 +218,8 @@ func NewHandler(c *cli.Cli) handlers.Handler {
 			cli.ExitWithError(fmt.Sprintf("Only one of %s must be set", cli.PrettyList(authFlags)), err)
 		}
 
+		applyProjectFlagDefaults(c, loadProjectConfig())
+
 		inMemoryProfile = true
 		config := profiles.ProfileConfig{
 			Name:        "temp",
@@ -179,6 +274,27 @@ func NewHandler(c *cli.Cli) handlers.Handler {
 		cp = InitProfile(c)
 	}
 
+	return cp, inMemoryProfile
+}
+
+// instantiates a new handler with authentication via client credentials
+// TODO make this a preRun hook
+func NewHandler(c *cli.Cli) handlers.Handler {
+	cp, inMemoryProfile := LoadProfile(c)
+	return newHandlerForProfileStore(c, cp, inMemoryProfile)
+}
+
+// NewHandlerForProfile instantiates a handler for a named profile rather than the profile selected for the command,
+// for commands which talk to more than one platform
+func NewHandlerForProfile(c *cli.Cli, profileName string) handlers.Handler {
+	cp, err := profiles.LoadOtdfctlProfileStore(profiles.ProfileDriverFileSystem, profileName)
+	if err != nil {
+		c.ExitWithError(fmt.Sprintf("Failed to load profile: %s", profileName), err)
+	}
+	return newHandlerForProfileStore(c, cp, false)
+}
+
+func newHandlerForProfileStore(c *cli.Cli, cp *profiles.OtdfctlProfileStore, inMemoryProfile bool) handlers.Handler {
 	if err := auth.ValidateProfileAuthCredentials(c.Context(), cp); err != nil {
 		endpoint := cp.GetEndpoint()
 		var certErr *tls.CertificateVerificationError
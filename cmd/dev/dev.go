@@ -63,4 +63,5 @@ func InitCommands() {
 	devCmd.AddCommand(&designCmd.Command)
 
 	initSelectorsCommands()
+	initKeyManagerCommands()
 }
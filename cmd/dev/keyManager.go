@@ -0,0 +1,172 @@
+//nolint:forbidigo // the emulator prints its provider config as it starts serving
+package dev
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/keymanager"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/spf13/cobra"
+)
+
+const (
+	keyManagerReadHeaderTimeout = 10 * time.Second
+	keyManagerShutdownTimeout   = 5 * time.Second
+	providerConfigFileMode      = 0o600
+)
+
+type keyManagerKey struct {
+	*keymanager.Key
+	WrappingKeyID string `json:"wrapping_key_id"`
+}
+
+func openKeyManager(c *cli.Cli) *keymanager.KeyManager {
+	path := c.Flags.GetRequiredString("key-file")
+	km, err := keymanager.Open(path)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to open key manager file %s", path), err)
+	}
+	return km
+}
+
+// keyManagerServe runs the emulator until interrupted. The provider config to register it with is printed, and written
+// to a file when asked, once it listens, so scripts can wait for the file to appear.
+func keyManagerServe(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	km := openKeyManager(c)
+	address := c.Flags.GetOptionalString("address")
+	token := c.Flags.GetOptionalString("token")
+	configOut := c.Flags.GetOptionalString("provider-config-out")
+
+	rootKeyID, err := km.RootKeyID()
+	if err != nil {
+		cli.ExitWithError("Failed to read the root key", err)
+	}
+
+	ln, err := net.Listen("tcp", address)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to listen on %s", address), err)
+	}
+	pc := keymanager.ProviderConfig{Endpoint: "http://" + ln.Addr().String(), RootKeyID: rootKeyID, Token: token}
+	config, err := json.Marshal(pc)
+	if err != nil {
+		cli.ExitWithError("Failed to encode the provider config", err)
+	}
+	if configOut != "" {
+		if err := os.WriteFile(configOut, config, providerConfigFileMode); err != nil {
+			cli.ExitWithError(fmt.Sprintf("Failed to write the provider config to %s", configOut), err)
+		}
+	}
+
+	if c.Flags.GetOptionalBool("json") {
+		fmt.Println(string(config))
+	} else {
+		fmt.Println(cli.SuccessMessage(fmt.Sprintf("Key manager emulator listening on %s with root key %s", pc.Endpoint, rootKeyID)))
+		fmt.Printf("Register it with:\n\n  otdfctl policy keymanagement provider create --name <name> --manager dev-key-manager --config '%s'\n\n", config)
+	}
+
+	srv := &http.Server{
+		Handler:           keymanager.NewHandler(km, token),
+		ReadHeaderTimeout: keyManagerReadHeaderTimeout,
+	}
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), keyManagerShutdownTimeout)
+		defer cancel()
+		//nolint:errcheck // the server is going away either way
+		srv.Shutdown(shutdownCtx)
+	}()
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		cli.ExitWithError("Key manager emulator stopped", err)
+	}
+}
+
+// keyManagerCreateKey creates a key pair in the key manager file directly, so keys can be prepared before the
+// emulator serves, or while it does.
+func keyManagerCreateKey(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	km := openKeyManager(c)
+	keyID := c.Flags.GetRequiredString("key-id")
+	algorithm := c.Flags.GetOptionalString("algorithm")
+
+	k, err := km.CreateKey(keyID, algorithm)
+	if err != nil {
+		cli.ExitWithError("Failed to create key", err)
+	}
+	rootKeyID, err := km.RootKeyID()
+	if err != nil {
+		cli.ExitWithError("Failed to read the root key", err)
+	}
+
+	t := cli.NewTabular(
+		[]string{"Key ID", k.KeyID},
+		[]string{"Algorithm", k.Algorithm},
+		[]string{"Wrapping Key ID", rootKeyID},
+		[]string{"Public Key PEM", k.PublicKeyPEM},
+		[]string{"Wrapped Private Key", k.WrappedPrivateKey},
+	)
+	common.HandleSuccess(cmd, k.KeyID, t, keyManagerKey{Key: k, WrappingKeyID: rootKeyID})
+}
+
+func initKeyManagerCommands() {
+	serveDoc := man.Docs.GetCommand("dev/key-manager/serve",
+		man.WithRun(keyManagerServe),
+	)
+	serveDoc.Flags().String(
+		serveDoc.GetDocFlag("key-file").Name,
+		serveDoc.GetDocFlag("key-file").Default,
+		serveDoc.GetDocFlag("key-file").Description,
+	)
+	serveDoc.Flags().String(
+		serveDoc.GetDocFlag("address").Name,
+		serveDoc.GetDocFlag("address").Default,
+		serveDoc.GetDocFlag("address").Description,
+	)
+	serveDoc.Flags().String(
+		serveDoc.GetDocFlag("token").Name,
+		serveDoc.GetDocFlag("token").Default,
+		serveDoc.GetDocFlag("token").Description,
+	)
+	serveDoc.Flags().String(
+		serveDoc.GetDocFlag("provider-config-out").Name,
+		serveDoc.GetDocFlag("provider-config-out").Default,
+		serveDoc.GetDocFlag("provider-config-out").Description,
+	)
+
+	createKeyDoc := man.Docs.GetCommand("dev/key-manager/create-key",
+		man.WithRun(keyManagerCreateKey),
+	)
+	createKeyDoc.Flags().String(
+		createKeyDoc.GetDocFlag("key-file").Name,
+		createKeyDoc.GetDocFlag("key-file").Default,
+		createKeyDoc.GetDocFlag("key-file").Description,
+	)
+	createKeyDoc.Flags().String(
+		createKeyDoc.GetDocFlag("key-id").Name,
+		createKeyDoc.GetDocFlag("key-id").Default,
+		createKeyDoc.GetDocFlag("key-id").Description,
+	)
+	createKeyDoc.Flags().String(
+		createKeyDoc.GetDocFlag("algorithm").Name,
+		createKeyDoc.GetDocFlag("algorithm").Default,
+		createKeyDoc.GetDocFlag("algorithm").Description,
+	)
+
+	keyManagerDoc := man.Docs.GetCommand("dev/key-manager",
+		man.WithSubcommands(serveDoc, createKeyDoc),
+	)
+	Cmd.AddCommand(&keyManagerDoc.Command)
+}
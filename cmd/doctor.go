@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/auth"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/doctor"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/otdfctl/pkg/profiles"
+	"github.com/opentdf/otdfctl/pkg/utils"
+	"github.com/opentdf/platform/sdk"
+	"github.com/spf13/cobra"
+)
+
+const (
+	tokenCheck = "Token"
+	skewCheck  = "Clock skew"
+	kasCheck   = "KAS"
+)
+
+func doctorRun(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	cp, _ := common.LoadProfile(c)
+
+	timeout, err := time.ParseDuration(c.Flags.GetOptionalString("timeout"))
+	if err != nil || timeout <= 0 {
+		c.ExitWithError("Invalid --timeout, expected a duration such as 10s", err)
+	}
+
+	report := runDoctor(cmd.Context(), cp, timeout)
+
+	code := cli.ExitCodeSuccess
+	if report.Failed() {
+		code = cli.ExitCodeError
+	}
+	c.ExitWith(renderDoctorReport(report), report, code, os.Stdout)
+}
+
+//nolint:nestif // each check depends on the outcome of the previous ones
+func runDoctor(ctx context.Context, cp *profiles.OtdfctlProfileStore, timeout time.Duration) doctor.Report {
+	endpoint := cp.GetEndpoint()
+	tlsNoVerify := cp.GetTLSNoVerify()
+	report := doctor.Report{Profile: cp.Name(), Endpoint: endpoint}
+
+	skipRemaining := func(reason string, names ...string) doctor.Report {
+		for _, n := range names {
+			report.Add(doctor.Skip(n, reason))
+		}
+		return report
+	}
+
+	u, err := utils.NormalizeEndpoint(endpoint)
+	if err != nil {
+		report.Add(doctor.Check{Name: "Endpoint", Status: doctor.StatusFail, Message: fmt.Sprintf("invalid endpoint %q: %s", endpoint, err)})
+		return report
+	}
+
+	checkCtx := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(ctx, timeout)
+	}
+
+	cctx, cancel := checkCtx()
+	dns := report.Add(doctor.CheckDNS(cctx, u))
+	cancel()
+	if dns.Status == doctor.StatusFail {
+		return skipRemaining("host could not be resolved", "TCP", "TLS", "Well-known configuration", "IdP discovery", tokenCheck, skewCheck, kasCheck, "SDK compatibility")
+	}
+
+	cctx, cancel = checkCtx()
+	tcp := report.Add(doctor.CheckTCP(cctx, u, timeout))
+	cancel()
+	if tcp.Status == doctor.StatusFail {
+		return skipRemaining("host is unreachable", "TLS", "Well-known configuration", "IdP discovery", tokenCheck, skewCheck, kasCheck, "SDK compatibility")
+	}
+
+	cctx, cancel = checkCtx()
+	tlsCheck := report.Add(doctor.CheckTLS(cctx, u, tlsNoVerify, timeout, time.Now()))
+	cancel()
+	if tlsCheck.Status == doctor.StatusFail {
+		return skipRemaining("TLS handshake did not succeed", "Well-known configuration", "IdP discovery", tokenCheck, skewCheck, kasCheck, "SDK compatibility")
+	}
+
+	client := utils.NewHTTPClient(tlsNoVerify)
+	client.Timeout = timeout
+
+	wk, wkCheck := doctor.CheckWellKnown(ctx, client, u.String())
+	report.Add(wkCheck)
+	if wkCheck.Status == doctor.StatusFail {
+		return skipRemaining("well-known configuration is unavailable", "IdP discovery", tokenCheck, skewCheck, kasCheck, "SDK compatibility")
+	}
+
+	report.Add(doctor.CheckIDPDiscovery(ctx, client, wk.Issuer))
+
+	// token acquisition goes through the same code path as every other command
+	tctx, cancel := checkCtx()
+	tok, err := auth.GetTokenWithProfile(tctx, cp)
+	cancel()
+	authType := cp.GetAuthCredentials().AuthType
+	switch {
+	case err != nil:
+		report.Add(doctor.Check{Name: tokenCheck, Status: doctor.StatusFail, Message: "could not acquire a token: " + err.Error()})
+		report.Add(doctor.Skip(skewCheck, "no token was acquired"))
+		report.Add(doctor.Skip(kasCheck, "no token was acquired"))
+	default:
+		info, decodeErr := auth.DecodeTokenInfo(tok.AccessToken, time.Now())
+		msg := fmt.Sprintf("acquired with %s", authType)
+		status := doctor.StatusPass
+		if decodeErr == nil {
+			msg += fmt.Sprintf(" for subject %q, %s", info.Subject, info.Health)
+			if info.Health == auth.TokenHealthExpired || info.Health == auth.TokenHealthNotYetValid {
+				status = doctor.StatusFail
+			} else if info.Health == auth.TokenHealthExpiringSoon {
+				status = doctor.StatusWarn
+			}
+		}
+		report.Add(doctor.Check{Name: tokenCheck, Status: status, Message: msg})
+
+		// a stored access token was issued in the past, so only a freshly issued token tells us about skew
+		switch {
+		case authType == profiles.AuthTypeAccessToken:
+			report.Add(doctor.Skip(skewCheck, "access token was not freshly issued"))
+		case decodeErr != nil || info.IssuedAt == nil:
+			report.Add(doctor.Skip(skewCheck, "token has no iat claim"))
+		default:
+			report.Add(doctor.CheckClockSkew(*info.IssuedAt, time.Now()))
+		}
+
+		runKASChecks(ctx, &report, cp, client)
+	}
+
+	report.Add(doctor.CheckSDKVersion(wk.Version, sdk.Version))
+	return report
+}
+
+func runKASChecks(ctx context.Context, report *doctor.Report, cp *profiles.OtdfctlProfileStore, client *http.Client) {
+	h, err := handlers.New(handlers.WithProfile(cp))
+	if err != nil {
+		report.Add(doctor.Check{Name: kasCheck, Status: doctor.StatusFail, Message: "could not connect to the platform: " + err.Error()})
+		return
+	}
+	defer h.Close()
+
+	entries, err := h.ListAllKasRegistryEntries(ctx)
+	if err != nil {
+		report.Add(doctor.Check{Name: kasCheck, Status: doctor.StatusFail, Message: "could not list registered KASes: " + err.Error()})
+		return
+	}
+	if len(entries) == 0 {
+		report.Add(doctor.Check{Name: kasCheck, Status: doctor.StatusWarn, Message: "no KASes are registered"})
+		return
+	}
+	for _, kas := range entries {
+		report.Add(doctor.CheckKASPublicKey(ctx, client, kas.GetUri()))
+	}
+}
+
+func renderDoctorReport(r doctor.Report) string {
+	t := cli.NewTable(
+		table.NewFlexColumn("status", "Status", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("check", "Check", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("message", "Message", cli.FlexColumnWidthFive),
+	)
+	rows := make([]table.Row, 0, len(r.Checks))
+	for _, check := range r.Checks {
+		rows = append(rows, table.NewRow(table.RowData{
+			"status":  strings.ToUpper(check.Status),
+			"check":   check.Name,
+			"message": check.Message,
+		}))
+	}
+	t = t.WithRows(rows)
+
+	counts := r.Counts()
+	summary := fmt.Sprintf("%d passed, %d warnings, %d failed, %d skipped for %s",
+		counts[doctor.StatusPass], counts[doctor.StatusWarn], counts[doctor.StatusFail], counts[doctor.StatusSkip], r.Endpoint)
+	switch {
+	case r.Failed():
+		summary = cli.ErrorMessage(summary, nil)
+	case counts[doctor.StatusWarn] > 0:
+		summary = cli.WarningMessage(summary)
+	default:
+		summary = cli.SuccessMessage(summary)
+	}
+	return t.View() + "\n" + summary
+}
+
+// newDoctorCmd creates and configures the doctor command.
+func newDoctorCmd() *cobra.Command {
+	doc := man.Docs.GetCommand("doctor", man.WithRun(doctorRun))
+	doc.Flags().String(
+		doc.GetDocFlag("timeout").Name,
+		doc.GetDocFlag("timeout").Default,
+		doc.GetDocFlag("timeout").Description,
+	)
+	return &doc.Command
+}
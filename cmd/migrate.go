@@ -32,7 +32,15 @@ func withMigrateSubcommand(root *cobra.Command) {
 
 	registeredResourcesMigrationCmd := man.Docs.GetCommand("migrate/registered-resources", man.WithRun(migrateRegisteredResources))
 
+	kasGrantsMigrationCmd := man.Docs.GetCommand("migrate/kas-grants", man.WithRun(migrateKasGrants))
+	kasGrantsMigrationCmd.Flags().Bool(
+		kasGrantsMigrationCmd.GetDocFlag("remove-grants").Name,
+		kasGrantsMigrationCmd.GetDocFlag("remove-grants").DefaultAsBool(),
+		kasGrantsMigrationCmd.GetDocFlag("remove-grants").Description,
+	)
+
 	migrateCmd.AddCommand(&registeredResourcesMigrationCmd.Command)
+	migrateCmd.AddCommand(&kasGrantsMigrationCmd.Command)
 
 	root.AddCommand(migrateCmd)
 }
@@ -56,3 +64,25 @@ func migrateRegisteredResources(cmd *cobra.Command, args []string) {
 		cli.ExitWithError("could not migrate registered resources", err)
 	}
 }
+
+func migrateKasGrants(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := otdfctl.NewHandler(c)
+	defer h.Close()
+
+	commit, err := cmd.InheritedFlags().GetBool("commit")
+	if err != nil {
+		cli.ExitWithError("could not read --commit flag", err)
+	}
+
+	interactive, err := cmd.InheritedFlags().GetBool("interactive")
+	if err != nil {
+		cli.ExitWithError("could not read --interactive flag", err)
+	}
+
+	removeGrants := c.Flags.GetOptionalBool("remove-grants")
+
+	if err := migrations.MigrateKasGrants(cmd.Context(), &h, &migrations.HuhPrompter{}, commit, interactive, removeGrants); err != nil {
+		cli.ExitWithError("could not migrate KAS grants", err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"github.com/evertras/bubble-table/table"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/spf13/cobra"
 )
@@ -103,6 +105,7 @@ func policyCreateAction(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create action", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindAction, nil, action)
 
 	rows := [][]string{
 		{"Id", action.GetId()},
@@ -140,6 +143,7 @@ func policyDeleteAction(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to delete action (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindAction, action, nil)
 	rows := [][]string{
 		{"Id", id},
 		{"Name", action.GetName()},
@@ -161,6 +165,11 @@ func policyUpdateAction(cmd *cobra.Command, args []string) {
 	name := c.Flags.GetOptionalString("name")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
+	before, err := h.GetAction(cmd.Context(), id, "", "")
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to find action (%s)", id), err)
+	}
+
 	updated, err := h.UpdateAction(
 		cmd.Context(),
 		id,
@@ -171,6 +180,7 @@ func policyUpdateAction(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to update action", err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindAction, before, updated)
 	rows := [][]string{
 		{"Id", id},
 		{"Name", updated.GetName()},
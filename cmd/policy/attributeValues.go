@@ -7,6 +7,9 @@ import (
 	"github.com/evertras/bubble-table/table"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/impact"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
 	policycommon "github.com/opentdf/platform/protocol/go/common"
 	"github.com/opentdf/platform/protocol/go/policy"
@@ -35,6 +38,7 @@ func createAttributeValue(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create attribute value", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindValue, nil, v)
 
 	handleValueSuccess(cmd, v)
 }
@@ -157,7 +161,7 @@ func updateAttributeValue(cmd *cobra.Command, args []string) {
 	id := c.Flags.GetRequiredID("id")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
-	_, err := h.GetAttributeValue(ctx, id)
+	before, err := h.GetAttributeValue(ctx, id)
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to get attribute value (%s)", id), err)
 	}
@@ -166,6 +170,7 @@ func updateAttributeValue(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to update attribute value", err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindValue, before, v)
 
 	handleValueSuccess(cmd, v)
 }
@@ -184,12 +189,14 @@ func deactivateAttributeValue(cmd *cobra.Command, args []string) {
 		cli.ExitWithError(fmt.Sprintf("Failed to get attribute value (%s)", id), err)
 	}
 
-	cli.ConfirmAction(cli.ActionDeactivate, "attribute value", value.GetValue(), force)
+	impactText := policyImpact(c, h, impact.OperationDeactivate, labels.KindValue, id, !force)
+	cli.ConfirmActionSubtext(cli.ActionDeactivate, "attribute value", value.GetValue(), impactText, force)
 
 	deactivated, err := h.DeactivateAttributeValue(ctx, id)
 	if err != nil {
 		cli.ExitWithError("Failed to deactivate attribute value", err)
 	}
+	journalChange(h, journal.OperationDeactivate, labels.KindValue, value, deactivated)
 
 	handleValueSuccess(cmd, deactivated)
 }
@@ -214,6 +221,7 @@ func unsafeReactivateAttributeValue(cmd *cobra.Command, args []string) {
 	if reactivated, err := h.UnsafeReactivateAttributeValue(ctx, id); err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to reactivate attribute value (%s)", id), err)
 	} else {
+		journalChange(h, journal.OperationReactivate, labels.KindValue, v, reactivated)
 		rows := [][]string{
 			{"Id", reactivated.GetId()},
 			{"Value", reactivated.GetValue()},
@@ -247,6 +255,8 @@ func unsafeUpdateAttributeValue(cmd *cobra.Command, args []string) {
 	if err := h.UnsafeUpdateAttributeValue(ctx, id, value); err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to update attribute value (%s)", id), err)
 	} else {
+		updated, _ := h.GetAttributeValue(ctx, id)
+		journalChange(h, journal.OperationUnsafeUpdate, labels.KindValue, v, updated)
 		rows := [][]string{
 			{"Id", v.GetId()},
 			{"Value", value},
@@ -272,13 +282,15 @@ func unsafeDeleteAttributeValue(cmd *cobra.Command, args []string) {
 		cli.ExitWithError(fmt.Sprintf("Failed to get attribute value (%s)", id), err)
 	}
 
+	impactText := policyImpact(c, h, impact.OperationDelete, labels.KindValue, id, !forceUnsafe)
 	if !forceUnsafe {
-		cli.ConfirmTextInput(cli.ActionDelete, "attribute value", cli.InputNameFQN, v.GetFqn())
+		cli.ConfirmTextInputSubtext(cli.ActionDelete, "attribute value", cli.InputNameFQN, v.GetFqn(), impactText)
 	}
 
 	if err := h.UnsafeDeleteAttributeValue(ctx, id, v.GetFqn()); err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to delete attribute (%s)", id), err)
 	} else {
+		journalChange(h, journal.OperationDelete, labels.KindValue, v, nil)
 		rows := [][]string{
 			{"Id", v.GetId()},
 			{"Value", v.GetValue()},
@@ -305,6 +317,7 @@ func policyAssignKeyToAttrValue(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to assign key: (%s) to attribute value: (%s)", keyID, value)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationAssign, kindValueKey, nil, attrKey)
 
 	rows := [][]string{
 		{"Value ID", attrKey.GetValueId()},
@@ -327,6 +340,7 @@ func policyRemoveKeyFromAttrValue(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to remove key (%s) from attribute value (%s)", keyID, value)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationUnassign, kindValueKey, &attributes.ValueKey{ValueId: value, KeyId: keyID}, nil)
 
 	rows := [][]string{
 		{"Removed", "true"},
@@ -407,6 +421,11 @@ func initAttributeValuesCommands() {
 		false,
 		deactivateCmd.GetDocFlag("force").Description,
 	)
+	deactivateCmd.Flags().Bool(
+		deactivateCmd.GetDocFlag("what-if").Name,
+		false,
+		deactivateCmd.GetDocFlag("what-if").Description,
+	)
 
 	keyCmd := man.Docs.GetCommand("policy/attributes/values/key")
 
@@ -461,6 +480,11 @@ func initAttributeValuesCommands() {
 		unsafeDeleteCmd.GetDocFlag("id").Default,
 		unsafeDeleteCmd.GetDocFlag("id").Description,
 	)
+	unsafeDeleteCmd.Flags().Bool(
+		unsafeDeleteCmd.GetDocFlag("what-if").Name,
+		false,
+		unsafeDeleteCmd.GetDocFlag("what-if").Description,
+	)
 
 	unsafeUpdateCmd := man.Docs.GetCommand("policy/attributes/values/unsafe/update",
 		man.WithRun(unsafeUpdateAttributeValue),
@@ -485,10 +509,12 @@ func initAttributeValuesCommands() {
 		unsafeCmd.GetDocFlag("force").Description,
 	)
 
+	reorderCmd := newAttributeValuesReorderCommand()
+
 	keyCmd.AddSubcommands(assignKasKeyCmd, removeKasKeyCmd)
 	unsafeCmd.AddSubcommands(unsafeReactivateCmd, unsafeDeleteCmd, unsafeUpdateCmd)
 	doc := man.Docs.GetCommand("policy/attributes/values",
-		man.WithSubcommands(createCmd, getCmd, listCmd, updateCmd, deactivateCmd, unsafeCmd, keyCmd),
+		man.WithSubcommands(createCmd, getCmd, listCmd, updateCmd, deactivateCmd, reorderCmd, unsafeCmd, keyCmd),
 	)
 	AttributeValuesCmd = &doc.Command
 	AttributesCmd.AddCommand(AttributeValuesCmd)
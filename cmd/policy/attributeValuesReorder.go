@@ -0,0 +1,175 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/otdfctl/pkg/valueorder"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/spf13/cobra"
+)
+
+type reorderResult struct {
+	ID     string   `json:"id"`
+	FQN    string   `json:"fqn"`
+	Rule   string   `json:"rule"`
+	Before []string `json:"before"`
+	After  []string `json:"after"`
+	DryRun bool     `json:"dry_run"`
+}
+
+func reorderAttributeValues(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	ctx := cmd.Context()
+	attrID := c.Flags.GetRequiredString("attribute-id")
+	order, err := cmd.Flags().GetStringSlice("order")
+	if err != nil {
+		c.ExitWithError("Invalid --order", err)
+	}
+	move := c.Flags.GetOptionalString("move")
+	above := c.Flags.GetOptionalString("above")
+	below := c.Flags.GetOptionalString("below")
+	dryRun := c.Flags.GetOptionalBool("dry-run")
+	force := c.Flags.GetOptionalBool("force")
+
+	switch {
+	case len(order) > 0 && move != "":
+		c.ExitWithError("Flags '--order' and '--move' cannot be used together", nil)
+	case len(order) == 0 && move == "":
+		c.ExitWithError("Either '--order' or '--move' is required", nil)
+	case move != "" && (above == "") == (below == ""):
+		c.ExitWithError("Flag '--move' requires exactly one of '--above' or '--below'", nil)
+	}
+
+	attr, err := h.GetAttribute(ctx, attrID)
+	if err != nil {
+		c.ExitWithError(fmt.Sprintf("Failed to get attribute (%s)", attrID), err)
+	}
+
+	var after []*policy.Value
+	if move != "" {
+		anchor := above
+		if anchor == "" {
+			anchor = below
+		}
+		after, err = valueorder.Move(attr.GetValues(), move, anchor, above != "")
+	} else {
+		after, err = valueorder.FromNames(attr.GetValues(), order)
+	}
+	if err != nil {
+		c.ExitWithError("Failed to reorder the attribute values", err)
+	}
+
+	result := reorderResult{
+		ID:     attr.GetId(),
+		FQN:    attr.GetFqn(),
+		Rule:   handlers.GetAttributeRuleFromAttributeType(attr.GetRule()),
+		Before: valueorder.Names(attr.GetValues()),
+		After:  valueorder.Names(after),
+		DryRun: dryRun,
+	}
+	if !valueorder.Changed(attr.GetValues(), after) {
+		c.ExitWith(renderReorder(result)+"\n"+cli.SuccessMessage("The values are already in this order"), result, cli.ExitCodeSuccess, os.Stdout)
+	}
+	if dryRun {
+		c.ExitWith(renderReorder(result)+"\n"+cli.WarningMessage("Dry run: the values were not reordered"), result, cli.ExitCodeSuccess, os.Stdout)
+	}
+
+	if !force {
+		cli.ConfirmTextInputSubtext(cli.ActionUpdateUnsafe, "attribute", cli.InputNameFQN, attr.GetFqn(), renderReorder(result))
+	}
+
+	updated, err := h.UnsafeUpdateAttribute(ctx, attr.GetId(), "", "", valueorder.IDs(after), nil)
+	if err != nil {
+		c.ExitWithError(fmt.Sprintf("Failed to reorder the values of attribute (%s)", attr.GetId()), err)
+	}
+	journalChange(h, journal.OperationUnsafeUpdate, labels.KindAttribute, attr, updated)
+
+	result.After = valueorder.Names(updated.GetValues())
+	c.ExitWith(renderReorder(result)+"\n"+cli.SuccessMessage("Reordered the values of "+attr.GetFqn()), result, cli.ExitCodeSuccess, os.Stdout)
+}
+
+// renderReorder shows the values before and after side by side, first to last
+func renderReorder(r reorderResult) string {
+	rank := "Order"
+	if r.Rule == handlers.AttributeRuleHierarchy {
+		rank = "Rank (highest first)"
+	}
+	t := cli.NewTable(
+		table.NewFlexColumn("rank", rank, cli.FlexColumnWidthOne),
+		table.NewFlexColumn("before", "Before", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("after", "After", cli.FlexColumnWidthTwo),
+	)
+	rows := make([]table.Row, 0, len(r.After))
+	for i := range r.After {
+		before := ""
+		if i < len(r.Before) {
+			before = r.Before[i]
+		}
+		after := r.After[i]
+		if before != after {
+			after += " *"
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"rank":   strconv.Itoa(i + 1),
+			"before": before,
+			"after":  after,
+		}))
+	}
+	return t.WithRows(rows).View()
+}
+
+func newAttributeValuesReorderCommand() *man.Doc {
+	doc := man.Docs.GetCommand("policy/attributes/values/reorder",
+		man.WithRun(reorderAttributeValues),
+	)
+	doc.Flags().StringP(
+		doc.GetDocFlag("attribute-id").Name,
+		doc.GetDocFlag("attribute-id").Shorthand,
+		doc.GetDocFlag("attribute-id").Default,
+		doc.GetDocFlag("attribute-id").Description,
+	)
+	doc.Flags().StringSliceP(
+		doc.GetDocFlag("order").Name,
+		doc.GetDocFlag("order").Shorthand,
+		[]string{},
+		doc.GetDocFlag("order").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("move").Name,
+		doc.GetDocFlag("move").Default,
+		doc.GetDocFlag("move").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("above").Name,
+		doc.GetDocFlag("above").Default,
+		doc.GetDocFlag("above").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("below").Name,
+		doc.GetDocFlag("below").Default,
+		doc.GetDocFlag("below").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("dry-run").Name,
+		false,
+		doc.GetDocFlag("dry-run").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("force").Name,
+		false,
+		doc.GetDocFlag("force").Description,
+	)
+	return doc
+}
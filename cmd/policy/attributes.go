@@ -7,7 +7,11 @@ import (
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
 	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/impact"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/platform/protocol/go/policy/attributes"
 	"github.com/spf13/cobra"
 )
 
@@ -35,6 +39,7 @@ func createAttribute(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create attribute", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindAttribute, nil, attr)
 
 	a := cli.GetSimpleAttribute(attr)
 	rows := [][]string{
@@ -138,13 +143,16 @@ func deactivateAttribute(cmd *cobra.Command, args []string) {
 		cli.ExitWithError(errMsg, err)
 	}
 
-	cli.ConfirmAction(cli.ActionDeactivate, "attribute", attr.GetName(), force)
+	impactText := policyImpact(c, h, impact.OperationDeactivate, labels.KindAttribute, id, !force)
+	cli.ConfirmActionSubtext(cli.ActionDeactivate, "attribute", attr.GetName(), impactText, force)
 
+	before := attr
 	attr, err = h.DeactivateAttribute(ctx, id)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to deactivate attribute (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDeactivate, labels.KindAttribute, before, attr)
 
 	a := cli.GetSimpleAttribute(attr)
 	rows := [][]string{
@@ -169,9 +177,15 @@ func updateAttribute(cmd *cobra.Command, args []string) {
 	id := c.Flags.GetRequiredID("id")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
+	before, err := h.GetAttribute(cmd.Context(), id)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to get attribute (%s)", id), err)
+	}
+
 	if a, err := h.UpdateAttribute(cmd.Context(), id, getMetadataMutable(metadataLabels), getMetadataUpdateBehavior()); err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to update attribute (%s)", id), err)
 	} else {
+		journalChange(h, journal.OperationUpdate, labels.KindAttribute, before, a)
 		rows := [][]string{
 			{"Id", a.GetId()},
 			{"Name", a.GetName()},
@@ -205,6 +219,7 @@ func unsafeReactivateAttribute(cmd *cobra.Command, args []string) {
 	if reactivatedAttr, err := h.UnsafeReactivateAttribute(ctx, id); err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to reactivate attribute (%s)", id), err)
 	} else {
+		journalChange(h, journal.OperationReactivate, labels.KindAttribute, a, reactivatedAttr)
 		rows := [][]string{
 			{"Id", reactivatedAttr.GetId()},
 			{"Name", reactivatedAttr.GetName()},
@@ -243,6 +258,7 @@ func unsafeUpdateAttribute(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to update attribute (%s)", id), err)
 	} else {
+		journalChange(h, journal.OperationUnsafeUpdate, labels.KindAttribute, a, updatedAttr)
 		var (
 			retrievedVals []string
 			valueIDs      []string
@@ -284,13 +300,15 @@ func unsafeDeleteAttribute(cmd *cobra.Command, args []string) {
 		cli.ExitWithError(errMsg, err)
 	}
 
+	impactText := policyImpact(c, h, impact.OperationDelete, labels.KindAttribute, id, !forceUnsafe)
 	if !forceUnsafe {
-		cli.ConfirmTextInput(cli.ActionDelete, "attribute", cli.InputNameFQN, a.GetFqn())
+		cli.ConfirmTextInputSubtext(cli.ActionDelete, "attribute", cli.InputNameFQN, a.GetFqn(), impactText)
 	}
 
 	if err := h.UnsafeDeleteAttribute(ctx, id, a.GetFqn()); err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to delete attribute (%s)", id), err)
 	} else {
+		journalChange(h, journal.OperationDelete, labels.KindAttribute, a, nil)
 		rows := [][]string{
 			{"Deleted", "true"},
 			{"Id", a.GetId()},
@@ -318,6 +336,7 @@ func policyAssignKeyToAttribute(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to assign key: (%s) to attribute: (%s)", keyID, attribute)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationAssign, kindAttributeKey, nil, attrKey)
 
 	// Prepare and display the result
 	rows := [][]string{
@@ -341,6 +360,7 @@ func policyRemoveKeyFromAttribute(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to remove key (%s) from attribute (%s)", keyID, attribute)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationUnassign, kindAttributeKey, &attributes.AttributeKey{AttributeId: attribute, KeyId: keyID}, nil)
 
 	// Prepare and display the result
 	rows := [][]string{
@@ -439,6 +459,11 @@ func initAttributesCommands() {
 		false,
 		deactivateDoc.GetDocFlag("force").Description,
 	)
+	deactivateDoc.Flags().Bool(
+		deactivateDoc.GetDocFlag("what-if").Name,
+		false,
+		deactivateDoc.GetDocFlag("what-if").Description,
+	)
 
 	// unsafe actions on attributes
 	unsafeCmd := man.Docs.GetCommand("policy/attributes/unsafe")
@@ -466,6 +491,11 @@ func initAttributesCommands() {
 		deleteCmd.GetDocFlag("id").Default,
 		deleteCmd.GetDocFlag("id").Description,
 	)
+	deleteCmd.Flags().Bool(
+		deleteCmd.GetDocFlag("what-if").Name,
+		false,
+		deleteCmd.GetDocFlag("what-if").Description,
+	)
 	unsafeUpdateCmd := man.Docs.GetCommand("policy/attributes/unsafe/update",
 		man.WithRun(unsafeUpdateAttribute),
 	)
@@ -2,11 +2,20 @@ package policy
 
 import (
 	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/evertras/bubble-table/table"
 	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/basekey"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/doctor"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
 	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/otdfctl/pkg/profiles"
 	"github.com/opentdf/otdfctl/pkg/utils"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/opentdf/platform/protocol/go/policy/kasregistry"
@@ -24,6 +33,8 @@ const (
 	kasKidColumn    = "Key ID"
 	isBaseKey       = "is_base_key"
 	isBaseKeyColumn = "Is Base Key"
+
+	baseKeyValidationTimeout = 10 * time.Second
 )
 
 // KAS Registry Base Keys Command
@@ -102,6 +113,18 @@ func getBaseKey(cmd *cobra.Command, args []string) {
 	common.HandleSuccess(cmd, "", t, baseKey)
 }
 
+// baseKeySetResult is the JSON output of setting or rolling back the base key, with the checks of the key set
+type baseKeySetResult struct {
+	*kasregistry.SetBaseKeyResponse
+	Checks []doctor.Check `json:"checks"`
+}
+
+type baseKeyDryRun struct {
+	Key       *policy.KasKey     `json:"key"`
+	Checks    []doctor.Check     `json:"checks"`
+	Fallbacks []basekey.Fallback `json:"fallbacks"`
+}
+
 func setBaseKey(cmd *cobra.Command, args []string) {
 	c := cli.New(cmd, args)
 	h := common.NewHandler(c)
@@ -117,11 +140,88 @@ func setBaseKey(cmd *cobra.Command, args []string) {
 			c.ExitWithError("Invalid key identifier", err)
 		}
 	}
+	kasKey, err := h.GetKasKey(c.Context(), id, identifier)
+	if err != nil {
+		cli.ExitWithError("Failed to get key", err)
+	}
+
+	if c.Flags.GetOptionalBool("dry-run") {
+		report := checkBaseKey(c, h, kasKey)
+		fallbacks, err := listBaseKeyFallbacks(c, h)
+		if err != nil {
+			cli.ExitWithError("Failed to list the attributes falling back to the base key", err)
+		}
+		c.ExitWith(renderBaseKeyChecks(report)+"\n"+renderBaseKeyFallbacks(fallbacks),
+			baseKeyDryRun{Key: kasKey, Checks: report.Checks, Fallbacks: fallbacks}, cli.ExitCodeSuccess, os.Stdout)
+	}
+	checks := validateBaseKey(c, h, kasKey, c.Flags.GetOptionalBool("force"))
+	if !c.Flags.GetOptionalBool("json") {
+		if fallbacks, err := listBaseKeyFallbacks(c, h); err != nil {
+			cmd.Println(cli.WarningMessage("Could not list the attributes falling back to the base key: " + err.Error()))
+		} else {
+			cmd.Println(renderBaseKeyFallbacks(fallbacks))
+		}
+	}
+
 	baseKey, err := h.SetBaseKey(c.Context(), id, identifier)
 	if err != nil {
 		cli.ExitWithError("Failed to set base key", err)
 	}
+	recordBaseKey(h, baseKey, kasKey.GetKey().GetId(), -1)
+	journalChange(h, journal.OperationUpdate, kindBaseKey, baseKey.GetPreviousBaseKey(), baseKey.GetNewBaseKey())
+	handleBaseKeySet(cmd, baseKey, checks)
+}
+
+// rollbackBaseKey sets the base key back to the last one recorded in the history of the profile
+func rollbackBaseKey(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	current, err := h.GetBaseKey(c.Context())
+	if err != nil {
+		cli.ExitWithError("Failed to get base key", err)
+	}
+	store, err := openBaseKeyHistory(h)
+	if err != nil {
+		cli.ExitWithError("Failed to open the base key history", err)
+	}
+	history, err := store.Load()
+	if err != nil {
+		cli.ExitWithError("Failed to read the base key history", err)
+	}
+	if history.Endpoint != h.PlatformEndpoint() {
+		history = &basekey.History{Endpoint: h.PlatformEndpoint()}
+	}
+	i, err := history.RollbackTarget(current.GetKasUri(), current.GetPublicKey().GetKid())
+	if err != nil {
+		cli.ExitWithError("Failed to roll back the base key", err)
+	}
+	target := history.Entries[i]
+
+	var identifier *kasregistry.KasKeyIdentifier
+	if target.ID == "" {
+		identifier = &kasregistry.KasKeyIdentifier{
+			Kid:        target.KeyID,
+			Identifier: &kasregistry.KasKeyIdentifier_Uri{Uri: target.KasURI},
+		}
+	}
+	kasKey, err := h.GetKasKey(c.Context(), target.ID, identifier)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to get previous base key %s of %s", target.KeyID, target.KasURI), err)
+	}
+	checks := validateBaseKey(c, h, kasKey, c.Flags.GetOptionalBool("force"))
+
+	baseKey, err := h.SetBaseKey(c.Context(), kasKey.GetKey().GetId(), nil)
+	if err != nil {
+		cli.ExitWithError("Failed to set base key", err)
+	}
+	recordBaseKey(h, baseKey, kasKey.GetKey().GetId(), i)
+	journalChange(h, journal.OperationUpdate, kindBaseKey, baseKey.GetPreviousBaseKey(), baseKey.GetNewBaseKey())
+	handleBaseKeySet(cmd, baseKey, checks)
+}
 
+func handleBaseKeySet(cmd *cobra.Command, baseKey *kasregistry.SetBaseKeyResponse, checks []doctor.Check) {
 	t := getBaseKeyTable([]table.Column{
 		table.NewFlexColumn(isBaseKey, isBaseKeyColumn, cli.FlexColumnWidthOne),
 	})
@@ -138,7 +238,197 @@ func setBaseKey(cmd *cobra.Command, args []string) {
 	}
 
 	t = t.WithRows(rows)
-	common.HandleSuccess(cmd, "", t, baseKey)
+	common.HandleSuccess(cmd, "", t, baseKeySetResult{SetBaseKeyResponse: baseKey, Checks: checks})
+}
+
+// checkBaseKey checks that the key is active, has an algorithm every SDK client can encrypt with and that its KAS
+// serves a key of that algorithm
+func checkBaseKey(c *cli.Cli, h handlers.Handler, kasKey *policy.KasKey) doctor.Report {
+	key := checkKey(kasKey)
+	client := utils.NewHTTPClient(h.TLSNoVerify())
+	client.Timeout = baseKeyValidationTimeout
+
+	return doctor.Report{
+		Endpoint: kasKey.GetKasUri(),
+		Checks: basekey.Validate(c.Context(), client, basekey.Candidate{
+			KeyID:     key.KeyID,
+			Algorithm: key.Algorithm,
+			Status:    key.Status,
+			KasURI:    kasKey.GetKasUri(),
+		}),
+	}
+}
+
+// validateBaseKey returns the checks of the key, and exits when one failed unless forced. The checks are printed, or
+// with JSON output are part of the output of the command, including the error when it exits.
+func validateBaseKey(c *cli.Cli, h handlers.Handler, kasKey *policy.KasKey, force bool) []doctor.Check {
+	report := checkBaseKey(c, h, kasKey)
+	jsonOutput := c.Flags.GetOptionalBool("json")
+	if !jsonOutput {
+		c.Cmd().Println(renderBaseKeyChecks(report))
+	}
+	if !report.Failed() {
+		return report.Checks
+	}
+	keyID := kasKey.GetKey().GetKeyId()
+	if !force {
+		msg := fmt.Sprintf("Key %s failed validation, use --force to set it as the base key anyway", keyID)
+		c.ExitWith(cli.ErrorMessage(msg, nil),
+			map[string]interface{}{"status": "ERROR", "message": msg, "checks": report.Checks}, cli.ExitCodeError, os.Stderr)
+	}
+	if !jsonOutput {
+		c.Cmd().Println(cli.WarningMessage(fmt.Sprintf("Key %s failed validation, setting it as the base key anyway", keyID)))
+	}
+	return report.Checks
+}
+
+func renderBaseKeyChecks(r doctor.Report) string {
+	t := cli.NewTable(
+		table.NewFlexColumn("status", "Status", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("check", "Check", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("message", "Message", cli.FlexColumnWidthFive),
+	)
+	rows := make([]table.Row, 0, len(r.Checks))
+	for _, check := range r.Checks {
+		rows = append(rows, table.NewRow(table.RowData{
+			"status":  strings.ToUpper(check.Status),
+			"check":   check.Name,
+			"message": check.Message,
+		}))
+	}
+	t = t.WithRows(rows)
+
+	counts := r.Counts()
+	summary := fmt.Sprintf("%d passed, %d warnings, %d failed for %s",
+		counts[doctor.StatusPass], counts[doctor.StatusWarn], counts[doctor.StatusFail], r.Endpoint)
+	switch {
+	case r.Failed():
+		summary = cli.ErrorMessage(summary, nil)
+	case counts[doctor.StatusWarn] > 0:
+		summary = cli.WarningMessage(summary)
+	default:
+		summary = cli.SuccessMessage(summary)
+	}
+	return t.View() + "\n" + summary
+}
+
+// listBaseKeyFallbacks lists the active attributes with values that have no key mapped nor KAS granted, through the
+// value, the attribute or its namespace, so they are encrypted with the base key
+func listBaseKeyFallbacks(c *cli.Cli, h handlers.Handler) ([]basekey.Fallback, error) {
+	attrs, err := h.ListAllAttributes(c.Context())
+	if err != nil {
+		return nil, err
+	}
+	mappings, err := h.ListAllKeyMappings(c.Context(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	explicit := basekey.Explicit{Namespaces: map[string]bool{}, Attributes: map[string]bool{}, Values: map[string]bool{}}
+	for _, m := range mappings {
+		for _, o := range m.GetNamespaceMappings() {
+			explicit.Namespaces[o.GetId()] = true
+		}
+		for _, o := range m.GetAttributeMappings() {
+			explicit.Attributes[o.GetId()] = true
+		}
+		for _, o := range m.GetValueMappings() {
+			explicit.Values[o.GetId()] = true
+		}
+	}
+
+	candidates := make([]basekey.Attribute, 0, len(attrs))
+	for _, a := range attrs {
+		ns := a.GetNamespace()
+		if len(ns.GetKasKeys()) > 0 || len(ns.GetGrants()) > 0 {
+			explicit.Namespaces[ns.GetId()] = true
+		}
+		if len(a.GetKasKeys()) > 0 || len(a.GetGrants()) > 0 {
+			explicit.Attributes[a.GetId()] = true
+		}
+		attr := basekey.Attribute{ID: a.GetId(), FQN: a.GetFqn(), NamespaceID: ns.GetId()}
+		for _, v := range a.GetValues() {
+			if len(v.GetKasKeys()) > 0 || len(v.GetGrants()) > 0 {
+				explicit.Values[v.GetId()] = true
+			}
+			attr.Values = append(attr.Values, basekey.Value{ID: v.GetId(), FQN: v.GetFqn()})
+		}
+		candidates = append(candidates, attr)
+	}
+	return basekey.Fallbacks(candidates, explicit), nil
+}
+
+func renderBaseKeyFallbacks(fallbacks []basekey.Fallback) string {
+	if len(fallbacks) == 0 {
+		return cli.SuccessMessage("Every attribute value has a key of its own, only data without attributes uses the base key")
+	}
+	t := cli.NewTable(
+		table.NewFlexColumn("attribute", "Attribute", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("values", "Values falling back to the base key", cli.FlexColumnWidthFour),
+	)
+	rows := make([]table.Row, 0, len(fallbacks))
+	for _, f := range fallbacks {
+		rows = append(rows, table.NewRow(table.RowData{
+			"attribute": f.FQN,
+			"values":    strings.Join(f.Values, ", "),
+		}))
+	}
+	return t.WithRows(rows).View() + "\n" +
+		cli.WarningMessage(fmt.Sprintf("%d attributes have values without an explicit key mapping", len(fallbacks)))
+}
+
+// openBaseKeyHistory opens the base key history of the profile the handler was created with
+func openBaseKeyHistory(h handlers.Handler) (*basekey.Store, error) {
+	dir, err := profiles.UserConfigDirectory()
+	if err != nil {
+		return nil, err
+	}
+	return basekey.ForProfile(dir, h.ProfileName()), nil
+}
+
+// recordBaseKey adds the new base key to the history of the profile, after forgetting the entries newer than the one
+// rolled back to, if any. The base key has already been set, so failing to record it is only logged.
+func recordBaseKey(h handlers.Handler, resp *kasregistry.SetBaseKeyResponse, keySystemID string, rolledBack int) {
+	err := func() error {
+		store, err := openBaseKeyHistory(h)
+		if err != nil {
+			return err
+		}
+		history, err := store.Load()
+		if err != nil {
+			return err
+		}
+		if history.Endpoint != h.PlatformEndpoint() {
+			history = &basekey.History{Endpoint: h.PlatformEndpoint()}
+		}
+		if rolledBack >= 0 && rolledBack < len(history.Entries) {
+			history.RolledBack(rolledBack)
+			// the key rolled back to is the last entry already
+			return store.Save(history)
+		}
+		var previous *basekey.Entry
+		if prev := resp.GetPreviousBaseKey(); prev != nil {
+			previous = baseKeyEntry(prev, "")
+		}
+		history.Record(previous, *baseKeyEntry(resp.GetNewBaseKey(), keySystemID))
+		return store.Save(history)
+	}()
+	if err != nil {
+		slog.Warn("Failed to record the base key in the base key history", "error", err)
+	}
+}
+
+func baseKeyEntry(k *policy.SimpleKasKey, keySystemID string) *basekey.Entry {
+	alg, err := cli.KeyEnumToAlg(k.GetPublicKey().GetAlgorithm())
+	if err != nil {
+		alg = k.GetPublicKey().GetAlgorithm().String()
+	}
+	return &basekey.Entry{
+		ID:        keySystemID,
+		KeyID:     k.GetPublicKey().GetKid(),
+		KasURI:    k.GetKasUri(),
+		Algorithm: alg,
+	}
 }
 
 // initBaseKeysCommands sets up the base-keys command and its subcommands.
@@ -162,9 +452,28 @@ func initBaseKeysCommands() {
 		setDoc.GetDocFlag("kas").Default,
 		setDoc.GetDocFlag("kas").Description,
 	)
+	setDoc.Flags().Bool(
+		setDoc.GetDocFlag("force").Name,
+		false,
+		setDoc.GetDocFlag("force").Description,
+	)
+	setDoc.Flags().Bool(
+		setDoc.GetDocFlag("dry-run").Name,
+		false,
+		setDoc.GetDocFlag("dry-run").Description,
+	)
+
+	rollbackDoc := man.Docs.GetCommand("policy/kas-registry/key/base/rollback",
+		man.WithRun(rollbackBaseKey),
+	)
+	rollbackDoc.Flags().Bool(
+		rollbackDoc.GetDocFlag("force").Name,
+		false,
+		rollbackDoc.GetDocFlag("force").Description,
+	)
 
 	doc := man.Docs.GetCommand("policy/kas-registry/key/base",
-		man.WithSubcommands(getDoc, setDoc))
+		man.WithSubcommands(getDoc, setDoc, rollbackDoc))
 	policyKasRegistryBaseKeysCmd = &doc.Command
 	policyKasRegistryKeysCmd.AddCommand(
 		policyKasRegistryBaseKeysCmd,
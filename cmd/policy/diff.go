@@ -0,0 +1,168 @@
+package policy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/diff"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/spf13/cobra"
+)
+
+type diffResult struct {
+	From    string        `json:"from"`
+	To      string        `json:"to"`
+	Changes []diff.Change `json:"changes"`
+}
+
+func policySnapshot(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	out := c.Flags.GetOptionalString("out")
+
+	snapshot, err := h.LoadPolicySnapshot(cmd.Context())
+	if err != nil {
+		c.ExitWithError("Failed to load policy", err)
+	}
+	b, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		c.ExitWithError("Failed to encode policy snapshot", err)
+	}
+
+	if out == "" {
+		c.ExitWith(string(b), snapshot, cli.ExitCodeSuccess, os.Stdout)
+	}
+	//nolint:mnd,gosec // snapshots may describe sensitive policy, so only the owner may read them
+	if err := os.WriteFile(out, append(b, '\n'), 0o600); err != nil {
+		c.ExitWithError(fmt.Sprintf("Failed to write policy snapshot to %s", out), err)
+	}
+	c.ExitWithSuccess(fmt.Sprintf("Policy snapshot written to %s", out))
+}
+
+func policyDiff(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+
+	from := c.Flags.GetRequiredString("from")
+	to := c.Flags.GetOptionalString("to")
+
+	fromSnapshot := loadDiffSource(c, cmd, from)
+	var toSnapshot *handlers.PolicySnapshot
+	if to == "" {
+		h := common.NewHandler(c)
+		defer h.Close()
+		to = "current profile"
+		var err error
+		if toSnapshot, err = h.LoadPolicySnapshot(cmd.Context()); err != nil {
+			c.ExitWithError("Failed to load policy", err)
+		}
+	} else {
+		toSnapshot = loadDiffSource(c, cmd, to)
+	}
+
+	result := diffResult{From: from, To: to, Changes: diff.Compare(fromSnapshot, toSnapshot)}
+
+	code := cli.ExitCodeSuccess
+	if len(result.Changes) > 0 {
+		code = cli.ExitCodeDrift
+	}
+	c.ExitWith(renderDiff(result), result, code, os.Stdout)
+}
+
+// loadDiffSource reads a snapshot file when the source is an existing path, otherwise loads the live policy of the
+// profile with that name
+func loadDiffSource(c *cli.Cli, cmd *cobra.Command, source string) *handlers.PolicySnapshot {
+	b, err := os.ReadFile(source)
+	if err == nil {
+		s := &handlers.PolicySnapshot{}
+		if err := json.Unmarshal(b, s); err != nil {
+			c.ExitWithError(fmt.Sprintf("Failed to read policy snapshot %s", source), err)
+		}
+		return s
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		c.ExitWithError(fmt.Sprintf("Failed to read policy snapshot %s", source), err)
+	}
+
+	h := common.NewHandlerForProfile(c, source)
+	defer h.Close()
+	s, err := h.LoadPolicySnapshot(cmd.Context())
+	if err != nil {
+		c.ExitWithError(fmt.Sprintf("Failed to load policy of profile %s", source), err)
+	}
+	return s
+}
+
+func renderDiff(r diffResult) string {
+	if len(r.Changes) == 0 {
+		return cli.SuccessMessage(fmt.Sprintf("No drift between %s and %s", r.From, r.To))
+	}
+
+	t := cli.NewTable(
+		table.NewFlexColumn("change", "Change", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("kind", "Kind", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("object", "Object", cli.FlexColumnWidthThree),
+		table.NewFlexColumn("field", "Field", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("from", "From", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("to", "To", cli.FlexColumnWidthTwo),
+	)
+	var rows []table.Row
+	counts := map[string]int{}
+	for _, ch := range r.Changes {
+		counts[ch.Type]++
+		if len(ch.Fields) == 0 {
+			rows = append(rows, table.NewRow(table.RowData{
+				"change": ch.Type,
+				"kind":   ch.Kind,
+				"object": ch.Key,
+			}))
+			continue
+		}
+		for _, f := range ch.Fields {
+			rows = append(rows, table.NewRow(table.RowData{
+				"change": ch.Type,
+				"kind":   ch.Kind,
+				"object": ch.Key,
+				"field":  f.Field,
+				"from":   f.From,
+				"to":     f.To,
+			}))
+		}
+	}
+	t = t.WithRows(rows)
+
+	summary := fmt.Sprintf("%d added, %d removed, %d changed between %s and %s",
+		counts[diff.ChangeAdded], counts[diff.ChangeRemoved], counts[diff.ChangeChanged], r.From, r.To)
+	return t.View() + "\n" + cli.WarningMessage(summary)
+}
+
+func initDiffCommands() {
+	snapshotDoc := man.Docs.GetCommand("policy/snapshot", man.WithRun(policySnapshot))
+	snapshotDoc.Flags().StringP(
+		snapshotDoc.GetDocFlag("out").Name,
+		snapshotDoc.GetDocFlag("out").Shorthand,
+		snapshotDoc.GetDocFlag("out").Default,
+		snapshotDoc.GetDocFlag("out").Description,
+	)
+	Cmd.AddCommand(&snapshotDoc.Command)
+
+	diffDoc := man.Docs.GetCommand("policy/diff", man.WithRun(policyDiff))
+	diffDoc.Flags().String(
+		diffDoc.GetDocFlag("from").Name,
+		diffDoc.GetDocFlag("from").Default,
+		diffDoc.GetDocFlag("from").Description,
+	)
+	diffDoc.Flags().String(
+		diffDoc.GetDocFlag("to").Name,
+		diffDoc.GetDocFlag("to").Default,
+		diffDoc.GetDocFlag("to").Description,
+	)
+	Cmd.AddCommand(&diffDoc.Command)
+}
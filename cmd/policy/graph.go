@@ -0,0 +1,87 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/graph"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/spf13/cobra"
+)
+
+func policyGraph(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	format := c.Flags.GetOptionalString("format")
+	switch format {
+	case graph.FormatDOT, graph.FormatMermaid:
+	case graph.FormatJSON:
+		c.SetJSONOutput(true)
+	default:
+		c.ExitWithError(fmt.Sprintf("Unknown --format '%s', expected one of dot, mermaid, json", format), nil)
+	}
+
+	filters := map[graph.NodeKind][]string{}
+	for flag, kind := range map[string]graph.NodeKind{
+		"namespace": graph.KindNamespace,
+		"attribute": graph.KindAttribute,
+		"kas":       graph.KindKeyAccessServer,
+	} {
+		v, err := cmd.Flags().GetStringSlice(flag)
+		if err != nil {
+			c.ExitWithError(fmt.Sprintf("Invalid --%s", flag), err)
+		}
+		filters[kind] = v
+	}
+
+	snapshot, err := h.LoadPolicySnapshot(cmd.Context())
+	if err != nil {
+		c.ExitWithError("Failed to load policy", err)
+	}
+
+	g := graph.Build(snapshot)
+
+	var roots []string
+	filtered := false
+	for _, kind := range []graph.NodeKind{graph.KindNamespace, graph.KindAttribute, graph.KindKeyAccessServer} {
+		for _, f := range filters[kind] {
+			filtered = true
+			found := g.FindRoots(kind, f)
+			if len(found) == 0 {
+				c.ExitWithError(fmt.Sprintf("Failed to find %s '%s': not found", kind, f), nil)
+			}
+			roots = append(roots, found...)
+		}
+	}
+	if filtered {
+		g = g.Subgraph(roots)
+	}
+
+	out := g.DOT()
+	if format == graph.FormatMermaid {
+		out = g.Mermaid()
+	}
+	c.ExitWith(out, g, cli.ExitCodeSuccess, os.Stdout)
+}
+
+func initGraphCommands() {
+	doc := man.Docs.GetCommand("policy/graph", man.WithRun(policyGraph))
+	doc.Flags().StringP(
+		doc.GetDocFlag("format").Name,
+		doc.GetDocFlag("format").Shorthand,
+		doc.GetDocFlag("format").Default,
+		doc.GetDocFlag("format").Description,
+	)
+	for _, name := range []string{"namespace", "attribute", "kas"} {
+		doc.Flags().StringSlice(
+			doc.GetDocFlag(name).Name,
+			[]string{},
+			doc.GetDocFlag(name).Description,
+		)
+	}
+	Cmd.AddCommand(&doc.Command)
+}
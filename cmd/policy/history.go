@@ -0,0 +1,570 @@
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
+	"github.com/opentdf/otdfctl/pkg/man"
+	commonpb "github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/opentdf/platform/protocol/go/policy/attributes"
+	"github.com/opentdf/platform/protocol/go/policy/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy/namespaces"
+	"github.com/opentdf/platform/protocol/go/policy/subjectmapping"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	kindResourceMapping      = "resource-mapping"
+	kindResourceMappingGroup = "resource-mapping-group"
+	kindObligationTrigger    = "obligation-trigger"
+	kindProviderConfig       = "provider-config"
+	kindBaseKey              = "base-key"
+	kindNamespaceKey         = "namespace-key"
+	kindAttributeKey         = "attribute-key"
+	kindValueKey             = "value-key"
+	kindNamespaceKasGrant    = "namespace-kas-grant"
+	kindAttributeKasGrant    = "attribute-kas-grant"
+	kindValueKasGrant        = "value-kas-grant"
+)
+
+var errCannotUndo = errors.New("cannot be undone")
+
+type historyEntry struct {
+	journal.Entry
+	UndoneBy int `json:"undone_by,omitempty"`
+}
+
+// journalChange records a change in the journal of the current profile. The change has already been made, so failing
+// to record it is only logged.
+func journalChange(h handlers.Handler, operation, kind string, before, after proto.Message) {
+	if err := h.RecordChange(operation, kind, before, after, 0); err != nil {
+		slog.Warn("Failed to record the change in the policy journal", "operation", operation, "kind", kind, "error", err)
+	}
+}
+
+// journalKasKey records a change to a KAS key, which is journaled without the KAS it belongs to
+func journalKasKey(h handlers.Handler, operation string, before, after *policy.KasKey) {
+	journalChange(h, operation, labels.KindKasKey, before.GetKey(), after.GetKey())
+}
+
+func policyHistory(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	limit := c.Flags.GetOptionalInt32("limit")
+	kind := c.Flags.GetOptionalString("kind")
+
+	j, err := h.Journal()
+	if err != nil {
+		c.ExitWithError("Failed to open the policy journal", err)
+	}
+	entries, err := j.Entries()
+	if err != nil {
+		c.ExitWithError("Failed to read the policy journal", err)
+	}
+	undone := journal.UndoneBy(entries)
+
+	// newest first
+	history := []historyEntry{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if kind != "" && entries[i].Kind != kind {
+			continue
+		}
+		if limit > 0 && len(history) == int(limit) {
+			break
+		}
+		history = append(history, historyEntry{Entry: entries[i], UndoneBy: undone[entries[i].ID]})
+	}
+
+	t := cli.NewTable(
+		table.NewFlexColumn("id", "Entry", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("time", "Time", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("operation", "Operation", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("kind", "Kind", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("object", "Object", cli.FlexColumnWidthThree),
+		table.NewFlexColumn("object_id", "Object ID", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("status", "Status", cli.FlexColumnWidthOne),
+	)
+	rows := make([]table.Row, 0, len(history))
+	for _, e := range history {
+		status := ""
+		switch {
+		case e.UndoneBy != 0:
+			status = fmt.Sprintf("undone by %d", e.UndoneBy)
+		case e.UndoOf != 0:
+			status = fmt.Sprintf("undid %d", e.UndoOf)
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"id":        strconv.Itoa(e.ID),
+			"time":      e.Time.Local().Format("2006-01-02 15:04:05"),
+			"operation": e.Operation,
+			"kind":      e.Kind,
+			"object":    e.Name,
+			"object_id": e.ObjectID,
+			"status":    status,
+		}))
+	}
+	t = t.WithRows(rows)
+	common.HandleSuccess(cmd, "", t, history)
+}
+
+func policyUndo(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+
+	id, err := strconv.Atoi(args[0])
+	if err != nil || id < 1 {
+		c.ExitWithError(fmt.Sprintf("Invalid journal entry '%s'", args[0]), err)
+	}
+	force := c.Flags.GetOptionalBool("force")
+
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	j, err := h.Journal()
+	if err != nil {
+		c.ExitWithError("Failed to open the policy journal", err)
+	}
+	entries, err := j.Entries()
+	if err != nil {
+		c.ExitWithError("Failed to read the policy journal", err)
+	}
+	e, err := j.Entry(id)
+	if err != nil {
+		c.ExitWithError(fmt.Sprintf("Failed to find journal entry %d", id), err)
+	}
+	if by, ok := journal.UndoneBy(entries)[id]; ok {
+		c.ExitWithWarning(fmt.Sprintf("Journal entry %d was already undone by entry %d", id, by))
+	}
+	if e.Endpoint != h.PlatformEndpoint() {
+		c.ExitWithWarning(fmt.Sprintf("Journal entry %d was made against %s, not %s", id, e.Endpoint, h.PlatformEndpoint()))
+	}
+
+	cli.ConfirmAction(cli.ActionUndo, fmt.Sprintf("%s of %s", e.Operation, e.Kind), e.Name, force)
+
+	operation, before, after, err := undoEntry(cmd.Context(), h, e)
+	if errors.Is(err, errCannotUndo) {
+		c.ExitWithWarning(fmt.Sprintf("The %s of %s %s %s", e.Operation, e.Kind, e.Name, err.Error()))
+	}
+	if err != nil {
+		c.ExitWithError(fmt.Sprintf("Failed to undo journal entry %d", id), err)
+	}
+	if err := h.RecordChange(operation, e.Kind, before, after, id); err != nil {
+		slog.Warn("Failed to record the undo in the policy journal", "error", err)
+	}
+
+	rows := [][]string{
+		{"Entry", strconv.Itoa(id)},
+		{"Undone", fmt.Sprintf("%s of %s", e.Operation, e.Kind)},
+		{"Object", e.Name},
+		{"Applied", operation},
+	}
+	t := cli.NewTabular(rows...)
+	common.HandleSuccess(cmd, e.ObjectID, t, after)
+}
+
+// undoEntry applies the inverse of a journal entry, returning the operation applied and the object before and after
+//
+//nolint:cyclop,gocyclo,funlen // a flat dispatch over each operation and object type
+func undoEntry(ctx context.Context, h handlers.Handler, e *journal.Entry) (string, proto.Message, proto.Message, error) {
+	var before, after proto.Message
+	if len(e.Before) > 0 {
+		before = newJournalObject(e.Kind)
+		if before == nil {
+			return "", nil, nil, fmt.Errorf("%w, as '%s' objects are not journaled", errCannotUndo, e.Kind)
+		}
+		if err := journal.Unmarshal(e.Before, before); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	if len(e.After) > 0 {
+		after = newJournalObject(e.Kind)
+		if after == nil {
+			return "", nil, nil, fmt.Errorf("%w, as '%s' objects are not journaled", errCannotUndo, e.Kind)
+		}
+		if err := journal.Unmarshal(e.After, after); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	if before == nil && (e.Operation == journal.OperationUpdate || e.Operation == journal.OperationUnsafeUpdate ||
+		e.Operation == journal.OperationDelete) {
+		return "", nil, nil, fmt.Errorf("%w, as the object was not recorded before the change", errCannotUndo)
+	}
+	id := e.ObjectID
+
+	var (
+		result proto.Message
+		err    error
+	)
+	switch e.Operation {
+	case journal.OperationDeactivate:
+		switch e.Kind {
+		case labels.KindNamespace:
+			result, err = h.UnsafeReactivateNamespace(ctx, id)
+		case labels.KindAttribute:
+			result, err = h.UnsafeReactivateAttribute(ctx, id)
+		case labels.KindValue:
+			result, err = h.UnsafeReactivateAttributeValue(ctx, id)
+		default:
+			return "", nil, nil, errCannotUndo
+		}
+		return journal.OperationReactivate, after, result, err
+
+	case journal.OperationReactivate:
+		return deactivateJournalObject(ctx, h, e.Kind, id, after)
+
+	case journal.OperationCreate:
+		if e.Kind == labels.KindNamespace || e.Kind == labels.KindAttribute || e.Kind == labels.KindValue {
+			// deleting these is unsafe, as it cascades to everything beneath them
+			return deactivateJournalObject(ctx, h, e.Kind, id, after)
+		}
+		err = deleteJournalObject(ctx, h, e.Kind, id)
+		return journal.OperationDelete, after, nil, err
+
+	case journal.OperationUpdate:
+		if b, ok := before.(*policy.SimpleKasKey); ok {
+			result, err = restoreBaseKey(ctx, h, b)
+			return journal.OperationUpdate, after, result, err
+		}
+		result, err = restoreJournalObject(ctx, h, e.Kind, id, before, after)
+		return journal.OperationUpdate, after, result, err
+
+	case journal.OperationAssign:
+		err = unassignJournalKey(ctx, h, after)
+		return journal.OperationUnassign, after, nil, err
+
+	case journal.OperationUnassign:
+		result, err = assignJournalKey(ctx, h, before)
+		return journal.OperationAssign, nil, result, err
+
+	case journal.OperationUnsafeUpdate:
+		switch b := before.(type) {
+		case *policy.Namespace:
+			result, err = h.UnsafeUpdateNamespace(ctx, id, b.GetName())
+		case *policy.Value:
+			if err = h.UnsafeUpdateAttributeValue(ctx, id, b.GetValue()); err == nil {
+				result, err = h.GetAttributeValue(ctx, id)
+			}
+		case *policy.Attribute:
+			a, _ := after.(*policy.Attribute)
+			var name, rule string
+			var order []string
+			if b.GetName() != a.GetName() {
+				name = b.GetName()
+			}
+			if b.GetRule() != a.GetRule() {
+				rule = handlers.GetAttributeRuleFromAttributeType(b.GetRule())
+			}
+			for _, v := range b.GetValues() {
+				order = append(order, v.GetId())
+			}
+			result, err = h.UnsafeUpdateAttribute(ctx, id, name, rule, order, b.GetAllowTraversal())
+		default:
+			return "", nil, nil, errCannotUndo
+		}
+		return journal.OperationUnsafeUpdate, after, result, err
+
+	case journal.OperationDelete:
+		result, err = recreateJournalObject(ctx, h, before)
+		return journal.OperationCreate, nil, result, err
+	}
+	return "", nil, nil, fmt.Errorf("%w, as undoing a %s is not supported", errCannotUndo, e.Operation)
+}
+
+// assignJournalKey maps a key to the namespace, attribute or value it was removed from
+func assignJournalKey(ctx context.Context, h handlers.Handler, mapping proto.Message) (proto.Message, error) {
+	switch m := mapping.(type) {
+	case *namespaces.NamespaceKey:
+		return h.AssignKeyToAttributeNamespace(ctx, m.GetNamespaceId(), m.GetKeyId())
+	case *attributes.AttributeKey:
+		return h.AssignKeyToAttribute(ctx, m.GetAttributeId(), m.GetKeyId())
+	case *attributes.ValueKey:
+		return h.AssignKeyToAttributeValue(ctx, m.GetValueId(), m.GetKeyId())
+	}
+	return nil, errCannotUndo
+}
+
+// unassignJournalKey removes a key from the namespace, attribute or value it was mapped to
+func unassignJournalKey(ctx context.Context, h handlers.Handler, mapping proto.Message) error {
+	switch m := mapping.(type) {
+	case *namespaces.NamespaceKey:
+		return h.RemoveKeyFromAttributeNamespace(ctx, m.GetNamespaceId(), m.GetKeyId())
+	case *attributes.AttributeKey:
+		return h.RemoveKeyFromAttribute(ctx, m.GetAttributeId(), m.GetKeyId())
+	case *attributes.ValueKey:
+		return h.RemoveKeyFromAttributeValue(ctx, m.GetValueId(), m.GetKeyId())
+	}
+	return errCannotUndo
+}
+
+// restoreBaseKey sets the base key back to the previous one, recording it in the base key history as well
+func restoreBaseKey(ctx context.Context, h handlers.Handler, previous *policy.SimpleKasKey) (proto.Message, error) {
+	resp, err := h.SetBaseKey(ctx, "", &kasregistry.KasKeyIdentifier{
+		Kid:        previous.GetPublicKey().GetKid(),
+		Identifier: &kasregistry.KasKeyIdentifier_Uri{Uri: previous.GetKasUri()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	recordBaseKey(h, resp, "", -1)
+	return resp.GetNewBaseKey(), nil
+}
+
+func deactivateJournalObject(ctx context.Context, h handlers.Handler, kind, id string, current proto.Message) (string, proto.Message, proto.Message, error) {
+	var (
+		result proto.Message
+		err    error
+	)
+	switch kind {
+	case labels.KindNamespace:
+		result, err = h.DeactivateNamespace(ctx, id)
+	case labels.KindAttribute:
+		result, err = h.DeactivateAttribute(ctx, id)
+	case labels.KindValue:
+		result, err = h.DeactivateAttributeValue(ctx, id)
+	default:
+		return "", nil, nil, errCannotUndo
+	}
+	return journal.OperationDeactivate, current, result, err
+}
+
+func deleteJournalObject(ctx context.Context, h handlers.Handler, kind, id string) error {
+	var err error
+	switch kind {
+	case labels.KindSubjectMapping:
+		_, err = h.DeleteSubjectMapping(ctx, id)
+	case kindObligationTrigger:
+		_, err = h.DeleteObligationTrigger(ctx, id)
+	case kindProviderConfig:
+		err = h.DeleteProviderConfig(ctx, id)
+	case labels.KindSubjectConditionSet:
+		err = h.DeleteSubjectConditionSet(ctx, id)
+	case labels.KindAction:
+		err = h.DeleteAction(ctx, id)
+	case labels.KindObligation:
+		err = h.DeleteObligation(ctx, id, "")
+	case labels.KindObligationValue:
+		err = h.DeleteObligationValue(ctx, id, "")
+	case labels.KindKeyAccessServer:
+		_, err = h.DeleteKasRegistryEntry(ctx, id)
+	case labels.KindRegisteredResource:
+		err = h.DeleteRegisteredResource(ctx, id)
+	case labels.KindRegisteredResourceValue:
+		err = h.DeleteRegisteredResourceValue(ctx, id)
+	case kindResourceMapping:
+		_, err = h.DeleteResourceMapping(id)
+	case kindResourceMappingGroup:
+		_, err = h.DeleteResourceMappingGroup(ctx, id)
+	default:
+		return errCannotUndo
+	}
+	return err
+}
+
+// restoreJournalObject reverts an update, restoring the labels and any other fields changed by the update. Updates
+// whose changes cannot all be restored are refused rather than partially undone.
+//
+//nolint:cyclop // a flat dispatch over each object type
+func restoreJournalObject(ctx context.Context, h handlers.Handler, kind, id string, before, after proto.Message) (proto.Message, error) {
+	md := &commonpb.MetadataMutable{Labels: journalObjectLabels(before)}
+	replace := commonpb.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE
+
+	// only send the fields which changed, as some objects only allow their labels to be updated
+	changed := func(field func(proto.Message) string) string {
+		if field(before) == field(after) {
+			return ""
+		}
+		return field(before)
+	}
+
+	switch b := before.(type) {
+	case *policy.SubjectMapping:
+		scsID := changed(func(m proto.Message) string {
+			sm, _ := m.(*policy.SubjectMapping)
+			return sm.GetSubjectConditionSet().GetId()
+		})
+		return h.UpdateSubjectMapping(ctx, id, scsID, b.GetActions(), md, replace)
+	case *policy.SubjectConditionSet:
+		return h.UpdateSubjectConditionSet(ctx, id, b.GetSubjectSets(), md, replace)
+	case *policy.Action:
+		return h.UpdateAction(ctx, id, changed(journalObjectName), md, replace)
+	case *policy.Obligation:
+		return h.UpdateObligation(ctx, id, changed(journalObjectName), md, replace)
+	case *policy.KeyAccessServer:
+		uri := changed(func(m proto.Message) string {
+			kas, _ := m.(*policy.KeyAccessServer)
+			return kas.GetUri()
+		})
+		return h.UpdateKasRegistryEntry(ctx, id, uri, changed(journalObjectName), md, replace)
+	case *policy.RegisteredResource:
+		return h.UpdateRegisteredResource(ctx, id, changed(journalObjectName), md, replace)
+	case *policy.ResourceMapping:
+		return h.UpdateResourceMapping(id, b.GetAttributeValue().GetId(), b.GetGroup().GetId(), b.GetTerms(), md, replace)
+	case *policy.ResourceMappingGroup:
+		return h.UpdateResourceMappingGroup(ctx, id, "", changed(journalObjectName), md, replace)
+	case *policy.ObligationValue:
+		a, _ := after.(*policy.ObligationValue)
+		if !equalMessages(b.GetTriggers(), a.GetTriggers()) {
+			return nil, fmt.Errorf("%w, as the update replaced the triggers of the obligation value", errCannotUndo)
+		}
+		return h.UpdateObligationValue(ctx, id, changed(journalObjectValue), nil, md, replace)
+	case *policy.RegisteredResourceValue:
+		a, _ := after.(*policy.RegisteredResourceValue)
+		if !equalMessages(b.GetActionAttributeValues(), a.GetActionAttributeValues()) {
+			return nil, fmt.Errorf("%w, as the update replaced the action attribute values of the registered resource value",
+				errCannotUndo)
+		}
+		return h.UpdateRegisteredResourceValue(ctx, id, changed(journalObjectValue), nil, md, replace)
+	}
+
+	// the remaining kinds only have their labels updated
+	switch kind {
+	case labels.KindNamespace, labels.KindAttribute, labels.KindValue, labels.KindKasKey:
+		return updateObjectLabels(ctx, h, labels.Change{Object: labels.Object{Kind: kind, ID: id}, After: md.GetLabels()})
+	}
+	return nil, fmt.Errorf("%w, as restoring '%s' objects is not supported", errCannotUndo, kind)
+}
+
+func equalMessages[M proto.Message](a, b []M) bool {
+	return slices.EqualFunc(a, b, func(x, y M) bool { return proto.Equal(x, y) })
+}
+
+// recreateJournalObject creates a deleted object again from its state before deletion. It gets a new ID.
+func recreateJournalObject(ctx context.Context, h handlers.Handler, before proto.Message) (proto.Message, error) {
+	md := &commonpb.MetadataMutable{Labels: journalObjectLabels(before)}
+
+	switch b := before.(type) {
+	case *policy.SubjectMapping:
+		scs := b.GetSubjectConditionSet()
+		if _, err := h.GetSubjectConditionSet(ctx, scs.GetId()); err == nil {
+			return h.CreateNewSubjectMapping(ctx, b.GetAttributeValue().GetId(), b.GetActions(), scs.GetId(), nil, md, b.GetNamespace().GetFqn())
+		}
+		// the condition set was deleted too, so recreate it along with the mapping
+		newSCS := &subjectmapping.SubjectConditionSetCreate{
+			SubjectSets: scs.GetSubjectSets(),
+			Metadata:    &commonpb.MetadataMutable{Labels: scs.GetMetadata().GetLabels()},
+		}
+		return h.CreateNewSubjectMapping(ctx, b.GetAttributeValue().GetId(), b.GetActions(), "", newSCS, md, b.GetNamespace().GetFqn())
+	case *policy.SubjectConditionSet:
+		return h.CreateSubjectConditionSet(ctx, b.GetSubjectSets(), md, b.GetNamespace().GetFqn())
+	case *policy.ResourceMapping:
+		return h.CreateResourceMapping(b.GetAttributeValue().GetId(), b.GetTerms(), b.GetGroup().GetId(), md)
+	case *policy.ResourceMappingGroup:
+		return h.CreateResourceMappingGroup(ctx, b.GetNamespaceId(), b.GetName(), md)
+	case *policy.Action:
+		return h.CreateAction(ctx, b.GetName(), b.GetNamespace().GetFqn(), md)
+	case *policy.KeyAccessServer:
+		return h.CreateKasRegistryEntry(ctx, b.GetUri(), b.GetName(), md)
+	}
+	return nil, fmt.Errorf("%w, as recreating it would not restore what was deleted with it", errCannotUndo)
+}
+
+// newJournalObject returns an empty message of the type journaled for a kind of object
+func newJournalObject(kind string) proto.Message {
+	switch kind {
+	case labels.KindNamespace:
+		return &policy.Namespace{}
+	case labels.KindAttribute:
+		return &policy.Attribute{}
+	case labels.KindValue:
+		return &policy.Value{}
+	case labels.KindSubjectMapping:
+		return &policy.SubjectMapping{}
+	case labels.KindSubjectConditionSet:
+		return &policy.SubjectConditionSet{}
+	case labels.KindAction:
+		return &policy.Action{}
+	case labels.KindObligation:
+		return &policy.Obligation{}
+	case labels.KindObligationValue:
+		return &policy.ObligationValue{}
+	case labels.KindKeyAccessServer:
+		return &policy.KeyAccessServer{}
+	case labels.KindRegisteredResource:
+		return &policy.RegisteredResource{}
+	case labels.KindRegisteredResourceValue:
+		return &policy.RegisteredResourceValue{}
+	case kindResourceMapping:
+		return &policy.ResourceMapping{}
+	case kindResourceMappingGroup:
+		return &policy.ResourceMappingGroup{}
+	case labels.KindKasKey:
+		return &policy.AsymmetricKey{}
+	case kindObligationTrigger:
+		return &policy.ObligationTrigger{}
+	case kindProviderConfig:
+		return &policy.KeyProviderConfig{}
+	case kindBaseKey:
+		return &policy.SimpleKasKey{}
+	case kindNamespaceKey:
+		return &namespaces.NamespaceKey{}
+	case kindAttributeKey:
+		return &attributes.AttributeKey{}
+	case kindValueKey:
+		return &attributes.ValueKey{}
+	case kindNamespaceKasGrant:
+		return &namespaces.NamespaceKeyAccessServer{}
+	case kindAttributeKasGrant:
+		return &attributes.AttributeKeyAccessServer{}
+	case kindValueKasGrant:
+		return &attributes.ValueKeyAccessServer{}
+	}
+	return nil
+}
+
+func journalObjectLabels(m proto.Message) map[string]string {
+	if o, ok := m.(interface{ GetMetadata() *commonpb.Metadata }); ok {
+		return o.GetMetadata().GetLabels()
+	}
+	return nil
+}
+
+func journalObjectName(m proto.Message) string {
+	if o, ok := m.(interface{ GetName() string }); ok {
+		return o.GetName()
+	}
+	return ""
+}
+
+func journalObjectValue(m proto.Message) string {
+	if o, ok := m.(interface{ GetValue() string }); ok {
+		return o.GetValue()
+	}
+	return ""
+}
+
+func initHistoryCommands() {
+	historyDoc := man.Docs.GetCommand("policy/history", man.WithRun(policyHistory))
+	historyDoc.Flags().Int32P(
+		historyDoc.GetDocFlag("limit").Name,
+		historyDoc.GetDocFlag("limit").Shorthand,
+		defaultListFlagLimit,
+		historyDoc.GetDocFlag("limit").Description,
+	)
+	historyDoc.Flags().String(
+		historyDoc.GetDocFlag("kind").Name,
+		historyDoc.GetDocFlag("kind").Default,
+		historyDoc.GetDocFlag("kind").Description,
+	)
+	Cmd.AddCommand(&historyDoc.Command)
+
+	undoDoc := man.Docs.GetCommand("policy/undo", man.WithRun(policyUndo))
+	undoDoc.Flags().Bool(
+		undoDoc.GetDocFlag("force").Name,
+		false,
+		undoDoc.GetDocFlag("force").Description,
+	)
+	Cmd.AddCommand(&undoDoc.Command)
+}
@@ -0,0 +1,83 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/impact"
+)
+
+// maxPromptImpactItems caps the objects listed per kind in a confirmation prompt, which --what-if lists in full
+const maxPromptImpactItems = 10
+
+// policyImpact works out what an operation on a namespace, attribute or value would affect. With --what-if the impact
+// is printed and the command exits without making the change. Otherwise it returns the impact to show in the
+// confirmation prompt, or nothing when there will be no prompt.
+func policyImpact(c *cli.Cli, h handlers.Handler, operation, kind, id string, prompt bool) string {
+	whatIf := c.Flags.GetOptionalBool("what-if")
+	if !whatIf && !prompt {
+		return ""
+	}
+
+	report, err := loadImpact(c.Context(), h, operation, kind, id)
+	if err != nil {
+		if whatIf {
+			c.ExitWithError("Failed to analyze the impact", err)
+		}
+		slog.Warn("Failed to analyze the impact", "operation", operation, "kind", kind, "id", id, "error", err)
+		return ""
+	}
+
+	if whatIf {
+		c.ExitWith(renderImpact(report, 0), report, cli.ExitCodeSuccess, os.Stdout)
+	}
+	return renderImpact(report, maxPromptImpactItems)
+}
+
+func loadImpact(ctx context.Context, h handlers.Handler, operation, kind, id string) (*impact.Report, error) {
+	snapshot, err := h.LoadPolicySnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p := impact.Policy{PolicySnapshot: snapshot}
+	if p.ResourceMappings, err = h.ListAllResourceMappings(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list resource mappings: %w", err)
+	}
+	if p.ResourceMappingGroups, err = h.ListAllResourceMappingGroups(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list resource mapping groups: %w", err)
+	}
+	return impact.Analyze(p, operation, kind, id)
+}
+
+// renderImpact lists the affected objects by kind, with at most maxItems per kind when maxItems is positive
+func renderImpact(r *impact.Report, maxItems int) string {
+	verb, effect := "Deleting", "also deletes"
+	if r.Operation == impact.OperationDeactivate {
+		verb, effect = "Deactivating", "affects"
+	}
+	if r.Total() == 0 {
+		return fmt.Sprintf("%s %s %s %s no other objects.", verb, r.Kind, r.FQN, effect)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s %s %s %d objects:\n", verb, r.Kind, r.FQN, effect, r.Total())
+	for _, g := range r.Affected {
+		fmt.Fprintf(&b, "\n  %s (%d)\n", g.Kind, g.Count)
+		for i, item := range g.Items {
+			if maxItems > 0 && i == maxItems {
+				fmt.Fprintf(&b, "    ... and %d more\n", g.Count-maxItems)
+				break
+			}
+			fmt.Fprintf(&b, "    - %s\n", item)
+		}
+	}
+	if r.Operation == impact.OperationDeactivate {
+		b.WriteString("\nMappings, triggers and registered resource entitlements of deactivated values stay in place, but no longer take effect.\n")
+	}
+	return b.String()
+}
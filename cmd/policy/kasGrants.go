@@ -9,6 +9,7 @@ import (
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
 	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/spf13/cobra"
 )
@@ -84,10 +85,12 @@ func unassignKasGrant(cmd *cobra.Command, args []string) {
 		}
 		confirm = fmt.Sprintf("the grant to namespace FQN (%s) of KAS URI", ns.GetFqn())
 		cli.ConfirmAction(cli.ActionDelete, confirm, kasURI, force)
-		res, err = h.DeleteKasGrantFromNamespace(ctx, nsID, kasID)
+		grant, err := h.DeleteKasGrantFromNamespace(ctx, nsID, kasID)
 		if err != nil {
 			cli.ExitWithError("Failed to update KAS grant for namespace", err)
 		}
+		journalChange(h, journal.OperationUnassign, kindNamespaceKasGrant, grant, nil)
+		res = grant
 
 		rowID = []string{"Namespace ID", nsID}
 		rowFQN = []string{"Namespace FQN", ns.GetFqn()}
@@ -98,10 +101,12 @@ func unassignKasGrant(cmd *cobra.Command, args []string) {
 		}
 		confirm = fmt.Sprintf("the grant to attribute FQN (%s) of KAS URI", attr.GetFqn())
 		cli.ConfirmAction(cli.ActionDelete, confirm, kasURI, force)
-		res, err = h.DeleteKasGrantFromAttribute(ctx, attrID, kasID)
+		grant, err := h.DeleteKasGrantFromAttribute(ctx, attrID, kasID)
 		if err != nil {
 			cli.ExitWithError("Failed to update KAS grant for attribute", err)
 		}
+		journalChange(h, journal.OperationUnassign, kindAttributeKasGrant, grant, nil)
+		res = grant
 
 		rowID = []string{"Attribute ID", attrID}
 		rowFQN = []string{"Attribute FQN", attr.GetFqn()}
@@ -112,10 +117,11 @@ func unassignKasGrant(cmd *cobra.Command, args []string) {
 		}
 		confirm = fmt.Sprintf("the grant to attribute value FQN (%s) of KAS URI", val.GetFqn())
 		cli.ConfirmAction(cli.ActionDelete, confirm, kasURI, force)
-		_, err = h.DeleteKasGrantFromValue(ctx, valID, kasID)
+		grant, err := h.DeleteKasGrantFromValue(ctx, valID, kasID)
 		if err != nil {
 			cli.ExitWithError("Failed to update KAS grant for attribute value", err)
 		}
+		journalChange(h, journal.OperationUnassign, kindValueKasGrant, grant, nil)
 		rowID = []string{"Value ID", valID}
 		rowFQN = []string{"Value FQN", val.GetFqn()}
 	}
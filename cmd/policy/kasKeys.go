@@ -1,10 +1,15 @@
 package policy
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
 	"encoding/base64"
-	"encoding/hex"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -12,6 +17,8 @@ import (
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
 	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/keymaterial"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/opentdf/otdfctl/pkg/utils"
 	"github.com/opentdf/platform/lib/ocrypto"
@@ -23,9 +30,6 @@ import (
 const (
 	rsa2048Len           = 2048
 	rsa4096Len           = 4096
-	ecSecp256Len         = 256
-	ecSecp384Len         = 384
-	ecSecp521Len         = 521
 	keyStatusActive      = "active"
 	keyStatusRotated     = "rotated"
 	keyModeLocal         = "local"
@@ -36,13 +40,13 @@ const (
 
 var policyKasRegistryKeysCmd = man.Docs.GetCommand("policy/kas-registry/key")
 
-func wrapKey(key string, wrappingKey []byte) ([]byte, error) {
+func wrapKey(key, wrappingKey []byte) ([]byte, error) {
 	aesKey, err := ocrypto.NewAESGcm(wrappingKey)
 	if err != nil {
 		return nil, errors.Join(errors.New("failed to create AES key"), err)
 	}
 
-	wrappedKek, err := aesKey.Encrypt([]byte(key))
+	wrappedKek, err := aesKey.Encrypt(key)
 	if err != nil {
 		return nil, errors.Join(errors.New("failed to wrap key"), err)
 	}
@@ -50,59 +54,46 @@ func wrapKey(key string, wrappingKey []byte) ([]byte, error) {
 	return wrappedKek, nil
 }
 
-func generateKeys(alg policy.Algorithm) (string, string, error) {
+// generateKeys returns a new private and public key pair as PEM. Both are kept as bytes so the caller can zero the
+// private key once it is written or wrapped.
+func generateKeys(alg policy.Algorithm) ([]byte, []byte, error) {
 	kek, err := generateKeyPair(alg)
 	if err != nil {
-		return "", "", errors.Join(errors.New("failed to generate key pair"), err)
+		return nil, nil, errors.Join(errors.New("failed to generate key pair"), err)
 	}
+	key := &keymaterial.Key{Format: keymaterial.FormatPEM, Public: kek.Public(), Private: kek}
 
-	kekPrivPem, err := kek.PrivateKeyInPemFormat()
+	kekPrivPem, err := key.PrivatePEM()
 	if err != nil {
-		return "", "", errors.Join(errors.New("failed to get private key in pem format"), err)
+		return nil, nil, errors.Join(errors.New("failed to get private key in pem format"), err)
 	}
 
-	kekPubPem, err := kek.PublicKeyInPemFormat()
+	kekPubPem, err := key.PublicPEM()
 	if err != nil {
-		return "", "", errors.Join(errors.New("failed to get public key in pem format"), err)
+		keymaterial.Zero(kekPrivPem)
+		return nil, nil, errors.Join(errors.New("failed to get public key in pem format"), err)
 	}
 
 	return kekPrivPem, kekPubPem, nil
 }
 
-func generateKeyPair(alg policy.Algorithm) (ocrypto.KeyPair, error) {
-	var key ocrypto.KeyPair
-	var err error
+func generateKeyPair(alg policy.Algorithm) (crypto.Signer, error) {
 	switch alg {
 	case policy.Algorithm_ALGORITHM_RSA_2048:
-		key, err = generateRSAKey(rsa2048Len)
+		return rsa.GenerateKey(rand.Reader, rsa2048Len)
 	case policy.Algorithm_ALGORITHM_RSA_4096:
-		key, err = generateRSAKey(rsa4096Len)
+		return rsa.GenerateKey(rand.Reader, rsa4096Len)
 	case policy.Algorithm_ALGORITHM_EC_P256:
-		key, err = generateECCKey(ecSecp256Len)
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 	case policy.Algorithm_ALGORITHM_EC_P384:
-		key, err = generateECCKey(ecSecp384Len)
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
 	case policy.Algorithm_ALGORITHM_EC_P521:
-		key, err = generateECCKey(ecSecp521Len)
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
 	case policy.Algorithm_ALGORITHM_UNSPECIFIED:
 		fallthrough
 	default:
 		return nil, errors.New("unsupported algorithm")
 	}
-
-	return key, err
-}
-
-func generateRSAKey(size int) (ocrypto.RsaKeyPair, error) {
-	return ocrypto.NewRSAKeyPair(size)
-}
-
-func generateECCKey(size int) (ocrypto.ECKeyPair, error) {
-	mode, err := ocrypto.ECSizeToMode(size)
-	if err != nil {
-		return ocrypto.ECKeyPair{}, err
-	}
-
-	return ocrypto.NewECKeyPair(mode)
 }
 
 func enumToStatus(enum policy.KeyStatus) (string, error) {
@@ -231,6 +222,7 @@ func policyCreateKasKey(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create kas key", err)
 	}
+	journalKasKey(h, journal.OperationCreate, nil, kasKey)
 
 	rows := getTableRows(kasKey)
 	if mdRows := getMetadataRows(kasKey.GetKey().GetMetadata()); mdRows != nil {
@@ -245,12 +237,14 @@ func policyImportKasKey(cmd *cobra.Command, args []string) {
 	h := common.NewHandler(c)
 	defer h.Close()
 
-	privateKeyPem := c.Flags.GetRequiredString("private-key-pem")
-	wrappingKey := c.Flags.GetRequiredString("wrapping-key")
+	privateKeyRef := c.Flags.GetRequiredString("private-key-pem")
+	wrappingKeyRef := c.Flags.GetRequiredString("wrapping-key")
 	wrappingKeyID := c.Flags.GetRequiredString("wrapping-key-id")
-	publicKeyPem := c.Flags.GetRequiredString("public-key-pem")
+	publicKeyRef := c.Flags.GetOptionalString("public-key-pem")
+	jwkKid := c.Flags.GetOptionalString("jwk-kid")
+	passwordFile := c.Flags.GetOptionalString("password-file")
 	keyIdentifier := c.Flags.GetRequiredString("key-id")
-	algorithm := c.Flags.GetRequiredString("algorithm")
+	algorithm := c.Flags.GetOptionalString("algorithm")
 	kasIdentifier := c.Flags.GetRequiredString("kas")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
@@ -264,28 +258,60 @@ func policyImportKasKey(cmd *cobra.Command, args []string) {
 	}
 
 	// Parse algorithm early for validation
-	alg, err := cli.KeyAlgToEnum(algorithm)
+	if algorithm != "" {
+		if _, err := cli.KeyAlgToEnum(algorithm); err != nil {
+			cli.ExitWithError("Invalid algorithm", err)
+		}
+	}
+
+	keys := keymaterial.NewSource(os.Stdin)
+	opts := keymaterial.Options{KeyID: jwkKid, Password: keyPassword(keys, passwordFile)}
+	var publicKey *keymaterial.Key
+	if publicKeyRef != "" {
+		publicKey, err = readKeyMaterialFlag(keys, "public-key-pem", publicKeyRef, opts)
+		if err != nil {
+			cli.ExitWithError("Invalid public key", err)
+		}
+	}
+	privateKey, err := readKeyMaterialFlag(keys, "private-key-pem", privateKeyRef, opts)
 	if err != nil {
-		cli.ExitWithError("Invalid algorithm", err)
+		cli.ExitWithError("Invalid private key", err)
+	}
+	defer privateKey.Zero()
+	if privateKey.Private == nil {
+		cli.ExitWithError("Invalid private key", fmt.Errorf("private-key-pem %w", keymaterial.ErrNoPrivateKey))
+	}
+	// The public key is derived from the private key unless given, in which case the two must belong together
+	if publicKey == nil {
+		publicKey = privateKey
+	} else if !publicKey.Matches(privateKey) {
+		cli.ExitWithError("Invalid key pair", keymaterial.ErrKeyMismatch)
 	}
 
-	decodedPub, err := base64.StdEncoding.DecodeString(publicKeyPem)
+	alg, err := keyAlgorithm(algorithm, publicKey)
 	if err != nil {
-		cli.ExitWithError("public-key-pem must be base64 encoded", err)
+		cli.ExitWithError("Invalid algorithm", err)
+	}
+	publicKeyPemBytes, err := publicKey.PublicPEM()
+	if err != nil {
+		cli.ExitWithError("Invalid public key", err)
 	}
-	if err := validatePublicKey(decodedPub, alg); err != nil {
+	if err := validatePublicKey(publicKeyPemBytes, alg); err != nil {
 		cli.ExitWithError("Invalid public key pem", err)
 	}
-	nonBase64PrivateKey, err := base64.StdEncoding.DecodeString(privateKeyPem)
+	publicKeyPem := base64.StdEncoding.EncodeToString(publicKeyPemBytes)
+	privateKeyPem, err := privateKey.PrivatePEM()
 	if err != nil {
-		cli.ExitWithError("private-key-pem must be base64 encoded", err)
+		cli.ExitWithError("Invalid private key", err)
 	}
-
-	wrappingKeyBytes, err := hex.DecodeString(wrappingKey)
+	defer keymaterial.Zero(privateKeyPem)
+	wrappingKey, err := readKeyFlag(keys, "wrapping-key", wrappingKeyRef, keymaterial.WrappingKey)
 	if err != nil {
-		cli.ExitWithError("wrapping-key must be hex encoded", err)
+		cli.ExitWithError("Invalid wrapping key", err)
 	}
-	wrappedPrivateKey, err := wrapKey(string(nonBase64PrivateKey), wrappingKeyBytes)
+	defer keymaterial.Zero(wrappingKey)
+
+	wrappedPrivateKey, err := wrapKey(privateKeyPem, wrappingKey)
 	if err != nil {
 		cli.ExitWithError("failed to wrap key", err)
 	}
@@ -323,6 +349,7 @@ func policyImportKasKey(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to import kas key", err)
 	}
+	journalKasKey(h, journal.OperationCreate, nil, importedKey)
 
 	rows := getTableRows(importedKey)
 	if mdRows := getMetadataRows(importedKey.GetKey().GetMetadata()); mdRows != nil {
@@ -369,6 +396,11 @@ func policyUpdateKasKey(cmd *cobra.Command, args []string) {
 	id := c.Flags.GetRequiredID("id")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
+	before, err := h.GetKasKey(c.Context(), id, nil)
+	if err != nil {
+		cli.ExitWithError("Failed to get kas key", err)
+	}
+
 	resp, err := h.UpdateKasKey(
 		c.Context(),
 		id,
@@ -383,6 +415,7 @@ func policyUpdateKasKey(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to get kas key", err)
 	}
+	journalKasKey(h, journal.OperationUpdate, before, kasKey)
 
 	rows := getTableRows(kasKey)
 	if mdRows := getMetadataRows(kasKey.GetKey().GetMetadata()); mdRows != nil {
@@ -553,6 +586,11 @@ func policyRotateKasKey(cmd *cobra.Command, args []string) {
 	h := common.NewHandler(c)
 	defer h.Close()
 
+	if c.Flags.GetOptionalBool("dry-run") || c.Flags.GetOptionalBool("staged") || c.Flags.GetOptionalBool("resume") {
+		policyPlanKasKeyRotation(c, h)
+		return
+	}
+
 	// Get parameters for the old key
 	oldKey := c.Flags.GetRequiredString("key")
 
@@ -601,6 +639,7 @@ func policyRotateKasKey(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to rotate key", err)
 	}
+	journalKasKey(h, journal.OperationRotate, nil, rotateKeyResult.KasKey)
 
 	rows := getTableRows(rotateKeyResult.KasKey)
 	if mdRows := getMetadataRows(rotateKeyResult.KasKey.GetKey().GetMetadata()); mdRows != nil {
@@ -671,27 +710,29 @@ func prepareKeyContexts(
 	var publicKeyCtx *policy.PublicKeyCtx
 	var privateKeyCtx *policy.PrivateKeyCtx
 	var providerConfigID string
+	keys := keymaterial.NewSource(os.Stdin)
 
 	switch mode {
 	case policy.KeyMode_KEY_MODE_CONFIG_ROOT_KEY:
 		// Local mode: generate keys locally and wrap with provided wrapping key
-		wrappingKey := c.Flags.GetRequiredString("wrapping-key")
-		wrappedKeyBytes, err := hex.DecodeString(wrappingKey)
+		wrappingKey, err := readKeyFlag(keys, "wrapping-key", c.Flags.GetRequiredString("wrapping-key"), keymaterial.WrappingKey)
 		if err != nil {
-			return nil, nil, "", errors.Join(errors.New("wrapping-key must be hex encoded"), err)
+			return nil, nil, "", err
 		}
+		defer keymaterial.Zero(wrappingKey)
 
 		privateKeyPem, publicKeyPem, err := generateKeys(alg)
 		if err != nil {
 			return nil, nil, "", errors.Join(errors.New("failed to generate keys"), err)
 		}
 
-		privateKey, err := wrapKey(privateKeyPem, wrappedKeyBytes)
+		defer keymaterial.Zero(privateKeyPem)
+		privateKey, err := wrapKey(privateKeyPem, wrappingKey)
 		if err != nil {
 			return nil, nil, "", errors.Join(errors.New("failed to wrap key"), err)
 		}
 
-		pubPemBase64 := base64.StdEncoding.EncodeToString([]byte(publicKeyPem))
+		pubPemBase64 := base64.StdEncoding.EncodeToString(publicKeyPem)
 		privPemBase64 := base64.StdEncoding.EncodeToString(privateKey)
 		publicKeyCtx = &policy.PublicKeyCtx{
 			Pem: pubPemBase64,
@@ -702,35 +743,27 @@ func prepareKeyContexts(
 		}
 	case policy.KeyMode_KEY_MODE_PROVIDER_ROOT_KEY:
 		providerConfigID = c.Flags.GetRequiredString("provider-config-id")
-		publicPem := c.Flags.GetRequiredString("public-key-pem")
-		privatePem := c.Flags.GetRequiredString("private-key-pem")
-		decodedPub, err := base64.StdEncoding.DecodeString(publicPem)
+		publicPem, err := readPublicKeyFlag(keys, "public-key-pem", c.Flags.GetRequiredString("public-key-pem"), alg)
 		if err != nil {
-			return nil, nil, "", errors.Join(errors.New("public key pem must be base64 encoded"), err)
-		}
-		if err := validatePublicKey(decodedPub, alg); err != nil {
 			return nil, nil, "", err
 		}
-		_, err = base64.StdEncoding.DecodeString(privatePem)
+		// The private key is already wrapped by the provider, so it is passed on as base64 without being decoded
+		privatePem, err := keys.Read(c.Flags.GetRequiredString("private-key-pem"))
 		if err != nil {
-			return nil, nil, "", errors.Join(errors.New("private key pem must be base64 encoded"), err)
+			return nil, nil, "", fmt.Errorf("failed to read private-key-pem: %w", err)
 		}
+		defer keymaterial.Zero(privatePem)
 		publicKeyCtx = &policy.PublicKeyCtx{
 			Pem: publicPem,
 		}
 		privateKeyCtx = &policy.PrivateKeyCtx{
 			KeyId:      wrappingKeyID,
-			WrappedKey: privatePem,
+			WrappedKey: keymaterial.Base64(privatePem),
 		}
 	case policy.KeyMode_KEY_MODE_REMOTE:
-		pem := c.Flags.GetRequiredString("public-key-pem")
 		providerConfigID = c.Flags.GetRequiredString("provider-config-id")
-
-		decoded, err := base64.StdEncoding.DecodeString(pem)
+		pem, err := readPublicKeyFlag(keys, "public-key-pem", c.Flags.GetRequiredString("public-key-pem"), alg)
 		if err != nil {
-			return nil, nil, "", errors.Join(errors.New("pem must be base64 encoded"), err)
-		}
-		if err := validatePublicKey(decoded, alg); err != nil {
 			return nil, nil, "", err
 		}
 
@@ -741,12 +774,8 @@ func prepareKeyContexts(
 			KeyId: wrappingKeyID,
 		}
 	case policy.KeyMode_KEY_MODE_PUBLIC_KEY_ONLY:
-		pem := c.Flags.GetRequiredString("public-key-pem")
-		decoded, err := base64.StdEncoding.DecodeString(pem)
+		pem, err := readPublicKeyFlag(keys, "public-key-pem", c.Flags.GetRequiredString("public-key-pem"), alg)
 		if err != nil {
-			return nil, nil, "", errors.Join(errors.New("pem must be base64 encoded"), err)
-		}
-		if err := validatePublicKey(decoded, alg); err != nil {
 			return nil, nil, "", err
 		}
 		publicKeyCtx = &policy.PublicKeyCtx{
@@ -761,6 +790,81 @@ func prepareKeyContexts(
 	return publicKeyCtx, privateKeyCtx, providerConfigID, nil
 }
 
+// readKeyFlag reads and decodes the key material given to a key-bearing flag, which may be the key itself or a
+// reference to a file (@path), stdin (-) or an environment variable (env:NAME). The caller zeroes the result.
+func readKeyFlag(keys *keymaterial.Source, flag, value string, decode func([]byte) ([]byte, error)) ([]byte, error) {
+	raw, err := keys.Read(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", flag, err)
+	}
+	defer keymaterial.Zero(raw)
+	decoded, err := decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", flag, err)
+	}
+	return decoded, nil
+}
+
+// readKeyMaterialFlag reads a key-bearing flag holding a key in any supported format: PEM, DER, PKCS#12, JWK, JWKS
+// or OpenSSH, raw or base64 encoded.
+func readKeyMaterialFlag(keys *keymaterial.Source, flag, value string, opts keymaterial.Options) (*keymaterial.Key, error) {
+	raw, err := keys.Read(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", flag, err)
+	}
+	defer keymaterial.Zero(raw)
+	key, err := keymaterial.Parse(raw, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%s %w", flag, err)
+	}
+	return key, nil
+}
+
+// keyAlgorithm infers the algorithm from the key when none is given, and rejects a given algorithm the key does not
+// match.
+func keyAlgorithm(algorithm string, key *keymaterial.Key) (policy.Algorithm, error) {
+	inferred, err := key.Algorithm()
+	if err != nil {
+		return policy.Algorithm_ALGORITHM_UNSPECIFIED, err
+	}
+	if algorithm != "" && !strings.EqualFold(algorithm, inferred) {
+		return policy.Algorithm_ALGORITHM_UNSPECIFIED, fmt.Errorf("algorithm mismatch: --algorithm is %s but the key is %s", algorithm, inferred)
+	}
+	return cli.KeyAlgToEnum(inferred)
+}
+
+// keyPassword returns the password of an encrypted PKCS#12 bundle or OpenSSH private key from the password file when
+// given, and prompts for it otherwise. The prompt needs stdin, so it fails when the key was read from stdin.
+func keyPassword(keys *keymaterial.Source, passwordFile string) func() (string, error) {
+	return func() (string, error) {
+		if passwordFile != "" {
+			b, err := utils.ReadBytesFromFile(passwordFile, keymaterial.MaxSize)
+			if err != nil {
+				return "", fmt.Errorf("failed to read password-file: %w", err)
+			}
+			defer keymaterial.Zero(b)
+			return strings.TrimRight(string(b), "\r\n"), nil
+		}
+		if keys.StdinUsed() {
+			return "", errors.New("cannot prompt for the password of a key read from stdin, give it with --password-file")
+		}
+		return cli.AskForSecret("Enter the password of the private key"), nil
+	}
+}
+
+// readPublicKeyFlag reads a PEM or base64 encoded PEM public key, validates it against the algorithm and returns it
+// base64 encoded, as the platform expects.
+func readPublicKeyFlag(keys *keymaterial.Source, flag, value string, alg policy.Algorithm) (string, error) {
+	pem, err := readKeyFlag(keys, flag, value, keymaterial.PEM)
+	if err != nil {
+		return "", err
+	}
+	if err := validatePublicKey(pem, alg); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(pem), nil
+}
+
 // validatePublicKey validates the PEM public key against the expected algorithm.
 func validatePublicKey(pemDecoded []byte, alg policy.Algorithm) error {
 	err := utils.ValidatePublicKeyPEM(pemDecoded, alg)
@@ -787,6 +891,7 @@ func policyUnsafeDeleteKasKey(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to delete key (%s)", id), err)
 	}
+	journalKasKey(h, journal.OperationDelete, key, nil)
 
 	rows := [][]string{
 		{"Deleted", "true"},
@@ -994,6 +1099,31 @@ func initKASKeysCommands() {
 		rotateDoc.GetDocFlag("private-key-pem").Default,
 		rotateDoc.GetDocFlag("private-key-pem").Description,
 	)
+	rotateDoc.Flags().Bool(
+		rotateDoc.GetDocFlag("dry-run").Name,
+		false,
+		rotateDoc.GetDocFlag("dry-run").Description,
+	)
+	rotateDoc.Flags().Bool(
+		rotateDoc.GetDocFlag("staged").Name,
+		false,
+		rotateDoc.GetDocFlag("staged").Description,
+	)
+	rotateDoc.Flags().Int32(
+		rotateDoc.GetDocFlag("batch-size").Name,
+		defaultRotationBatchSize,
+		rotateDoc.GetDocFlag("batch-size").Description,
+	)
+	rotateDoc.Flags().Int32(
+		rotateDoc.GetDocFlag("max-batches").Name,
+		0,
+		rotateDoc.GetDocFlag("max-batches").Description,
+	)
+	rotateDoc.Flags().Bool(
+		rotateDoc.GetDocFlag("resume").Name,
+		false,
+		rotateDoc.GetDocFlag("resume").Description,
+	)
 	injectLabelFlags(&rotateDoc.Command, true)
 
 	// Import Kas Key
@@ -1042,6 +1172,16 @@ func initKASKeysCommands() {
 		importDoc.GetDocFlag("private-key-pem").Default,
 		importDoc.GetDocFlag("private-key-pem").Description,
 	)
+	importDoc.Flags().String(
+		importDoc.GetDocFlag("jwk-kid").Name,
+		importDoc.GetDocFlag("jwk-kid").Default,
+		importDoc.GetDocFlag("jwk-kid").Description,
+	)
+	importDoc.Flags().String(
+		importDoc.GetDocFlag("password-file").Name,
+		importDoc.GetDocFlag("password-file").Default,
+		importDoc.GetDocFlag("password-file").Description,
+	)
 	importDoc.Flags().StringP(
 		importDoc.GetDocFlag("legacy").Name,
 		importDoc.GetDocFlag("legacy").Shorthand,
@@ -1107,6 +1247,6 @@ func initKASKeysCommands() {
 	)
 
 	unsafeCmd.AddSubcommands(unsafeDeleteDoc)
-	policyKasRegistryKeysCmd.AddSubcommands(createDoc, getDoc, updateDoc, listDoc, rotateDoc, importDoc, mappingsDoc, unsafeCmd)
+	policyKasRegistryKeysCmd.AddSubcommands(createDoc, getDoc, updateDoc, listDoc, rotateDoc, importDoc, newKasKeyGenerateCommand(), newKasKeyReportCommand(), mappingsDoc, unsafeCmd)
 	KasRegistryCmd.AddCommand(&policyKasRegistryKeysCmd.Command)
 }
@@ -0,0 +1,252 @@
+package policy
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/keymaterial"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/spf13/cobra"
+)
+
+const (
+	generatedPrivateKeyFileMode = 0o600
+	generatedPublicKeyFileMode  = 0o644
+
+	generatedFilePrivateKey = "private key"
+	generatedFilePublicKey  = "public key"
+	generatedFileWrappedKey = "wrapped private key"
+	generatedFileJWKS       = "jwks"
+)
+
+type generatedFile struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
+// keyFile is a file of the generated key pair, to be written to the output directory
+type keyFile struct {
+	kind string
+	name string
+	data []byte
+	mode os.FileMode
+}
+
+type generateKeyResult struct {
+	KeyID       string          `json:"key_id"`
+	Algorithm   string          `json:"algorithm"`
+	Fingerprint string          `json:"fingerprint"`
+	Files       []generatedFile `json:"files"`
+}
+
+func policyGenerateKasKey(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+
+	keyID := c.Flags.GetRequiredString("key-id")
+	algorithm := c.Flags.GetRequiredString("algorithm")
+	outDir := c.Flags.GetOptionalString("out-dir")
+	wrappingKeyRef := c.Flags.GetOptionalString("wrapping-key")
+	wrappedOnly := c.Flags.GetOptionalBool("wrapped-only")
+	withJWKS := c.Flags.GetOptionalBool("jwks")
+	force := c.Flags.GetOptionalBool("force")
+
+	if wrappedOnly && wrappingKeyRef == "" {
+		cli.ExitWithError("Flag '--wrapped-only' requires '--wrapping-key'", nil)
+	}
+	// the key id names the files, so it must not reach outside of the output directory
+	if keyID != filepath.Base(keyID) || strings.ContainsAny(keyID, `/\`) || keyID == "." || keyID == ".." {
+		cli.ExitWithError("Invalid key-id", fmt.Errorf("'%s' cannot be used as a file name", keyID))
+	}
+	alg, err := cli.KeyAlgToEnum(algorithm)
+	if err != nil {
+		cli.ExitWithError("Invalid algorithm", err)
+	}
+
+	var wrappingKey []byte
+	if wrappingKeyRef != "" {
+		wrappingKey, err = readKeyFlag(keymaterial.NewSource(os.Stdin), "wrapping-key", wrappingKeyRef, keymaterial.WrappingKey)
+		if err != nil {
+			cli.ExitWithError("Invalid wrapping key", err)
+		}
+		defer keymaterial.Zero(wrappingKey)
+	}
+
+	privateKeyBytes, publicKeyPem, err := generateKeys(alg)
+	if err != nil {
+		cli.ExitWithError("Failed to generate key pair", err)
+	}
+	defer keymaterial.Zero(privateKeyBytes)
+
+	key, err := keymaterial.Parse(privateKeyBytes, keymaterial.Options{})
+	if err != nil {
+		cli.ExitWithError("Failed to read the generated key pair", err)
+	}
+	defer key.Zero()
+	key.KeyID = keyID
+	fingerprint, err := key.Fingerprint()
+	if err != nil {
+		cli.ExitWithError("Failed to fingerprint the generated key pair", err)
+	}
+
+	files := []keyFile{{generatedFilePublicKey, keyID + ".pub.pem", publicKeyPem, generatedPublicKeyFileMode}}
+	if !wrappedOnly {
+		files = append(files,
+			keyFile{generatedFilePrivateKey, keyID + ".key.pem", privateKeyBytes, generatedPrivateKeyFileMode})
+	}
+	if wrappingKey != nil {
+		wrapped, err := wrapKey(privateKeyBytes, wrappingKey)
+		if err != nil {
+			cli.ExitWithError("Failed to wrap the private key", err)
+		}
+		// base64, as 'key create --mode provider --private-key-pem @file' expects
+		encoded := []byte(base64.StdEncoding.EncodeToString(wrapped))
+		files = append(files, keyFile{generatedFileWrappedKey, keyID + ".wrapped", encoded, generatedPrivateKeyFileMode})
+	}
+	if withJWKS {
+		jwks, err := keymaterial.JWKS(key)
+		if err != nil {
+			cli.ExitWithError("Failed to build JWKS", err)
+		}
+		files = append(files, keyFile{generatedFileJWKS, keyID + ".jwks", jwks, generatedPublicKeyFileMode})
+	}
+
+	if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to create output directory %s", outDir), err)
+	}
+	if !force {
+		if err := checkKeyFilesFree(outDir, files); err != nil {
+			cli.ExitWithError("Failed to write the key pair", err)
+		}
+	}
+	result := generateKeyResult{KeyID: keyID, Algorithm: strings.ToLower(algorithm), Fingerprint: fingerprint}
+	for _, f := range files {
+		path := filepath.Join(outDir, f.name)
+		if err := writeKeyFile(path, f.data, f.mode, force); err != nil {
+			cli.ExitWithError(fmt.Sprintf("Failed to write %s", f.kind), err)
+		}
+		result.Files = append(result.Files, generatedFile{Kind: f.kind, Path: path})
+	}
+
+	c.ExitWith(renderGeneratedKey(result), result, cli.ExitCodeSuccess, os.Stdout)
+}
+
+// checkKeyFilesFree fails when any of the files exists, so the command stops before writing part of a key pair
+func checkKeyFilesFree(dir string, files []keyFile) error {
+	for _, f := range files {
+		path := filepath.Join(dir, f.name)
+		_, err := os.Lstat(path)
+		switch {
+		case err == nil:
+			return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+		case !errors.Is(err, os.ErrNotExist):
+			return err
+		}
+	}
+	return nil
+}
+
+// writeKeyFile never replaces an existing file unless forced, so a rerun cannot silently destroy a key
+func writeKeyFile(path string, data []byte, mode os.FileMode, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, mode)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	// an existing file keeps its mode when overwritten, so tighten it explicitly
+	if err := f.Chmod(mode); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func renderGeneratedKey(r generateKeyResult) string {
+	t := cli.NewTable(
+		table.NewFlexColumn("kind", "File", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("path", "Path", cli.FlexColumnWidthThree),
+	)
+	rows := make([]table.Row, 0, len(r.Files))
+	var privatePath, wrappedPath string
+	for _, f := range r.Files {
+		rows = append(rows, table.NewRow(table.RowData{"kind": f.Kind, "path": f.Path}))
+		switch f.Kind {
+		case generatedFilePrivateKey:
+			privatePath = f.Path
+		case generatedFileWrappedKey:
+			wrappedPath = f.Path
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(cli.SuccessMessage(fmt.Sprintf("Generated %s key pair %s", r.Algorithm, r.KeyID)) + "\n")
+	b.WriteString("SHA-256 fingerprint: " + r.Fingerprint + "\n")
+	b.WriteString(t.WithRows(rows).View() + "\n")
+	if privatePath != "" {
+		b.WriteString(fmt.Sprintf("Import with: otdfctl policy kas-registry key import --key-id %s --private-key-pem @%s ...\n", r.KeyID, privatePath))
+	}
+	if wrappedPath != "" {
+		b.WriteString(fmt.Sprintf("Register with: otdfctl policy kas-registry key create --key-id %s --mode provider --private-key-pem @%s ...\n", r.KeyID, wrappedPath))
+	}
+	return b.String()
+}
+
+func newKasKeyGenerateCommand() *man.Doc {
+	doc := man.Docs.GetCommand("policy/kas-registry/key/generate",
+		man.WithRun(policyGenerateKasKey),
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("key-id").Name,
+		doc.GetDocFlag("key-id").Default,
+		doc.GetDocFlag("key-id").Description,
+	)
+	doc.Flags().StringP(
+		doc.GetDocFlag("algorithm").Name,
+		doc.GetDocFlag("algorithm").Shorthand,
+		doc.GetDocFlag("algorithm").Default,
+		doc.GetDocFlag("algorithm").Description,
+	)
+	doc.Flags().StringP(
+		doc.GetDocFlag("out-dir").Name,
+		doc.GetDocFlag("out-dir").Shorthand,
+		doc.GetDocFlag("out-dir").Default,
+		doc.GetDocFlag("out-dir").Description,
+	)
+	doc.Flags().StringP(
+		doc.GetDocFlag("wrapping-key").Name,
+		doc.GetDocFlag("wrapping-key").Shorthand,
+		doc.GetDocFlag("wrapping-key").Default,
+		doc.GetDocFlag("wrapping-key").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("wrapped-only").Name,
+		false,
+		doc.GetDocFlag("wrapped-only").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("jwks").Name,
+		false,
+		doc.GetDocFlag("jwks").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("force").Name,
+		false,
+		doc.GetDocFlag("force").Description,
+	)
+	return doc
+}
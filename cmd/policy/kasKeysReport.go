@@ -0,0 +1,249 @@
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/keyreport"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/opentdf/platform/protocol/go/policy/kasregistry"
+	"github.com/spf13/cobra"
+)
+
+const (
+	keyReportFormatTable = "table"
+	keyReportFormatCSV   = "csv"
+
+	defaultKeyReportMinStrength = 128
+)
+
+func policyKasKeyReport(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+	ctx := c.Context()
+
+	format := c.Flags.GetOptionalString("format")
+	kasFilter := c.Flags.GetOptionalString("kas")
+	flaggedOnly := c.Flags.GetOptionalBool("flagged-only")
+	minStrength := int(c.Flags.GetOptionalInt32("min-strength"))
+
+	if format != keyReportFormatTable && format != keyReportFormatCSV {
+		cli.ExitWithError(fmt.Sprintf("Invalid format '%s', expected %s or %s", format, keyReportFormatTable, keyReportFormatCSV), nil)
+	}
+	var maxAge time.Duration
+	if v := c.Flags.GetOptionalString("max-age"); v != "" && v != "0" {
+		var err error
+		if maxAge, err = keyreport.ParseAge(v); err != nil {
+			cli.ExitWithError("Invalid --max-age", err)
+		}
+	}
+	kasLookup, err := resolveKasIdentifier(kasFilter)
+	if err != nil {
+		cli.ExitWithError("Invalid kas identifier", err)
+	}
+
+	servers, err := h.ListAllKasRegistryEntries(ctx)
+	if err != nil {
+		cli.ExitWithError("Failed to list KAS registry entries", err)
+	}
+	kasKeys, err := h.ListAllKasKeys(ctx)
+	if err != nil {
+		cli.ExitWithError("Failed to list kas keys", err)
+	}
+	mappings, err := h.ListAllKeyMappings(ctx, "")
+	if err != nil {
+		cli.ExitWithError("Failed to list key mappings", err)
+	}
+	// a platform without a base key answers with an error, which leaves no key to mark
+	base, _ := h.GetBaseKey(ctx)
+
+	names := make(map[string]string, len(servers))
+	for _, s := range servers {
+		names[s.GetId()] = s.GetName()
+	}
+	keys := make([]keyreport.Key, 0, len(kasKeys))
+	for _, k := range kasKeys {
+		if !kasMatches(k, names[k.GetKasId()], kasLookup) {
+			continue
+		}
+		keys = append(keys, reportKey(k, names[k.GetKasId()], mappings, base))
+	}
+
+	report := keyreport.Build(keys, keyreport.Options{MaxAge: maxAge, MinStrength: minStrength})
+	if flaggedOnly {
+		report = report.FlaggedOnly()
+	}
+
+	if format == keyReportFormatCSV && !c.Flags.GetOptionalBool("json") {
+		var b bytes.Buffer
+		if err := keyreport.WriteCSV(&b, report); err != nil {
+			cli.ExitWithError("Failed to write CSV", err)
+		}
+		c.ExitWith(strings.TrimSuffix(b.String(), "\n"), report, cli.ExitCodeSuccess, os.Stdout)
+	}
+	c.ExitWith(renderKeyReport(report), report, cli.ExitCodeSuccess, os.Stdout)
+}
+
+func kasMatches(k *policy.KasKey, name string, lookup handlers.KasIdentifier) bool {
+	switch {
+	case lookup.ID != "":
+		return k.GetKasId() == lookup.ID
+	case lookup.URI != "":
+		return k.GetKasUri() == lookup.URI
+	case lookup.Name != "":
+		return name == lookup.Name
+	}
+	return true
+}
+
+// reportKey describes a key for the report. A key rotated in stages keeps its status, so the labels of a staged
+// rotation count as a rotation as well.
+func reportKey(k *policy.KasKey, kasName string, mappings []*kasregistry.KeyMapping, base *policy.SimpleKasKey) keyreport.Key {
+	key := k.GetKey()
+	alg, err := cli.KeyEnumToAlg(key.GetKeyAlgorithm())
+	if err != nil {
+		alg = key.GetKeyAlgorithm().String()
+	}
+	mode, err := enumToMode(key.GetKeyMode())
+	if err != nil {
+		mode = key.GetKeyMode().String()
+	}
+	status, err := enumToStatus(key.GetKeyStatus())
+	if err != nil {
+		status = key.GetKeyStatus().String()
+	}
+
+	r := keyreport.Key{
+		KasID:     k.GetKasId(),
+		KasURI:    k.GetKasUri(),
+		KasName:   kasName,
+		ID:        key.GetId(),
+		KeyID:     key.GetKeyId(),
+		Algorithm: alg,
+		Mode:      mode,
+		Status:    status,
+		Legacy:    key.GetLegacy(),
+		Base:      base.GetKasUri() == k.GetKasUri() && base.GetPublicKey().GetKid() == key.GetKeyId(),
+		RotatedTo: key.GetMetadata().GetLabels()[rotatedToLabel],
+	}
+	if created := key.GetMetadata().GetCreatedAt(); created != nil {
+		r.CreatedAt = created.AsTime()
+	}
+	if at, err := time.Parse(time.RFC3339, key.GetMetadata().GetLabels()[rotatedAtLabel]); err == nil {
+		r.RotatedAt = &at
+	} else if key.GetKeyStatus() == policy.KeyStatus_KEY_STATUS_ROTATED && key.GetMetadata().GetUpdatedAt() != nil {
+		// rotating is the last change the platform makes to a key
+		at := key.GetMetadata().GetUpdatedAt().AsTime()
+		r.RotatedAt = &at
+	}
+	for _, m := range mappings {
+		if m.GetKid() == key.GetKeyId() && m.GetKasUri() == k.GetKasUri() {
+			r.Namespaces += len(m.GetNamespaceMappings())
+			r.Attributes += len(m.GetAttributeMappings())
+			r.Values += len(m.GetValueMappings())
+		}
+	}
+	return r
+}
+
+func renderKeyReport(r keyreport.Report) string {
+	if len(r.Keys) == 0 {
+		return cli.SuccessMessage("No keys to report")
+	}
+
+	t := cli.NewTable(
+		table.NewFlexColumn("kas", "KAS", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("key_id", "Key ID", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("algorithm", "Algorithm", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("mode", "Mode", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("status", "Status", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("created", "Created", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("age", "Age (days)", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("rotated", "Rotated", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("legacy", "Legacy", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("base", "Base", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("mapped", "NS/Attr/Val", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("findings", "Findings", cli.FlexColumnWidthThree),
+	)
+	rows := make([]table.Row, 0, len(r.Keys))
+	for _, k := range r.Keys {
+		kas := k.KasURI
+		if k.KasName != "" {
+			kas = k.KasName + " (" + k.KasURI + ")"
+		}
+		var created, rotated string
+		if !k.CreatedAt.IsZero() {
+			created = k.CreatedAt.Format(time.DateOnly)
+		}
+		if k.RotatedAt != nil {
+			rotated = k.RotatedAt.Format(time.DateOnly)
+		}
+		findings := make([]string, 0, len(k.Findings))
+		for _, f := range k.Findings {
+			findings = append(findings, f.Message)
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"kas":       kas,
+			"key_id":    k.KeyID,
+			"algorithm": k.Algorithm,
+			"mode":      k.Mode,
+			"status":    k.Status,
+			"created":   created,
+			"age":       strconv.Itoa(k.AgeDays),
+			"rotated":   rotated,
+			"legacy":    strconv.FormatBool(k.Legacy),
+			"base":      strconv.FormatBool(k.Base),
+			"mapped":    fmt.Sprintf("%d/%d/%d", k.Namespaces, k.Attributes, k.Values),
+			"findings":  strings.Join(findings, "; "),
+		}))
+	}
+
+	summary := fmt.Sprintf("%d keys, %d flagged", len(r.Keys), r.Flagged)
+	if r.Flagged > 0 {
+		return t.WithRows(rows).View() + "\n" + cli.WarningMessage(summary)
+	}
+	return t.WithRows(rows).View() + "\n" + cli.SuccessMessage(summary)
+}
+
+func newKasKeyReportCommand() *man.Doc {
+	doc := man.Docs.GetCommand("policy/kas-registry/key/report",
+		man.WithRun(policyKasKeyReport),
+	)
+	doc.Flags().StringP(
+		doc.GetDocFlag("format").Name,
+		doc.GetDocFlag("format").Shorthand,
+		doc.GetDocFlag("format").Default,
+		doc.GetDocFlag("format").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("kas").Name,
+		doc.GetDocFlag("kas").Default,
+		doc.GetDocFlag("kas").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("max-age").Name,
+		doc.GetDocFlag("max-age").Default,
+		doc.GetDocFlag("max-age").Description,
+	)
+	doc.Flags().Int32(
+		doc.GetDocFlag("min-strength").Name,
+		defaultKeyReportMinStrength,
+		doc.GetDocFlag("min-strength").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("flagged-only").Name,
+		false,
+		doc.GetDocFlag("flagged-only").Description,
+	)
+	return doc
+}
@@ -0,0 +1,396 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/profiles"
+	"github.com/opentdf/otdfctl/pkg/rotation"
+	"github.com/opentdf/otdfctl/pkg/utils"
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/opentdf/platform/protocol/go/policy/attributes"
+	"github.com/opentdf/platform/protocol/go/policy/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy/namespaces"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultRotationBatchSize = 10
+
+// labels a staged rotation leaves on the old key, as only a one-shot rotation can set its status to rotated
+const (
+	rotatedToLabel = "rotated_to"
+	rotatedAtLabel = "rotated_at"
+)
+
+type rotationResult struct {
+	*rotation.Plan
+	DryRun    bool `json:"dry_run,omitempty"`
+	Staged    bool `json:"staged"`
+	BatchSize int  `json:"batch_size,omitempty"`
+	Finished  bool `json:"finished"`
+}
+
+// openRotations opens the staged rotations of the profile the handler was created with
+func openRotations(h handlers.Handler) (*rotation.Store, error) {
+	dir, err := profiles.UserConfigDirectory()
+	if err != nil {
+		return nil, err
+	}
+	return rotation.ForProfile(dir, h.ProfileName()), nil
+}
+
+// getRotatingKey resolves the --key and --kas flags of 'key rotate' to the key being rotated
+func getRotatingKey(c *cli.Cli, h handlers.Handler) *policy.KasKey {
+	oldKey := c.Flags.GetRequiredString("key")
+
+	var identifier *kasregistry.KasKeyIdentifier
+	if utils.ClassifyString(oldKey) != utils.StringTypeUUID {
+		var err error
+		identifier, err = getKasKeyIdentifier(c)
+		if err != nil {
+			cli.ExitWithError("Invalid key identifier", err)
+		}
+	}
+	kasKey, err := h.GetKasKey(c.Context(), oldKey, identifier)
+	if err != nil {
+		cli.ExitWithError("Failed to get the key to rotate", err)
+	}
+	return kasKey
+}
+
+// listMappedObjects lists the namespaces, attributes and values mapped to a key
+func listMappedObjects(c *cli.Cli, h handlers.Handler, keySystemID string) []rotation.Object {
+	mappings, err := h.ListAllKeyMappings(c.Context(), keySystemID)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to list the key mappings of key %s", keySystemID), err)
+	}
+	var objects []rotation.Object
+	add := func(kind string, mapped []*kasregistry.MappedPolicyObject) {
+		for _, m := range mapped {
+			objects = append(objects, rotation.Object{Kind: kind, ID: m.GetId(), FQN: m.GetFqn()})
+		}
+	}
+	for _, m := range mappings {
+		add(rotation.KindNamespace, m.GetNamespaceMappings())
+		add(rotation.KindAttribute, m.GetAttributeMappings())
+		add(rotation.KindValue, m.GetValueMappings())
+	}
+	return objects
+}
+
+// policyPlanKasKeyRotation handles 'key rotate --dry-run' and '--staged'. A dry run shows the namespaces, attributes and
+// values that will move to the new key. A staged rotation creates the new key, moves them over in batches and only then
+// marks the old key rotated, saving its progress after every step so that it can be resumed.
+func policyPlanKasKeyRotation(c *cli.Cli, h handlers.Handler) {
+	dryRun := c.Flags.GetOptionalBool("dry-run")
+	resume := c.Flags.GetOptionalBool("resume")
+	staged := c.Flags.GetOptionalBool("staged") || resume
+	batchSize := int(c.Flags.GetOptionalInt32("batch-size"))
+	maxBatches := int(c.Flags.GetOptionalInt32("max-batches"))
+
+	if batchSize < 0 || maxBatches < 0 {
+		cli.ExitWithError("Flags '--batch-size' and '--max-batches' cannot be negative", nil)
+	}
+	if !staged {
+		batchSize = 0
+	}
+
+	oldKey := getRotatingKey(c, h)
+	store, err := openRotations(h)
+	if err != nil {
+		cli.ExitWithError("Failed to open staged rotations", err)
+	}
+
+	plan, err := store.Load(oldKey.GetKey().GetId())
+	resumed := err == nil
+	switch {
+	case err == nil && !resume && !dryRun:
+		cli.ExitWithError(fmt.Sprintf("A staged rotation of key %s to %s is in progress", oldKey.GetKey().GetKeyId(), plan.NewKey.KeyID),
+			errors.New("continue it with --resume, or show its progress with --dry-run"))
+	case err == nil:
+		if plan.Endpoint != h.PlatformEndpoint() {
+			cli.ExitWithError("Cannot resume the staged rotation",
+				fmt.Errorf("it was started against %s, not %s", plan.Endpoint, h.PlatformEndpoint()))
+		}
+		var onNew []rotation.Object
+		if plan.NewKeyCreated() {
+			onNew = listMappedObjects(c, h, plan.NewKey.ID)
+		}
+		plan.Reconcile(listMappedObjects(c, h, plan.OldKey.ID), onNew)
+	case errors.Is(err, rotation.ErrNotFound) && resume:
+		cli.ExitWithError("Nothing to resume", err)
+	case errors.Is(err, rotation.ErrNotFound):
+		if oldKey.GetKey().GetKeyStatus() != policy.KeyStatus_KEY_STATUS_ACTIVE {
+			status, _ := enumToStatus(oldKey.GetKey().GetKeyStatus())
+			cli.ExitWithError("Cannot rotate key "+oldKey.GetKey().GetKeyId(), fmt.Errorf("its status is %s, not active", status))
+		}
+		plan = rotation.NewPlan(h.PlatformEndpoint(),
+			rotation.Key{ID: oldKey.GetKey().GetId(), KeyID: oldKey.GetKey().GetKeyId(), KasID: oldKey.GetKasId(), KasURI: oldKey.GetKasUri()},
+			rotation.Key{KeyID: c.Flags.GetRequiredString("key-id"), KasID: oldKey.GetKasId(), KasURI: oldKey.GetKasUri()},
+			listMappedObjects(c, h, oldKey.GetKey().GetId()),
+		)
+	default:
+		cli.ExitWithError("Failed to read the staged rotation", err)
+	}
+
+	result := rotationResult{Plan: plan, DryRun: dryRun, Staged: staged, BatchSize: batchSize}
+	if dryRun {
+		c.ExitWith(renderRotation(result)+"\n"+cli.WarningMessage("Dry run: the key was not rotated"), result, cli.ExitCodeSuccess, os.Stdout)
+	}
+	if !staged {
+		// --dry-run is the only way here without --staged
+		cli.ExitWithError("Flag '--staged' is required", nil)
+	}
+
+	if !plan.NewKeyCreated() {
+		// saved before the key is created, so a rollout interrupted in between looks the key up on resume
+		if err := store.Save(plan); err != nil {
+			cli.ExitWithError("Failed to save the staged rotation", err)
+		}
+		if resumed {
+			plan.NewKey.ID = findRotationKey(c, h, plan)
+		}
+		if plan.NewKey.ID == "" {
+			plan.NewKey.ID = createRotationKey(c, h, plan)
+		}
+		if err := store.Save(plan); err != nil {
+			cli.ExitWithError("Failed to save the progress of the staged rotation", err)
+		}
+	}
+
+	paused := moveMappedObjects(c, h, store, plan, batchSize, maxBatches)
+	if !paused {
+		finishRotation(c, h, store, plan)
+		result.Finished = true
+	}
+
+	msg := cli.SuccessMessage(fmt.Sprintf("Rotated key %s to %s", plan.OldKey.KeyID, plan.NewKey.KeyID))
+	if paused {
+		done, total := plan.Progress()
+		msg = cli.WarningMessage(fmt.Sprintf("Paused after %d batches with %d of %d objects moved, continue with --resume", maxBatches, done, total))
+	}
+	c.ExitWith(renderRotation(result)+"\n"+msg, result, cli.ExitCodeSuccess, os.Stdout)
+}
+
+// findRotationKey returns the ID of the new key of a resumed rollout that was interrupted right after creating it,
+// and an empty ID when it was not created. A failed lookup is left to the create, which fails rather than creating the key twice.
+func findRotationKey(c *cli.Cli, h handlers.Handler, plan *rotation.Plan) string {
+	kasKey, err := h.GetKasKey(c.Context(), "", &kasregistry.KasKeyIdentifier{
+		Kid:        plan.NewKey.KeyID,
+		Identifier: &kasregistry.KasKeyIdentifier_KasId{KasId: plan.NewKey.KasID},
+	})
+	if err != nil {
+		return ""
+	}
+	return kasKey.GetKey().GetId()
+}
+
+// createRotationKey creates the new key from the key flags, alongside the old key in the same KAS
+func createRotationKey(c *cli.Cli, h handlers.Handler, plan *rotation.Plan) string {
+	alg, mode, wrappingKeyID, err := prepareKeyParams(c)
+	if err != nil {
+		cli.ExitWithError("Invalid key parameters", err)
+	}
+	publicKeyCtx, privateKeyCtx, providerConfigID, err := prepareKeyContexts(c, mode, alg, wrappingKeyID)
+	if err != nil {
+		cli.ExitWithError("Failed to prepare key contexts", err)
+	}
+	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
+
+	kasKey, err := h.CreateKasKey(
+		c.Context(),
+		plan.NewKey.KasID,
+		plan.NewKey.KeyID,
+		alg,
+		mode,
+		publicKeyCtx,
+		privateKeyCtx,
+		providerConfigID,
+		getMetadataMutable(metadataLabels),
+		false,
+	)
+	if err != nil {
+		cli.ExitWithError("Failed to create the new key", err)
+	}
+	journalKasKey(h, journal.OperationCreate, nil, kasKey)
+	return kasKey.GetKey().GetId()
+}
+
+// moveMappedObjects moves the objects still on the old key in batches, and reports whether it stopped at maxBatches
+// with objects left to move. Objects mapped to the old key during the rollout are moved as well.
+func moveMappedObjects(c *cli.Cli, h handlers.Handler, store *rotation.Store, plan *rotation.Plan, batchSize, maxBatches int) bool {
+	save := func() {
+		if err := store.Save(plan); err != nil {
+			cli.ExitWithError("Failed to save the progress of the staged rotation", err)
+		}
+	}
+
+	ran := 0
+	for {
+		batches := plan.Batches(batchSize)
+		if len(batches) == 0 {
+			return false
+		}
+		for _, batch := range batches {
+			if maxBatches > 0 && ran == maxBatches {
+				return true
+			}
+			for _, i := range batch {
+				if err := moveMappedObject(c, h, plan, &plan.Objects[i], save); err != nil {
+					cli.ExitWithError(fmt.Sprintf("Failed to move %s %s to the new key, fix the cause and continue with --resume",
+						plan.Objects[i].Kind, plan.Objects[i].FQN), err)
+				}
+			}
+			ran++
+			done, total := plan.Progress()
+			printRotationProgress(c, fmt.Sprintf("Batch %d: %d of %d objects moved to %s", ran, done, total, plan.NewKey.KeyID))
+		}
+		// pick up anything mapped to the old key while the batches ran
+		plan.Reconcile(listMappedObjects(c, h, plan.OldKey.ID), listMappedObjects(c, h, plan.NewKey.ID))
+		save()
+	}
+}
+
+// moveMappedObject assigns the new key before removing the old one, so the object is never left without a key
+func moveMappedObject(c *cli.Cli, h handlers.Handler, plan *rotation.Plan, o *rotation.Object, save func()) error {
+	ctx := c.Context()
+	if o.Step == rotation.StepPending {
+		var err error
+		switch o.Kind {
+		case rotation.KindNamespace:
+			_, err = h.AssignKeyToAttributeNamespace(ctx, o.ID, plan.NewKey.ID)
+		case rotation.KindAttribute:
+			_, err = h.AssignKeyToAttribute(ctx, o.ID, plan.NewKey.ID)
+		case rotation.KindValue:
+			_, err = h.AssignKeyToAttributeValue(ctx, o.ID, plan.NewKey.ID)
+		}
+		if err != nil {
+			return err
+		}
+		kind, mapping := rotationKeyMapping(o, plan.NewKey.ID)
+		journalChange(h, journal.OperationAssign, kind, nil, mapping)
+		o.Step = rotation.StepAssigned
+		save()
+	}
+
+	var err error
+	switch o.Kind {
+	case rotation.KindNamespace:
+		err = h.RemoveKeyFromAttributeNamespace(ctx, o.ID, plan.OldKey.ID)
+	case rotation.KindAttribute:
+		err = h.RemoveKeyFromAttribute(ctx, o.ID, plan.OldKey.ID)
+	case rotation.KindValue:
+		err = h.RemoveKeyFromAttributeValue(ctx, o.ID, plan.OldKey.ID)
+	}
+	if err != nil {
+		return err
+	}
+	kind, mapping := rotationKeyMapping(o, plan.OldKey.ID)
+	journalChange(h, journal.OperationUnassign, kind, mapping, nil)
+	o.Step = rotation.StepMoved
+	save()
+	return nil
+}
+
+// rotationKeyMapping is the mapping of a key to an object being moved, as journaled for its kind
+func rotationKeyMapping(o *rotation.Object, keyID string) (string, proto.Message) {
+	switch o.Kind {
+	case rotation.KindNamespace:
+		return kindNamespaceKey, &namespaces.NamespaceKey{NamespaceId: o.ID, KeyId: keyID}
+	case rotation.KindAttribute:
+		return kindAttributeKey, &attributes.AttributeKey{AttributeId: o.ID, KeyId: keyID}
+	}
+	return kindValueKey, &attributes.ValueKey{ValueId: o.ID, KeyId: keyID}
+}
+
+// finishRotation marks the old key rotated once nothing is mapped to it, moves the base key along if it was the old
+// key, and forgets the rollout
+func finishRotation(c *cli.Cli, h handlers.Handler, store *rotation.Store, plan *rotation.Plan) {
+	ctx := c.Context()
+	rotated, err := h.UpdateKasKey(ctx, plan.OldKey.ID, &common.MetadataMutable{
+		Labels: map[string]string{
+			rotatedToLabel: plan.NewKey.KeyID,
+			rotatedAtLabel: time.Now().UTC().Format(time.RFC3339),
+		},
+	}, common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_EXTEND)
+	if err != nil {
+		cli.ExitWithError("Failed to mark the old key rotated, continue with --resume", err)
+	}
+	journalKasKey(h, journal.OperationRotate, nil, rotated)
+
+	// a platform without a base key answers with an error, which leaves nothing to move
+	if base, err := h.GetBaseKey(ctx); err == nil &&
+		base.GetKasUri() == plan.OldKey.KasURI && base.GetPublicKey().GetKid() == plan.OldKey.KeyID {
+		resp, err := h.SetBaseKey(ctx, plan.NewKey.ID, nil)
+		if err != nil {
+			cli.ExitWithError("Failed to make the new key the base key, continue with --resume", err)
+		}
+		journalChange(h, journal.OperationUpdate, kindBaseKey, resp.GetPreviousBaseKey(), resp.GetNewBaseKey())
+		printRotationProgress(c, "Base key set to "+plan.NewKey.KeyID)
+	}
+
+	if err := store.Remove(plan.OldKey.ID); err != nil {
+		cli.ExitWithError("Failed to remove the finished staged rotation", err)
+	}
+}
+
+// printRotationProgress reports progress on stderr, as a rollout of many batches can take a while
+func printRotationProgress(c *cli.Cli, msg string) {
+	if !c.Flags.GetOptionalBool("json") {
+		fmt.Fprintln(os.Stderr, msg)
+	}
+}
+
+func renderRotation(r rotationResult) string {
+	columns := []table.Column{
+		table.NewFlexColumn("kind", "Kind", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("fqn", "FQN", cli.FlexColumnWidthFour),
+	}
+	if r.Staged {
+		columns = append(columns,
+			table.NewFlexColumn("batch", "Batch", cli.FlexColumnWidthOne),
+			table.NewFlexColumn("step", "Step", cli.FlexColumnWidthOne),
+		)
+	}
+
+	// number the batches of the objects still to move, as they would run
+	batchOf := map[int]int{}
+	for n, batch := range r.Batches(r.BatchSize) {
+		for _, i := range batch {
+			batchOf[i] = n + 1
+		}
+	}
+	rows := make([]table.Row, 0, len(r.Objects))
+	for i, o := range r.Objects {
+		batch, step := "", o.Step
+		if n, ok := batchOf[i]; ok {
+			batch = strconv.Itoa(n)
+		}
+		if step == rotation.StepPending {
+			step = "pending"
+		}
+		rows = append(rows, table.NewRow(table.RowData{"kind": o.Kind, "fqn": o.FQN, "batch": batch, "step": step}))
+	}
+
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("Rotate %s to %s on %s\n", r.OldKey.KeyID, r.NewKey.KeyID, r.OldKey.KasURI))
+	if len(rows) == 0 {
+		b.WriteString("No namespaces, attributes or values are mapped to " + r.OldKey.KeyID + "\n")
+		return b.String()
+	}
+	b.WriteString(cli.NewTable(columns...).WithRows(rows).View() + "\n")
+	done, total := r.Progress()
+	if r.Staged && done > 0 {
+		b.WriteString(fmt.Sprintf("%d of %d objects moved\n", done, total))
+	}
+	return b.String()
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
 	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/spf13/cobra"
@@ -121,6 +123,7 @@ func createKeyAccessRegistry(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create Registered KAS entry", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindKeyAccessServer, nil, created)
 
 	rows := [][]string{
 		{"Id", created.GetId()},
@@ -155,6 +158,13 @@ func updateKeyAccessRegistry(cmd *cobra.Command, args []string) {
 		cmd.Println(cli.WarningMessage(message))
 	}
 
+	before, err := h.GetKasRegistryEntry(cmd.Context(), handlers.KasIdentifier{
+		ID: id,
+	})
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to get Registered KAS entry (%s)", id), err)
+	}
+
 	updated, err := h.UpdateKasRegistryEntry(
 		cmd.Context(),
 		id,
@@ -166,6 +176,7 @@ func updateKeyAccessRegistry(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to update Registered KAS entry (%s)", id), err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindKeyAccessServer, before, updated)
 	rows := [][]string{
 		{"Id", id},
 		{"URI", updated.GetUri()},
@@ -204,6 +215,7 @@ func deleteKeyAccessRegistry(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to delete Registered KAS entry (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindKeyAccessServer, kas, nil)
 
 	t := cli.NewTabular(
 		[]string{"Id", kas.GetId()},
@@ -308,6 +320,6 @@ func initKASRegistryCommands() {
 		deleteDoc.GetDocFlag("force").Description,
 	)
 
-	KasRegistryCmd.AddSubcommands(createDoc, getDoc, listDoc, updateDoc, deleteDoc)
+	KasRegistryCmd.AddSubcommands(createDoc, getDoc, listDoc, updateDoc, deleteDoc, newKasRegistryCheckCommand())
 	Cmd.AddCommand(&KasRegistryCmd.Command)
 }
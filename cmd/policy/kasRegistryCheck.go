@@ -0,0 +1,155 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/kascheck"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/otdfctl/pkg/utils"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/spf13/cobra"
+)
+
+type kasCheckResult struct {
+	Results []kascheck.Result `json:"results"`
+	Failed  int               `json:"failed"`
+}
+
+func checkKeyAccessRegistries(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+	ctx := c.Context()
+
+	timeout, err := time.ParseDuration(c.Flags.GetOptionalString("timeout"))
+	if err != nil || timeout <= 0 {
+		cli.ExitWithError("Invalid --timeout, expected a duration such as 10s", err)
+	}
+	kasLookup, err := resolveKasIdentifier(c.Flags.GetOptionalString("kas"))
+	if err != nil {
+		cli.ExitWithError("Invalid kas identifier", err)
+	}
+
+	servers, err := h.ListAllKasRegistryEntries(ctx)
+	if err != nil {
+		cli.ExitWithError("Failed to list KAS registry entries", err)
+	}
+	kasKeys, err := h.ListAllKasKeys(ctx)
+	if err != nil {
+		cli.ExitWithError("Failed to list kas keys", err)
+	}
+
+	client := utils.NewHTTPClient(h.TLSNoVerify())
+	client.Timeout = timeout
+
+	result := kasCheckResult{Results: []kascheck.Result{}}
+	for _, s := range servers {
+		switch {
+		case kasLookup.ID != "" && s.GetId() != kasLookup.ID,
+			kasLookup.URI != "" && s.GetUri() != kasLookup.URI,
+			kasLookup.Name != "" && s.GetName() != kasLookup.Name:
+			continue
+		}
+		keys := []kascheck.Key{}
+		for _, k := range kasKeys {
+			if k.GetKasId() == s.GetId() {
+				keys = append(keys, checkKey(k))
+			}
+		}
+		cctx, cancel := context.WithTimeout(ctx, timeout*time.Duration(len(keys)+1))
+		r := kascheck.Result{
+			KasID:    s.GetId(),
+			KasURI:   s.GetUri(),
+			KasName:  s.GetName(),
+			Findings: kascheck.Check(cctx, client, s.GetUri(), keys),
+		}
+		cancel()
+		if r.Failed() {
+			result.Failed++
+		}
+		result.Results = append(result.Results, r)
+	}
+	if len(result.Results) == 0 {
+		cli.ExitWithError("No registered KAS to check", nil)
+	}
+
+	code := cli.ExitCodeSuccess
+	if result.Failed > 0 {
+		code = cli.ExitCodeError
+	}
+	c.ExitWith(renderKasCheck(result), result, code, os.Stdout)
+}
+
+func checkKey(k *policy.KasKey) kascheck.Key {
+	key := k.GetKey()
+	alg, err := cli.KeyEnumToAlg(key.GetKeyAlgorithm())
+	if err != nil {
+		alg = key.GetKeyAlgorithm().String()
+	}
+	status, err := enumToStatus(key.GetKeyStatus())
+	if err != nil {
+		status = key.GetKeyStatus().String()
+	}
+	return kascheck.Key{
+		KeyID:     key.GetKeyId(),
+		Algorithm: alg,
+		PEM:       key.GetPublicKeyCtx().GetPem(),
+		Status:    status,
+	}
+}
+
+func renderKasCheck(r kasCheckResult) string {
+	t := cli.NewTable(
+		table.NewFlexColumn("kas", "KAS", cli.FlexColumnWidthThree),
+		table.NewFlexColumn("status", "Status", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("algorithm", "Algorithm", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("key_id", "Key ID", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("message", "Message", cli.FlexColumnWidthFive),
+	)
+	rows := []table.Row{}
+	for _, res := range r.Results {
+		kas := res.KasURI
+		if res.KasName != "" {
+			kas = res.KasName + " (" + res.KasURI + ")"
+		}
+		for _, f := range res.Findings {
+			rows = append(rows, table.NewRow(table.RowData{
+				"kas":       kas,
+				"status":    strings.ToUpper(f.Status),
+				"algorithm": f.Algorithm,
+				"key_id":    f.KeyID,
+				"message":   f.Message,
+			}))
+		}
+	}
+
+	summary := fmt.Sprintf("%d of %d KAS checked with problems", r.Failed, len(r.Results))
+	if r.Failed > 0 {
+		return t.WithRows(rows).View() + "\n" + cli.ErrorMessage(summary, nil)
+	}
+	return t.WithRows(rows).View() + "\n" + cli.SuccessMessage(summary)
+}
+
+func newKasRegistryCheckCommand() *man.Doc {
+	doc := man.Docs.GetCommand("policy/kas-registry/check",
+		man.WithRun(checkKeyAccessRegistries),
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("kas").Name,
+		doc.GetDocFlag("kas").Default,
+		doc.GetDocFlag("kas").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("timeout").Name,
+		doc.GetDocFlag("timeout").Default,
+		doc.GetDocFlag("timeout").Description,
+	)
+	return doc
+}
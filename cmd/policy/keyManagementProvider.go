@@ -1,31 +1,75 @@
 package policy
 
 import (
+	"encoding/json"
 	"fmt"
+	"os"
 
 	"github.com/evertras/bubble-table/table"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
 	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/otdfctl/pkg/providerconfig"
+	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
 )
 
-func createProviderConfig(cmd *cobra.Command, args []string) {
-	c := cli.New(cmd, args)
-	h := common.NewHandler(c)
-	defer h.Close()
+// validateProviderConfig checks the config against the schema of its key manager. Key managers which are not a known
+// provider type are left to the platform to validate.
+func validateProviderConfig(manager string, config []byte) {
+	t, ok := providerconfig.Lookup(manager)
+	if !ok {
+		return
+	}
+	if err := t.Validate(config); err != nil {
+		cli.ExitWithError(fmt.Sprintf("Invalid config for provider type '%s', see 'provider template %s' or pass --skip-validation",
+			t.Name, t.Name), err)
+	}
+}
 
-	name := c.Flags.GetRequiredString("name")
-	manager := c.Flags.GetRequiredString("manager")
-	config := c.Flags.GetRequiredString("config")
-	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
+// validateProviderConfigUpdate validates the config against the manager a provider config ends up with. Changing only
+// one of them needs the other from the current provider config, which is only fetched when there is a schema to check.
+func validateProviderConfigUpdate(c *cli.Cli, h handlers.Handler, id, manager string, config []byte) {
+	if manager == "" && len(config) == 0 {
+		return
+	}
+	if manager != "" {
+		if _, ok := providerconfig.Lookup(manager); !ok {
+			return
+		}
+	}
+	if manager == "" || len(config) == 0 {
+		current, err := h.GetProviderConfig(c.Context(), id, "")
+		if err != nil {
+			cli.ExitWithError("Failed to get provider config", err)
+		}
+		if manager == "" {
+			manager = current.GetManager()
+		}
+		if len(config) == 0 {
+			config = current.GetConfigJson()
+		}
+	}
+	validateProviderConfig(manager, config)
+}
 
-	// Do not need to get provider config after, since this endpoint returns the created config.
-	pc, err := h.CreateProviderConfig(c.Context(), name, manager, []byte(config), getMetadataMutable(metadataLabels))
-	if err != nil {
-		cli.ExitWithError("Failed to create provider config", err)
+// maskProviderConfig returns the provider config with the values of its secret fields masked, as a copy when any is.
+func maskProviderConfig(pc *policy.KeyProviderConfig) *policy.KeyProviderConfig {
+	config := pc.GetConfigJson()
+	masked := providerconfig.Mask(pc.GetManager(), config)
+	if string(masked) == string(config) {
+		return pc
 	}
+	pc = proto.Clone(pc).(*policy.KeyProviderConfig) //nolint:forcetypeassert // proto.Clone returns the type it is given
+	pc.ConfigJson = masked
+	return pc
+}
 
+func handleProviderConfigSuccess(cmd *cobra.Command, pc *policy.KeyProviderConfig) {
+	pc = maskProviderConfig(pc)
 	rows := [][]string{
 		{"ID", pc.GetId()},
 		{"Name", pc.GetName()},
@@ -42,6 +86,30 @@ func createProviderConfig(cmd *cobra.Command, args []string) {
 	common.HandleSuccess(cmd, pc.GetId(), t, pc)
 }
 
+func createProviderConfig(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	name := c.Flags.GetRequiredString("name")
+	manager := c.Flags.GetRequiredString("manager")
+	config := c.Flags.GetRequiredString("config")
+	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
+
+	if !c.Flags.GetOptionalBool("skip-validation") {
+		validateProviderConfig(manager, []byte(config))
+	}
+
+	// Do not need to get provider config after, since this endpoint returns the created config.
+	pc, err := h.CreateProviderConfig(c.Context(), name, manager, []byte(config), getMetadataMutable(metadataLabels))
+	if err != nil {
+		cli.ExitWithError("Failed to create provider config", err)
+	}
+	journalChange(h, journal.OperationCreate, kindProviderConfig, nil, pc)
+
+	handleProviderConfigSuccess(cmd, pc)
+}
+
 func getProviderConfig(cmd *cobra.Command, args []string) {
 	c := cli.New(cmd, args)
 	h := common.NewHandler(c)
@@ -55,20 +123,7 @@ func getProviderConfig(cmd *cobra.Command, args []string) {
 		cli.ExitWithError("Failed to get provider config", err)
 	}
 
-	rows := [][]string{
-		{"ID", pc.GetId()},
-		{"Name", pc.GetName()},
-		{"Config", string(pc.GetConfigJson())},
-		{"Manager", pc.GetManager()},
-	}
-
-	if mdRows := getMetadataRows(pc.GetMetadata()); mdRows != nil {
-		rows = append(rows, mdRows...)
-	}
-
-	t := cli.NewTabular(rows...)
-
-	common.HandleSuccess(cmd, pc.GetId(), t, pc)
+	handleProviderConfigSuccess(cmd, pc)
 }
 
 func updateProviderConfig(cmd *cobra.Command, args []string) {
@@ -86,25 +141,18 @@ func updateProviderConfig(cmd *cobra.Command, args []string) {
 		cli.ExitWithError("At least one field (name, manager, config, or metadata labels) must be updated", nil)
 	}
 
+	if !c.Flags.GetOptionalBool("skip-validation") {
+		validateProviderConfigUpdate(c, h, id, manager, []byte(config))
+	}
+
 	pc, err := h.UpdateProviderConfig(c.Context(), id, name, manager, []byte(config), getMetadataMutable(metadataLabels), getMetadataUpdateBehavior())
 	if err != nil {
 		cli.ExitWithError("Failed to update provider config", err)
 	}
+	// the configuration is never journaled, so the update is recorded without the state before it
+	journalChange(h, journal.OperationUpdate, kindProviderConfig, nil, pc)
 
-	rows := [][]string{
-		{"ID", pc.GetId()},
-		{"Name", pc.GetName()},
-		{"Config", string(pc.GetConfigJson())},
-		{"Manager", pc.GetManager()},
-	}
-
-	if mdRows := getMetadataRows(pc.GetMetadata()); mdRows != nil {
-		rows = append(rows, mdRows...)
-	}
-
-	t := cli.NewTabular(rows...)
-
-	common.HandleSuccess(cmd, pc.GetId(), t, pc)
+	handleProviderConfigSuccess(cmd, pc)
 }
 
 func listProviderConfig(cmd *cobra.Command, args []string) {
@@ -131,6 +179,9 @@ func listProviderConfig(cmd *cobra.Command, args []string) {
 		table.NewFlexColumn("created_at", "Created At", cli.FlexColumnWidthOne),
 		table.NewFlexColumn("updated_at", "Updated At", cli.FlexColumnWidthOne),
 	)
+	for i, pc := range resp.GetProviderConfigs() {
+		resp.ProviderConfigs[i] = maskProviderConfig(pc)
+	}
 	rows := []table.Row{}
 	for _, pc := range resp.GetProviderConfigs() {
 		metadata := cli.ConstructMetadata(pc.GetMetadata())
@@ -169,6 +220,7 @@ func deleteProviderConfig(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to delete provider config", err)
 	}
+	journalChange(h, journal.OperationDelete, kindProviderConfig, pc, nil)
 
 	rows := [][]string{
 		{"Deleted", "true"},
@@ -179,6 +231,20 @@ func deleteProviderConfig(cmd *cobra.Command, args []string) {
 	common.HandleSuccess(cmd, id, t, nil)
 }
 
+func providerConfigTemplate(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+
+	t, err := providerconfig.Get(args[0])
+	if err != nil {
+		cli.ExitWithError("Failed to scaffold provider config", err)
+	}
+	tmpl, err := t.Template()
+	if err != nil {
+		cli.ExitWithError("Failed to scaffold provider config", err)
+	}
+	c.ExitWith(string(tmpl), json.RawMessage(tmpl), cli.ExitCodeSuccess, os.Stdout)
+}
+
 func initKeyManagementProviderCommands() {
 	// Create Provider Config
 	createDoc := man.Docs.GetCommand("policy/key-management/provider/create",
@@ -202,6 +268,11 @@ func initKeyManagementProviderCommands() {
 		createDoc.GetDocFlag("config").Default,
 		createDoc.GetDocFlag("config").Description,
 	)
+	createDoc.Flags().Bool(
+		createDoc.GetDocFlag("skip-validation").Name,
+		false,
+		createDoc.GetDocFlag("skip-validation").Description,
+	)
 	injectLabelFlags(&createDoc.Command, false)
 
 	// Get Provider Config
@@ -251,6 +322,11 @@ func initKeyManagementProviderCommands() {
 		updateDoc.GetDocFlag("config").Default,
 		updateDoc.GetDocFlag("config").Description,
 	)
+	updateDoc.Flags().Bool(
+		updateDoc.GetDocFlag("skip-validation").Name,
+		false,
+		updateDoc.GetDocFlag("skip-validation").Description,
+	)
 	injectLabelFlags(&updateDoc.Command, true)
 
 	// List Provider Configs
@@ -276,8 +352,13 @@ func initKeyManagementProviderCommands() {
 		deleteDoc.GetDocFlag("force").Description,
 	)
 
+	// Scaffold a Provider Config
+	templateDoc := man.Docs.GetCommand("policy/key-management/provider/template",
+		man.WithRun(providerConfigTemplate),
+	)
+
 	doc := man.Docs.GetCommand("policy/key-management/provider",
-		man.WithSubcommands(createDoc, getDoc, updateDoc, listDoc, deleteDoc))
+		man.WithSubcommands(createDoc, getDoc, updateDoc, listDoc, deleteDoc, templateDoc))
 
 	KeyManagementCmd.AddCommand(&doc.Command)
 }
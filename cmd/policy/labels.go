@@ -0,0 +1,273 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
+	"github.com/opentdf/otdfctl/pkg/man"
+	policycommon "github.com/opentdf/platform/protocol/go/common"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultLabelsConcurrency = 4
+
+type labelsResult struct {
+	DryRun  bool            `json:"dry_run"`
+	Changes []labels.Change `json:"changes"`
+	Failed  int             `json:"failed"`
+}
+
+func policyAddLabels(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	if len(args) == 0 {
+		c.ExitWithError("At least one label in the format key=value is required", nil)
+	}
+	op := labels.Operation{Set: map[string]string{}}
+	for _, l := range args {
+		k, v, ok := strings.Cut(l, "=")
+		if !ok || k == "" || v == "" {
+			c.ExitWithError(fmt.Sprintf("Invalid label '%s', expected key=value", l), nil)
+		}
+		op.Set[k] = v
+	}
+	runLabelsOperation(c, cmd, op)
+}
+
+func policyRemoveLabels(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	if len(args) == 0 {
+		c.ExitWithError("At least one label key is required", nil)
+	}
+	runLabelsOperation(c, cmd, labels.Operation{Remove: args})
+}
+
+func policyRenameLabel(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	runLabelsOperation(c, cmd, labels.Operation{Rename: map[string]string{args[0]: args[1]}})
+}
+
+func runLabelsOperation(c *cli.Cli, cmd *cobra.Command, op labels.Operation) {
+	if err := op.Validate(); err != nil {
+		c.ExitWithError("Invalid label operation", err)
+	}
+	kinds, err := cmd.Flags().GetStringSlice("kind")
+	if err != nil {
+		c.ExitWithError("Invalid --kind", err)
+	}
+	hasLabels, err := cmd.Flags().GetStringSlice("has-label")
+	if err != nil {
+		c.ExitWithError("Invalid --has-label", err)
+	}
+	concurrency, err := cmd.Flags().GetInt("concurrency")
+	if err != nil || concurrency < 1 {
+		c.ExitWithError("Flag '--concurrency' must be a positive number", err)
+	}
+	sel, err := labels.NewSelector(kinds, c.Flags.GetOptionalString("namespace"), c.Flags.GetOptionalString("fqn"), hasLabels)
+	if err != nil {
+		c.ExitWithError("Invalid selector", err)
+	}
+
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	snapshot, err := h.LoadPolicySnapshot(cmd.Context())
+	if err != nil {
+		c.ExitWithError("Failed to load policy", err)
+	}
+
+	result := labelsResult{
+		DryRun:  c.Flags.GetOptionalBool("dry-run"),
+		Changes: labels.Plan(labels.Objects(snapshot), sel, op),
+	}
+	if result.DryRun {
+		result.Failed = labels.Rejected(result.Changes)
+	} else {
+		// without a selector the operation applies to every labeled object in the policy
+		if sel.IsEmpty() && len(result.Changes) > 0 {
+			what := fmt.Sprintf("%d policy objects (no selector given)", len(result.Changes))
+			cli.ConfirmAction(cli.ActionUpdate, "the labels of", what, c.Flags.GetOptionalBool("force"))
+		}
+		result.Failed = labels.Apply(cmd.Context(), result.Changes, concurrency, func(ctx context.Context, ch labels.Change) error {
+			after, err := updateObjectLabels(ctx, h, ch)
+			if err == nil {
+				journalChange(h, journal.OperationUpdate, ch.Kind, labelsBefore(after, ch.Labels), after)
+			}
+			return err
+		})
+	}
+
+	code := cli.ExitCodeSuccess
+	if result.Failed > 0 {
+		code = cli.ExitCodeError
+	}
+	c.ExitWith(renderLabelsResult(result), result, code, os.Stdout)
+}
+
+// updateObjectLabels replaces the labels of an object with the planned ones through the update of its type, returning
+// the object as journaled for its kind
+//
+//nolint:cyclop // a flat dispatch over each object type
+func updateObjectLabels(ctx context.Context, h handlers.Handler, ch labels.Change) (proto.Message, error) {
+	md := &policycommon.MetadataMutable{Labels: ch.After}
+	behavior := policycommon.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE
+
+	switch ch.Kind {
+	case labels.KindNamespace:
+		return h.UpdateNamespace(ctx, ch.ID, md, behavior)
+	case labels.KindAttribute:
+		return h.UpdateAttribute(ctx, ch.ID, md, behavior)
+	case labels.KindValue:
+		return h.UpdateAttributeValue(ctx, ch.ID, md, behavior)
+	case labels.KindSubjectMapping:
+		return h.UpdateSubjectMapping(ctx, ch.ID, "", nil, md, behavior)
+	case labels.KindSubjectConditionSet:
+		return h.UpdateSubjectConditionSet(ctx, ch.ID, nil, md, behavior)
+	case labels.KindAction:
+		return h.UpdateAction(ctx, ch.ID, "", md, behavior)
+	case labels.KindObligation:
+		return h.UpdateObligation(ctx, ch.ID, "", md, behavior)
+	case labels.KindObligationValue:
+		return h.UpdateObligationValue(ctx, ch.ID, "", nil, md, behavior)
+	case labels.KindKeyAccessServer:
+		return h.UpdateKasRegistryEntry(ctx, ch.ID, "", "", md, behavior)
+	case labels.KindKasKey:
+		k, err := h.UpdateKasKey(ctx, ch.ID, md, behavior)
+		return k.GetKey(), err
+	case labels.KindRegisteredResource:
+		return h.UpdateRegisteredResource(ctx, ch.ID, "", md, behavior)
+	case labels.KindRegisteredResourceValue:
+		return h.UpdateRegisteredResourceValue(ctx, ch.ID, "", nil, md, behavior)
+	}
+	return nil, fmt.Errorf("unsupported object type '%s'", ch.Kind)
+}
+
+// labelsBefore is the journaled state of an object before its labels changed: the updated object with its old labels
+func labelsBefore(after proto.Message, old map[string]string) proto.Message {
+	if after == nil || !after.ProtoReflect().IsValid() {
+		return nil
+	}
+	before := proto.Clone(after)
+	r := before.ProtoReflect()
+	f := r.Descriptor().Fields().ByName("metadata")
+	if f == nil || f.Message() == nil {
+		return before
+	}
+	md, ok := r.Mutable(f).Message().Interface().(*policycommon.Metadata)
+	if ok {
+		md.Labels = old
+	}
+	return before
+}
+
+func renderLabelsResult(r labelsResult) string {
+	if len(r.Changes) == 0 {
+		return cli.SuccessMessage("No objects matched whose labels would change")
+	}
+
+	t := cli.NewTable(
+		table.NewFlexColumn("kind", "Kind", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("object", "Object", cli.FlexColumnWidthThree),
+		table.NewFlexColumn("id", "ID", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("before", "Before", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("after", "After", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("result", "Result", cli.FlexColumnWidthOne),
+	)
+	rows := make([]table.Row, 0, len(r.Changes))
+	for _, ch := range r.Changes {
+		res := "updated"
+		switch {
+		case ch.Error != "":
+			res = ch.Error
+		case r.DryRun:
+			res = "dry run"
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"kind":   ch.Kind,
+			"object": ch.Name,
+			"id":     ch.ID,
+			"before": formatLabels(ch.Labels),
+			"after":  formatLabels(ch.After),
+			"result": res,
+		}))
+	}
+	t = t.WithRows(rows)
+
+	switch {
+	case r.DryRun:
+		return t.View() + "\n" + cli.WarningMessage(fmt.Sprintf("Dry run: %d objects would be updated, %d rejected",
+			len(r.Changes)-r.Failed, r.Failed))
+	case r.Failed > 0:
+		return t.View() + "\n" + cli.ErrorMessage(fmt.Sprintf("%d of %d objects failed to update", r.Failed, len(r.Changes)), nil)
+	default:
+		return t.View() + "\n" + cli.SuccessMessage(fmt.Sprintf("Updated the labels of %d objects", len(r.Changes)))
+	}
+}
+
+func formatLabels(l map[string]string) string {
+	out := make([]string, 0, len(l))
+	for k, v := range l {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return strings.Join(out, ", ")
+}
+
+func injectLabelSelectorFlags(doc *man.Doc) {
+	doc.Flags().StringSlice(
+		doc.GetDocFlag("kind").Name,
+		[]string{},
+		doc.GetDocFlag("kind").Description,
+	)
+	injectNamespaceFlag(doc)
+	doc.Flags().String(
+		doc.GetDocFlag("fqn").Name,
+		doc.GetDocFlag("fqn").Default,
+		doc.GetDocFlag("fqn").Description,
+	)
+	doc.Flags().StringSlice(
+		doc.GetDocFlag("has-label").Name,
+		[]string{},
+		doc.GetDocFlag("has-label").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("dry-run").Name,
+		false,
+		doc.GetDocFlag("dry-run").Description,
+	)
+	doc.Flags().Bool(
+		doc.GetDocFlag("force").Name,
+		false,
+		doc.GetDocFlag("force").Description,
+	)
+	doc.Flags().Int(
+		doc.GetDocFlag("concurrency").Name,
+		defaultLabelsConcurrency,
+		doc.GetDocFlag("concurrency").Description,
+	)
+}
+
+func initLabelsCommands() {
+	addDoc := man.Docs.GetCommand("policy/labels/add", man.WithRun(policyAddLabels))
+	injectLabelSelectorFlags(addDoc)
+
+	removeDoc := man.Docs.GetCommand("policy/labels/remove", man.WithRun(policyRemoveLabels))
+	injectLabelSelectorFlags(removeDoc)
+
+	renameDoc := man.Docs.GetCommand("policy/labels/rename", man.WithRun(policyRenameLabel))
+	injectLabelSelectorFlags(renameDoc)
+
+	labelsDoc := man.Docs.GetCommand("policy/labels",
+		man.WithSubcommands(addDoc, removeDoc, renameDoc),
+	)
+	Cmd.AddCommand(&labelsDoc.Command)
+}
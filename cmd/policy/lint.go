@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/lint"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/spf13/cobra"
+)
+
+const lintFailOnNone = "none"
+
+type lintResult struct {
+	Findings []lint.Finding        `json:"findings"`
+	Summary  map[lint.Severity]int `json:"summary"`
+}
+
+func policyLint(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	failOn := c.Flags.GetOptionalString("fail-on")
+	var threshold lint.Severity
+	if failOn != lintFailOnNone {
+		var err error
+		if threshold, err = lint.ParseSeverity(failOn); err != nil {
+			c.ExitWithError("Invalid --fail-on", err)
+		}
+	}
+
+	ignore, err := cmd.Flags().GetStringSlice("ignore")
+	if err != nil {
+		c.ExitWithError("Invalid --ignore", err)
+	}
+	for _, r := range ignore {
+		if !isLintRule(r) {
+			c.ExitWithError(fmt.Sprintf("Unknown lint rule '%s'", r), nil)
+		}
+	}
+
+	snapshot, err := h.LoadPolicySnapshot(cmd.Context())
+	if err != nil {
+		c.ExitWithError("Failed to load policy", err)
+	}
+
+	findings := lint.Run(snapshot, lint.Options{Ignore: ignore})
+	result := lintResult{Findings: findings, Summary: lint.Summarize(findings)}
+
+	code := cli.ExitCodeSuccess
+	if threshold != "" && lint.Exceeds(findings, threshold) {
+		code = cli.ExitCodeError
+	}
+	c.ExitWith(renderLintFindings(result), result, code, os.Stdout)
+}
+
+func isLintRule(name string) bool {
+	for _, r := range lint.Rules {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func renderLintFindings(r lintResult) string {
+	if len(r.Findings) == 0 {
+		return cli.SuccessMessage("No findings")
+	}
+
+	t := cli.NewTable(
+		table.NewFlexColumn("severity", "Severity", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("rule", "Rule", cli.FlexColumnWidthTwo),
+		table.NewFlexColumn("object", "Object", cli.FlexColumnWidthThree),
+		table.NewFlexColumn("message", "Message", cli.FlexColumnWidthFour),
+	)
+	rows := make([]table.Row, 0, len(r.Findings))
+	for _, f := range r.Findings {
+		obj := f.Object.String()
+		if f.Object.Name != "" {
+			obj += " (" + f.Object.ID + ")"
+		}
+		msg := f.Message
+		if len(f.Related) > 0 {
+			related := make([]string, 0, len(f.Related))
+			for _, rel := range f.Related {
+				related = append(related, rel.String())
+			}
+			msg += "; related: " + strings.Join(related, ", ")
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"severity": strings.ToUpper(string(f.Severity)),
+			"rule":     f.Rule,
+			"object":   obj,
+			"message":  msg,
+		}))
+	}
+	t = t.WithRows(rows)
+
+	summary := fmt.Sprintf("%d errors, %d warnings, %d info",
+		r.Summary[lint.SeverityError], r.Summary[lint.SeverityWarning], r.Summary[lint.SeverityInfo])
+	if r.Summary[lint.SeverityError] > 0 {
+		return t.View() + "\n" + cli.ErrorMessage(summary, nil)
+	}
+	return t.View() + "\n" + cli.WarningMessage(summary)
+}
+
+func initLintCommands() {
+	doc := man.Docs.GetCommand("policy/lint", man.WithRun(policyLint))
+	doc.Flags().String(
+		doc.GetDocFlag("fail-on").Name,
+		doc.GetDocFlag("fail-on").Default,
+		doc.GetDocFlag("fail-on").Description,
+	)
+	doc.Flags().StringSlice(
+		doc.GetDocFlag("ignore").Name,
+		[]string{},
+		doc.GetDocFlag("ignore").Description,
+	)
+	Cmd.AddCommand(&doc.Command)
+}
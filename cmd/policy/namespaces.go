@@ -7,7 +7,11 @@ import (
 	"github.com/evertras/bubble-table/table"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/impact"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/platform/protocol/go/policy/namespaces"
 	"github.com/spf13/cobra"
 )
 
@@ -89,6 +93,7 @@ func createAttributeNamespace(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create namespace", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindNamespace, nil, created)
 	rows := [][]string{
 		{"Name", name},
 		{"Id", created.GetId()},
@@ -116,13 +121,15 @@ func deactivateAttributeNamespace(cmd *cobra.Command, args []string) {
 		cli.ExitWithError(errMsg, err)
 	}
 
-	cli.ConfirmAction(cli.ActionDeactivate, "namespace", ns.GetName(), force)
+	impactText := policyImpact(c, h, impact.OperationDeactivate, labels.KindNamespace, id, !force)
+	cli.ConfirmActionSubtext(cli.ActionDeactivate, "namespace", ns.GetName(), impactText, force)
 
 	d, err := h.DeactivateNamespace(ctx, id)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to deactivate namespace (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDeactivate, labels.KindNamespace, ns, d)
 	rows := [][]string{
 		{"Id", ns.GetId()},
 		{"Name", ns.GetName()},
@@ -142,6 +149,11 @@ func updateAttributeNamespace(cmd *cobra.Command, args []string) {
 	id := c.Flags.GetRequiredID("id")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
+	before, err := h.GetNamespace(cmd.Context(), id)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to find namespace (%s)", id), err)
+	}
+
 	ns, err := h.UpdateNamespace(
 		cmd.Context(),
 		id,
@@ -151,6 +163,7 @@ func updateAttributeNamespace(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to update namespace (%s)", id), err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindNamespace, before, ns)
 	rows := [][]string{
 		{"Id", ns.GetId()},
 		{"Name", ns.GetName()},
@@ -177,14 +190,16 @@ func unsafeDeleteAttributeNamespace(cmd *cobra.Command, args []string) {
 		cli.ExitWithError(errMsg, err)
 	}
 
+	impactText := policyImpact(c, h, impact.OperationDelete, labels.KindNamespace, id, !forceUnsafe)
 	if !forceUnsafe {
-		cli.ConfirmTextInput(cli.ActionDelete, "namespace", cli.InputNameFQN, ns.GetFqn())
+		cli.ConfirmTextInputSubtext(cli.ActionDelete, "namespace", cli.InputNameFQN, ns.GetFqn(), impactText)
 	}
 
 	if err := h.UnsafeDeleteNamespace(ctx, id, ns.GetFqn()); err != nil {
 		errMsg := fmt.Sprintf("Failed to delete namespace (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindNamespace, ns, nil)
 
 	rows := [][]string{
 		{"Id", ns.GetId()},
@@ -215,11 +230,13 @@ func unsafeReactivateAttributeNamespace(cmd *cobra.Command, args []string) {
 		cli.ConfirmTextInput(cli.ActionReactivate, "namespace", cli.InputNameFQN, ns.GetFqn())
 	}
 
+	before := ns
 	ns, err = h.UnsafeReactivateNamespace(ctx, id)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to reactivate namespace (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationReactivate, labels.KindNamespace, before, ns)
 
 	rows := [][]string{
 		{"Id", ns.GetId()},
@@ -251,11 +268,13 @@ func unsafeUpdateAttributeNamespace(cmd *cobra.Command, args []string) {
 		cli.ConfirmTextInput(cli.ActionUpdateUnsafe, "namespace", cli.InputNameFQNUpdated, ns.GetFqn())
 	}
 
+	before := ns
 	ns, err = h.UnsafeUpdateNamespace(ctx, id, name)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to reactivate namespace (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationUnsafeUpdate, labels.KindNamespace, before, ns)
 
 	rows := [][]string{
 		{"Id", ns.GetId()},
@@ -282,6 +301,7 @@ func policyAssignKeyToNamespace(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to assign key: (%s) to attribute namespace: (%s)", keyID, namespace)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationAssign, kindNamespaceKey, nil, attrKey)
 
 	// Prepare and display the result
 	rows := [][]string{
@@ -305,6 +325,7 @@ func policyRemoveKeyFromNamespace(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to remove key (%s) from attribute namespace (%s)", keyID, namespace)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationUnassign, kindNamespaceKey, &namespaces.NamespaceKey{NamespaceId: namespace, KeyId: keyID}, nil)
 
 	// Prepare and display the result
 	rows := [][]string{
@@ -400,6 +421,11 @@ func buildNamespacesCommandTree() *cobra.Command {
 		false,
 		deactivateDoc.GetDocFlag("force").Description,
 	)
+	deactivateCmd.Flags().Bool(
+		deactivateDoc.GetDocFlag("what-if").Name,
+		false,
+		deactivateDoc.GetDocFlag("what-if").Description,
+	)
 
 	// unsafe
 	unsafeDoc := man.Docs.GetDoc("policy/namespaces/unsafe")
@@ -419,6 +445,11 @@ func buildNamespacesCommandTree() *cobra.Command {
 		deleteDoc.GetDocFlag("id").Default,
 		deleteDoc.GetDocFlag("id").Description,
 	)
+	deleteCmd.Flags().Bool(
+		deleteDoc.GetDocFlag("what-if").Name,
+		false,
+		deleteDoc.GetDocFlag("what-if").Description,
+	)
 
 	reactivateDoc := man.Docs.GetDoc("policy/namespaces/unsafe/reactivate")
 	reactivateCmd := newCommandFromDoc(reactivateDoc, unsafeReactivateAttributeNamespace)
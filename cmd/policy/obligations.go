@@ -11,6 +11,8 @@ import (
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
 	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/opentdf/platform/protocol/go/policy/obligations"
@@ -42,6 +44,7 @@ func policyCreateObligation(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create obligation", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindObligation, nil, obl)
 
 	simpleObligationValues := cli.GetSimpleObligationValues(obl.GetValues())
 
@@ -134,6 +137,11 @@ func policyUpdateObligation(cmd *cobra.Command, args []string) {
 	name := c.Flags.GetOptionalString("name")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
+	before, err := h.GetObligation(cmd.Context(), id, "")
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to find obligation (%s)", id), err)
+	}
+
 	updated, err := h.UpdateObligation(
 		cmd.Context(),
 		id,
@@ -144,6 +152,7 @@ func policyUpdateObligation(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to update obligation", err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindObligation, before, updated)
 
 	rows := [][]string{
 		{"Id", id},
@@ -185,6 +194,7 @@ func policyDeleteObligation(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to delete obligation (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindObligation, obl, nil)
 
 	rows := [][]string{
 		{"Id", id},
@@ -223,6 +233,7 @@ func policyCreateObligationValue(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create obligation value", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindObligationValue, nil, oblVal)
 
 	rows := [][]string{
 		{"Id", oblVal.GetId()},
@@ -286,6 +297,11 @@ func policyUpdateObligationValue(cmd *cobra.Command, args []string) {
 		cli.ExitWithError("Invalid trigger configuration", err)
 	}
 
+	before, err := h.GetObligationValue(cmd.Context(), id, "")
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to find obligation value (%s)", id), err)
+	}
+
 	updated, err := h.UpdateObligationValue(
 		cmd.Context(),
 		id,
@@ -297,6 +313,7 @@ func policyUpdateObligationValue(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to update obligation value", err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindObligationValue, before, updated)
 
 	rows := [][]string{
 		{"Id", id},
@@ -340,6 +357,7 @@ func policyDeleteObligationValue(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to delete obligation value (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindObligationValue, val, nil)
 
 	rows := [][]string{
 		{"Id", id},
@@ -372,6 +390,7 @@ func policyCreateObligationTrigger(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create obligation trigger", err)
 	}
+	journalChange(h, journal.OperationCreate, kindObligationTrigger, nil, trigger)
 
 	rows := getObligationTriggerRows(trigger)
 	t := cli.NewTabular(rows...)
@@ -394,6 +413,7 @@ func policyDeleteObligationTrigger(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to delete obligation trigger (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, kindObligationTrigger, resp, nil)
 
 	rows := [][]string{
 		{"Id", id},
@@ -102,4 +102,10 @@ func InitCommands() {
 	initKASKeysCommands()
 	initKASGrantsCommands()
 	initBaseKeysCommands()
+	initLintCommands()
+	initGraphCommands()
+	initDiffCommands()
+	initTestCommands()
+	initLabelsCommands()
+	initHistoryCommands()
 }
@@ -8,6 +8,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/opentdf/platform/protocol/go/policy/registeredresources"
 	"github.com/spf13/cobra"
@@ -38,6 +40,7 @@ func policyCreateRegisteredResource(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create registered resource", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindRegisteredResource, nil, resource)
 
 	simpleRegResValues := cli.GetSimpleRegisteredResourceValues(resource.GetValues())
 
@@ -139,6 +142,11 @@ func policyUpdateRegisteredResource(cmd *cobra.Command, args []string) {
 	name := c.Flags.GetOptionalString("name")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
+	before, err := h.GetRegisteredResource(cmd.Context(), id, "", "")
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to find registered resource (%s)", id), err)
+	}
+
 	updated, err := h.UpdateRegisteredResource(
 		cmd.Context(),
 		id,
@@ -149,6 +157,7 @@ func policyUpdateRegisteredResource(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to update registered resource", err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindRegisteredResource, before, updated)
 
 	rows := [][]string{
 		{"Id", id},
@@ -185,6 +194,7 @@ func policyDeleteRegisteredResource(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to delete registered resource (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindRegisteredResource, resource, nil)
 
 	rows := [][]string{
 		{"Id", id},
@@ -233,6 +243,7 @@ func policyCreateRegisteredResourceValue(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create registered resource value", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindRegisteredResourceValue, nil, resourceValue)
 
 	simpleActionAttributeValues := cli.GetSimpleRegisteredResourceActionAttributeValues(resourceValue.GetActionAttributeValues())
 
@@ -354,6 +365,11 @@ func policyUpdateRegisteredResourceValue(cmd *cobra.Command, args []string) {
 			force)
 	}
 
+	before, err := h.GetRegisteredResourceValue(cmd.Context(), id, "")
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to find registered resource value (%s)", id), err)
+	}
+
 	updated, err := h.UpdateRegisteredResourceValue(
 		cmd.Context(),
 		id,
@@ -365,6 +381,7 @@ func policyUpdateRegisteredResourceValue(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to update registered resource value", err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindRegisteredResourceValue, before, updated)
 
 	simpleActionAttributeValues := cli.GetSimpleRegisteredResourceActionAttributeValues(updated.GetActionAttributeValues())
 
@@ -403,6 +420,7 @@ func policyDeleteRegisteredResourceValue(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to delete registered resource value (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindRegisteredResourceValue, resource, nil)
 
 	rows := [][]string{
 		{"Id", id},
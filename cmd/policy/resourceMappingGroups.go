@@ -6,6 +6,7 @@ import (
 	"github.com/evertras/bubble-table/table"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/journal"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/spf13/cobra"
 )
@@ -27,6 +28,7 @@ func policyCreateResourceMappingGroup(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create resource mapping group", err)
 	}
+	journalChange(h, journal.OperationCreate, kindResourceMappingGroup, nil, resourceMappingGroup)
 	rows := [][]string{
 		{"Id", resourceMappingGroup.GetId()},
 		{"Namespace Id", resourceMappingGroup.GetNamespaceId()},
@@ -110,10 +112,16 @@ func policyUpdateResourceMappingGroup(cmd *cobra.Command, args []string) {
 	name := c.Flags.GetOptionalString("name")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
+	before, err := h.GetResourceMappingGroup(cmd.Context(), id)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to get resource mapping group (%s)", id), err)
+	}
+
 	resourceMappingGroup, err := h.UpdateResourceMappingGroup(cmd.Context(), id, nsID, name, getMetadataMutable(metadataLabels), getMetadataUpdateBehavior())
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to update resource mapping group (%s)", id), err)
 	}
+	journalChange(h, journal.OperationUpdate, kindResourceMappingGroup, before, resourceMappingGroup)
 	rows := [][]string{
 		{"Id", resourceMappingGroup.GetId()},
 		{"Namespace Id", resourceMappingGroup.GetNamespaceId()},
@@ -145,6 +153,7 @@ func policyDeleteResourceMappingGroup(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to delete resource mapping group (%s)", id), err)
 	}
+	journalChange(h, journal.OperationDelete, kindResourceMappingGroup, resourceMappingGroup, nil)
 	rows := [][]string{
 		{"Id", resourceMappingGroup.GetId()},
 		{"Namespace Id", resourceMappingGroup.GetNamespaceId()},
@@ -8,6 +8,7 @@ import (
 	"github.com/evertras/bubble-table/table"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/journal"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/spf13/cobra"
 )
@@ -33,6 +34,7 @@ func createResourceMapping(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to create resource mapping", err)
 	}
+	journalChange(h, journal.OperationCreate, kindResourceMapping, nil, resourceMapping)
 	rows := [][]string{
 		{"Id", resourceMapping.GetId()},
 		{"Attribute Value Id", resourceMapping.GetAttributeValue().GetId()},
@@ -129,10 +131,16 @@ func updateResourceMapping(cmd *cobra.Command, args []string) {
 	terms = c.Flags.GetStringSlice("terms", terms, cli.FlagsStringSliceOptions{})
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
 
+	before, err := h.GetResourceMapping(id)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to get resource mapping (%s)", id), err)
+	}
+
 	resourceMapping, err := h.UpdateResourceMapping(id, attrValueID, grpID, terms, getMetadataMutable(metadataLabels), getMetadataUpdateBehavior())
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to update resource mapping (%s)", id), err)
 	}
+	journalChange(h, journal.OperationUpdate, kindResourceMapping, before, resourceMapping)
 	rows := [][]string{
 		{"Id", resourceMapping.GetId()},
 		{"Attribute Value Id", resourceMapping.GetAttributeValue().GetId()},
@@ -167,6 +175,7 @@ func deleteResourceMapping(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError(fmt.Sprintf("Failed to delete resource mapping (%s)", id), err)
 	}
+	journalChange(h, journal.OperationDelete, kindResourceMapping, resourceMapping, nil)
 	rows := [][]string{
 		{"Id", resourceMapping.GetId()},
 		{"Attribute Value Id", resourceMapping.GetAttributeValue().GetId()},
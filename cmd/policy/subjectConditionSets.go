@@ -9,7 +9,11 @@ import (
 	"github.com/evertras/bubble-table/table"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/conditions"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
+	forms "github.com/opentdf/otdfctl/tui/form"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/spf13/cobra"
 	"google.golang.org/protobuf/encoding/protojson"
@@ -49,25 +53,48 @@ func marshalSubjectSetsProto(subjectSet []*policy.SubjectSet) ([]byte, error) {
 	return json.Marshal(raw)
 }
 
-func createSubjectConditionSet(cmd *cobra.Command, args []string) {
-	c := cli.New(cmd, args)
-	h := common.NewHandler(c)
-	defer h.Close()
-	var ssBytes []byte
-
+// getSubjectSetsFromFlags reads the subject sets from exactly one of the JSON, JSON file or conditions expression
+// flags, or builds them interactively starting from the current subject sets
+func getSubjectSetsFromFlags(c *cli.Cli, current []*policy.SubjectSet) []*policy.SubjectSet {
 	ssFlagJSON := c.Flags.GetOptionalString("subject-sets")
 	ssFileJSON := c.Flags.GetOptionalString("subject-sets-file-json")
-	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
-	namespace := c.Flags.GetOptionalString("namespace")
+	expr := c.Flags.GetOptionalString("conditions")
+	interactive := c.Flags.GetOptionalBool("interactive")
 
 	// validate no flag conflicts
-	if ssFileJSON == "" && ssFlagJSON == "" {
-		cli.ExitWithError("At least one subject set must be provided ('--subject-sets', '--subject-sets-file-json')", nil)
-	} else if ssFileJSON != "" && ssFlagJSON != "" {
-		cli.ExitWithError("Only one of '--subject-sets' or '--subject-sets-file-json' can be provided", nil)
+	provided := 0
+	for _, v := range []string{ssFlagJSON, ssFileJSON, expr} {
+		if v != "" {
+			provided++
+		}
+	}
+	if interactive {
+		provided++
+	}
+	if provided == 0 {
+		cli.ExitWithError("At least one subject set must be provided ('--subject-sets', '--subject-sets-file-json', '--conditions', '--interactive')", nil)
+	} else if provided > 1 {
+		cli.ExitWithError("Only one of '--subject-sets', '--subject-sets-file-json', '--conditions' or '--interactive' can be provided", nil)
+	}
+
+	if interactive {
+		ss, err := forms.BuildSubjectConditionSet(current)
+		if err != nil {
+			cli.ExitWithError("Subject condition set not submitted", err)
+		}
+		return ss
+	}
+
+	if expr != "" {
+		ss, err := conditions.Parse(expr)
+		if err != nil {
+			cli.ExitWithError("Error parsing conditions", err)
+		}
+		return ss
 	}
 
 	// read subject sets into bytes from either the flagged json file or json string
+	ssBytes := []byte(ssFlagJSON)
 	if ssFileJSON != "" {
 		jsonFile, err := os.Open(ssFileJSON)
 		if err != nil {
@@ -80,19 +107,29 @@ func createSubjectConditionSet(cmd *cobra.Command, args []string) {
 			cli.ExitWithError(fmt.Sprintf("Failed to read bytes from file at path: %s", ssFileJSON), err)
 		}
 		ssBytes = bytes
-	} else {
-		ssBytes = []byte(ssFlagJSON)
 	}
 
 	ss, err := unmarshalSubjectSetsProto(ssBytes)
 	if err != nil {
 		cli.ExitWithError("Error unmarshalling subject sets", err)
 	}
+	return ss
+}
+
+func createSubjectConditionSet(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
+	namespace := c.Flags.GetOptionalString("namespace")
+
+	ss := getSubjectSetsFromFlags(c, nil)
 
 	scs, err := h.CreateSubjectConditionSet(cmd.Context(), ss, getMetadataMutable(metadataLabels), namespace)
 	if err != nil {
 		cli.ExitWithError("Error creating subject condition set", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindSubjectConditionSet, nil, scs)
 
 	subjectSetsJSON, err := marshalSubjectSetsProto(scs.GetSubjectSets())
 	if err != nil {
@@ -103,6 +140,7 @@ func createSubjectConditionSet(cmd *cobra.Command, args []string) {
 		{"Id", scs.GetId()},
 		{"Namespace", scs.GetNamespace().GetFqn()},
 		{"SubjectSets", string(subjectSetsJSON)},
+		{"Conditions", conditions.Format(scs.GetSubjectSets())},
 	}
 
 	if mdRows := getMetadataRows(scs.GetMetadata()); mdRows != nil {
@@ -133,6 +171,7 @@ func getSubjectConditionSet(cmd *cobra.Command, args []string) {
 		{"Id", scs.GetId()},
 		{"Namespace", scs.GetNamespace().GetFqn()},
 		{"SubjectSets", string(subjectSetsJSON)},
+		{"Conditions", conditions.Format(scs.GetSubjectSets())},
 	}
 	if mdRows := getMetadataRows(scs.GetMetadata()); mdRows != nil {
 		rows = append(rows, mdRows...)
@@ -160,6 +199,7 @@ func listSubjectConditionSets(cmd *cobra.Command, args []string) {
 		cli.NewUUIDColumn(),
 		table.NewFlexColumn("namespace", "Namespace", cli.FlexColumnWidthFour),
 		table.NewFlexColumn("subject_sets", "SubjectSets", cli.FlexColumnWidthFour),
+		table.NewFlexColumn("conditions", "Conditions", cli.FlexColumnWidthFour),
 		table.NewFlexColumn("labels", "Labels", cli.FlexColumnWidthOne),
 		table.NewFlexColumn("created_at", "Created At", cli.FlexColumnWidthOne),
 		table.NewFlexColumn("updated_at", "Updated At", cli.FlexColumnWidthOne),
@@ -175,6 +215,7 @@ func listSubjectConditionSets(cmd *cobra.Command, args []string) {
 			"id":           scs.GetId(),
 			"namespace":    scs.GetNamespace().GetFqn(),
 			"subject_sets": string(subjectSetsJSON),
+			"conditions":   conditions.Format(scs.GetSubjectSets()),
 			"labels":       metadata["Labels"],
 			"created_at":   metadata["Created At"],
 			"updated_at":   metadata["Updated At"],
@@ -193,38 +234,15 @@ func updateSubjectConditionSet(cmd *cobra.Command, args []string) {
 	ctx := cmd.Context()
 	id := c.Flags.GetRequiredID("id")
 	metadataLabels = c.Flags.GetStringSlice("label", metadataLabels, cli.FlagsStringSliceOptions{Min: 0})
-	ssFlagJSON := c.Flags.GetOptionalString("subject-sets")
-	ssFileJSON := c.Flags.GetOptionalString("subject-sets-file-json")
-
-	var ssBytes []byte
-	// validate no flag conflicts
-	if ssFileJSON == "" && ssFlagJSON == "" {
-		cli.ExitWithError("At least one subject set must be provided ('--subject-sets', '--subject-sets-file-json')", nil)
-	} else if ssFileJSON != "" && ssFlagJSON != "" {
-		cli.ExitWithError("Only one of '--subject-sets' or '--subject-sets-file-json' can be provided", nil)
-	}
-
-	// read subject sets into bytes from either the flagged json file or json string
-	if ssFileJSON != "" {
-		jsonFile, err := os.Open(ssFileJSON)
-		if err != nil {
-			cli.ExitWithError(fmt.Sprintf("Failed to open file at path: %s", ssFileJSON), err)
-		}
-		defer jsonFile.Close()
-
-		bytes, err := io.ReadAll(jsonFile)
-		if err != nil {
-			cli.ExitWithError(fmt.Sprintf("Failed to read bytes from file at path: %s", ssFileJSON), err)
-		}
-		ssBytes = bytes
-	} else {
-		ssBytes = []byte(ssFlagJSON)
-	}
-
-	ss, err := unmarshalSubjectSetsProto(ssBytes)
+	existing, err := h.GetSubjectConditionSet(ctx, id)
 	if err != nil {
-		cli.ExitWithError("Error unmarshalling subject sets", err)
+		cli.ExitWithError(fmt.Sprintf("Subject Condition Set with id %s not found", id), err)
 	}
+	var current []*policy.SubjectSet
+	if c.Flags.GetOptionalBool("interactive") {
+		current = existing.GetSubjectSets()
+	}
+	ss := getSubjectSetsFromFlags(c, current)
 
 	_, err = h.UpdateSubjectConditionSet(ctx, id, ss, getMetadataMutable(metadataLabels), getMetadataUpdateBehavior())
 	if err != nil {
@@ -235,6 +253,7 @@ func updateSubjectConditionSet(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Error getting subject condition set", err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindSubjectConditionSet, existing, scs)
 
 	subjectSetsJSON, err := marshalSubjectSetsProto(scs.GetSubjectSets())
 	if err != nil {
@@ -244,6 +263,7 @@ func updateSubjectConditionSet(cmd *cobra.Command, args []string) {
 	rows := [][]string{
 		{"Id", scs.GetId()},
 		{"SubjectSets", string(subjectSetsJSON)},
+		{"Conditions", conditions.Format(scs.GetSubjectSets())},
 	}
 
 	if mdRows := getMetadataRows(scs.GetMetadata()); mdRows != nil {
@@ -273,6 +293,7 @@ func deleteSubjectConditionSet(cmd *cobra.Command, args []string) {
 	if err := h.DeleteSubjectConditionSet(ctx, id); err != nil {
 		cli.ExitWithError(fmt.Sprintf("Subject Condition Set with id %s not found", id), err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindSubjectConditionSet, scs, nil)
 
 	subjectSetsJSON, err := marshalSubjectSetsProto(scs.GetSubjectSets())
 	if err != nil {
@@ -282,6 +303,7 @@ func deleteSubjectConditionSet(cmd *cobra.Command, args []string) {
 	rows := [][]string{
 		{"Id", scs.GetId()},
 		{"SubjectSets", string(subjectSetsJSON)},
+		{"Conditions", conditions.Format(scs.GetSubjectSets())},
 	}
 
 	if mdRows := getMetadataRows(scs.GetMetadata()); mdRows != nil {
@@ -308,6 +330,7 @@ func pruneSubjectConditionSet(cmd *cobra.Command, args []string) {
 
 	rows := []table.Row{}
 	for _, scs := range pruned {
+		journalChange(h, journal.OperationDelete, labels.KindSubjectConditionSet, scs, nil)
 		rows = append(rows, table.NewRow(table.RowData{
 			"id": scs.GetId(),
 		}))
@@ -339,6 +362,16 @@ func initSubjectConditionSetsCommands() {
 		createDoc.GetDocFlag("subject-sets-file-json").Default,
 		createDoc.GetDocFlag("subject-sets-file-json").Description,
 	)
+	createDoc.Flags().String(
+		createDoc.GetDocFlag("conditions").Name,
+		createDoc.GetDocFlag("conditions").Default,
+		createDoc.GetDocFlag("conditions").Description,
+	)
+	createDoc.Flags().Bool(
+		createDoc.GetDocFlag("interactive").Name,
+		false,
+		createDoc.GetDocFlag("interactive").Description,
+	)
 	createDoc.Flags().StringP(
 		createDoc.GetDocFlag("namespace").Name,
 		createDoc.GetDocFlag("namespace").Shorthand,
@@ -389,6 +422,16 @@ func initSubjectConditionSetsCommands() {
 		createDoc.GetDocFlag("subject-sets-file-json").Default,
 		createDoc.GetDocFlag("subject-sets-file-json").Description,
 	)
+	updateDoc.Flags().String(
+		updateDoc.GetDocFlag("conditions").Name,
+		updateDoc.GetDocFlag("conditions").Default,
+		updateDoc.GetDocFlag("conditions").Description,
+	)
+	updateDoc.Flags().Bool(
+		updateDoc.GetDocFlag("interactive").Name,
+		false,
+		updateDoc.GetDocFlag("interactive").Description,
+	)
 
 	deleteDoc := man.Docs.GetCommand(
 		"policy/subject-condition-sets/delete",
@@ -8,7 +8,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/opentdf/otdfctl/cmd/common"
 	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/conditions"
 	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/otdfctl/pkg/man"
 	"github.com/opentdf/platform/protocol/go/policy"
 	"github.com/opentdf/platform/protocol/go/policy/subjectmapping"
@@ -50,6 +53,7 @@ func policyGetSubjectMapping(cmd *cobra.Command, args []string) {
 		{"Actions", string(actionsJSON)},
 		{"Subject Condition Set: Id", mapping.GetSubjectConditionSet().GetId()},
 		{"Subject Condition Set", string(subjectSetsJSON)},
+		{"Subject Condition Set: Conditions", conditions.Format(mapping.GetSubjectConditionSet().GetSubjectSets())},
 	}
 	if mdRows := getMetadataRows(mapping.GetMetadata()); mdRows != nil {
 		rows = append(rows, mdRows...)
@@ -119,14 +123,18 @@ func policyCreateSubjectMapping(cmd *cobra.Command, args []string) {
 	existingSCSId := c.Flags.GetOptionalID("subject-condition-set-id")
 	// NOTE: labels within a new Subject Condition Set created on a SM creation are not supported
 	newScsJSON := c.Flags.GetOptionalString("subject-condition-set-new")
+	newScsExpr := c.Flags.GetOptionalString("subject-condition-set-conditions")
 	namespace := c.Flags.GetOptionalString("namespace")
 
 	// validations
 	if len(actionFlagValues) == 0 {
 		cli.ExitWithError("At least one Action [--action] is required", nil)
 	}
-	if existingSCSId == "" && newScsJSON == "" {
-		cli.ExitWithError("At least one Subject Condition Set flag [--subject-condition-set-id, --subject-condition-set-new] must be provided", nil)
+	if existingSCSId == "" && newScsJSON == "" && newScsExpr == "" {
+		cli.ExitWithError("At least one Subject Condition Set flag [--subject-condition-set-id, --subject-condition-set-new, --subject-condition-set-conditions] must be provided", nil)
+	}
+	if newScsJSON != "" && newScsExpr != "" {
+		cli.ExitWithError("Only one of '--subject-condition-set-new' or '--subject-condition-set-conditions' can be provided", nil)
 	}
 
 	actions := make([]*policy.Action, len(actionFlagValues))
@@ -150,12 +158,21 @@ func policyCreateSubjectMapping(cmd *cobra.Command, args []string) {
 		scs = &subjectmapping.SubjectConditionSetCreate{
 			SubjectSets: ss,
 		}
+	} else if newScsExpr != "" {
+		ss, err := conditions.Parse(newScsExpr)
+		if err != nil {
+			cli.ExitWithError("Error parsing subject condition set conditions", err)
+		}
+		scs = &subjectmapping.SubjectConditionSetCreate{
+			SubjectSets: ss,
+		}
 	}
 
 	mapping, err := h.CreateNewSubjectMapping(cmd.Context(), attrValueID, actions, existingSCSId, scs, getMetadataMutable(metadataLabels), namespace)
 	if err != nil {
 		cli.ExitWithError("Failed to create subject mapping", err)
 	}
+	journalChange(h, journal.OperationCreate, labels.KindSubjectMapping, nil, mapping)
 
 	var actionsJSON []byte
 	if actionsJSON, err = json.Marshal(mapping.GetActions()); err != nil {
@@ -176,6 +193,7 @@ func policyCreateSubjectMapping(cmd *cobra.Command, args []string) {
 		{"Actions", string(actionsJSON)},
 		{"Subject Condition Set: Id", mapping.GetSubjectConditionSet().GetId()},
 		{"Subject Condition Set", string(subjectSetsJSON)},
+		{"Subject Condition Set: Conditions", conditions.Format(mapping.GetSubjectConditionSet().GetSubjectSets())},
 	}
 
 	if mdRows := getMetadataRows(mapping.GetMetadata()); mdRows != nil {
@@ -207,6 +225,7 @@ func policyDeleteSubjectMapping(cmd *cobra.Command, args []string) {
 		errMsg := fmt.Sprintf("Failed to delete subject mapping (%s)", id)
 		cli.ExitWithError(errMsg, err)
 	}
+	journalChange(h, journal.OperationDelete, labels.KindSubjectMapping, sm, nil)
 	rows := [][]string{{"Id", sm.GetId()}}
 	if mdRows := getMetadataRows(deleted.GetMetadata()); mdRows != nil {
 		rows = append(rows, mdRows...)
@@ -239,6 +258,11 @@ func policyUpdateSubjectMapping(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	before, err := h.GetSubjectMapping(cmd.Context(), id)
+	if err != nil {
+		cli.ExitWithError(fmt.Sprintf("Failed to find subject mapping (%s)", id), err)
+	}
+
 	updated, err := h.UpdateSubjectMapping(
 		cmd.Context(),
 		id,
@@ -250,6 +274,7 @@ func policyUpdateSubjectMapping(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cli.ExitWithError("Failed to update subject mapping", err)
 	}
+	journalChange(h, journal.OperationUpdate, labels.KindSubjectMapping, before, updated)
 	rows := [][]string{
 		{"Id", id},
 	}
@@ -389,6 +414,11 @@ func initSubjectMappingsCommands() {
 		createDoc.GetDocFlag("subject-condition-set-new").Default,
 		createDoc.GetDocFlag("subject-condition-set-new").Description,
 	)
+	createDoc.Flags().String(
+		createDoc.GetDocFlag("subject-condition-set-conditions").Name,
+		createDoc.GetDocFlag("subject-condition-set-conditions").Default,
+		createDoc.GetDocFlag("subject-condition-set-conditions").Description,
+	)
 	createDoc.Flags().StringP(
 		createDoc.GetDocFlag("namespace").Name,
 		createDoc.GetDocFlag("namespace").Shorthand,
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/evertras/bubble-table/table"
+	"github.com/opentdf/otdfctl/cmd/common"
+	"github.com/opentdf/otdfctl/pkg/cli"
+	"github.com/opentdf/otdfctl/pkg/man"
+	"github.com/opentdf/otdfctl/pkg/policytest"
+	"github.com/spf13/cobra"
+)
+
+func policyTest(cmd *cobra.Command, args []string) {
+	c := cli.New(cmd, args)
+	h := common.NewHandler(c)
+	defer h.Close()
+
+	local := c.Flags.GetOptionalBool("local")
+	junitPath := c.Flags.GetOptionalString("junit")
+
+	suite, err := policytest.Load(args[0])
+	if err != nil {
+		c.ExitWithError(fmt.Sprintf("Failed to load policy tests from %s", args[0]), err)
+	}
+
+	evaluate := func(ctx context.Context, subject string) (map[string][]string, error) {
+		return h.GetEntitlementsForSubject(ctx, subject, true)
+	}
+	if local {
+		snapshot, err := h.LoadPolicySnapshot(cmd.Context())
+		if err != nil {
+			c.ExitWithError("Failed to load policy", err)
+		}
+		evaluate = policytest.LocalEvaluator(snapshot)
+	}
+
+	report := policytest.Run(cmd.Context(), suite, evaluate)
+
+	if junitPath != "" {
+		b, err := report.JUnit()
+		if err != nil {
+			c.ExitWithError("Failed to render JUnit report", err)
+		}
+		//nolint:mnd,gosec // the report is read by CI tooling
+		if err := os.WriteFile(junitPath, b, 0o644); err != nil {
+			c.ExitWithError(fmt.Sprintf("Failed to write JUnit report to %s", junitPath), err)
+		}
+	}
+
+	code := cli.ExitCodeSuccess
+	if !report.OK() {
+		code = cli.ExitCodeError
+	}
+	c.ExitWith(renderPolicyTestReport(report), report, code, os.Stdout)
+}
+
+func renderPolicyTestReport(r policytest.Report) string {
+	t := cli.NewTable(
+		table.NewFlexColumn("result", "Result", cli.FlexColumnWidthOne),
+		table.NewFlexColumn("name", "Case", cli.FlexColumnWidthThree),
+		table.NewFlexColumn("details", "Details", cli.FlexColumnWidthFour),
+	)
+	rows := make([]table.Row, 0, len(r.Cases))
+	for _, tc := range r.Cases {
+		result, details := "PASS", ""
+		switch {
+		case tc.Error != "":
+			result, details = "ERROR", tc.Error
+		case !tc.Passed:
+			result, details = "FAIL", strings.Join(tc.Failures, "; ")
+		}
+		rows = append(rows, table.NewRow(table.RowData{
+			"result":  result,
+			"name":    tc.Name,
+			"details": details,
+		}))
+	}
+	t = t.WithRows(rows)
+
+	summary := fmt.Sprintf("%d passed, %d failed, %d errors", r.Passed, r.Failed, r.Errors)
+	if !r.OK() {
+		return t.View() + "\n" + cli.ErrorMessage(summary, nil)
+	}
+	return t.View() + "\n" + cli.SuccessMessage(summary)
+}
+
+func initTestCommands() {
+	doc := man.Docs.GetCommand("policy/test", man.WithRun(policyTest))
+	doc.Flags().Bool(
+		doc.GetDocFlag("local").Name,
+		false,
+		doc.GetDocFlag("local").Description,
+	)
+	doc.Flags().String(
+		doc.GetDocFlag("junit").Name,
+		doc.GetDocFlag("junit").Default,
+		doc.GetDocFlag("junit").Description,
+	)
+	Cmd.AddCommand(&doc.Command)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"errors"
 	"fmt"
+	"os"
 	"runtime"
 	"strings"
 
@@ -20,6 +21,7 @@ var (
 )
 
 const (
+	profileBundleFileMode    = 0o600
 	profileMigrationLongDesc = "Migrate all profiles from keyring to filesystem. " +
 		"If you get stuck during your migration due to name collisions across the filesystem/keyring, please" +
 		" delete the specific profile from either the filesystem or keyring and run the migration again." +
@@ -72,15 +74,17 @@ var profileCreateCmd = &cobra.Command{
 		setDefault := c.FlagHelper.GetOptionalBool("set-default")
 		tlsNoVerify := c.FlagHelper.GetOptionalBool("tls-no-verify")
 		outputFormat := c.FlagHelper.GetOptionalString("output-format")
+		loginClientID := c.FlagHelper.GetOptionalString("login-client-id")
 		if !profiles.IsValidOutputFormat(outputFormat) {
 			c.ExitWithError("Output format must be either 'styled' or 'json'", nil)
 		}
 
 		profileConfig := profiles.ProfileConfig{
-			Name:         profileName,
-			Endpoint:     endpoint,
-			TLSNoVerify:  tlsNoVerify,
-			OutputFormat: profiles.NormalizeOutputFormat(outputFormat),
+			Name:          profileName,
+			Endpoint:      endpoint,
+			TLSNoVerify:   tlsNoVerify,
+			OutputFormat:  profiles.NormalizeOutputFormat(outputFormat),
+			LoginClientID: loginClientID,
 		}
 		_, err := profiles.NewOtdfctlProfileStore(profiles.ProfileDriverFileSystem, &profileConfig, setDefault)
 		if err != nil {
@@ -141,12 +145,20 @@ var profileGetCmd = &cobra.Command{
 			maskedSecret := "********"
 			auth = "client-credentials (" + ac.ClientID + ", " + maskedSecret + ")"
 		}
+		if ac.AuthType == profiles.AuthTypeWorkloadIdentity && ac.WorkloadIdentity != nil {
+			source := ac.WorkloadIdentity.TokenFile
+			if source == "" {
+				source = "$" + ac.WorkloadIdentity.TokenEnv
+			}
+			auth = "workload-identity (" + ac.WorkloadIdentity.Grant + ", " + source + ")"
+		}
 
 		t := cli.NewTabular(
 			[]string{"Profile", profileStore.Name()},
 			[]string{"Endpoint", profileStore.GetEndpoint()},
 			[]string{"Is default", isDefault},
 			[]string{"Output format", profileStore.GetOutputFormat()},
+			[]string{"Login client ID", profileStore.GetLoginClientID()},
 			[]string{"Auth type", auth},
 		)
 
@@ -276,6 +288,94 @@ var profileSetOutputFormatCmd = &cobra.Command{
 	},
 }
 
+type profileExportResult struct {
+	File     string `json:"file"`
+	Store    string `json:"store"`
+	Profiles int    `json:"profiles"`
+}
+
+var profileExportCmd = &cobra.Command{
+	Use:   "export [profile...]",
+	Short: "Export profiles to a YAML bundle (all profiles when none are named)",
+	Run: func(cmd *cobra.Command, args []string) {
+		c := cli.New(cmd, args)
+		filePath := c.FlagHelper.GetOptionalString("file")
+		includeCredentials := c.FlagHelper.GetOptionalBool("include-credentials")
+		driverType := getDriverTypeFromUser(c)
+
+		bundle, err := profiles.ExportProfiles(driverType, args, includeCredentials)
+		if err != nil {
+			c.ExitWithError("Failed to export profiles", err)
+		}
+
+		out, err := bundle.Marshal()
+		if err != nil {
+			c.ExitWithError("Failed to encode profile bundle", err)
+		}
+
+		if filePath == "" {
+			c.ExitWith(string(out), bundle, cli.ExitCodeSuccess, os.Stdout)
+			return
+		}
+		// the bundle may contain credentials, so keep it private to the current user
+		if err := os.WriteFile(filePath, out, profileBundleFileMode); err != nil {
+			c.ExitWithError(fmt.Sprintf("Failed to write profile bundle to %s", filePath), err)
+		}
+		c.ExitWith(
+			cli.SuccessMessage(fmt.Sprintf("Exported %d profiles from %s to %s", len(bundle.Profiles), driverType, filePath)),
+			profileExportResult{File: filePath, Store: string(driverType), Profiles: len(bundle.Profiles)},
+			cli.ExitCodeSuccess, os.Stdout,
+		)
+	},
+}
+
+var profileImportCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import profiles from a YAML bundle file or stdin",
+	Long: "Import one or more profiles from a bundle produced by `profile export` or from a shared team bundle. " +
+		"Existing profiles are skipped unless --overwrite is set.",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := cli.New(cmd, args)
+		overwrite := c.FlagHelper.GetOptionalBool("overwrite")
+		stripCredentials := c.FlagHelper.GetOptionalBool("strip-credentials")
+
+		data := cli.ReadFromArgsOrPipe(args, nil)
+		if len(data) == 0 {
+			c.ExitWithError("Must provide a profile bundle file or pipe one through stdin", nil)
+		}
+
+		bundle, err := profiles.ParseProfileBundle(data)
+		if err != nil {
+			c.ExitWithError("Failed to read profile bundle", err)
+		}
+		if stripCredentials {
+			bundle.StripCredentials()
+		}
+
+		result, err := profiles.ImportProfiles(profiles.ProfileDriverFileSystem, bundle, overwrite)
+		if err != nil {
+			c.ExitWithError("Failed to import profiles", err)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Imported %d profiles\n", len(result.Created)+len(result.Updated)))
+		for _, p := range result.Created {
+			sb.WriteString(fmt.Sprintf("  created  %s\n", p))
+		}
+		for _, p := range result.Updated {
+			sb.WriteString(fmt.Sprintf("  updated  %s\n", p))
+		}
+		for _, p := range result.Skipped {
+			sb.WriteString(fmt.Sprintf("  skipped  %s (already exists, use --overwrite to replace)\n", p))
+		}
+		if result.Default != "" {
+			sb.WriteString(fmt.Sprintf("Set profile %s as default\n", result.Default))
+		}
+		c.ExitWith(sb.String(), result, cli.ExitCodeSuccess, os.Stdout)
+	},
+}
+
 var profileMigrateCmd = &cobra.Command{
 	Use:   "migrate",
 	Short: "Migrate all profiles from keyring to filesystem.",
@@ -317,6 +417,7 @@ func InitProfileCommands() {
 	profileCreateCmd.Flags().Bool("set-default", false, "Set the profile as default")
 	profileCreateCmd.Flags().Bool("tls-no-verify", false, "Disable TLS verification")
 	profileCreateCmd.Flags().String("output-format", profiles.OutputStyled, "Preferred output format: styled or json")
+	profileCreateCmd.Flags().String("login-client-id", "", "Public IdP client ID used by `auth login` when --client-id is not provided")
 
 	profileListCmd.Flags().String("store", "filesystem", "Profile store to use: filesystem or keyring")
 	profileGetCmd.Flags().String("store", "filesystem", "Profile store to use: filesystem or keyring")
@@ -326,6 +427,12 @@ func InitProfileCommands() {
 
 	profileSetEndpointCmd.Flags().Bool("tls-no-verify", false, "Disable TLS verification")
 
+	profileExportCmd.Flags().String("store", "filesystem", "Profile store to use: filesystem or keyring")
+	profileExportCmd.Flags().String("file", "", "Path to write the bundle to (defaults to stdout)")
+	profileExportCmd.Flags().Bool("include-credentials", false, "Include stored credentials and tokens in the bundle")
+	profileImportCmd.Flags().Bool("overwrite", false, "Replace profiles that already exist")
+	profileImportCmd.Flags().Bool("strip-credentials", false, "Ignore any credentials and tokens contained in the bundle")
+
 	RootCmd.AddCommand(profileCmd)
 
 	profileCmd.AddCommand(profileCreateCmd)
@@ -336,6 +443,8 @@ func InitProfileCommands() {
 	profileCmd.AddCommand(profileSetDefaultCmd)
 	profileCmd.AddCommand(profileSetEndpointCmd)
 	profileCmd.AddCommand(profileSetOutputFormatCmd)
+	profileCmd.AddCommand(profileExportCmd)
+	profileCmd.AddCommand(profileImportCmd)
 	profileCmd.AddCommand(profileMigrateCmd)
 	profileCmd.AddCommand(profileKeyringCleanupCmd)
 
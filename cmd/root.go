@@ -6,6 +6,7 @@ import (
 	"os"
 
 	"github.com/opentdf/otdfctl/cmd/auth"
+	"github.com/opentdf/otdfctl/cmd/common"
 	cfg "github.com/opentdf/otdfctl/cmd/config"
 	"github.com/opentdf/otdfctl/cmd/dev"
 	"github.com/opentdf/otdfctl/cmd/policy"
@@ -80,6 +81,8 @@ func init() {
 
 			slog.SetDefault(logger)
 		}
+
+		common.ApplyProjectFlagDefaults(c)
 		return nil
 	}
 
@@ -171,6 +174,9 @@ func init() {
 	// Add migrate command
 	withMigrateSubcommand(RootCmd)
 
+	// Add doctor command
+	RootCmd.AddCommand(newDoctorCmd())
+
 	// Add interactive command
 	RootCmd.AddCommand(newInteractiveCmd())
 }
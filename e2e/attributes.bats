@@ -505,3 +505,28 @@ teardown_file() {
   assert_failure
   assert_output --partial "must be a valid UUID"
 }
+
+@test "Reorder attribute values" {
+  HIER_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes create --namespace "$NS_ID" --name "reorder-$ATTR_NAME_RANDOM" --rule HIERARCHY -v topsecret -v confidential -v secret --json | jq -r '.id')
+
+  # dry run leaves the order alone
+  run_otdfctl_attr values reorder --attribute-id "$HIER_ID" --move secret --above confidential --dry-run --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -c '.after')" '["topsecret","secret","confidential"]'
+  assert_equal "$(./otdfctl $HOST $WITH_CREDS policy attributes get --id "$HIER_ID" --json | jq -c '[.values[].value]')" '["topsecret","confidential","secret"]'
+
+  run_otdfctl_attr values reorder --attribute-id "$HIER_ID" --move secret --above confidential --force --json
+  assert_success
+  assert_equal "$(./otdfctl $HOST $WITH_CREDS policy attributes get --id "$HIER_ID" --json | jq -c '[.values[].value]')" '["topsecret","secret","confidential"]'
+
+  # an incomplete order is rejected
+  run_otdfctl_attr values reorder --attribute-id "$HIER_ID" --order topsecret,secret --force
+  assert_failure
+  assert_output --partial "missing values confidential"
+
+  run_otdfctl_attr values reorder --attribute-id "$HIER_ID" --order confidential,secret,topsecret --force --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -c '.after')" '["confidential","secret","topsecret"]'
+
+  ./otdfctl $HOST $WITH_CREDS policy attributes unsafe delete --force --id "$HIER_ID"
+}
@@ -56,4 +56,18 @@ teardown_file() {
     run_otdfctl $WITH_CREDS policy attributes list
     assert_failure
     assert_output --partial "Host must be set: when using global flags"
-}
\ No newline at end of file
+}
+
+@test "doctor reports checks against a healthy platform" {
+    run_otdfctl $HOST $WITH_CREDS doctor --json
+    assert_success
+    assert_equal "$(echo "$output" | jq -r '[.checks[] | select(.status == "fail")] | length')" "0"
+    assert_equal "$(echo "$output" | jq -r '.checks[] | select(.name == "Token") | .status')" "pass"
+}
+
+@test "doctor fails and skips dependent checks when the platform is unreachable" {
+    run_otdfctl --host http://localhost:9000 $WITH_CREDS doctor --json --timeout 2s
+    assert_failure
+    assert_equal "$(echo "$output" | jq -r '.checks[] | select(.name == "TCP") | .status')" "fail"
+    assert_equal "$(echo "$output" | jq -r '.checks[] | select(.name == "Token") | .status')" "skip"
+}
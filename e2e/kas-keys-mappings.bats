@@ -244,3 +244,88 @@ assert_key_mapping_details() {
   assert_failure
   assert_output --partial "Error: if any flags in the group [kas id] are set none of the others can be; [id kas] were all set"
 }
+
+@test "kas-keys-mappings: rotate key dry run lists the objects that will move" {
+  OLD_KEY_ID=$(generate_key_id)
+  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${OLD_KEY_ID}" --algorithm "rsa:2048" --mode "public_key" --public-key-pem "${PEM_B64_RSA_2048}" --json
+  assert_success
+  OLD_SYSTEM_KEY_ID=$(echo "$output" | jq -r '.key.id')
+
+  run_otdfctl_attribute_create --name "$(generate_kas_name)" --namespace "${NAMESPACE_ID}" --rule ANY_OF -v one -v two --json
+  assert_success
+  ROTATE_ATTRIBUTE_ID=$(echo "$output" | jq -r '.id')
+  run_otdfctl_attribute_assign_key --attribute "${ROTATE_ATTRIBUTE_ID}" --key-id "${OLD_SYSTEM_KEY_ID}"
+  assert_success
+  for value_id in $(./otdfctl policy attributes get $HOST $WITH_CREDS --id "${ROTATE_ATTRIBUTE_ID}" --json | jq -r '.values[].id'); do
+    run_otdfctl_value_assign_key --value "${value_id}" --key-id "${OLD_SYSTEM_KEY_ID}"
+    assert_success
+  done
+
+  run_otdfctl_key rotate --key "${OLD_KEY_ID}" --kas "${KAS_REGISTRY_ID}" --key-id "$(generate_key_id)" --dry-run --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.dry_run')" "true"
+  assert_equal "$(echo "$output" | jq -r '.objects | length')" "3"
+  assert_equal "$(echo "$output" | jq -r '[.objects[].kind] | join(",")')" "value,value,attribute"
+
+  # nothing changed
+  run_otdfctl_key list-mappings --id "${OLD_SYSTEM_KEY_ID}" --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.key_mappings[0].value_mappings | length')" "2"
+
+  run_otdfctl_attribute_delete --id "${ROTATE_ATTRIBUTE_ID}"
+}
+
+@test "kas-keys-mappings: staged rotation moves mappings in batches and resumes" {
+  OLD_KEY_ID=$(generate_key_id)
+  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${OLD_KEY_ID}" --algorithm "rsa:2048" --mode "public_key" --public-key-pem "${PEM_B64_RSA_2048}" --json
+  assert_success
+  OLD_SYSTEM_KEY_ID=$(echo "$output" | jq -r '.key.id')
+
+  run_otdfctl_attribute_create --name "$(generate_kas_name)" --namespace "${NAMESPACE_ID}" --rule ANY_OF -v one -v two -v three --json
+  assert_success
+  ROTATE_ATTRIBUTE_ID=$(echo "$output" | jq -r '.id')
+  run_otdfctl_attribute_assign_key --attribute "${ROTATE_ATTRIBUTE_ID}" --key-id "${OLD_SYSTEM_KEY_ID}"
+  assert_success
+  for value_id in $(./otdfctl policy attributes get $HOST $WITH_CREDS --id "${ROTATE_ATTRIBUTE_ID}" --json | jq -r '.values[].id'); do
+    run_otdfctl_value_assign_key --value "${value_id}" --key-id "${OLD_SYSTEM_KEY_ID}"
+    assert_success
+  done
+
+  # the first batch only
+  NEW_KEY_ID=$(generate_key_id)
+  run_otdfctl_key rotate --key "${OLD_SYSTEM_KEY_ID}" --key-id "${NEW_KEY_ID}" --algorithm "ec:secp256r1" --mode "public_key" --public-key-pem "${PEM_B64_EC_P256}" --staged --batch-size 2 --max-batches 1 --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.finished')" "false"
+  assert_equal "$(echo "$output" | jq -r '[.objects[] | select(.step == "moved")] | length')" "2"
+  NEW_SYSTEM_KEY_ID=$(echo "$output" | jq -r '.new_key.id')
+
+  run_otdfctl_key list-mappings --id "${OLD_SYSTEM_KEY_ID}" --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.key_mappings[0].value_mappings | length')" "1"
+  assert_equal "$(echo "$output" | jq -r '.key_mappings[0].attribute_mappings | length')" "1"
+
+  # a second rotation of the same key is refused while one is in progress
+  run_otdfctl_key rotate --key "${OLD_SYSTEM_KEY_ID}" --key-id "$(generate_key_id)" --algorithm "ec:secp256r1" --mode "public_key" --public-key-pem "${PEM_B64_EC_P256}" --staged
+  assert_failure
+  assert_output --partial "is in progress"
+
+  run_otdfctl_key rotate --key "${OLD_SYSTEM_KEY_ID}" --resume --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.finished')" "true"
+  assert_equal "$(echo "$output" | jq -r '.new_key.id')" "${NEW_SYSTEM_KEY_ID}"
+
+  run_otdfctl_key list-mappings --id "${NEW_SYSTEM_KEY_ID}" --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.key_mappings[0].value_mappings | length')" "3"
+  assert_equal "$(echo "$output" | jq -r '.key_mappings[0].attribute_mappings | length')" "1"
+
+  run_otdfctl_key get --key "${OLD_SYSTEM_KEY_ID}" --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.key.metadata.labels.rotated_to')" "${NEW_KEY_ID}"
+
+  run_otdfctl_key rotate --key "${OLD_SYSTEM_KEY_ID}" --resume
+  assert_failure
+  assert_output --partial "Nothing to resume"
+
+  run_otdfctl_attribute_delete --id "${ROTATE_ATTRIBUTE_ID}"
+}
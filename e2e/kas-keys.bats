@@ -30,10 +30,12 @@ setup_file() {
   fi
   export WRAPPING_KEY=$(openssl rand -hex 32)
   # Generate valid public keys and base64 encode (single-line)
-  export PEM_B64_RSA=$(openssl genrsa 2048 2>/dev/null | openssl rsa -pubout 2>/dev/null | base64 | tr -d '\n')
+  export PRIVATE_PEM_B64_RSA=$(openssl genrsa 2048 2>/dev/null | base64 | tr -d '\n')
+  export PEM_B64_RSA=$(echo "${PRIVATE_PEM_B64_RSA}" | base64 -d | openssl rsa -pubout 2>/dev/null | base64 | tr -d '\n')
   export PEM_B64_EC_P256=$(openssl ecparam -name prime256v1 -genkey 2>/dev/null | openssl ec -pubout 2>/dev/null | base64 | tr -d '\n')
   export PEM_B64_RSA_4096=$(openssl genrsa 4096 2>/dev/null | openssl rsa -pubout 2>/dev/null | base64 | tr -d '\n')
   export PEM_B64=${PEM_B64_RSA}
+  export PRIVATE_PEM_B64=${PRIVATE_PEM_B64_RSA}
 }
 
 setup() {
@@ -49,7 +51,7 @@ teardown_file() {
     delete_provider_config "$PC_ID"
   fi
 
-  unset HOST WITH_CREDS KAS_REGISTRY_ID KAS_NAME KAS_URI PEM_B64 WRAPPING_KEY PC_ID
+  unset HOST WITH_CREDS KAS_REGISTRY_ID KAS_NAME KAS_URI PEM_B64 PRIVATE_PEM_B64 WRAPPING_KEY PC_ID
 }
 
 # Helper function to generate a unique key ID
@@ -173,7 +175,7 @@ format_kas_name_as_uri() {
   fi
   KEY_ID=$(generate_key_id)
   WRAPPING_KEY_ID="wrapping-key-for-provider"
-  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "rsa:2048" --mode "provider" --provider-config-id "${PC_ID}" --wrapping-key-id "${WRAPPING_KEY_ID}" --public-key-pem "${PEM_B64}" --private-key-pem "${PEM_B64}"  --json
+  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "rsa:2048" --mode "provider" --provider-config-id "${PC_ID}" --wrapping-key-id "${WRAPPING_KEY_ID}" --public-key-pem "${PEM_B64}" --private-key-pem "${PRIVATE_PEM_B64}"  --json
   assert_success
   assert_equal "$(echo "$output" | jq -r .kas_id)" "${KAS_REGISTRY_ID}"
   assert_equal "$(echo "$output" | jq -r .key.key_id)" "${KEY_ID}"
@@ -288,14 +290,14 @@ format_kas_name_as_uri() {
   KEY_ID=$(generate_key_id)
   run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "rsa:2048" --mode "remote" --public-key-pem "not-base64-value" --provider-config-id "pc-1" --wrapping-key-id "wk-1"
   assert_failure
-  assert_output --partial "pem must be base64 encoded"
+  assert_output --partial "public-key-pem must be a PEM block or a base64 encoded PEM block"
 }
 
 @test "kas-keys: create key (public_key mode, pem not base64)" {
   KEY_ID=$(generate_key_id)
   run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "rsa:2048" --mode "public_key" --public-key-pem "not-base64-value"
   assert_failure
-  assert_output --partial "pem must be base64 encoded"
+  assert_output --partial "public-key-pem must be a PEM block or a base64 encoded PEM block"
 }
 
 @test "kas-keys: create key (public_key mode, invalid PEM content)" {
@@ -304,7 +306,7 @@ format_kas_name_as_uri() {
   BAD_PEM_B64=$(echo "not a pem" | base64 | tr -d '\n')
   run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "rsa:2048" --mode "public_key" --public-key-pem "${BAD_PEM_B64}"
   assert_failure
-  assert_output --partial "invalid public key pem"
+  assert_output --partial "public-key-pem must be a PEM block or a base64 encoded PEM block"
 }
 
 @test "kas-keys: create key (public_key mode, EC key with RSA algorithm)" {
@@ -389,7 +391,36 @@ format_kas_name_as_uri() {
   run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "ec:secp256r1" --mode "local" --wrapping-key-id "wrapping-key-1" --wrapping-key "not-hex-encoded" --json
   assert_failure
 
-  assert_output --partial "wrapping-key must be hex encoded"
+  assert_output --partial "wrapping-key must be a 16, 24 or 32 byte AES key"
+}
+
+@test "kas-keys: create key (local mode, wrapping-key from file and env)" {
+  WRAPPING_KEY_FILE="${BATS_TEST_TMPDIR}/wrapping.key"
+  echo "${WRAPPING_KEY}" > "${WRAPPING_KEY_FILE}"
+
+  KEY_ID=$(generate_key_id)
+  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "rsa:2048" --mode "local" --wrapping-key-id "wrapping-key-1" --wrapping-key "@${WRAPPING_KEY_FILE}" --json
+  assert_success
+  assert_not_equal "$(echo "$output" | jq -r .key.private_key_ctx.wrapped_key)" "null"
+
+  KEY_ID=$(generate_key_id)
+  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "rsa:2048" --mode "local" --wrapping-key-id "wrapping-key-1" --wrapping-key "env:WRAPPING_KEY" --json
+  assert_success
+  assert_not_equal "$(echo "$output" | jq -r .key.private_key_ctx.wrapped_key)" "null"
+}
+
+@test "kas-keys: create key (public_key mode, raw PEM from stdin)" {
+  KEY_ID=$(generate_key_id)
+  run sh -c "echo '${PEM_B64}' | base64 -d | ./otdfctl policy kas-registry key create $HOST $WITH_CREDS --kas ${KAS_REGISTRY_ID} --key-id ${KEY_ID} --algorithm rsa:2048 --mode public_key --public-key-pem - --json"
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .key.public_key_ctx.pem)" "${PEM_B64}"
+}
+
+@test "kas-keys: create key (wrapping-key env var not set)" {
+  KEY_ID=$(generate_key_id)
+  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KEY_ID}" --algorithm "rsa:2048" --mode "local" --wrapping-key-id "wrapping-key-1" --wrapping-key "env:OTDFCTL_UNSET_WRAPPING_KEY"
+  assert_failure
+  assert_output --partial "environment variable 'OTDFCTL_UNSET_WRAPPING_KEY' is not set"
 }
 
 @test "kas-keys: get key by system ID" {
@@ -817,7 +848,7 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}" \
+    --private-key-pem "${PRIVATE_PEM_B64}" \
     --legacy false \
     --json
 
@@ -829,7 +860,7 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key-legacy" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}" \
+    --private-key-pem "${PRIVATE_PEM_B64}" \
     --legacy true \
     --json
   assert_success
@@ -986,7 +1017,7 @@ format_kas_name_as_uri() {
   NEW_KEY_ID=$(generate_key_id)
   run_otdfctl_key rotate --key "${OLD_KEY_ID}" --key-id "${NEW_KEY_ID}" --algorithm "rsa:2048" --mode "local" --public-key-pem "${PEM_B64}" --wrapping-key "not-hex-encoded" --wrapping-key-id "wrapping-key-1"
   assert_failure
-  assert_output --partial "wrapping-key must be hex encoded"
+  assert_output --partial "wrapping-key must be a 16, 24 or 32 byte AES key"
 }
 
 @test "kas-keys: import key successful" {
@@ -998,7 +1029,7 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}" \
+    --private-key-pem "${PRIVATE_PEM_B64}" \
     --json
   assert_success
   assert_equal "$(echo "$output" | jq -r .key.key_id)" "${KEY_ID}"
@@ -1012,6 +1043,150 @@ format_kas_name_as_uri() {
 }
 
 
+@test "kas-keys: import key successful (raw PEM files)" {
+  KEY_ID="imported-key-$(generate_key_id)"
+  PRIVATE_PEM_FILE="${BATS_TEST_TMPDIR}/private.pem"
+  PUBLIC_PEM_FILE="${BATS_TEST_TMPDIR}/public.pem"
+  openssl genrsa -out "${PRIVATE_PEM_FILE}" 2048 2>/dev/null
+  openssl rsa -in "${PRIVATE_PEM_FILE}" -pubout -out "${PUBLIC_PEM_FILE}" 2>/dev/null
+
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --algorithm "rsa:2048" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "env:WRAPPING_KEY" \
+    --public-key-pem "@${PUBLIC_PEM_FILE}" \
+    --private-key-pem "@${PRIVATE_PEM_FILE}" \
+    --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .key.key_id)" "${KEY_ID}"
+  assert_equal "$(echo "$output" | jq -r .key.public_key_ctx.pem)" "$(base64 < "${PUBLIC_PEM_FILE}" | tr -d '\n')"
+  assert_not_equal "$(echo "$output" | jq -r .key.private_key_ctx.wrapped_key)" "null"
+}
+
+@test "kas-keys: import key failure - stdin read by two flags" {
+  KEY_ID="import-fail-$(generate_key_id)"
+  run sh -c "echo '${PEM_B64}' | ./otdfctl policy kas-registry key import $HOST $WITH_CREDS --key-id ${KEY_ID} --algorithm rsa:2048 --kas ${KAS_REGISTRY_ID} --wrapping-key-id test-wrapping-key --wrapping-key ${WRAPPING_KEY} --public-key-pem - --private-key-pem -"
+  assert_failure
+  assert_output --partial "stdin can only be read by one flag"
+}
+
+@test "kas-keys: import key successful (algorithm and public key inferred from the private key)" {
+  KEY_ID="imported-key-$(generate_key_id)"
+
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --private-key-pem "${PRIVATE_PEM_B64}" \
+    --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .key.key_algorithm)" "1"
+  assert_equal "$(echo "$output" | jq -r .key.public_key_ctx.pem | base64 -d | openssl pkey -pubin -outform DER | base64)" "$(echo "${PEM_B64}" | base64 -d | openssl pkey -pubin -outform DER | base64)"
+}
+
+@test "kas-keys: import key successful (PKCS#12 bundle)" {
+  KEY_ID="imported-key-$(generate_key_id)"
+  P12_DIR="${BATS_TEST_TMPDIR}"
+  openssl ecparam -name prime256v1 -genkey -noout -out "${P12_DIR}/key.pem" 2>/dev/null
+  openssl req -new -x509 -key "${P12_DIR}/key.pem" -subj "/CN=${KEY_ID}" -out "${P12_DIR}/cert.pem" 2>/dev/null
+  openssl pkcs12 -export -legacy -inkey "${P12_DIR}/key.pem" -in "${P12_DIR}/cert.pem" -passout pass: -out "${P12_DIR}/key.p12"
+
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --private-key-pem "@${P12_DIR}/key.p12" \
+    --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .key.key_algorithm)" "3" # ec:secp256r1
+}
+
+@test "kas-keys: import key from an encrypted PKCS#12 bundle with a chain on stdin" {
+  KEY_ID="imported-key-$(generate_key_id)"
+  P12_DIR="${BATS_TEST_TMPDIR}"
+  openssl ecparam -name prime256v1 -genkey -noout -out "${P12_DIR}/ca.pem" 2>/dev/null
+  openssl req -new -x509 -key "${P12_DIR}/ca.pem" -subj "/CN=${KEY_ID}-ca" -out "${P12_DIR}/ca-cert.pem" 2>/dev/null
+  openssl ecparam -name prime256v1 -genkey -noout -out "${P12_DIR}/key.pem" 2>/dev/null
+  openssl req -new -x509 -key "${P12_DIR}/key.pem" -subj "/CN=${KEY_ID}" -out "${P12_DIR}/cert.pem" 2>/dev/null
+  openssl pkcs12 -export -legacy -inkey "${P12_DIR}/key.pem" -in "${P12_DIR}/cert.pem" -certfile "${P12_DIR}/ca-cert.pem" \
+    -passout pass:secret -out "${P12_DIR}/key.p12"
+  echo "secret" > "${P12_DIR}/password"
+
+  # stdin holds the bundle, so the password cannot be prompted for
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --private-key-pem - "<${P12_DIR}/key.p12"
+  assert_failure
+  assert_output --partial "password-file"
+
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --password-file "${P12_DIR}/password" \
+    --private-key-pem - "<${P12_DIR}/key.p12" \
+    --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .key.key_algorithm)" "3" # ec:secp256r1
+}
+
+@test "kas-keys: import key successful (DER private key, JWKS public key)" {
+  KEY_ID="imported-key-$(generate_key_id)"
+  echo "${PRIVATE_PEM_B64}" | base64 -d | openssl pkcs8 -topk8 -nocrypt -outform DER -out "${BATS_TEST_TMPDIR}/key.der"
+  MODULUS=$(echo "${PRIVATE_PEM_B64}" | base64 -d | openssl rsa -noout -modulus 2>/dev/null | cut -d= -f2 | xxd -r -p | base64 | tr -d '\n=' | tr '/+' '_-')
+  echo "{\"keys\":[{\"kty\":\"RSA\",\"kid\":\"other\",\"n\":\"${MODULUS}\",\"e\":\"AQAB\"},{\"kty\":\"RSA\",\"kid\":\"${KEY_ID}\",\"n\":\"${MODULUS}\",\"e\":\"AQAB\"}]}" > "${BATS_TEST_TMPDIR}/keys.jwks"
+
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --public-key-pem "@${BATS_TEST_TMPDIR}/keys.jwks" \
+    --jwk-kid "${KEY_ID}" \
+    --private-key-pem "@${BATS_TEST_TMPDIR}/key.der" \
+    --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .key.key_algorithm)" "1"
+
+  # several keys in the set and no kid to pick one
+  run_otdfctl_key import --key-id "${KEY_ID}-2" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --public-key-pem "@${BATS_TEST_TMPDIR}/keys.jwks" \
+    --private-key-pem "@${BATS_TEST_TMPDIR}/key.der"
+  assert_failure
+  assert_output --partial "pick one by kid"
+}
+
+@test "kas-keys: import key failure - algorithm does not match the key" {
+  KEY_ID="import-fail-$(generate_key_id)"
+
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --algorithm "ec:secp256r1" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --private-key-pem "${PRIVATE_PEM_B64}"
+  assert_failure
+  assert_output --partial "algorithm mismatch: --algorithm is ec:secp256r1 but the key is rsa:2048"
+}
+
+@test "kas-keys: import key failure - public key does not belong to the private key" {
+  KEY_ID="import-fail-$(generate_key_id)"
+
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --public-key-pem "${PEM_B64_RSA_4096}" \
+    --private-key-pem "${PRIVATE_PEM_B64}"
+  assert_failure
+  assert_output --partial "private key does not belong to the public key"
+}
+
 @test "kas-keys: import key successful (legacy=true)" {
   KEY_ID="imported-key-$(generate_key_id)"
   
@@ -1021,7 +1196,7 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}" \
+    --private-key-pem "${PRIVATE_PEM_B64}" \
     --legacy true \
     --json
   assert_success
@@ -1044,7 +1219,7 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}" \
+    --private-key-pem "${PRIVATE_PEM_B64}" \
     --legacy false \
     --json
   assert_success
@@ -1068,7 +1243,7 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}" \
+    --private-key-pem "${PRIVATE_PEM_B64}" \
     --legacy invalid \
     --json
   assert_failure
@@ -1098,10 +1273,10 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key" \
     --wrapping-key "not-a-valid-hex-string" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}"
+    --private-key-pem "${PRIVATE_PEM_B64}"
   
   assert_failure
-  assert_output --partial "wrapping-key must be hex encoded"
+  assert_output --partial "wrapping-key must be a 16, 24 or 32 byte AES key"
 }
 
 @test "kas-keys: import key failure - invalid public key PEM" {
@@ -1113,10 +1288,10 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "not-base64-encoded" \
-    --private-key-pem "${PEM_B64}"
+    --private-key-pem "${PRIVATE_PEM_B64}"
   
   assert_failure
-  assert_output --partial "public-key-pem must be base64 encoded"
+  assert_output --partial "public-key-pem must be PEM, DER, PKCS#12, JWK, JWKS or an OpenSSH key"
 }
 
 @test "kas-keys: import key failure - invalid private key PEM" {
@@ -1131,7 +1306,7 @@ format_kas_name_as_uri() {
     --private-key-pem "not-base64-encoded"
   
   assert_failure
-  assert_output --partial "private-key-pem must be base64 encoded"
+  assert_output --partial "private-key-pem must be PEM, DER, PKCS#12, JWK, JWKS or an OpenSSH key"
 }
 
 @test "kas-keys: import key failure - invalid algorithm" {
@@ -1143,7 +1318,7 @@ format_kas_name_as_uri() {
     --wrapping-key-id "test-wrapping-key" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}"
+    --private-key-pem "${PRIVATE_PEM_B64}"
   
   assert_failure
   assert_output --partial "Invalid algorithm"
@@ -1157,7 +1332,7 @@ format_kas_name_as_uri() {
     --kas "${KAS_REGISTRY_ID}" \
     --wrapping-key "${WRAPPING_KEY}" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}"
+    --private-key-pem "${PRIVATE_PEM_B64}"
   
   assert_failure
   assert_output --partial "'--wrapping-key-id' is required"
@@ -1171,12 +1346,92 @@ format_kas_name_as_uri() {
     --kas "${KAS_REGISTRY_ID}" \
     --wrapping-key-id "test-wrapping-key" \
     --public-key-pem "${PEM_B64}" \
-    --private-key-pem "${PEM_B64}"
+    --private-key-pem "${PRIVATE_PEM_B64}"
   
   assert_failure
   assert_output --partial "'--wrapping-key' is required"
 }
 
+@test "kas-keys: generate key pair, wrapped key and JWKS, then import it" {
+  KEY_ID="generated-$(generate_key_id)"
+  OUT_DIR="${BATS_TEST_TMPDIR}/ceremony"
+
+  run_otdfctl_key generate --key-id "${KEY_ID}" --algorithm "rsa:4096" --out-dir "${OUT_DIR}" --wrapping-key "env:WRAPPING_KEY" --jwks --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .algorithm)" "rsa:4096"
+  assert_equal "$(echo "$output" | jq -r '.files | length')" "4"
+  assert_equal "$(echo "$output" | jq -r .fingerprint)" "$(openssl pkey -pubin -in "${OUT_DIR}/${KEY_ID}.pub.pem" -outform DER | sha256sum | cut -d' ' -f1)"
+  assert_equal "$(stat -c %a "${OUT_DIR}/${KEY_ID}.key.pem")" "600"
+  assert_equal "$(jq -r '.keys[0].kid' "${OUT_DIR}/${KEY_ID}.jwks")" "${KEY_ID}"
+  assert_equal "$(jq -r '.keys[0].d' "${OUT_DIR}/${KEY_ID}.jwks")" "null"
+
+  run_otdfctl_key import --key-id "${KEY_ID}" \
+    --kas "${KAS_REGISTRY_ID}" \
+    --wrapping-key-id "test-wrapping-key" \
+    --wrapping-key "${WRAPPING_KEY}" \
+    --public-key-pem "@${OUT_DIR}/${KEY_ID}.jwks" \
+    --private-key-pem "@${OUT_DIR}/${KEY_ID}.key.pem" \
+    --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .key.key_algorithm)" "2" # rsa:4096
+}
+
+@test "kas-keys: generate key (wrapped only, no overwrite)" {
+  KEY_ID="generated-$(generate_key_id)"
+  OUT_DIR="${BATS_TEST_TMPDIR}/ceremony"
+
+  run_otdfctl_key generate --key-id "${KEY_ID}" --algorithm "ec:secp384r1" --out-dir "${OUT_DIR}" --wrapping-key "${WRAPPING_KEY}" --wrapped-only --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '[.files[].kind] | join(",")')" "public key,wrapped private key"
+  [ ! -f "${OUT_DIR}/${KEY_ID}.key.pem" ]
+
+  run_otdfctl_key generate --key-id "${KEY_ID}" --algorithm "ec:secp384r1" --out-dir "${OUT_DIR}"
+  assert_failure
+  assert_output --partial "already exists, use --force to overwrite it"
+
+  # an existing file written last stops the command before any other file is written
+  OTHER_ID="generated-$(generate_key_id)"
+  touch "${OUT_DIR}/${OTHER_ID}.jwks"
+  run_otdfctl_key generate --key-id "${OTHER_ID}" --algorithm "ec:secp384r1" --out-dir "${OUT_DIR}" --jwks
+  assert_failure
+  assert_output --partial "${OTHER_ID}.jwks already exists"
+  [ ! -f "${OUT_DIR}/${OTHER_ID}.pub.pem" ]
+  [ ! -f "${OUT_DIR}/${OTHER_ID}.key.pem" ]
+
+  run_otdfctl_key generate --key-id "${KEY_ID}" --algorithm "ec:secp384r1" --out-dir "${OUT_DIR}" --wrapped-only
+  assert_failure
+  assert_output --partial "Flag '--wrapped-only' requires '--wrapping-key'"
+}
+
+@test "kas-keys: report keys" {
+  WEAK_KID=$(generate_key_id)
+  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${WEAK_KID}" --algorithm "rsa:2048" --mode "public_key" --public-key-pem "${PEM_B64}" --json
+  assert_success
+  STRONG_KID=$(generate_key_id)
+  run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${STRONG_KID}" --algorithm "ec:secp256r1" --mode "public_key" --public-key-pem "${PEM_B64_EC_P256}" --json
+  assert_success
+
+  run_otdfctl_key report --kas "${KAS_REGISTRY_ID}" --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r --arg kid "${WEAK_KID}" '.keys[] | select(.key_id == $kid) | .findings[0].rule')" "weak-algorithm"
+  assert_equal "$(echo "$output" | jq -r --arg kid "${WEAK_KID}" '.keys[] | select(.key_id == $kid) | .kas_name')" "${KAS_NAME}"
+  assert_equal "$(echo "$output" | jq -r --arg kid "${STRONG_KID}" '.keys[] | select(.key_id == $kid) | .findings | length')" "0"
+  assert_equal "$(echo "$output" | jq -r '[.keys[].kas_id] | unique | join(",")')" "${KAS_REGISTRY_ID}"
+
+  run_otdfctl_key report --kas "${KAS_URI}" --flagged-only --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r --arg kid "${STRONG_KID}" '[.keys[] | select(.key_id == $kid)] | length')" "0"
+
+  run_otdfctl_key report --kas "${KAS_NAME}" --min-strength 0 --format csv
+  assert_success
+  assert_line --index 0 "kas_uri,kas_name,id,key_id,algorithm,mode,status,created_at,age_days,rotated_at,rotated_to,legacy,base,namespaces,attributes,values,findings"
+  assert_output --partial ",${WEAK_KID},rsa:2048,public_key,active,"
+
+  run_otdfctl_key report --max-age soon
+  assert_failure
+  assert_output --partial "Invalid --max-age"
+}
+
 @test "kas-keys: delete key" {
   KID=$(generate_key_id)
   run_otdfctl_key create --kas "${KAS_REGISTRY_ID}" --key-id "${KID}" --algorithm "rsa:2048" --mode "public_key" --public-key-pem "${PEM_B64}" --json
@@ -156,3 +156,19 @@ teardown() {
     total=$(echo "$output" | jq -r ".pagination.total")
     [[ $total -ge 1 ]]
 }
+
+@test "check a registered KAS that is not running - fails" {
+    URI="http://localhost:1"
+    NAME="unreachable-kas"
+    export CREATED=$(./otdfctl $HOST $DEBUG_LEVEL $WITH_CREDS policy kas-registry create --uri "$URI" -n "$NAME" --json)
+
+    run_otdfctl_kasr check --kas "$NAME" --timeout 2s --json
+    assert_failure
+    assert_equal "$(echo "$output" | jq -r '.failed')" "1"
+    assert_equal "$(echo "$output" | jq -r '.results[0].kas_uri')" "$URI"
+    assert_equal "$(echo "$output" | jq -r '.results[0].findings[0].status')" "unreachable"
+
+    run_otdfctl_kasr check --kas "$NAME" --timeout 2s
+    assert_failure
+    assert_output --partial "UNREACHABLE"
+}
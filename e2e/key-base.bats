@@ -47,7 +47,7 @@ teardown_file() {
 # --- set base key tests ---
 
 @test "base-key: set by --key (uuid)" {
-  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --json
+  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --force --json
   assert_success
   # Verify the new base key part of the response
   assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.kid)" "${REGULAR_KEY_ID_FOR_BASE_TEST}"
@@ -57,10 +57,12 @@ teardown_file() {
   assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.algorithm)" 1
   # Verify previous base key is null or not present if this is the first set
   assert_equal "$(echo "$output" | jq -r .previous_base_key)" "null"
+  # the checks the key was forced past are part of the output
+  assert_equal "$(echo "$output" | jq -r '[.checks[] | select(.name == "KAS")][0].status')" "fail"
 }
 
 @test "base-key: set by --key(id) and --kas(id)" {
-  run_otdfctl_base_key set --key "${REGULAR_KEY_ID_FOR_BASE_TEST}" --kas "${KAS_REGISTRY_ID_BASE_KEY_TEST}" --json
+  run_otdfctl_base_key set --key "${REGULAR_KEY_ID_FOR_BASE_TEST}" --kas "${KAS_REGISTRY_ID_BASE_KEY_TEST}" --force --json
   assert_success
   # Verify the new base key part of the response
   assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.kid)" "${REGULAR_KEY_ID_FOR_BASE_TEST}"
@@ -71,7 +73,7 @@ teardown_file() {
 }
 
 @test "base-key: get (after setting a base key)" {
-  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --json
+  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --force --json
   assert_success
 
   run_otdfctl_base_key get --json
@@ -84,7 +86,7 @@ teardown_file() {
 }
 
 @test "base-key: set by --key(id) and --kas(name)" {
-  run_otdfctl_base_key set --key "${REGULAR_KEY_ID_FOR_BASE_TEST}" --kas "${KAS_NAME_BASE_KEY_TEST}" --json
+  run_otdfctl_base_key set --key "${REGULAR_KEY_ID_FOR_BASE_TEST}" --kas "${KAS_NAME_BASE_KEY_TEST}" --force --json
   assert_success
   assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.kid)" "${REGULAR_KEY_ID_FOR_BASE_TEST}"
   assert_equal "$(echo "$output" | jq -r .new_base_key.kas_uri)" "${KAS_URI_BASE_KEY_TEST}" # KAS URI should remain the same for the KAS Name
@@ -95,7 +97,7 @@ teardown_file() {
 
 @test "base-key: set by --key(id) and --kas(uri)" {
   # This will set REGULAR_KEY_ID_FOR_BASE_TEST back as the base key
-  run_otdfctl_base_key set --key "${REGULAR_KEY_ID_FOR_BASE_TEST}" --kas "${KAS_URI_BASE_KEY_TEST}" --json
+  run_otdfctl_base_key set --key "${REGULAR_KEY_ID_FOR_BASE_TEST}" --kas "${KAS_URI_BASE_KEY_TEST}" --force --json
   assert_success
   # Verify the new base key
   assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.kid)" "${REGULAR_KEY_ID_FOR_BASE_TEST}"
@@ -106,7 +108,7 @@ teardown_file() {
 }
 
 @test "base-key: set, get, and verify previous base key" {
-  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --json
+  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --force --json
   assert_success
   assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.kid)" "${REGULAR_KEY_ID_FOR_BASE_TEST}"
   assert_equal "$(echo "$output" | jq -r .new_base_key.kas_uri)" "${KAS_URI_BASE_KEY_TEST}"
@@ -125,7 +127,7 @@ teardown_file() {
   SECOND_KEY_ID_FOR_BASE_TEST="second-key-for-base-$(date +%s)"
   SECOND_KAS_KEY_SYSTEM_ID=$(./otdfctl $HOST $WITH_CREDS policy kas-registry key create --kas "${KAS_REGISTRY_ID_BASE_KEY_TEST}" --key-id "${SECOND_KEY_ID_FOR_BASE_TEST}" --algorithm ec:secp256r1 --mode local --wrapping-key "${WRAPPING_KEY}" --wrapping-key-id "test-key" --json | jq -r '.key.id')
 
-  run_otdfctl_base_key set --key "${SECOND_KAS_KEY_SYSTEM_ID}" --json
+  run_otdfctl_base_key set --key "${SECOND_KAS_KEY_SYSTEM_ID}" --force --json
   assert_success
   assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.kid)" "${SECOND_KEY_ID_FOR_BASE_TEST}"
   assert_equal "$(echo "$output" | jq -r .new_base_key.kas_uri)" "${KAS_URI_BASE_KEY_TEST}"
@@ -140,6 +142,59 @@ teardown_file() {
   assert_equal "$(echo "$output" | jq -r .previous_base_key.public_key.algorithm)" 1
 }
 
+@test "base-key: set (unreachable KAS fails validation without --force)" {
+  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}"
+  assert_failure
+  assert_output --partial "failed validation"
+  assert_output --partial "--force"
+
+  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --json
+  assert_failure
+  assert_equal "$(echo "$output" | jq -r .status)" "ERROR"
+  assert_equal "$(echo "$output" | jq -r '[.checks[] | select(.name == "KAS")][0].status')" "fail"
+}
+
+@test "base-key: set --dry-run shows validation and fallback attributes without setting" {
+  run_otdfctl_base_key get --json
+  assert_success
+  CURRENT_KID=$(echo "$output" | jq -r .public_key.kid)
+
+  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --dry-run --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '[.checks[] | select(.name == "KAS")][0].status')" "fail"
+  assert_equal "$(echo "$output" | jq -r '[.checks[] | select(.name == "Status")][0].status')" "pass"
+  assert_equal "$(echo "$output" | jq -r '.fallbacks | type')" "array"
+
+  run_otdfctl_base_key get --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .public_key.kid)" "${CURRENT_KID}"
+}
+
+@test "base-key: rollback to the previous base key" {
+  ROLLBACK_KEY_ID="rollback-key-for-base-$(date +%s)"
+  ROLLBACK_KAS_KEY_SYSTEM_ID=$(./otdfctl $HOST $WITH_CREDS policy kas-registry key create --kas "${KAS_REGISTRY_ID_BASE_KEY_TEST}" --key-id "${ROLLBACK_KEY_ID}" --algorithm rsa:2048 --mode local --wrapping-key "${WRAPPING_KEY}" --wrapping-key-id "test-key" --json | jq -r '.key.id')
+
+  run_otdfctl_base_key set --key "${KAS_KEY_SYSTEM_ID}" --force --json
+  assert_success
+  run_otdfctl_base_key set --key "${ROLLBACK_KAS_KEY_SYSTEM_ID}" --force --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.kid)" "${ROLLBACK_KEY_ID}"
+
+  run_otdfctl_base_key rollback --json
+  assert_failure
+  assert_output --partial "failed validation"
+
+  run_otdfctl_base_key rollback --force --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .new_base_key.public_key.kid)" "${REGULAR_KEY_ID_FOR_BASE_TEST}"
+  assert_equal "$(echo "$output" | jq -r '.checks | length')" "3"
+  assert_equal "$(echo "$output" | jq -r .previous_base_key.public_key.kid)" "${ROLLBACK_KEY_ID}"
+
+  run_otdfctl_base_key get --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r .public_key.kid)" "${REGULAR_KEY_ID_FOR_BASE_TEST}"
+}
+
 @test "base-key: set (missing kas identifier)" {
   run_otdfctl_base_key set --key "${REGULAR_KEY_ID_FOR_BASE_TEST}"
   assert_failure
@@ -0,0 +1,59 @@
+#!/usr/bin/env bats
+
+# Tests for policy snapshot and diff
+
+setup_file() {
+  export WITH_CREDS='--with-client-creds-file ./creds.json'
+  export HOST='--host http://localhost:8080'
+
+  export NS_NAME="policy-diff.net"
+  export NS_FQN="https://$NS_NAME"
+  export NS_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes namespaces create -n "$NS_NAME" --json | jq -r '.id')
+  export ATTR_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes create --namespace "$NS_ID" --name level --rule HIERARCHY -v high -v low --json | jq -r '.id')
+
+  export SNAPSHOT_FILE="$BATS_FILE_TMPDIR/baseline.json"
+  ./otdfctl $HOST $WITH_CREDS policy snapshot --out "$SNAPSHOT_FILE"
+}
+
+setup() {
+  load "${BATS_LIB_PATH}/bats-support/load.bash"
+  load "${BATS_LIB_PATH}/bats-assert/load.bash"
+
+  # invoke binary with credentials
+  run_otdfctl_diff () {
+    run sh -c "./otdfctl $HOST $WITH_CREDS policy diff $*"
+  }
+}
+
+teardown_file() {
+  ./otdfctl $HOST $WITH_CREDS policy attributes namespaces unsafe delete --force --id "$NS_ID"
+
+  unset HOST WITH_CREDS NS_NAME NS_FQN NS_ID ATTR_ID SNAPSHOT_FILE
+}
+
+@test "Snapshot contains the policy" {
+  assert_equal "$(jq -r '.version' "$SNAPSHOT_FILE")" "1"
+  assert_equal "$(jq -r --arg fqn "$NS_FQN/attr/level" '[.attributes[] | select(.fqn == $fqn)] | length' "$SNAPSHOT_FILE")" "1"
+}
+
+@test "Diff of a snapshot with itself has no drift" {
+  run_otdfctl_diff --from "$SNAPSHOT_FILE" --to "$SNAPSHOT_FILE"
+  assert_success
+  assert_output --partial "No drift"
+}
+
+@test "Diff detects added objects and changed value order" {
+  ./otdfctl $HOST $WITH_CREDS policy attributes create --namespace "$NS_ID" --name added -v one
+  ./otdfctl $HOST $WITH_CREDS policy attributes values create --attribute-id "$ATTR_ID" --value lowest
+
+  run_otdfctl_diff --from "$SNAPSHOT_FILE" --json
+  assert_failure 2
+  assert_equal "$(echo "$output" | jq -r --arg key "$NS_FQN/attr/added" '.changes[] | select(.key == $key) | .type')" "added"
+  assert_equal "$(echo "$output" | jq -r --arg key "$NS_FQN/attr/level" '.changes[] | select(.key == $key) | .fields[] | select(.field == "values_order") | .to')" "high, low, lowest"
+}
+
+@test "Diff fails for an unknown profile" {
+  run_otdfctl_diff --from does-not-exist
+  assert_failure 1
+  assert_output --partial "Failed to load profile"
+}
@@ -0,0 +1,56 @@
+#!/usr/bin/env bats
+
+# Tests for policy graph
+
+setup_file() {
+  export WITH_CREDS='--with-client-creds-file ./creds.json'
+  export HOST='--host http://localhost:8080'
+
+  export NS_NAME="policy-graph.net"
+  export NS_FQN="https://$NS_NAME"
+  export NS_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes namespaces create -n "$NS_NAME" --json | jq -r '.id')
+  export ATTR_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes create --namespace "$NS_ID" --name level --rule HIERARCHY -v high -v low --json | jq -r '.id')
+}
+
+setup() {
+  load "${BATS_LIB_PATH}/bats-support/load.bash"
+  load "${BATS_LIB_PATH}/bats-assert/load.bash"
+
+  # invoke binary with credentials
+  run_otdfctl_graph () {
+    run sh -c "./otdfctl $HOST $WITH_CREDS policy graph $*"
+  }
+}
+
+teardown_file() {
+  ./otdfctl $HOST $WITH_CREDS policy attributes namespaces unsafe delete --force --id "$NS_ID"
+
+  unset HOST WITH_CREDS NS_NAME NS_FQN NS_ID ATTR_ID
+}
+
+@test "Graph rooted at a namespace as DOT" {
+  run_otdfctl_graph --namespace "$NS_FQN"
+  assert_success
+  assert_output --partial "digraph policy {"
+  assert_output --partial "\"namespace:$NS_ID\" -> \"attribute:$ATTR_ID\" [label=\"contains\"];"
+  assert_output --partial 'label="contains: rank 1"'
+}
+
+@test "Graph rooted at an attribute as Mermaid" {
+  run_otdfctl_graph --attribute "$NS_FQN/attr/level" --format mermaid
+  assert_success
+  assert_output --partial "flowchart LR"
+  assert_output --partial "$NS_FQN/attr/level/value/high"
+}
+
+@test "Graph as JSON" {
+  run_otdfctl_graph --namespace "$NS_ID" --format json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '[.nodes[] | select(.kind == "value")] | length')" "2"
+}
+
+@test "Graph fails for an unknown root" {
+  run_otdfctl_graph --namespace https://does-not-exist.example
+  assert_failure
+  assert_output --partial "not found"
+}
@@ -0,0 +1,85 @@
+#!/usr/bin/env bats
+
+# Tests for the policy change journal, history and undo
+
+setup_file() {
+  export WITH_CREDS='--with-client-creds-file ./creds.json'
+  export HOST='--host http://localhost:8080'
+
+  export NS_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes namespaces create -n "policy-history.net" --json | jq -r '.id')
+  export ATTR_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes create --namespace "$NS_ID" --name level --rule ANY_OF -v high -v low --label team=a --json | jq -r '.id')
+}
+
+setup() {
+  load "${BATS_LIB_PATH}/bats-support/load.bash"
+  load "${BATS_LIB_PATH}/bats-assert/load.bash"
+
+  # invoke binary with credentials
+  run_otdfctl_policy () {
+    run sh -c "./otdfctl $HOST $WITH_CREDS policy $*"
+  }
+
+  # newest journal entry for the attribute with the given operation
+  attr_entry () {
+    ./otdfctl $HOST $WITH_CREDS policy history --kind attribute --limit 0 --json |
+      jq -r --arg id "$ATTR_ID" --arg op "$1" 'map(select(.object_id == $id and .operation == $op)) | first | .id'
+  }
+}
+
+teardown_file() {
+  ./otdfctl $HOST $WITH_CREDS policy attributes namespaces unsafe delete --force --id "$NS_ID"
+
+  unset HOST WITH_CREDS NS_ID ATTR_ID
+}
+
+@test "Creates are recorded in the history" {
+  run_otdfctl_policy history --kind attribute --limit 0 --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r --arg id "$ATTR_ID" 'map(select(.object_id == $id)) | first | .operation')" "create"
+  assert_equal "$(echo "$output" | jq -r --arg id "$ATTR_ID" 'map(select(.object_id == $id)) | first | .after.metadata.labels.team')" "a"
+}
+
+@test "Undo a deactivation reactivates the attribute" {
+  ./otdfctl $HOST $WITH_CREDS policy attributes deactivate --id "$ATTR_ID" --force
+  ENTRY=$(attr_entry deactivate)
+
+  run_otdfctl_policy undo "$ENTRY" --force --json
+  assert_success
+  assert_equal "$(./otdfctl $HOST $WITH_CREDS policy attributes get --id "$ATTR_ID" --json | jq -r '.active')" "true"
+
+  run_otdfctl_policy history --kind attribute --limit 0 --json
+  assert_equal "$(echo "$output" | jq -r --argjson e "$ENTRY" 'map(select(.id == $e)) | first | .undone_by != null')" "true"
+}
+
+@test "Undo an entry twice warns" {
+  ENTRY=$(attr_entry deactivate)
+
+  run_otdfctl_policy undo "$ENTRY" --force
+  assert_output --partial "was already undone"
+}
+
+@test "Undo a label update restores the labels" {
+  ./otdfctl $HOST $WITH_CREDS policy attributes update --id "$ATTR_ID" --label team=b --force-replace-labels
+  ENTRY=$(attr_entry update)
+
+  run_otdfctl_policy undo "$ENTRY" --force
+  assert_success
+  assert_equal "$(./otdfctl $HOST $WITH_CREDS policy attributes get --id "$ATTR_ID" --json | jq -c '.metadata.labels')" '{"team":"a"}'
+}
+
+@test "Bulk label changes are journaled and can be undone" {
+  ./otdfctl $HOST $WITH_CREDS policy labels add env=prod --kind attribute --fqn https://policy-history.net/attr/level
+  ENTRY=$(attr_entry update)
+
+  run_otdfctl_policy history --kind attribute --limit 0 --json
+  assert_equal "$(echo "$output" | jq -c --argjson e "$ENTRY" 'map(select(.id == $e)) | first | .before.metadata.labels')" '{"team":"a"}'
+
+  run_otdfctl_policy undo "$ENTRY" --force
+  assert_success
+  assert_equal "$(./otdfctl $HOST $WITH_CREDS policy attributes get --id "$ATTR_ID" --json | jq -c '.metadata.labels')" '{"team":"a"}'
+}
+
+@test "Undo an unknown entry fails" {
+  run_otdfctl_policy undo 999999999 --force
+  assert_failure
+}
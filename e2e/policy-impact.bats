@@ -0,0 +1,67 @@
+#!/usr/bin/env bats
+
+# Tests for the impact analysis of deactivations and deletions
+
+setup_file() {
+  export WITH_CREDS='--with-client-creds-file ./creds.json'
+  export HOST='--host http://localhost:8080'
+
+  export NS_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes namespaces create -n "policy-impact.net" --json | jq -r '.id')
+  export ATTR_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes create --namespace "$NS_ID" --name level --rule HIERARCHY -v high -v low --json | jq -r '.id')
+  export VAL_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes get --id "$ATTR_ID" --json | jq -r '.values[0].id')
+  SCS='[{"conditionGroups":[{"conditions":[{"operator":"SUBJECT_MAPPING_OPERATOR_ENUM_IN","subjectExternalValues":["admin"],"subjectExternalSelectorValue":".role"}],"booleanOperator":"CONDITION_BOOLEAN_TYPE_ENUM_OR"}]}]'
+  ./otdfctl $HOST $WITH_CREDS policy subject-mappings create --attribute-value-id "$VAL_ID" --action read --subject-condition-set-new "$SCS"
+  ./otdfctl $HOST $WITH_CREDS policy resource-mappings create --attribute-value-id "$VAL_ID" --terms HIGH
+}
+
+setup() {
+  load "${BATS_LIB_PATH}/bats-support/load.bash"
+  load "${BATS_LIB_PATH}/bats-assert/load.bash"
+
+  # invoke binary with credentials
+  run_otdfctl_attr () {
+    run sh -c "./otdfctl $HOST $WITH_CREDS policy attributes $*"
+  }
+
+  affected () {
+    echo "$output" | jq -r --arg kind "$1" '.affected[] | select(.kind == $kind) | .count'
+  }
+}
+
+teardown_file() {
+  ./otdfctl $HOST $WITH_CREDS policy attributes namespaces unsafe delete --force --id "$NS_ID"
+
+  unset HOST WITH_CREDS NS_ID ATTR_ID VAL_ID
+}
+
+@test "What-if of a namespace deletion lists the cascade without deleting" {
+  run_otdfctl_attr namespaces unsafe delete --id "$NS_ID" --what-if --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.operation')" "delete"
+  assert_equal "$(affected attribute)" "1"
+  assert_equal "$(affected value)" "2"
+  assert_equal "$(affected subject-mapping)" "1"
+  assert_equal "$(affected resource-mapping)" "1"
+
+  run_otdfctl_attr namespaces get --id "$NS_ID" --json
+  assert_success
+}
+
+@test "What-if of a value deactivation lists its mappings" {
+  run_otdfctl_attr values deactivate --id "$VAL_ID" --what-if
+  assert_success
+  assert_output --partial "Deactivating value"
+  assert_output --partial "subject-mapping (1)"
+
+  run_otdfctl_attr values get --id "$VAL_ID" --json
+  assert_equal "$(echo "$output" | jq -r '.active')" "true"
+}
+
+@test "What-if of an attribute deletion does not delete" {
+  run_otdfctl_attr unsafe delete --id "$ATTR_ID" --what-if --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.fqn')" "https://policy-impact.net/attr/level"
+
+  run_otdfctl_attr get --id "$ATTR_ID" --json
+  assert_success
+}
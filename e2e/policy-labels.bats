@@ -0,0 +1,86 @@
+#!/usr/bin/env bats
+
+# Tests for bulk label management
+
+setup_file() {
+  export WITH_CREDS='--with-client-creds-file ./creds.json'
+  export HOST='--host http://localhost:8080'
+
+  export NS_NAME="policy-labels.net"
+  export NS_FQN="https://$NS_NAME"
+  export NS_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes namespaces create -n "$NS_NAME" --json | jq -r '.id')
+  export ATTR_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes create --namespace "$NS_ID" --name level --rule HIERARCHY -v high -v low --label team=a --json | jq -r '.id')
+}
+
+setup() {
+  load "${BATS_LIB_PATH}/bats-support/load.bash"
+  load "${BATS_LIB_PATH}/bats-assert/load.bash"
+
+  # invoke binary with credentials
+  run_otdfctl_labels () {
+    run sh -c "./otdfctl $HOST $WITH_CREDS policy labels $*"
+  }
+
+  attr_labels () {
+    ./otdfctl $HOST $WITH_CREDS policy attributes get --id "$ATTR_ID" --json | jq -c '.metadata.labels // {}'
+  }
+}
+
+teardown_file() {
+  ./otdfctl $HOST $WITH_CREDS policy attributes namespaces unsafe delete --force --id "$NS_ID"
+
+  unset HOST WITH_CREDS NS_NAME NS_FQN NS_ID ATTR_ID
+}
+
+@test "Dry run previews changes without updating" {
+  run_otdfctl_labels add env=prod --namespace "$NS_FQN" --dry-run --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.dry_run')" "true"
+  assert_equal "$(echo "$output" | jq -r '[.changes[] | select(.kind == "value")] | length')" "2"
+  assert_equal "$(attr_labels)" '{"team":"a"}'
+}
+
+@test "Add labels to selected objects" {
+  run_otdfctl_labels add env=prod --kind attribute,value --fqn "'$NS_FQN/attr/level*'" --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.changes | length')" "3"
+  assert_equal "$(echo "$output" | jq -r '.failed')" "0"
+  assert_equal "$(attr_labels)" '{"env":"prod","team":"a"}'
+}
+
+@test "Rename a label key on objects carrying it" {
+  run_otdfctl_labels rename team owner --namespace "$NS_FQN" --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.changes | length')" "1"
+  assert_equal "$(attr_labels)" '{"env":"prod","owner":"a"}'
+}
+
+@test "Rename onto an existing label key is rejected" {
+  run_otdfctl_labels add team=b --namespace "$NS_FQN" --kind attribute --json
+  assert_success
+
+  run_otdfctl_labels rename team owner --namespace "$NS_FQN" --json
+  assert_failure
+  assert_equal "$(echo "$output" | jq -r '.failed')" "1"
+  assert_output --partial "label already exists"
+  assert_equal "$(attr_labels)" '{"env":"prod","owner":"a","team":"b"}'
+
+  run_otdfctl_labels remove team --namespace "$NS_FQN" --kind attribute --json
+  assert_success
+}
+
+@test "Remove labels selected by an existing label" {
+  run_otdfctl_labels remove env --has-label owner=a --json
+  assert_success
+  assert_equal "$(attr_labels)" '{"owner":"a"}'
+}
+
+@test "Invalid selectors are rejected" {
+  run_otdfctl_labels add env=prod --kind widget
+  assert_failure
+  assert_output --partial "unknown object type"
+
+  run_otdfctl_labels add env
+  assert_failure
+  assert_output --partial "expected key=value"
+}
@@ -0,0 +1,56 @@
+#!/usr/bin/env bats
+
+# Tests for policy lint
+
+setup_file() {
+  export WITH_CREDS='--with-client-creds-file ./creds.json'
+  export HOST='--host http://localhost:8080'
+
+  export NS_NAME="policy-lint.net"
+  export NS_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes namespaces create -n "$NS_NAME" --json | jq -r '.id')
+
+  # AND group requiring .team.name to be both IN and NOT_IN "red"
+  export SCS_CONTRADICTORY='[{"condition_groups":[{"conditions":[{"operator":1,"subject_external_values":["red"],"subject_external_selector_value":".team.name"},{"operator":2,"subject_external_values":["red"],"subject_external_selector_value":".team.name"}],"boolean_operator":1}]}]'
+  export SCS_ID=$(./otdfctl $HOST $WITH_CREDS policy scs create --namespace "$NS_ID" --subject-sets "$SCS_CONTRADICTORY" --json | jq -r '.id')
+}
+
+setup() {
+  load "${BATS_LIB_PATH}/bats-support/load.bash"
+  load "${BATS_LIB_PATH}/bats-assert/load.bash"
+
+  # invoke binary with credentials
+  run_otdfctl_lint () {
+    run sh -c "./otdfctl $HOST $WITH_CREDS policy lint $*"
+  }
+}
+
+teardown_file() {
+  ./otdfctl $HOST $WITH_CREDS policy scs delete --id "$SCS_ID" --force
+  ./otdfctl $HOST $WITH_CREDS policy attributes namespaces unsafe delete --force --id "$NS_ID"
+
+  unset HOST WITH_CREDS NS_NAME NS_ID SCS_CONTRADICTORY SCS_ID
+}
+
+@test "Lint reports a contradictory subject condition set and fails" {
+  run_otdfctl_lint --json
+  assert_failure
+  assert_equal "$(echo "$output" | jq -r --arg id "$SCS_ID" '.findings[] | select(.object.id == $id and .rule == "condition-set-contradictory") | .severity')" "error"
+}
+
+@test "Lint passes with --fail-on none" {
+  run_otdfctl_lint --fail-on none --json
+  assert_success
+  assert_output --partial '"summary"'
+}
+
+@test "Lint ignores rules" {
+  run_otdfctl_lint --ignore condition-set-contradictory --fail-on none --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '[.findings[] | select(.rule == "condition-set-contradictory")] | length')" "0"
+}
+
+@test "Lint rejects unknown rules" {
+  run_otdfctl_lint --ignore not-a-rule
+  assert_failure
+  assert_output --partial "Unknown lint rule"
+}
@@ -0,0 +1,75 @@
+#!/usr/bin/env bats
+
+# Tests for policy test
+
+setup_file() {
+  export WITH_CREDS='--with-client-creds-file ./creds.json'
+  export HOST='--host http://localhost:8080'
+
+  export NS_NAME="policy-test.net"
+  export NS_FQN="https://$NS_NAME"
+  export NS_ID=$(./otdfctl $HOST $WITH_CREDS policy attributes namespaces create -n "$NS_NAME" --json | jq -r '.id')
+  ATTR_JSON=$(./otdfctl $HOST $WITH_CREDS policy attributes create --namespace "$NS_ID" --name level --rule HIERARCHY -v high -v low --json)
+  HIGH_ID=$(echo "$ATTR_JSON" | jq -r '.values[] | select(.value == "high") | .id')
+  ./otdfctl $HOST $WITH_CREDS policy subject-mappings create --attribute-value-id "$HIGH_ID" --action read \
+    --subject-condition-set-conditions '.team IN ["policy-test"]'
+
+  export TESTS_DIR="$BATS_FILE_TMPDIR"
+  cat > "$TESTS_DIR/pass.yaml" <<YAML
+tests:
+  - name: team member reads high and low
+    subject: '{"team": "policy-test"}'
+    entitled:
+      - value: $NS_FQN/attr/level/value/high
+        actions: [read]
+      - value: $NS_FQN/attr/level/value/low
+  - name: outsider is denied
+    subject: '{"team": "other"}'
+    denied:
+      - value: $NS_FQN/attr/level/value/high
+YAML
+  cat > "$TESTS_DIR/fail.yaml" <<YAML
+tests:
+  - name: outsider reads high
+    subject: '{"team": "other"}'
+    entitled:
+      - value: $NS_FQN/attr/level/value/high
+YAML
+}
+
+setup() {
+  load "${BATS_LIB_PATH}/bats-support/load.bash"
+  load "${BATS_LIB_PATH}/bats-assert/load.bash"
+
+  # invoke binary with credentials
+  run_otdfctl_test () {
+    run sh -c "./otdfctl $HOST $WITH_CREDS policy test $*"
+  }
+}
+
+teardown_file() {
+  ./otdfctl $HOST $WITH_CREDS policy attributes namespaces unsafe delete --force --id "$NS_ID"
+
+  unset HOST WITH_CREDS NS_NAME NS_FQN NS_ID TESTS_DIR
+}
+
+@test "Policy tests pass with local evaluation" {
+  run_otdfctl_test "$TESTS_DIR/pass.yaml" --local --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.passed')" "2"
+}
+
+@test "Policy tests fail and write JUnit XML" {
+  run_otdfctl_test "$TESTS_DIR/fail.yaml" --local --junit "$TESTS_DIR/results.xml"
+  assert_failure
+  assert_output --partial "FAIL"
+  run cat "$TESTS_DIR/results.xml"
+  assert_output --partial '<failure message="1 expectations not met">'
+}
+
+@test "Policy tests reject an invalid file" {
+  echo "tests: []" > "$TESTS_DIR/empty.yaml"
+  run_otdfctl_test "$TESTS_DIR/empty.yaml"
+  assert_failure
+  assert_output --partial "invalid policy test file"
+}
@@ -277,3 +277,85 @@ teardown() {
   refute_output --partial "$profile1"
   refute_output --partial "$profile2"
 }
+
+@test "profile export and import round trip a bundle" {
+  base="${PROFILE_TEST_PREFIX}-export"
+  profile1="${base}-1"
+  profile2="${base}-2"
+  bundle="${BATS_TEST_TMPDIR}/profiles.yaml"
+
+  run_otdfctl create "$profile1" http://localhost:8080 --set-default --login-client-id cli-client
+  assert_success
+
+  run_otdfctl create "$profile2" http://localhost:8081 --output-format json
+  assert_success
+
+  # credentials are left out unless asked for
+  run_otdfctl export "$profile1" "$profile2" --file "$bundle"
+  assert_success
+  assert_output --partial "Exported 2 profiles from filesystem to ${bundle}"
+
+  run cat "$bundle"
+  assert_output --partial "name: ${profile1}"
+  assert_output --partial "loginClientId: cli-client"
+  assert_output --partial "outputFormat: json"
+  refute_output --partial "authCredentials"
+
+  run_otdfctl delete-all --force
+  assert_success
+
+  run_otdfctl import "$bundle"
+  assert_success
+  assert_output --partial "Imported 2 profiles"
+  assert_output --partial "created  ${profile1}"
+  assert_output --partial "Set profile ${profile1} as default"
+
+  run_otdfctl list
+  assert_success
+  assert_output --partial "* ${profile1}"
+  assert_output --partial "  ${profile2}"
+
+  run_otdfctl get "$profile1"
+  assert_success
+  assert_output --partial "cli-client"
+
+  # existing profiles are skipped unless overwritten
+  run_otdfctl import "$bundle"
+  assert_success
+  assert_output --partial "skipped  ${profile1}"
+
+  run_otdfctl import "$bundle" --json
+  assert_success
+  assert_equal "$(echo "$output" | jq -r '.skipped | length')" "2"
+
+  run_otdfctl import "$bundle" --overwrite
+  assert_success
+  assert_output --partial "updated  ${profile1}"
+}
+
+@test "profile import onboards a team bundle from stdin" {
+  base="${PROFILE_TEST_PREFIX}-team"
+
+  run bash -c "cat <<YAML | ./otdfctl profile import
+profiles:
+  - name: ${base}-dev
+    endpoint: http://localhost:8080
+    tlsNoVerify: true
+    loginClientId: cli-client
+  - name: ${base}-prod
+    endpoint: https://platform.example.com
+    outputFormat: json
+    default: true
+YAML"
+  assert_success
+  assert_output --partial "Imported 2 profiles"
+
+  run_otdfctl list
+  assert_success
+  assert_output --partial "  ${base}-dev"
+  assert_output --partial "* ${base}-prod"
+
+  run bash -c "printf 'profiles:\n  - name: ${base}-bad\n    endpoint: localhost:8080\n' | ./otdfctl profile import"
+  assert_failure
+  assert_output --partial "Failed to read profile bundle"
+}
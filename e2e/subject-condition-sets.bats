@@ -198,3 +198,35 @@ teardown_file() {
     assert_output --partial "$UNMAPPED_ID"
     refute_output --partial "$MAPPED_ID"
 }
+
+@test "Create a SCS - from condition expression" {
+  run_otdfctl_scs create --conditions "'.team.name IN [\"CoolTool\", \"RadService\"] AND NOT .org.name IN [\"marketing\"]'" --json
+  assert_success
+  CREATED_ID=$(echo "$output" | jq -r '.id')
+  assert_equal "$(echo "$output" | jq -r '.subject_sets[0].condition_groups[0].conditions[1].operator')" "SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN"
+
+  run_otdfctl_scs get --id "$CREATED_ID"
+  assert_success
+  assert_output --partial 'Conditions'
+  assert_output --partial '.org.name NOT IN ["marketing"]'
+
+  run_delete_scs "$CREATED_ID"
+}
+
+@test "Create a SCS - invalid condition expression" {
+  run_otdfctl_scs create --conditions "'.team.name EQUALS [\"CoolTool\"]'"
+  assert_failure
+  assert_output --partial "unknown operator"
+}
+
+@test "Create a SCS - conditions conflict with JSON" {
+  run_otdfctl_scs create --subject-sets "'$SCS_1'" --conditions "'.team.name IN [\"CoolTool\"]'"
+  assert_failure
+  assert_output --partial "Only one of"
+}
+
+@test "Create a SCS - interactive conflicts with other input" {
+  run_otdfctl_scs create --interactive --conditions "'.team.name IN [\"CoolTool\"]'"
+  assert_failure
+  assert_output --partial "Only one of"
+}
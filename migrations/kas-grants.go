@@ -0,0 +1,612 @@
+//nolint:forbidigo,staticcheck // migration output requires direct terminal printing, and reads the deprecated KAS grants
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/opentdf/platform/protocol/go/policy/attributes"
+	"github.com/opentdf/platform/protocol/go/policy/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy/namespaces"
+)
+
+const (
+	grantTypeNamespace = "namespace"
+	grantTypeAttribute = "attribute"
+	grantTypeValue     = "value"
+)
+
+// KasGrantMigrationHandler defines the handler methods needed for KAS grant migration.
+// *handlers.Handler satisfies this interface implicitly.
+type KasGrantMigrationHandler interface {
+	ListKasGrants(ctx context.Context, kasID, kasURI string, limit, offset int32) ([]*kasregistry.KeyAccessServerGrants, *policy.PageResponse, error)
+	ListAllKasKeys(ctx context.Context) ([]*policy.KasKey, error)
+	ListAllKeyMappings(ctx context.Context, keySystemID string) ([]*kasregistry.KeyMapping, error)
+	AssignKeyToAttributeNamespace(ctx context.Context, namespace, keyID string) (*namespaces.NamespaceKey, error)
+	AssignKeyToAttribute(ctx context.Context, attr, keyID string) (*attributes.AttributeKey, error)
+	AssignKeyToAttributeValue(ctx context.Context, value, keyID string) (*attributes.ValueKey, error)
+	DeleteKasGrantFromNamespace(ctx context.Context, nsID string, kasID string) (*namespaces.NamespaceKeyAccessServer, error)
+	DeleteKasGrantFromAttribute(ctx context.Context, attrID string, kasID string) (*attributes.AttributeKeyAccessServer, error)
+	DeleteKasGrantFromValue(ctx context.Context, valID string, kasID string) (*attributes.ValueKeyAccessServer, error)
+}
+
+// KasGrantMigrationPrompter abstracts interactive prompts so they can be mocked in tests.
+type KasGrantMigrationPrompter interface {
+	// ConfirmGrantBackup prompts the user to confirm they have taken a backup.
+	ConfirmGrantBackup(removeGrants bool) (bool, error)
+
+	// SelectKasKey prompts the user to select which of the active keys of a KAS to map grants to.
+	// The returned string may be a key ID, optSkipResource, or optAbortAll.
+	SelectKasKey(subject string, keys []*policy.KasKey) (string, error)
+
+	// ConfirmKasKey shows the only active key of the granted KAS and asks the user to confirm,
+	// skip the grant, or abort. Returns the key ID, optSkipResource, or optAbortAll.
+	ConfirmKasKey(subject string, key *policy.KasKey) (string, error)
+}
+
+func (p *HuhPrompter) ConfirmGrantBackup(removeGrants bool) (bool, error) {
+	var backupResponse bool
+	styles := initMigrationDisplayStyles()
+
+	fmt.Println(styles.styleWarning.Render("WARNING: This operation will map KAS keys to the namespaces, attributes and values with KAS grants."))
+	if removeGrants {
+		fmt.Println(styles.styleWarning.Render("The KAS grants will be removed once their key is mapped."))
+	}
+	fmt.Println(styles.styleWarning.Render("It is STRONGLY recommended to take a complete backup of your system before proceeding.\n"))
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[bool]().
+				Title("Have you taken a complete backup? (yes/no): ").
+				Options(
+					huh.NewOption("yes", true),
+					huh.NewOption("no", false),
+					huh.NewOption("cancel", false),
+				).
+				Value(&backupResponse),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return false, errors.New("user aborted backup form")
+		}
+		return false, err
+	}
+	return backupResponse, nil
+}
+
+func (p *HuhPrompter) SelectKasKey(subject string, keys []*policy.KasKey) (string, error) {
+	opts := make([]huh.Option[string], 0, len(keys))
+	for _, k := range keys {
+		opts = append(opts, huh.NewOption(kasKeyLabel(k), k.GetKey().GetId()))
+	}
+	opts = append(opts,
+		huh.NewOption("Skip", optSkipResource),
+		huh.NewOption("Abort entire migration", optAbortAll),
+	)
+
+	var keyID string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("Select the key to map %s to:", subject)).
+				Options(opts...).
+				Value(&keyID),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", huh.ErrUserAborted
+		}
+		return "", err
+	}
+	return keyID, nil
+}
+
+func (p *HuhPrompter) ConfirmKasKey(subject string, key *policy.KasKey) (string, error) {
+	var choice string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(fmt.Sprintf("Map %s to key %s?", subject, kasKeyLabel(key))).
+				Options(
+					huh.NewOption(fmt.Sprintf("Confirm: %s", key.GetKey().GetKeyId()), key.GetKey().GetId()),
+					huh.NewOption("Skip this grant", optSkipResource),
+					huh.NewOption("Abort entire migration", optAbortAll),
+				).
+				Value(&choice),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", huh.ErrUserAborted
+		}
+		return "", err
+	}
+	return choice, nil
+}
+
+// KasGrantMigrationPlan holds a KAS grant on a namespace, attribute or value and the key of the granted KAS to map
+// in its place.
+type KasGrantMigrationPlan struct {
+	GrantType string
+	ObjectID  string
+	ObjectFQN string
+	KasID     string
+	KasURI    string
+	// Keys are the active keys of the granted KAS
+	Keys []*policy.KasKey
+	// MappedKeyID is the ID of a key of the granted KAS which is already mapped to the object
+	MappedKeyID string
+	// TargetKeyID is the ID of the key to map
+	TargetKeyID string
+	RemoveGrant bool
+	Commit      bool
+}
+
+func (p KasGrantMigrationPlan) subject() string {
+	return fmt.Sprintf("%s %s", p.GrantType, p.ObjectFQN)
+}
+
+// MigrateKasGrants is the main entry point for migrating KAS grants to key mappings.
+func MigrateKasGrants(ctx context.Context, h KasGrantMigrationHandler, prompter KasGrantMigrationPrompter, commit, interactive, removeGrants bool) error {
+	styles := initMigrationDisplayStyles()
+
+	plan, err := buildKasGrantPlan(ctx, h, removeGrants)
+	if err != nil {
+		return err
+	}
+
+	if len(plan) == 0 {
+		fmt.Println(styles.styleWarning.Render("No KAS grants found that need migration to key mappings."))
+		return nil
+	}
+
+	if commit {
+		didBackup, err := prompter.ConfirmGrantBackup(removeGrants)
+		if err != nil {
+			return err
+		}
+		if !didBackup {
+			return errors.New("user did not confirm backup")
+		}
+	}
+
+	switch {
+	case interactive && commit:
+		return runInteractiveKasGrantMigration(ctx, h, prompter, styles, plan)
+	case commit:
+		return runBatchKasGrantMigration(ctx, h, prompter, styles, plan)
+	default:
+		displayKasGrantPlan(styles, plan)
+		if interactive {
+			fmt.Println(styles.styleInfo.Render("\nNote: --interactive without --commit only shows a preview. Add --commit to apply changes."))
+		}
+	}
+
+	return nil
+}
+
+// buildKasGrantPlan lists every KAS grant with the active keys of the granted KAS. Grants already mapped to a key of
+// their KAS are left out, unless the grants are to be removed.
+func buildKasGrantPlan(ctx context.Context, h KasGrantMigrationHandler, removeGrants bool) ([]KasGrantMigrationPlan, error) {
+	grants, err := listAllKasGrants(ctx, h)
+	if err != nil {
+		return nil, err
+	}
+	if len(grants) == 0 {
+		return nil, nil
+	}
+
+	keys, err := h.ListAllKasKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list KAS keys: %w", err)
+	}
+	activeKeys := make(map[string][]*policy.KasKey)
+	keyIDs := make(map[string]string) // KAS URI and key ID to the key's system ID
+	for _, k := range keys {
+		keyIDs[k.GetKasUri()+"|"+k.GetKey().GetKeyId()] = k.GetKey().GetId()
+		if k.GetKey().GetKeyStatus() == policy.KeyStatus_KEY_STATUS_ACTIVE {
+			activeKeys[k.GetKasId()] = append(activeKeys[k.GetKasId()], k)
+		}
+	}
+
+	mappings, err := h.ListAllKeyMappings(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list key mappings: %w", err)
+	}
+	mapped := make(map[string]string) // grant type, object ID and KAS URI to the ID of the mapped key
+	for _, m := range mappings {
+		keyID := keyIDs[m.GetKasUri()+"|"+m.GetKid()]
+		for grantType, objects := range map[string][]*kasregistry.MappedPolicyObject{
+			grantTypeNamespace: m.GetNamespaceMappings(),
+			grantTypeAttribute: m.GetAttributeMappings(),
+			grantTypeValue:     m.GetValueMappings(),
+		} {
+			for _, o := range objects {
+				mapped[grantType+"|"+o.GetId()+"|"+m.GetKasUri()] = keyID
+			}
+		}
+	}
+
+	var plans []KasGrantMigrationPlan
+	for _, g := range grants {
+		kas := g.GetKeyAccessServer()
+		for grantType, objects := range map[string][]*kasregistry.GrantedPolicyObject{
+			grantTypeNamespace: g.GetNamespaceGrants(),
+			grantTypeAttribute: g.GetAttributeGrants(),
+			grantTypeValue:     g.GetValueGrants(),
+		} {
+			for _, o := range objects {
+				p := KasGrantMigrationPlan{
+					GrantType:   grantType,
+					ObjectID:    o.GetId(),
+					ObjectFQN:   o.GetFqn(),
+					KasID:       kas.GetId(),
+					KasURI:      kas.GetUri(),
+					Keys:        activeKeys[kas.GetId()],
+					MappedKeyID: mapped[grantType+"|"+o.GetId()+"|"+kas.GetUri()],
+					RemoveGrant: removeGrants,
+				}
+				if p.MappedKeyID != "" && !removeGrants {
+					continue
+				}
+				switch {
+				case p.MappedKeyID != "":
+					p.TargetKeyID = p.MappedKeyID
+				case len(p.Keys) == 1:
+					p.TargetKeyID = p.Keys[0].GetKey().GetId()
+				}
+				plans = append(plans, p)
+			}
+		}
+	}
+	sortKasGrantPlan(plans)
+	return plans, nil
+}
+
+// sortKasGrantPlan orders grants by KAS, then namespaces, attributes and values, then FQN.
+func sortKasGrantPlan(plans []KasGrantMigrationPlan) {
+	order := map[string]int{grantTypeNamespace: 0, grantTypeAttribute: 1, grantTypeValue: 2}
+	sort.SliceStable(plans, func(i, j int) bool {
+		a, b := plans[i], plans[j]
+		if a.KasURI != b.KasURI {
+			return a.KasURI < b.KasURI
+		}
+		if a.GrantType != b.GrantType {
+			return order[a.GrantType] < order[b.GrantType]
+		}
+		return a.ObjectFQN < b.ObjectFQN
+	})
+}
+
+// listAllKasGrants paginates through the grants of every KAS.
+func listAllKasGrants(ctx context.Context, h KasGrantMigrationHandler) ([]*kasregistry.KeyAccessServerGrants, error) {
+	var (
+		all      []*kasregistry.KeyAccessServerGrants
+		offset   int32
+		pageSize int32 = 100
+	)
+
+	for {
+		grants, _, err := h.ListKasGrants(ctx, "", "", pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list KAS grants: %w", err)
+		}
+
+		if len(grants) == 0 {
+			break
+		}
+
+		all = append(all, grants...)
+
+		qty := len(grants)
+		if qty > math.MaxInt32 || offset+int32(qty) < 0 {
+			return nil, errors.New("grant count exceeded safe limit")
+		}
+		offset += int32(qty)
+
+		if int32(qty) < pageSize {
+			break
+		}
+	}
+
+	return all, nil
+}
+
+// kasKeyLabel describes a key by its key ID and algorithm.
+func kasKeyLabel(k *policy.KasKey) string {
+	alg := strings.ToLower(strings.TrimPrefix(k.GetKey().GetKeyAlgorithm().String(), "ALGORITHM_"))
+	return fmt.Sprintf("%s (%s, ID: %s)", k.GetKey().GetKeyId(), alg, k.GetKey().GetId())
+}
+
+// findKasKey returns the key of the plan with the given ID.
+func findKasKey(p KasGrantMigrationPlan, keyID string) *policy.KasKey {
+	for _, k := range p.Keys {
+		if k.GetKey().GetId() == keyID {
+			return k
+		}
+	}
+	return nil
+}
+
+// displayKasGrantPlan shows a preview of the grants that would be migrated.
+func displayKasGrantPlan(styles *migrationDisplayStyles, plan []KasGrantMigrationPlan) {
+	fmt.Println(styles.styleTitle.Render("\nKAS Grants Migration Plan"))
+	fmt.Println(styles.styleSeparator.Render(styles.separatorText))
+	fmt.Printf("%s %d\n\n",
+		styles.styleInfo.Render("KAS grants to migrate to key mappings:"),
+		len(plan),
+	)
+
+	for i, p := range plan {
+		fmt.Printf("%s %s\n",
+			styles.styleInfo.Render(fmt.Sprintf("%d. %s:", i+1, strings.ToUpper(p.GrantType[:1])+p.GrantType[1:])),
+			styles.styleName.Render(p.ObjectFQN),
+		)
+		fmt.Printf("   %s %s\n", styles.styleInfo.Render("ID:"), styles.styleResourceID.Render(p.ObjectID))
+		fmt.Printf("   %s %s\n", styles.styleInfo.Render("KAS:"), styles.styleNamespace.Render(p.KasURI))
+
+		switch {
+		case p.MappedKeyID != "":
+			fmt.Printf("   %s %s\n",
+				styles.styleInfo.Render("Already mapped to key:"),
+				styles.styleValue.Render(p.MappedKeyID),
+			)
+		case len(p.Keys) == 0:
+			fmt.Printf("   %s\n",
+				styles.styleWarning.Render("The KAS has no active key - create or import one before migrating"),
+			)
+		case len(p.Keys) == 1:
+			fmt.Printf("   %s %s\n",
+				styles.styleInfo.Render("Key:"),
+				styles.styleValue.Render(kasKeyLabel(p.Keys[0])),
+			)
+		default:
+			fmt.Printf("   %s\n", styles.styleInfo.Render("The KAS has several active keys, one will be selected:"))
+			for _, k := range p.Keys {
+				fmt.Printf("     - %s\n", styles.styleValue.Render(kasKeyLabel(k)))
+			}
+		}
+		if p.RemoveGrant {
+			fmt.Printf("   %s\n", styles.styleInfo.Render("The grant will be removed"))
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Println(styles.styleSeparator.Render(styles.separatorText))
+	fmt.Println(styles.styleInfo.Render("\nRun with --commit to map keys in batch mode."))
+	fmt.Println(styles.styleInfo.Render("Run with --interactive --commit to confirm the key of each grant."))
+	fmt.Println(styles.styleInfo.Render("Add --remove-grants to remove each grant once its key is mapped."))
+}
+
+// runBatchKasGrantMigration maps each grant to the only active key of its KAS, and prompts once per KAS with several.
+func runBatchKasGrantMigration(ctx context.Context, h KasGrantMigrationHandler, prompter KasGrantMigrationPrompter, styles *migrationDisplayStyles, plan []KasGrantMigrationPlan) error {
+	displayKasGrantPlan(styles, plan)
+
+	// Phase 1: Select a key for each KAS with several active keys
+	selected := make(map[string]string)
+	for i := range plan {
+		p := plan[i]
+		if p.TargetKeyID != "" || len(p.Keys) <= 1 {
+			continue
+		}
+		keyID, seen := selected[p.KasID]
+		if !seen {
+			var err error
+			keyID, err = prompter.SelectKasKey(fmt.Sprintf("all grants of KAS '%s'", p.KasURI), p.Keys)
+			if err != nil {
+				return err
+			}
+			if keyID == optAbortAll {
+				return errors.New("migration aborted by user")
+			}
+			selected[p.KasID] = keyID
+		}
+		if keyID != optSkipResource {
+			plan[i].TargetKeyID = keyID
+		}
+	}
+
+	// Phase 2: Execute all migrations
+	successCount := 0
+	skippedCount := 0
+	failedGrants := make(map[string]string)
+
+	for _, p := range plan {
+		if p.TargetKeyID == "" {
+			if len(p.Keys) == 0 {
+				fmt.Println(styles.styleWarning.Render(fmt.Sprintf("Skipping %s: KAS %s has no active key", p.subject(), p.KasURI)))
+			}
+			skippedCount++
+			continue
+		}
+		p.Commit = true
+
+		fmt.Printf("%s %s to key %s...\n",
+			styles.styleInfo.Render("Migrating grant on"),
+			styles.styleName.Render(p.subject()),
+			styles.styleValue.Render(p.TargetKeyID),
+		)
+
+		if err := commitKasGrantMigration(ctx, h, p); err != nil {
+			fmt.Println(styles.styleWarning.Render(fmt.Sprintf("Failed to migrate grant on %s: %v", p.subject(), err)))
+			failedGrants[p.subject()] = err.Error()
+		} else {
+			fmt.Println(styles.styleAction.Render(fmt.Sprintf("  Successfully migrated grant on %s", p.subject())))
+			successCount++
+		}
+	}
+
+	printKasGrantMigrationSummary(styles, "\nBatch Migration Summary:", len(plan), successCount, skippedCount, failedGrants)
+	if len(failedGrants) > 0 {
+		return fmt.Errorf("%d of %d grants failed to migrate", len(failedGrants), len(plan))
+	}
+	return nil
+}
+
+// runInteractiveKasGrantMigration prompts per grant for the key to map.
+func runInteractiveKasGrantMigration(ctx context.Context, h KasGrantMigrationHandler, prompter KasGrantMigrationPrompter, styles *migrationDisplayStyles, plan []KasGrantMigrationPlan) error {
+	fmt.Println(styles.styleInfo.Render("Interactive mode: processing grants one by one..."))
+
+	var (
+		successCount int
+		skippedCount int
+		aborted      bool
+		failedGrants = make(map[string]string)
+	)
+
+	for i, p := range plan {
+		fmt.Println(styles.styleSeparator.Render(styles.separatorText))
+		fmt.Printf("%s %s (%s %s)\n",
+			styles.styleTitle.Render(fmt.Sprintf("Grant %d/%d:", i+1, len(plan))),
+			styles.styleName.Render(p.subject()),
+			styles.styleInfo.Render("KAS:"),
+			styles.styleNamespace.Render(p.KasURI),
+		)
+
+		var (
+			keyID     string
+			promptErr error
+		)
+		switch {
+		case p.MappedKeyID != "":
+			fmt.Printf("  %s %s\n", styles.styleInfo.Render("Already mapped to key:"), styles.styleValue.Render(p.MappedKeyID))
+			keyID = p.MappedKeyID
+		case len(p.Keys) == 0:
+			fmt.Println(styles.styleWarning.Render(fmt.Sprintf("  Skipping: KAS %s has no active key", p.KasURI)))
+			keyID = optSkipResource
+		case len(p.Keys) == 1:
+			keyID, promptErr = prompter.ConfirmKasKey(p.subject(), p.Keys[0])
+		default:
+			keyID, promptErr = prompter.SelectKasKey(p.subject(), p.Keys)
+		}
+
+		if promptErr != nil {
+			if errors.Is(promptErr, huh.ErrUserAborted) {
+				fmt.Println(styles.styleWarning.Render("Migration aborted by user."))
+				aborted = true
+				break
+			}
+			fmt.Println(styles.styleWarning.Render(fmt.Sprintf("Error during prompt: %v. Skipping grant.", promptErr)))
+			skippedCount++
+			continue
+		}
+
+		switch keyID {
+		case optSkipResource:
+			fmt.Println(styles.styleInfo.Render(fmt.Sprintf("Skipping grant on %s.", p.subject())))
+			skippedCount++
+			continue
+		case optAbortAll:
+			fmt.Println(styles.styleWarning.Render("Aborting migration."))
+			aborted = true
+			goto summary
+		}
+
+		if p.MappedKeyID == "" && findKasKey(p, keyID) == nil {
+			fmt.Println(styles.styleWarning.Render(fmt.Sprintf("Key %s is not an active key of KAS %s. Skipping grant.", keyID, p.KasURI)))
+			skippedCount++
+			continue
+		}
+
+		p.TargetKeyID = keyID
+		p.Commit = true
+
+		fmt.Printf("%s %s to key %s...\n",
+			styles.styleAction.Render("  Migrating"),
+			styles.styleName.Render(p.subject()),
+			styles.styleValue.Render(keyID),
+		)
+
+		if err := commitKasGrantMigration(ctx, h, p); err != nil {
+			fmt.Println(styles.styleWarning.Render(fmt.Sprintf("Failed to migrate grant on %s: %v", p.subject(), err)))
+			failedGrants[p.subject()] = err.Error()
+		} else {
+			fmt.Println(styles.styleAction.Render(fmt.Sprintf("  Successfully migrated grant on %s", p.subject())))
+			successCount++
+		}
+	}
+
+summary:
+	printKasGrantMigrationSummary(styles, "\nInteractive Migration Summary:", len(plan), successCount, skippedCount, failedGrants)
+
+	if aborted {
+		return errors.New("migration aborted by user")
+	}
+	if len(failedGrants) > 0 {
+		return fmt.Errorf("%d of %d grants failed to migrate", len(failedGrants), len(plan))
+	}
+
+	return nil
+}
+
+func printKasGrantMigrationSummary(styles *migrationDisplayStyles, title string, total, successCount, skippedCount int, failedGrants map[string]string) {
+	fmt.Println(styles.styleTitle.Render(title))
+	fmt.Printf("  Total Grants: %d\n", total)
+	fmt.Printf("  Successfully Migrated: %d\n", successCount)
+	fmt.Printf("  Skipped: %d\n", skippedCount)
+	fmt.Printf("  Failed: %d\n", len(failedGrants))
+	if len(failedGrants) > 0 {
+		fmt.Println(styles.styleWarning.Render("  Failed Grants:"))
+		for subject, errMsg := range failedGrants {
+			fmt.Printf("    - %s: %s\n", styles.styleName.Render(subject), errMsg)
+		}
+	}
+}
+
+// commitKasGrantMigration maps the target key to the granted object, then removes the grant if asked to.
+func commitKasGrantMigration(ctx context.Context, h KasGrantMigrationHandler, plan KasGrantMigrationPlan) error {
+	if !plan.Commit || plan.TargetKeyID == "" {
+		return errors.New("migration plan is not ready for commit")
+	}
+
+	// Step 1: Map the key, unless it already is
+	if plan.MappedKeyID == "" {
+		var err error
+		switch plan.GrantType {
+		case grantTypeNamespace:
+			_, err = h.AssignKeyToAttributeNamespace(ctx, plan.ObjectID, plan.TargetKeyID)
+		case grantTypeAttribute:
+			_, err = h.AssignKeyToAttribute(ctx, plan.ObjectID, plan.TargetKeyID)
+		case grantTypeValue:
+			_, err = h.AssignKeyToAttributeValue(ctx, plan.ObjectID, plan.TargetKeyID)
+		default:
+			return fmt.Errorf("unknown grant type %s", plan.GrantType)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to map key %s to %s %s: %w", plan.TargetKeyID, plan.GrantType, plan.ObjectID, err)
+		}
+	}
+
+	if !plan.RemoveGrant {
+		return nil
+	}
+
+	// Step 2: Remove the legacy grant
+	var err error
+	switch plan.GrantType {
+	case grantTypeNamespace:
+		_, err = h.DeleteKasGrantFromNamespace(ctx, plan.ObjectID, plan.KasID)
+	case grantTypeAttribute:
+		_, err = h.DeleteKasGrantFromAttribute(ctx, plan.ObjectID, plan.KasID)
+	case grantTypeValue:
+		_, err = h.DeleteKasGrantFromValue(ctx, plan.ObjectID, plan.KasID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to remove the grant of KAS %s from %s %s (key %s was mapped successfully - manual cleanup may be needed): %w",
+			plan.KasID, plan.GrantType, plan.ObjectID, plan.TargetKeyID, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,317 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/opentdf/platform/protocol/go/policy/attributes"
+	"github.com/opentdf/platform/protocol/go/policy/kasregistry"
+	"github.com/opentdf/platform/protocol/go/policy/namespaces"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// MockKasGrantHandler implements KasGrantMigrationHandler for testing.
+type MockKasGrantHandler struct {
+	Grants   []*kasregistry.KeyAccessServerGrants
+	Keys     []*policy.KasKey
+	Mappings []*kasregistry.KeyMapping
+
+	// Track calls, as "<grant type>:<object ID>:<key or KAS ID>"
+	Assigned []string
+	Removed  []string
+
+	// Control behavior
+	AssignErr error
+	RemoveErr error
+}
+
+func (m *MockKasGrantHandler) ListKasGrants(_ context.Context, _, _ string, limit, offset int32) ([]*kasregistry.KeyAccessServerGrants, *policy.PageResponse, error) {
+	start := int(offset)
+	if start >= len(m.Grants) {
+		return nil, &policy.PageResponse{}, nil
+	}
+	end := start + int(limit)
+	if end > len(m.Grants) {
+		end = len(m.Grants)
+	}
+	return m.Grants[start:end], &policy.PageResponse{}, nil
+}
+
+func (m *MockKasGrantHandler) ListAllKasKeys(_ context.Context) ([]*policy.KasKey, error) {
+	return m.Keys, nil
+}
+
+func (m *MockKasGrantHandler) ListAllKeyMappings(_ context.Context, _ string) ([]*kasregistry.KeyMapping, error) {
+	return m.Mappings, nil
+}
+
+func (m *MockKasGrantHandler) assign(grantType, objectID, keyID string) error {
+	m.Assigned = append(m.Assigned, grantType+":"+objectID+":"+keyID)
+	return m.AssignErr
+}
+
+func (m *MockKasGrantHandler) remove(grantType, objectID, kasID string) error {
+	m.Removed = append(m.Removed, grantType+":"+objectID+":"+kasID)
+	return m.RemoveErr
+}
+
+func (m *MockKasGrantHandler) AssignKeyToAttributeNamespace(_ context.Context, namespace, keyID string) (*namespaces.NamespaceKey, error) {
+	return &namespaces.NamespaceKey{}, m.assign(grantTypeNamespace, namespace, keyID)
+}
+
+func (m *MockKasGrantHandler) AssignKeyToAttribute(_ context.Context, attr, keyID string) (*attributes.AttributeKey, error) {
+	return &attributes.AttributeKey{}, m.assign(grantTypeAttribute, attr, keyID)
+}
+
+func (m *MockKasGrantHandler) AssignKeyToAttributeValue(_ context.Context, value, keyID string) (*attributes.ValueKey, error) {
+	return &attributes.ValueKey{}, m.assign(grantTypeValue, value, keyID)
+}
+
+func (m *MockKasGrantHandler) DeleteKasGrantFromNamespace(_ context.Context, nsID string, kasID string) (*namespaces.NamespaceKeyAccessServer, error) {
+	return &namespaces.NamespaceKeyAccessServer{}, m.remove(grantTypeNamespace, nsID, kasID)
+}
+
+func (m *MockKasGrantHandler) DeleteKasGrantFromAttribute(_ context.Context, attrID string, kasID string) (*attributes.AttributeKeyAccessServer, error) {
+	return &attributes.AttributeKeyAccessServer{}, m.remove(grantTypeAttribute, attrID, kasID)
+}
+
+func (m *MockKasGrantHandler) DeleteKasGrantFromValue(_ context.Context, valID string, kasID string) (*attributes.ValueKeyAccessServer, error) {
+	return &attributes.ValueKeyAccessServer{}, m.remove(grantTypeValue, valID, kasID)
+}
+
+// MockKasGrantPrompter implements KasGrantMigrationPrompter for testing.
+type MockKasGrantPrompter struct {
+	ConfirmBackupResponse bool
+
+	// SelectKeyResponses and ConfirmKeyResponses are returned in order, one per call.
+	SelectKeyResponses  []string
+	ConfirmKeyResponses []string
+	ConfirmKeyErr       error
+
+	SelectKeySubjects []string
+}
+
+func (m *MockKasGrantPrompter) ConfirmGrantBackup(_ bool) (bool, error) {
+	return m.ConfirmBackupResponse, nil
+}
+
+func (m *MockKasGrantPrompter) SelectKasKey(subject string, _ []*policy.KasKey) (string, error) {
+	m.SelectKeySubjects = append(m.SelectKeySubjects, subject)
+	if len(m.SelectKeyResponses) == 0 {
+		return "", errors.New("no more mock SelectKasKey responses configured")
+	}
+	r := m.SelectKeyResponses[0]
+	m.SelectKeyResponses = m.SelectKeyResponses[1:]
+	return r, nil
+}
+
+func (m *MockKasGrantPrompter) ConfirmKasKey(_ string, _ *policy.KasKey) (string, error) {
+	if m.ConfirmKeyErr != nil {
+		return "", m.ConfirmKeyErr
+	}
+	if len(m.ConfirmKeyResponses) == 0 {
+		return "", errors.New("no more mock ConfirmKasKey responses configured")
+	}
+	r := m.ConfirmKeyResponses[0]
+	m.ConfirmKeyResponses = m.ConfirmKeyResponses[1:]
+	return r, nil
+}
+
+func kasKey(kasID, kasURI, id, kid string, status policy.KeyStatus) *policy.KasKey {
+	return &policy.KasKey{
+		KasId:  kasID,
+		KasUri: kasURI,
+		Key: &policy.AsymmetricKey{
+			Id:           id,
+			KeyId:        kid,
+			KeyAlgorithm: policy.Algorithm_ALGORITHM_EC_P256,
+			KeyStatus:    status,
+		},
+	}
+}
+
+func kasGrants(kasID, kasURI string, ns, attrs, vals []string) *kasregistry.KeyAccessServerGrants {
+	objects := func(ids []string) []*kasregistry.GrantedPolicyObject {
+		var o []*kasregistry.GrantedPolicyObject
+		for _, id := range ids {
+			o = append(o, &kasregistry.GrantedPolicyObject{Id: id, Fqn: "https://example.com/" + id})
+		}
+		return o
+	}
+	return &kasregistry.KeyAccessServerGrants{
+		KeyAccessServer: &policy.KeyAccessServer{Id: kasID, Uri: kasURI},
+		NamespaceGrants: objects(ns),
+		AttributeGrants: objects(attrs),
+		ValueGrants:     objects(vals),
+	}
+}
+
+func TestBuildKasGrantPlan(t *testing.T) {
+	handler := &MockKasGrantHandler{
+		Grants: []*kasregistry.KeyAccessServerGrants{
+			kasGrants("kas-b", "https://b.example.com", nil, []string{"attr-2"}, nil),
+			kasGrants("kas-a", "https://a.example.com", []string{"ns-1"}, []string{"attr-1"}, []string{"val-1"}),
+		},
+		Keys: []*policy.KasKey{
+			kasKey("kas-a", "https://a.example.com", "key-a1", "a1", policy.KeyStatus_KEY_STATUS_ACTIVE),
+			kasKey("kas-a", "https://a.example.com", "key-a0", "a0", policy.KeyStatus_KEY_STATUS_ROTATED),
+			kasKey("kas-b", "https://b.example.com", "key-b1", "b1", policy.KeyStatus_KEY_STATUS_ACTIVE),
+			kasKey("kas-b", "https://b.example.com", "key-b2", "b2", policy.KeyStatus_KEY_STATUS_ACTIVE),
+		},
+		Mappings: []*kasregistry.KeyMapping{{
+			Kid:               "a0",
+			KasUri:            "https://a.example.com",
+			AttributeMappings: []*kasregistry.MappedPolicyObject{{Id: "attr-1"}},
+		}},
+	}
+
+	t.Run("already mapped grants are left out", func(t *testing.T) {
+		plan, err := buildKasGrantPlan(context.Background(), handler, false)
+		require.NoError(t, err)
+
+		var got []string
+		for _, p := range plan {
+			got = append(got, p.GrantType+":"+p.ObjectID+":"+p.TargetKeyID)
+		}
+		assert.Equal(t, []string{
+			"namespace:ns-1:key-a1",
+			"value:val-1:key-a1",
+			"attribute:attr-2:",
+		}, got, "ordered by KAS, then namespaces, attributes and values; only a single active key is chosen")
+		assert.Len(t, plan[2].Keys, 2)
+	})
+
+	t.Run("already mapped grants are kept when removing grants", func(t *testing.T) {
+		plan, err := buildKasGrantPlan(context.Background(), handler, true)
+		require.NoError(t, err)
+		require.Len(t, plan, 4)
+		assert.Equal(t, "attr-1", plan[1].ObjectID)
+		assert.Equal(t, "key-a0", plan[1].MappedKeyID)
+		assert.Equal(t, "key-a0", plan[1].TargetKeyID)
+		assert.True(t, plan[1].RemoveGrant)
+	})
+}
+
+func TestMigrateKasGrants(t *testing.T) {
+	newHandler := func() *MockKasGrantHandler {
+		return &MockKasGrantHandler{
+			Grants: []*kasregistry.KeyAccessServerGrants{
+				kasGrants("kas-a", "https://a.example.com", []string{"ns-1"}, nil, nil),
+				kasGrants("kas-b", "https://b.example.com", nil, []string{"attr-1", "attr-2"}, nil),
+			},
+			Keys: []*policy.KasKey{
+				kasKey("kas-a", "https://a.example.com", "key-a1", "a1", policy.KeyStatus_KEY_STATUS_ACTIVE),
+				kasKey("kas-b", "https://b.example.com", "key-b1", "b1", policy.KeyStatus_KEY_STATUS_ACTIVE),
+				kasKey("kas-b", "https://b.example.com", "key-b2", "b2", policy.KeyStatus_KEY_STATUS_ACTIVE),
+			},
+		}
+	}
+
+	t.Run("preview makes no changes", func(t *testing.T) {
+		handler := newHandler()
+		err := MigrateKasGrants(context.Background(), handler, &MockKasGrantPrompter{}, false, false, true)
+		require.NoError(t, err)
+		assert.Empty(t, handler.Assigned)
+		assert.Empty(t, handler.Removed)
+	})
+
+	t.Run("batch commit prompts once per KAS with several keys", func(t *testing.T) {
+		handler := newHandler()
+		prompter := &MockKasGrantPrompter{ConfirmBackupResponse: true, SelectKeyResponses: []string{"key-b2"}}
+
+		err := MigrateKasGrants(context.Background(), handler, prompter, true, false, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"namespace:ns-1:key-a1",
+			"attribute:attr-1:key-b2",
+			"attribute:attr-2:key-b2",
+		}, handler.Assigned)
+		assert.Empty(t, handler.Removed)
+		assert.Equal(t, []string{"all grants of KAS 'https://b.example.com'"}, prompter.SelectKeySubjects)
+	})
+
+	t.Run("batch commit removes grants", func(t *testing.T) {
+		handler := newHandler()
+		prompter := &MockKasGrantPrompter{ConfirmBackupResponse: true, SelectKeyResponses: []string{optSkipResource}}
+
+		err := MigrateKasGrants(context.Background(), handler, prompter, true, false, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"namespace:ns-1:key-a1"}, handler.Assigned)
+		assert.Equal(t, []string{"namespace:ns-1:kas-a"}, handler.Removed, "skipped grants are kept")
+	})
+
+	t.Run("interactive commit prompts per grant", func(t *testing.T) {
+		handler := newHandler()
+		prompter := &MockKasGrantPrompter{
+			ConfirmBackupResponse: true,
+			ConfirmKeyResponses:   []string{optSkipResource},
+			SelectKeyResponses:    []string{"key-b1", "key-b2"},
+		}
+
+		err := MigrateKasGrants(context.Background(), handler, prompter, true, true, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"attribute:attr-1:key-b1", "attribute:attr-2:key-b2"}, handler.Assigned)
+	})
+
+	t.Run("interactive commit abort", func(t *testing.T) {
+		handler := newHandler()
+		prompter := &MockKasGrantPrompter{ConfirmBackupResponse: true, ConfirmKeyErr: huh.ErrUserAborted}
+
+		err := MigrateKasGrants(context.Background(), handler, prompter, true, true, false)
+		require.Error(t, err)
+		assert.Empty(t, handler.Assigned)
+	})
+
+	t.Run("backup not confirmed - returns error", func(t *testing.T) {
+		handler := newHandler()
+		err := MigrateKasGrants(context.Background(), handler, &MockKasGrantPrompter{}, true, false, false)
+		require.Error(t, err)
+		assert.Empty(t, handler.Assigned)
+	})
+
+	t.Run("no grants", func(t *testing.T) {
+		err := MigrateKasGrants(context.Background(), &MockKasGrantHandler{}, &MockKasGrantPrompter{}, true, false, false)
+		require.NoError(t, err)
+	})
+}
+
+func TestCommitKasGrantMigration(t *testing.T) {
+	plan := KasGrantMigrationPlan{GrantType: grantTypeValue, ObjectID: "val-1", KasID: "kas-a", TargetKeyID: "key-a1", Commit: true}
+
+	t.Run("not ready for commit", func(t *testing.T) {
+		p := plan
+		p.Commit = false
+		require.Error(t, commitKasGrantMigration(context.Background(), &MockKasGrantHandler{}, p))
+	})
+
+	t.Run("already mapped key only removes the grant", func(t *testing.T) {
+		handler := &MockKasGrantHandler{}
+		p := plan
+		p.MappedKeyID = "key-a1"
+		p.RemoveGrant = true
+		require.NoError(t, commitKasGrantMigration(context.Background(), handler, p))
+		assert.Empty(t, handler.Assigned)
+		assert.Equal(t, []string{"value:val-1:kas-a"}, handler.Removed)
+	})
+
+	t.Run("grant is kept when mapping fails", func(t *testing.T) {
+		handler := &MockKasGrantHandler{AssignErr: errors.New("boom")}
+		p := plan
+		p.RemoveGrant = true
+		err := commitKasGrantMigration(context.Background(), handler, p)
+		require.ErrorContains(t, err, "boom")
+		assert.Empty(t, handler.Removed)
+	})
+
+	t.Run("failed removal reports the mapped key", func(t *testing.T) {
+		handler := &MockKasGrantHandler{RemoveErr: errors.New("boom")}
+		p := plan
+		p.RemoveGrant = true
+		err := commitKasGrantMigration(context.Background(), handler, p)
+		require.ErrorContains(t, err, "key-a1 was mapped successfully")
+	})
+}
@@ -172,6 +172,12 @@ func GetSDKAuthOptionFromProfile(profile *profiles.OtdfctlProfileStore) (sdk.Opt
 	case profiles.AuthTypeAccessToken:
 		tokenSource := oauth2.StaticTokenSource(buildToken(&c))
 		return sdk.WithOAuthAccessTokenSource(tokenSource), nil
+	case profiles.AuthTypeWorkloadIdentity:
+		tokenSource, err := workloadTokenSourceForProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		return sdk.WithOAuthAccessTokenSource(tokenSource), nil
 	default:
 		return nil, ErrInvalidAuthType
 	}
@@ -193,6 +199,14 @@ func ValidateProfileAuthCredentials(ctx context.Context, profile *profiles.Otdfc
 		if !buildToken(&c).Valid() {
 			return ErrAccessTokenExpired
 		}
+	case profiles.AuthTypeWorkloadIdentity:
+		tokenSource, err := workloadTokenSourceForProfile(profile)
+		if err != nil {
+			return err
+		}
+		if _, err := tokenSource.Token(); err != nil {
+			return err
+		}
 	default:
 		return ErrInvalidAuthType
 	}
@@ -207,6 +221,12 @@ func GetTokenWithProfile(ctx context.Context, profile *profiles.OtdfctlProfileSt
 		return GetTokenWithClientCreds(ctx, profile.GetEndpoint(), c.ClientID, c.ClientSecret, profile.GetTLSNoVerify(), c.Scopes)
 	case profiles.AuthTypeAccessToken:
 		return buildToken(&c), nil
+	case profiles.AuthTypeWorkloadIdentity:
+		tokenSource, err := workloadTokenSourceForProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		return tokenSource.Token()
 	default:
 		return nil, ErrInvalidAuthType
 	}
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-jose/go-jose/v3/jwt"
+)
+
+const (
+	TokenHealthValid        = "valid"
+	TokenHealthExpiringSoon = "expiring soon"
+	TokenHealthExpired      = "expired"
+	TokenHealthNotYetValid  = "not yet valid"
+
+	// tokens expiring within this window are reported as expiring soon
+	tokenExpiringSoonWindow = 5 * time.Minute
+)
+
+// TokenInfo is the decoded, unverified claim set of an access token along with its health at decode time.
+type TokenInfo struct {
+	Issuer    string         `json:"issuer"`
+	Audience  []string       `json:"audience"`
+	Subject   string         `json:"subject"`
+	ClientID  string         `json:"client_id"`
+	Scopes    []string       `json:"scopes"`
+	IssuedAt  *time.Time     `json:"issued_at,omitempty"`
+	NotBefore *time.Time     `json:"not_before,omitempty"`
+	ExpiresAt *time.Time     `json:"expires_at,omitempty"`
+	ExpiresIn string         `json:"expires_in,omitempty"`
+	Health    string         `json:"health"`
+	Claims    map[string]any `json:"claims"`
+}
+
+// DecodeTokenInfo decodes the claims of a JWT access token without verifying its signature.
+func DecodeTokenInfo(accessToken string, now time.Time) (TokenInfo, error) {
+	info := TokenInfo{}
+	tok, err := jwt.ParseSigned(accessToken)
+	if err != nil {
+		return info, errors.Join(ErrParsingAccessToken, err)
+	}
+
+	std := jwt.Claims{}
+	all := map[string]any{}
+	if err := tok.UnsafeClaimsWithoutVerification(&std, &all); err != nil {
+		return info, errors.Join(ErrParsingAccessToken, err)
+	}
+
+	info.Issuer = std.Issuer
+	info.Audience = std.Audience
+	info.Subject = std.Subject
+	info.Claims = all
+	info.ClientID = firstStringClaim(all, "client_id", "azp", "cid", "clientId")
+	info.Scopes = scopesFromClaims(all)
+
+	if std.IssuedAt != nil {
+		t := std.IssuedAt.Time()
+		info.IssuedAt = &t
+	}
+	if std.NotBefore != nil {
+		t := std.NotBefore.Time()
+		info.NotBefore = &t
+	}
+
+	info.Health = TokenHealthValid
+	if std.Expiry != nil {
+		t := std.Expiry.Time()
+		info.ExpiresAt = &t
+		remaining := t.Sub(now)
+		switch {
+		case remaining <= 0:
+			info.Health = TokenHealthExpired
+			info.ExpiresIn = "expired " + (-remaining).Round(time.Second).String() + " ago"
+		case remaining < tokenExpiringSoonWindow:
+			info.Health = TokenHealthExpiringSoon
+			info.ExpiresIn = remaining.Round(time.Second).String()
+		default:
+			info.ExpiresIn = remaining.Round(time.Second).String()
+		}
+	}
+	if info.NotBefore != nil && now.Before(*info.NotBefore) {
+		info.Health = TokenHealthNotYetValid
+	}
+
+	return info, nil
+}
+
+func firstStringClaim(claims map[string]any, names ...string) string {
+	for _, n := range names {
+		if v, ok := claims[n].(string); ok && v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// scopesFromClaims supports both the space-delimited 'scope' claim and the array form 'scp' used by some IdPs
+func scopesFromClaims(claims map[string]any) []string {
+	var scopes []string
+	for _, n := range []string{"scope", "scp"} {
+		switch v := claims[n].(type) {
+		case string:
+			scopes = append(scopes, strings.Fields(v)...)
+		case []any:
+			for _, s := range v {
+				if str, ok := s.(string); ok {
+					scopes = append(scopes, str)
+				}
+			}
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/go-jose/go-jose/v3/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signTestToken(t *testing.T, claims ...any) string {
+	t.Helper()
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte("0123456789abcdef0123456789abcdef")}, nil)
+	require.NoError(t, err)
+	b := jwt.Signed(signer)
+	for _, c := range claims {
+		b = b.Claims(c)
+	}
+	tok, err := b.CompactSerialize()
+	require.NoError(t, err)
+	return tok
+}
+
+func TestDecodeTokenInfo(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tok := signTestToken(t,
+		jwt.Claims{
+			Issuer:   "https://idp.example.com/realms/opentdf",
+			Subject:  "1234",
+			Audience: jwt.Audience{"http://localhost:8080", "account"},
+			IssuedAt: jwt.NewNumericDate(now.Add(-time.Minute)),
+			Expiry:   jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		map[string]any{
+			"azp":   "opentdf",
+			"scope": "openid profile email",
+		},
+	)
+
+	info, err := DecodeTokenInfo(tok, now)
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.example.com/realms/opentdf", info.Issuer)
+	assert.Equal(t, "1234", info.Subject)
+	assert.Equal(t, []string{"http://localhost:8080", "account"}, info.Audience)
+	assert.Equal(t, "opentdf", info.ClientID)
+	assert.Equal(t, []string{"email", "openid", "profile"}, info.Scopes)
+	assert.Equal(t, TokenHealthValid, info.Health)
+	assert.Equal(t, "1h0m0s", info.ExpiresIn)
+	assert.Equal(t, "opentdf", info.Claims["azp"])
+}
+
+func TestDecodeTokenInfo_Health(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	tests := []struct {
+		name   string
+		claims jwt.Claims
+		health string
+	}{
+		{name: "expired", claims: jwt.Claims{Expiry: jwt.NewNumericDate(now.Add(-time.Second))}, health: TokenHealthExpired},
+		{name: "expiring soon", claims: jwt.Claims{Expiry: jwt.NewNumericDate(now.Add(time.Minute))}, health: TokenHealthExpiringSoon},
+		{name: "not yet valid", claims: jwt.Claims{NotBefore: jwt.NewNumericDate(now.Add(time.Minute))}, health: TokenHealthNotYetValid},
+		{name: "no expiry", claims: jwt.Claims{Subject: "s"}, health: TokenHealthValid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := DecodeTokenInfo(signTestToken(t, tt.claims), now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.health, info.Health)
+		})
+	}
+}
+
+func TestDecodeTokenInfo_ScpArrayClaim(t *testing.T) {
+	tok := signTestToken(t, map[string]any{"scp": []string{"b", "a"}, "client_id": "svc"})
+	info, err := DecodeTokenInfo(tok, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, info.Scopes)
+	assert.Equal(t, "svc", info.ClientID)
+}
+
+func TestDecodeTokenInfo_NotJWT(t *testing.T) {
+	_, err := DecodeTokenInfo("opaque-token", time.Now())
+	require.ErrorIs(t, err, ErrParsingAccessToken)
+}
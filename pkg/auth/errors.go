@@ -10,4 +10,8 @@ var (
 	ErrUnauthenticated            = errors.New("not logged in")
 	ErrParsingAccessToken         = errors.New("failed to parse access token")
 	ErrProfileCredentialsNotFound = errors.New("profile missing credentials")
+	ErrInvalidWorkloadIdentity    = errors.New("invalid workload identity configuration")
+	ErrSubjectTokenNotFound       = errors.New("workload identity token not found")
+	ErrSubjectTokenExpired        = errors.New("workload identity token expired")
+	ErrTokenExchangeFailed        = errors.New("failed to exchange workload identity token")
 )
@@ -0,0 +1,255 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentdf/otdfctl/pkg/profiles"
+	"github.com/opentdf/otdfctl/pkg/utils"
+	"golang.org/x/oauth2"
+)
+
+const (
+	grantTypeTokenExchange       = "urn:ietf:params:oauth:grant-type:token-exchange"
+	grantTypeClientCredentials   = "client_credentials"
+	clientAssertionTypeJWTBearer = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+	TokenTypeJWT         = "urn:ietf:params:oauth:token-type:jwt"
+	TokenTypeIDToken     = "urn:ietf:params:oauth:token-type:id_token"
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	// exchanged tokens are renewed slightly before they expire to absorb clock skew and request latency
+	workloadTokenExpiryDelta = 30 * time.Second
+	maxTokenResponseBytes    = 1 << 20
+)
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	IssuedTokenType  string `json:"issued_token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// WorkloadIdentityTokenSource is an oauth2.TokenSource which exchanges an externally issued identity token for a
+// platform access token. The identity token is re-read on every call so rotated tokens are picked up, and a new
+// exchange is performed when the identity token changes or the access token is about to expire.
+type WorkloadIdentityTokenSource struct {
+	mu sync.Mutex
+
+	endpoint      string
+	tlsNoVerify   bool
+	tokenEndpoint string
+	httpClient    *http.Client
+	clientID      string
+	scopes        []string
+	wi            profiles.AuthCredentialsWorkloadIdentity
+
+	subjectToken string
+	token        *oauth2.Token
+}
+
+// ValidateWorkloadIdentity checks that the workload identity credentials are complete.
+func ValidateWorkloadIdentity(c profiles.AuthCredentials) error {
+	wi := c.WorkloadIdentity
+	if wi == nil {
+		return errors.Join(ErrInvalidWorkloadIdentity, errors.New("missing workload identity configuration"))
+	}
+	if (wi.TokenFile == "") == (wi.TokenEnv == "") {
+		return errors.Join(ErrInvalidWorkloadIdentity, errors.New("exactly one of a token file or token environment variable is required"))
+	}
+	switch wi.Grant {
+	case profiles.WorkloadIdentityGrantTokenExchange:
+	case profiles.WorkloadIdentityGrantJWTBearer:
+		if c.ClientID == "" {
+			return errors.Join(ErrInvalidWorkloadIdentity, errors.New("a client ID is required for the jwt-bearer grant"))
+		}
+	default:
+		return errors.Join(ErrInvalidWorkloadIdentity, fmt.Errorf("unknown grant %q", wi.Grant))
+	}
+	return nil
+}
+
+// NewWorkloadIdentityTokenSource creates a token source for the platform at endpoint. The IdP token endpoint is
+// discovered from the platform well-known configuration on first use.
+func NewWorkloadIdentityTokenSource(endpoint string, tlsNoVerify bool, c profiles.AuthCredentials) (*WorkloadIdentityTokenSource, error) {
+	if err := ValidateWorkloadIdentity(c); err != nil {
+		return nil, err
+	}
+	return &WorkloadIdentityTokenSource{
+		endpoint:    endpoint,
+		tlsNoVerify: tlsNoVerify,
+		httpClient:  utils.NewHTTPClient(tlsNoVerify),
+		clientID:    c.ClientID,
+		scopes:      NormalizeScopes(c.Scopes),
+		wi:          *c.WorkloadIdentity,
+	}, nil
+}
+
+// ReadSubjectToken reads the current workload identity token from its file or environment variable.
+func ReadSubjectToken(wi profiles.AuthCredentialsWorkloadIdentity) (string, error) {
+	var tok string
+	if wi.TokenFile != "" {
+		b, err := os.ReadFile(wi.TokenFile)
+		if err != nil {
+			return "", errors.Join(ErrSubjectTokenNotFound, err)
+		}
+		tok = string(b)
+	} else {
+		v, ok := os.LookupEnv(wi.TokenEnv)
+		if !ok {
+			return "", errors.Join(ErrSubjectTokenNotFound, fmt.Errorf("environment variable %s is not set", wi.TokenEnv))
+		}
+		tok = v
+	}
+	tok = strings.TrimSpace(tok)
+	if tok == "" {
+		return "", ErrSubjectTokenNotFound
+	}
+
+	// opaque tokens cannot be checked locally, so only reject JWTs known to be expired
+	if claims, err := ParseClaimsJWT(tok); err == nil && claims.Expiration > 0 && time.Now().Unix() >= claims.Expiration {
+		return "", ErrSubjectTokenExpired
+	}
+	return tok, nil
+}
+
+func (s *WorkloadIdentityTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subject, err := ReadSubjectToken(s.wi)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated := s.subjectToken != "" && subject != s.subjectToken
+	if !rotated && s.token != nil && s.token.Expiry.After(time.Now().Add(workloadTokenExpiryDelta)) {
+		return s.token, nil
+	}
+	if rotated {
+		slog.Debug("Workload identity token rotated, exchanging for a new access token")
+	}
+
+	if s.tokenEndpoint == "" {
+		pc, err := getPlatformConfiguration(s.endpoint, s.tlsNoVerify)
+		if err != nil {
+			return nil, err
+		}
+		s.tokenEndpoint = pc.tokenEndpoint
+	}
+
+	tok, err := s.exchange(context.Background(), subject)
+	if err != nil {
+		return nil, err
+	}
+	s.subjectToken = subject
+	s.token = tok
+	return tok, nil
+}
+
+func (s *WorkloadIdentityTokenSource) exchange(ctx context.Context, subject string) (*oauth2.Token, error) {
+	form := url.Values{}
+	switch s.wi.Grant {
+	case profiles.WorkloadIdentityGrantJWTBearer:
+		form.Set("grant_type", grantTypeClientCredentials)
+		form.Set("client_assertion_type", clientAssertionTypeJWTBearer)
+		form.Set("client_assertion", subject)
+	default:
+		subjectTokenType := s.wi.SubjectTokenType
+		if subjectTokenType == "" {
+			subjectTokenType = TokenTypeJWT
+		}
+		form.Set("grant_type", grantTypeTokenExchange)
+		form.Set("subject_token", subject)
+		form.Set("subject_token_type", subjectTokenType)
+		form.Set("requested_token_type", TokenTypeAccessToken)
+		if s.wi.Audience != "" {
+			form.Set("audience", s.wi.Audience)
+		}
+	}
+	if s.clientID != "" {
+		form.Set("client_id", s.clientID)
+	}
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Join(ErrTokenExchangeFailed, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxTokenResponseBytes))
+	if err != nil {
+		return nil, errors.Join(ErrTokenExchangeFailed, err)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil {
+		return nil, errors.Join(ErrTokenExchangeFailed, fmt.Errorf("unexpected response (status %d)", resp.StatusCode))
+	}
+	if resp.StatusCode != http.StatusOK || tr.Error != "" {
+		msg := tr.Error
+		if tr.ErrorDescription != "" {
+			msg += ": " + tr.ErrorDescription
+		}
+		return nil, errors.Join(ErrTokenExchangeFailed, fmt.Errorf("status %d: %s", resp.StatusCode, msg))
+	}
+	if tr.AccessToken == "" {
+		return nil, errors.Join(ErrTokenExchangeFailed, errors.New("response did not contain an access token"))
+	}
+
+	tok := &oauth2.Token{
+		AccessToken: tr.AccessToken,
+		TokenType:   tr.TokenType,
+	}
+	if tr.ExpiresIn > 0 {
+		tok.Expiry = time.Now().Add(time.Duration(tr.ExpiresIn) * time.Second)
+	} else if claims, err := ParseClaimsJWT(tr.AccessToken); err == nil && claims.Expiration > 0 {
+		tok.Expiry = time.Unix(claims.Expiration, 0)
+	}
+	return tok, nil
+}
+
+var (
+	workloadTokenSourcesMu sync.Mutex
+	workloadTokenSources   = map[string]*WorkloadIdentityTokenSource{}
+)
+
+// workloadTokenSourceForProfile returns a token source shared by every caller using the same profile, so that a
+// single invocation does not exchange the identity token more than once.
+func workloadTokenSourceForProfile(profile *profiles.OtdfctlProfileStore) (*WorkloadIdentityTokenSource, error) {
+	workloadTokenSourcesMu.Lock()
+	defer workloadTokenSourcesMu.Unlock()
+
+	key := profile.Name() + "|" + profile.GetEndpoint()
+	if ts, ok := workloadTokenSources[key]; ok {
+		return ts, nil
+	}
+	ts, err := NewWorkloadIdentityTokenSource(profile.GetEndpoint(), profile.GetTLSNoVerify(), profile.GetAuthCredentials())
+	if err != nil {
+		return nil, err
+	}
+	workloadTokenSources[key] = ts
+	return ts, nil
+}
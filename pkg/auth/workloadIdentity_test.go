@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/profiles"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTokenServer(t *testing.T, check func(t *testing.T, r *http.Request)) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		require.NoError(t, r.ParseForm())
+		check(t, r)
+		w.Header().Set("Content-Type", "application/json")
+		//nolint:errcheck // test response
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "exchanged-" + r.Form.Get("subject_token") + r.Form.Get("client_assertion"),
+			"token_type":   "Bearer",
+			"expires_in":   300,
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func newTestTokenSource(t *testing.T, tokenEndpoint string, c profiles.AuthCredentials) *WorkloadIdentityTokenSource {
+	t.Helper()
+	ts, err := NewWorkloadIdentityTokenSource("http://localhost:8080", false, c)
+	require.NoError(t, err)
+	// skip well-known discovery
+	ts.tokenEndpoint = tokenEndpoint
+	return ts
+}
+
+func TestWorkloadIdentity_TokenExchangeRereadsRotatedFile(t *testing.T) {
+	srv, calls := newTestTokenServer(t, func(t *testing.T, r *http.Request) {
+		assert.Equal(t, grantTypeTokenExchange, r.Form.Get("grant_type"))
+		assert.Equal(t, TokenTypeJWT, r.Form.Get("subject_token_type"))
+		assert.Equal(t, TokenTypeAccessToken, r.Form.Get("requested_token_type"))
+		assert.Equal(t, "platform", r.Form.Get("audience"))
+		assert.Equal(t, "ci-runner", r.Form.Get("client_id"))
+	})
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("first\n"), 0o600))
+
+	ts := newTestTokenSource(t, srv.URL, profiles.AuthCredentials{
+		AuthType: profiles.AuthTypeWorkloadIdentity,
+		ClientID: "ci-runner",
+		WorkloadIdentity: &profiles.AuthCredentialsWorkloadIdentity{
+			Grant:     profiles.WorkloadIdentityGrantTokenExchange,
+			TokenFile: tokenFile,
+			Audience:  "platform",
+		},
+	})
+
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "exchanged-first", tok.AccessToken)
+
+	// cached while the subject token is unchanged
+	tok, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "exchanged-first", tok.AccessToken)
+	assert.Equal(t, int32(1), calls.Load())
+
+	// rotation triggers a new exchange
+	require.NoError(t, os.WriteFile(tokenFile, []byte("second"), 0o600))
+	tok, err = ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "exchanged-second", tok.AccessToken)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestWorkloadIdentity_JWTBearerFromEnv(t *testing.T) {
+	srv, _ := newTestTokenServer(t, func(t *testing.T, r *http.Request) {
+		assert.Equal(t, grantTypeClientCredentials, r.Form.Get("grant_type"))
+		assert.Equal(t, clientAssertionTypeJWTBearer, r.Form.Get("client_assertion_type"))
+		assert.Equal(t, "opentdf", r.Form.Get("client_id"))
+		assert.Equal(t, "openid profile", r.Form.Get("scope"))
+	})
+
+	t.Setenv("OTDFCTL_TEST_ID_TOKEN", "assertion")
+	ts := newTestTokenSource(t, srv.URL, profiles.AuthCredentials{
+		AuthType: profiles.AuthTypeWorkloadIdentity,
+		ClientID: "opentdf",
+		Scopes:   []string{"openid profile"},
+		WorkloadIdentity: &profiles.AuthCredentialsWorkloadIdentity{
+			Grant:    profiles.WorkloadIdentityGrantJWTBearer,
+			TokenEnv: "OTDFCTL_TEST_ID_TOKEN",
+		},
+	})
+
+	tok, err := ts.Token()
+	require.NoError(t, err)
+	assert.Equal(t, "exchanged-assertion", tok.AccessToken)
+}
+
+func TestWorkloadIdentity_ExchangeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		//nolint:errcheck // test response
+		w.Write([]byte(`{"error":"invalid_grant","error_description":"subject token rejected"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	t.Setenv("OTDFCTL_TEST_ID_TOKEN", "rejected")
+	ts := newTestTokenSource(t, srv.URL, profiles.AuthCredentials{
+		AuthType: profiles.AuthTypeWorkloadIdentity,
+		WorkloadIdentity: &profiles.AuthCredentialsWorkloadIdentity{
+			Grant:    profiles.WorkloadIdentityGrantTokenExchange,
+			TokenEnv: "OTDFCTL_TEST_ID_TOKEN",
+		},
+	})
+
+	_, err := ts.Token()
+	require.ErrorIs(t, err, ErrTokenExchangeFailed)
+	assert.Contains(t, err.Error(), "subject token rejected")
+}
+
+func TestValidateWorkloadIdentity(t *testing.T) {
+	tests := []struct {
+		name string
+		wi   *profiles.AuthCredentialsWorkloadIdentity
+	}{
+		{name: "missing config", wi: nil},
+		{name: "no token source", wi: &profiles.AuthCredentialsWorkloadIdentity{Grant: profiles.WorkloadIdentityGrantTokenExchange}},
+		{name: "both token sources", wi: &profiles.AuthCredentialsWorkloadIdentity{Grant: profiles.WorkloadIdentityGrantTokenExchange, TokenFile: "f", TokenEnv: "E"}},
+		{name: "jwt-bearer without client id", wi: &profiles.AuthCredentialsWorkloadIdentity{Grant: profiles.WorkloadIdentityGrantJWTBearer, TokenEnv: "E"}},
+		{name: "unknown grant", wi: &profiles.AuthCredentialsWorkloadIdentity{Grant: "password", TokenEnv: "E"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWorkloadIdentity(profiles.AuthCredentials{WorkloadIdentity: tt.wi})
+			require.ErrorIs(t, err, ErrInvalidWorkloadIdentity)
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Package basekey checks a key before it becomes the platform base key, works out which attribute values fall back to
+// the base key, and keeps a local history of the base keys set from a profile so that a change can be rolled back.
+package basekey
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/opentdf/otdfctl/pkg/doctor"
+)
+
+const (
+	CheckStatus    = "Status"
+	CheckAlgorithm = "Algorithm"
+	CheckKAS       = "KAS"
+
+	StatusActive = "active"
+
+	// MaxHistory is the number of base keys a history keeps
+	MaxHistory = 20
+
+	dirName  = "base-keys"
+	dirMode  = 0o700
+	fileMode = 0o600
+)
+
+var (
+	ErrNoRollback = errors.New("no previous base key to roll back to")
+
+	unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// algorithms every SDK client can encrypt with, and those only recent SDK versions support
+var (
+	universalAlgorithms = map[string]bool{"rsa:2048": true, "ec:secp256r1": true}
+	recentAlgorithms    = map[string]bool{"rsa:4096": true, "ec:secp384r1": true, "ec:secp521r1": true}
+)
+
+// Candidate is a key about to become the base key.
+type Candidate struct {
+	KeyID     string
+	Algorithm string
+	Status    string
+	KasURI    string
+}
+
+// Validate checks that the key is active, that SDK clients can encrypt with its algorithm and that its KAS serves a
+// key of that algorithm.
+func Validate(ctx context.Context, client *http.Client, c Candidate) []doctor.Check {
+	var checks []doctor.Check
+
+	if c.Status == StatusActive {
+		checks = append(checks, doctor.Check{Name: CheckStatus, Status: doctor.StatusPass, Message: "key is active"})
+	} else {
+		checks = append(checks, doctor.Check{
+			Name: CheckStatus, Status: doctor.StatusFail,
+			Message: fmt.Sprintf("key is %s, only an active key can be the base key", c.Status),
+		})
+	}
+
+	switch {
+	case universalAlgorithms[c.Algorithm]:
+		checks = append(checks, doctor.Check{
+			Name: CheckAlgorithm, Status: doctor.StatusPass, Message: c.Algorithm + " is supported by every SDK",
+		})
+	case recentAlgorithms[c.Algorithm]:
+		checks = append(checks, doctor.Check{
+			Name: CheckAlgorithm, Status: doctor.StatusWarn,
+			Message: c.Algorithm + " is only supported by recent SDK versions, older clients will fail to encrypt",
+		})
+	default:
+		checks = append(checks, doctor.Check{
+			Name: CheckAlgorithm, Status: doctor.StatusFail,
+			Message: fmt.Sprintf("algorithm '%s' is not supported by the SDK", c.Algorithm),
+		})
+	}
+
+	if _, kid, err := doctor.FetchKASPublicKey(ctx, client, c.KasURI, c.Algorithm); err != nil {
+		checks = append(checks, doctor.Check{
+			Name: CheckKAS, Status: doctor.StatusFail,
+			Message: fmt.Sprintf("could not fetch a %s key from %s: %s", c.Algorithm, c.KasURI, err),
+		})
+	} else {
+		checks = append(checks, doctor.Check{
+			Name: CheckKAS, Status: doctor.StatusPass,
+			Message: fmt.Sprintf("%s is reachable and serves %s key %s", c.KasURI, c.Algorithm, kid),
+		})
+	}
+	return checks
+}
+
+// Value is an attribute value, by ID and FQN.
+type Value struct {
+	ID  string
+	FQN string
+}
+
+// Attribute is an attribute definition with its namespace and values.
+type Attribute struct {
+	ID          string
+	FQN         string
+	NamespaceID string
+	Values      []Value
+}
+
+// Explicit holds the IDs of the namespaces, attributes and values with a key mapped or a KAS granted.
+type Explicit struct {
+	Namespaces map[string]bool
+	Attributes map[string]bool
+	Values     map[string]bool
+}
+
+// Fallback is an attribute with values that have no key of their own, through the value, the attribute or its
+// namespace, so they are encrypted with the base key.
+type Fallback struct {
+	FQN    string   `json:"fqn"`
+	Values []string `json:"values"`
+}
+
+// Fallbacks lists the attributes with values that fall back to the base key, by FQN.
+func Fallbacks(attrs []Attribute, explicit Explicit) []Fallback {
+	fallbacks := []Fallback{}
+	for _, a := range attrs {
+		if explicit.Namespaces[a.NamespaceID] || explicit.Attributes[a.ID] {
+			continue
+		}
+		var values []string
+		for _, v := range a.Values {
+			if !explicit.Values[v.ID] {
+				values = append(values, v.FQN)
+			}
+		}
+		if len(values) > 0 {
+			sort.Strings(values)
+			fallbacks = append(fallbacks, Fallback{FQN: a.FQN, Values: values})
+		}
+	}
+	sort.Slice(fallbacks, func(i, j int) bool { return fallbacks[i].FQN < fallbacks[j].FQN })
+	return fallbacks
+}
+
+// Entry is a base key set from the profile.
+type Entry struct {
+	ID        string    `json:"id,omitempty"`
+	KeyID     string    `json:"key_id"`
+	KasURI    string    `json:"kas_uri"`
+	Algorithm string    `json:"algorithm,omitempty"`
+	SetAt     time.Time `json:"set_at,omitzero"`
+}
+
+// Is reports whether the entry is the key with the given key ID in the given KAS.
+func (e Entry) Is(kasURI, keyID string) bool {
+	return e.KasURI == kasURI && e.KeyID == keyID
+}
+
+// History is the base keys of a platform, oldest first.
+type History struct {
+	Endpoint string  `json:"endpoint"`
+	Entries  []Entry `json:"entries"`
+}
+
+// Record adds a newly set base key. The base key it replaced is added first when it is not the last recorded one, so
+// that a base key set elsewhere can still be rolled back to. Only the last MaxHistory entries are kept.
+func (h *History) Record(previous *Entry, current Entry) {
+	if previous != nil && previous.KeyID != "" && !h.isLast(previous.KasURI, previous.KeyID) {
+		h.Entries = append(h.Entries, *previous)
+	}
+	if current.SetAt.IsZero() {
+		current.SetAt = time.Now().UTC()
+	}
+	h.Entries = append(h.Entries, current)
+	if len(h.Entries) > MaxHistory {
+		h.Entries = h.Entries[len(h.Entries)-MaxHistory:]
+	}
+}
+
+func (h *History) isLast(kasURI, keyID string) bool {
+	return len(h.Entries) > 0 && h.Entries[len(h.Entries)-1].Is(kasURI, keyID)
+}
+
+// RollbackTarget is the index of the most recent entry which is not the current base key.
+func (h *History) RollbackTarget(kasURI, keyID string) (int, error) {
+	for i := len(h.Entries) - 1; i >= 0; i-- {
+		if !h.Entries[i].Is(kasURI, keyID) {
+			return i, nil
+		}
+	}
+	return 0, ErrNoRollback
+}
+
+// RolledBack forgets the entries newer than the one rolled back to, which becomes the last entry.
+func (h *History) RolledBack(i int) {
+	h.Entries = h.Entries[:i+1]
+}
+
+// Store keeps the base key history of a profile in one file.
+type Store struct {
+	path string
+}
+
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// ForProfile opens the base key history of a profile within the config directory.
+func ForProfile(configDir, profile string) *Store {
+	if profile == "" {
+		profile = "default"
+	}
+	return NewStore(filepath.Join(configDir, dirName, unsafeFileChars.ReplaceAllString(profile, "_")+".json"))
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the history, which is empty when no base key was set from the profile yet.
+func (s *Store) Load() (*History, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &History{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var h History
+	if err := json.Unmarshal(b, &h); err != nil {
+		return nil, fmt.Errorf("invalid base key history %s: %w", s.path, err)
+	}
+	return &h, nil
+}
+
+// Save writes the history through a temporary file, so an interruption never leaves a partly written file.
+func (s *Store) Save(h *History) error {
+	b, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".base-keys-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(fileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
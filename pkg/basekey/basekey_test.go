@@ -0,0 +1,145 @@
+package basekey
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/doctor"
+	"github.com/opentdf/otdfctl/pkg/internal/kastest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func checkStatuses(checks []doctor.Check) map[string]string {
+	m := map[string]string{}
+	for _, c := range checks {
+		m[c.Name] = c.Status
+	}
+	return m
+}
+
+// newKAS stands in for a KAS serving a key of the algorithm only
+func newKAS(t *testing.T, algorithm string) *httptest.Server {
+	t.Helper()
+	return kastest.NewServer(t, doctor.KASPublicKeyRPCPath, map[string]kastest.Key{
+		algorithm: {KID: "k1", PEM: kastest.PublicKeyPEM(t)},
+	})
+}
+
+func TestValidate(t *testing.T) {
+	srv := newKAS(t, "ec:secp256r1")
+
+	checks := Validate(context.Background(), srv.Client(), Candidate{
+		KeyID: "k1", Algorithm: "ec:secp256r1", Status: StatusActive, KasURI: srv.URL,
+	})
+	assert.Equal(t, map[string]string{
+		CheckStatus: doctor.StatusPass, CheckAlgorithm: doctor.StatusPass, CheckKAS: doctor.StatusPass,
+	}, checkStatuses(checks))
+
+	checks = Validate(context.Background(), srv.Client(), Candidate{
+		KeyID: "k2", Algorithm: "ec:secp384r1", Status: "rotated", KasURI: srv.URL,
+	})
+	assert.Equal(t, map[string]string{
+		CheckStatus: doctor.StatusFail, CheckAlgorithm: doctor.StatusWarn, CheckKAS: doctor.StatusFail,
+	}, checkStatuses(checks), "a KAS that serves no key of the algorithm fails")
+
+	checks = Validate(context.Background(), srv.Client(), Candidate{
+		KeyID: "k3", Algorithm: "ALGORITHM_UNSPECIFIED", Status: StatusActive, KasURI: "http://127.0.0.1:1",
+	})
+	assert.Equal(t, doctor.StatusFail, checkStatuses(checks)[CheckAlgorithm])
+	assert.Equal(t, doctor.StatusFail, checkStatuses(checks)[CheckKAS], "an unreachable KAS fails")
+}
+
+func TestFallbacks(t *testing.T) {
+	attrs := []Attribute{
+		{ID: "a1", FQN: "https://b.com/attr/one", NamespaceID: "ns-mapped", Values: []Value{{ID: "v1", FQN: "https://b.com/attr/one/value/x"}}},
+		{ID: "a2", FQN: "https://a.com/attr/two", NamespaceID: "ns", Values: []Value{
+			{ID: "v2", FQN: "https://a.com/attr/two/value/y"},
+			{ID: "v3", FQN: "https://a.com/attr/two/value/mapped"},
+		}},
+		{ID: "a3", FQN: "https://a.com/attr/three", NamespaceID: "ns", Values: []Value{{ID: "v4", FQN: "https://a.com/attr/three/value/z"}}},
+		{ID: "a4", FQN: "https://a.com/attr/four", NamespaceID: "ns", Values: []Value{{ID: "v5", FQN: "https://a.com/attr/four/value/w"}}},
+		{ID: "a5", FQN: "https://a.com/attr/empty", NamespaceID: "ns"},
+	}
+	explicit := Explicit{
+		Namespaces: map[string]bool{"ns-mapped": true},
+		Attributes: map[string]bool{"a3": true},
+		Values:     map[string]bool{"v3": true},
+	}
+
+	assert.Equal(t, []Fallback{
+		{FQN: "https://a.com/attr/four", Values: []string{"https://a.com/attr/four/value/w"}},
+		{FQN: "https://a.com/attr/two", Values: []string{"https://a.com/attr/two/value/y"}},
+	}, Fallbacks(attrs, explicit))
+
+	assert.Empty(t, Fallbacks(nil, Explicit{}))
+}
+
+func TestHistory(t *testing.T) {
+	var h History
+
+	_, err := h.RollbackTarget("https://kas", "k1")
+	require.ErrorIs(t, err, ErrNoRollback)
+
+	// the first base key set from the profile records the one it replaced
+	h.Record(&Entry{KeyID: "k0", KasURI: "https://kas"}, Entry{KeyID: "k1", KasURI: "https://kas"})
+	h.Record(&Entry{KeyID: "k1", KasURI: "https://kas"}, Entry{KeyID: "k2", KasURI: "https://kas"})
+	require.Len(t, h.Entries, 3)
+	assert.False(t, h.Entries[2].SetAt.IsZero())
+
+	i, err := h.RollbackTarget("https://kas", "k2")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", h.Entries[i].KeyID)
+	h.RolledBack(i)
+	require.Len(t, h.Entries, 2)
+
+	i, err = h.RollbackTarget("https://kas", "k1")
+	require.NoError(t, err)
+	assert.Equal(t, "k0", h.Entries[i].KeyID)
+
+	// a base key changed elsewhere is the one to roll back to
+	i, err = h.RollbackTarget("https://kas", "elsewhere")
+	require.NoError(t, err)
+	assert.Equal(t, "k1", h.Entries[i].KeyID)
+}
+
+func TestHistory_Limit(t *testing.T) {
+	var h History
+	for i := 0; i < MaxHistory+5; i++ {
+		h.Record(nil, Entry{KeyID: strconv.Itoa(i), KasURI: "https://kas"})
+	}
+	require.Len(t, h.Entries, MaxHistory)
+	assert.Equal(t, strconv.Itoa(MaxHistory+4), h.Entries[MaxHistory-1].KeyID)
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	s := ForProfile(dir, "my profile")
+	assert.Equal(t, filepath.Join(dir, dirName, "my_profile.json"), s.Path())
+
+	h, err := s.Load()
+	require.NoError(t, err)
+	assert.Empty(t, h.Entries)
+
+	h.Endpoint = "https://platform"
+	h.Record(nil, Entry{KeyID: "k1", KasURI: "https://kas"})
+	require.NoError(t, s.Save(h))
+
+	info, err := os.Stat(s.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(fileMode), info.Mode().Perm())
+
+	loaded, err := s.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "https://platform", loaded.Endpoint)
+	require.Len(t, loaded.Entries, 1)
+	assert.Equal(t, "k1", loaded.Entries[0].KeyID)
+
+	require.NoError(t, os.WriteFile(s.Path(), []byte("{"), fileMode))
+	_, err = s.Load()
+	require.Error(t, err)
+}
@@ -16,6 +16,7 @@ const (
 	ActionDeactivate   = "deactivate"
 	ActionReactivate   = "reactivate"
 	ActionDelete       = "delete"
+	ActionUndo         = "undo"
 
 	// text input names
 	InputNameFQN        = "fully qualified name (FQN)"
@@ -69,9 +70,15 @@ func ConfirmAction(action, resource, id string, force bool) {
 }
 
 func ConfirmTextInput(action, resource, inputName, shouldMatchValue string) {
+	ConfirmTextInputSubtext(action, resource, inputName, shouldMatchValue, "")
+}
+
+// ConfirmTextInputSubtext is ConfirmTextInput with a description of the side effects shown under the prompt
+func ConfirmTextInputSubtext(action, resource, inputName, shouldMatchValue, subtext string) {
 	var input string
 	err := huh.NewInput().
 		Title(fmt.Sprintf("To confirm you want to %s this %s and accept any side effects, please enter the %s to proceed: %s", action, resource, inputName, shouldMatchValue)).
+		Description(subtext).
 		Value(&input).
 		Validate(func(s string) error {
 			if s != shouldMatchValue {
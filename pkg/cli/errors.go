@@ -11,6 +11,8 @@ import (
 const (
 	ExitCodeSuccess = 0
 	ExitCodeError   = 1
+	// ExitCodeDrift reports that the command ran but found differences, so CI can tell them from a failure
+	ExitCodeDrift = 2
 )
 
 func ExitWithError(errMsg string, err error) {
@@ -0,0 +1,222 @@
+// Package conditions implements a human-readable expression language for subject condition sets, e.g.
+//
+//	.groups[] IN ["admin", "ops"] AND .clearance IN ["secret"] OR NOT .contractor IN ["true"]
+//
+// Expressions may combine conditions with AND, OR, NOT and parentheses. AND binds tighter than OR. Because a subject
+// condition set can only express an AND of condition groups, each of which is an AND or an OR of conditions, an
+// expression is normalized into that form when it is compiled.
+package conditions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+const (
+	opIn         = "IN"
+	opNotIn      = "NOT IN"
+	opInContains = "IN_CONTAINS"
+
+	// maxGroups bounds the growth from distributing OR over AND while normalizing an expression
+	maxGroups = 64
+)
+
+var (
+	ErrSyntax        = errors.New("invalid condition expression")
+	ErrNegation      = errors.New("IN_CONTAINS conditions cannot be negated")
+	ErrTooComplex    = fmt.Errorf("condition expression expands to more than %d condition groups", maxGroups)
+	errUnexpectedEnd = errors.New("unexpected end of expression")
+)
+
+// node is an expression tree: a *policy.Condition leaf or an and/or/not of other nodes
+type node interface{}
+
+type (
+	andNode struct{ l, r node }
+	orNode  struct{ l, r node }
+	notNode struct{ x node }
+)
+
+// Parse compiles an expression into the subject sets of a subject condition set.
+func Parse(expr string) ([]*policy.SubjectSet, error) {
+	toks, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrSyntax, t.text, t.pos)
+	}
+
+	n, err = pushNot(n, false)
+	if err != nil {
+		return nil, err
+	}
+	clauses, err := cnf(n)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*policy.ConditionGroup
+	all := &policy.ConditionGroup{BooleanOperator: policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND}
+	for _, cl := range clauses {
+		if len(cl) == 1 {
+			all.Conditions = append(all.Conditions, cl[0])
+			continue
+		}
+		groups = append(groups, &policy.ConditionGroup{
+			BooleanOperator: policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR,
+			Conditions:      cl,
+		})
+	}
+	if len(all.GetConditions()) > 0 {
+		groups = append([]*policy.ConditionGroup{all}, groups...)
+	}
+	return []*policy.SubjectSet{{ConditionGroups: groups}}, nil
+}
+
+// Format renders subject sets as an expression which Parse compiles back to an equivalent condition set.
+func Format(sets []*policy.SubjectSet) string {
+	var clauses [][]string
+	for _, ss := range sets {
+		for _, g := range ss.GetConditionGroups() {
+			conds := make([]string, 0, len(g.GetConditions()))
+			for _, c := range g.GetConditions() {
+				conds = append(conds, formatCondition(c))
+			}
+			if g.GetBooleanOperator() == policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR {
+				clauses = append(clauses, conds)
+				continue
+			}
+			for _, c := range conds {
+				clauses = append(clauses, []string{c})
+			}
+		}
+	}
+
+	parts := make([]string, 0, len(clauses))
+	for _, cl := range clauses {
+		s := strings.Join(cl, " OR ")
+		if len(cl) > 1 && len(clauses) > 1 {
+			s = "(" + s + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func formatCondition(c *policy.Condition) string {
+	var op string
+	switch c.GetOperator() {
+	case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN:
+		op = opIn
+	case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN:
+		op = opNotIn
+	case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN_CONTAINS:
+		op = opInContains
+	default:
+		op = strings.TrimPrefix(c.GetOperator().String(), "SUBJECT_MAPPING_OPERATOR_ENUM_")
+	}
+	values := make([]string, 0, len(c.GetSubjectExternalValues()))
+	for _, v := range c.GetSubjectExternalValues() {
+		b, _ := json.Marshal(v)
+		values = append(values, string(b))
+	}
+	return fmt.Sprintf("%s %s [%s]", c.GetSubjectExternalSelectorValue(), op, strings.Join(values, ", "))
+}
+
+// pushNot moves negations onto the conditions, where they become the NOT IN (or IN) operator
+func pushNot(n node, negate bool) (node, error) {
+	switch n := n.(type) {
+	case *notNode:
+		return pushNot(n.x, !negate)
+	case *andNode:
+		return pushNotBinary(n.l, n.r, true, negate)
+	case *orNode:
+		return pushNotBinary(n.l, n.r, false, negate)
+	case *policy.Condition:
+		if !negate {
+			return n, nil
+		}
+		c := &policy.Condition{
+			SubjectExternalSelectorValue: n.GetSubjectExternalSelectorValue(),
+			SubjectExternalValues:        n.GetSubjectExternalValues(),
+		}
+		switch n.GetOperator() {
+		case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN:
+			c.Operator = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN
+		case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN:
+			c.Operator = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrNegation, formatCondition(n))
+		}
+		return c, nil
+	}
+	return nil, fmt.Errorf("%w: unknown expression node %T", ErrSyntax, n)
+}
+
+func pushNotBinary(l, r node, isAnd, negate bool) (node, error) {
+	l, err := pushNot(l, negate)
+	if err != nil {
+		return nil, err
+	}
+	r, err = pushNot(r, negate)
+	if err != nil {
+		return nil, err
+	}
+	// De Morgan: a negated AND is an OR of the negations, and vice versa
+	if isAnd != negate {
+		return &andNode{l, r}, nil
+	}
+	return &orNode{l, r}, nil
+}
+
+// cnf converts a negation-free expression into an AND of OR clauses
+func cnf(n node) ([][]*policy.Condition, error) {
+	switch n := n.(type) {
+	case *policy.Condition:
+		return [][]*policy.Condition{{n}}, nil
+	case *andNode:
+		l, err := cnf(n.l)
+		if err != nil {
+			return nil, err
+		}
+		r, err := cnf(n.r)
+		if err != nil {
+			return nil, err
+		}
+		if len(l)+len(r) > maxGroups {
+			return nil, ErrTooComplex
+		}
+		return append(l, r...), nil
+	case *orNode:
+		l, err := cnf(n.l)
+		if err != nil {
+			return nil, err
+		}
+		r, err := cnf(n.r)
+		if err != nil {
+			return nil, err
+		}
+		if len(l)*len(r) > maxGroups {
+			return nil, ErrTooComplex
+		}
+		out := make([][]*policy.Condition, 0, len(l)*len(r))
+		for _, lc := range l {
+			for _, rc := range r {
+				cl := make([]*policy.Condition, 0, len(lc)+len(rc))
+				out = append(out, append(append(cl, lc...), rc...))
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%w: unknown expression node %T", ErrSyntax, n)
+}
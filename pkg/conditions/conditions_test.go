@@ -0,0 +1,132 @@
+package conditions
+
+import (
+	"testing"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	and   = policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND
+	or    = policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR
+	in    = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN
+	notIn = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN
+)
+
+func cond(selector string, op policy.SubjectMappingOperatorEnum, values ...string) *policy.Condition {
+	return &policy.Condition{SubjectExternalSelectorValue: selector, Operator: op, SubjectExternalValues: values}
+}
+
+func TestParse(t *testing.T) {
+	ss, err := Parse(`.groups[] IN ["admin","ops"] AND .clearance in ["secret"] OR NOT .contractor IN ["true"]`)
+	require.NoError(t, err)
+	require.Len(t, ss, 1)
+
+	// (A AND B) OR C is normalized to (A OR C) AND (B OR C)
+	groups := ss[0].GetConditionGroups()
+	require.Len(t, groups, 2)
+	for _, g := range groups {
+		assert.Equal(t, or, g.GetBooleanOperator())
+		require.Len(t, g.GetConditions(), 2)
+		assert.Equal(t, ".contractor", g.GetConditions()[1].GetSubjectExternalSelectorValue())
+		assert.Equal(t, notIn, g.GetConditions()[1].GetOperator())
+	}
+	assert.Equal(t, []string{"admin", "ops"}, groups[0].GetConditions()[0].GetSubjectExternalValues())
+	assert.Equal(t, ".clearance", groups[1].GetConditions()[0].GetSubjectExternalSelectorValue())
+}
+
+func TestParseConjunction(t *testing.T) {
+	ss, err := Parse(`.team.name IN ["CoolTool", "RadService"] AND .org.name NOT IN ["marketing"] AND (.email IN_CONTAINS ["@example.com"] OR .dept IN ["sales"])`)
+	require.NoError(t, err)
+
+	groups := ss[0].GetConditionGroups()
+	require.Len(t, groups, 2)
+	assert.Equal(t, and, groups[0].GetBooleanOperator())
+	assert.Equal(t, []*policy.Condition{
+		cond(".team.name", in, "CoolTool", "RadService"),
+		cond(".org.name", notIn, "marketing"),
+	}, groups[0].GetConditions())
+	assert.Equal(t, or, groups[1].GetBooleanOperator())
+	assert.Len(t, groups[1].GetConditions(), 2)
+}
+
+func TestParseNegation(t *testing.T) {
+	// NOT (A OR B) is NOT A AND NOT B
+	ss, err := Parse(`NOT (.a IN ["1"] OR .b NOT IN ["2"])`)
+	require.NoError(t, err)
+	assert.Equal(t, []*policy.Condition{cond(".a", notIn, "1"), cond(".b", in, "2")}, ss[0].GetConditionGroups()[0].GetConditions())
+
+	_, err = Parse(`NOT .email IN_CONTAINS ["@example.com"]`)
+	require.ErrorIs(t, err, ErrNegation)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`.a IN`,
+		`.a IN ["x"`,
+		`.a EQUALS ["x"]`,
+		`.a IN ["x"] AND`,
+		`(.a IN ["x"]`,
+		`.a IN ["x] `,
+		`a IN ["x"]`,
+		`.a IN [x]`,
+	} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrSyntax, expr)
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	for _, expr := range []string{
+		`.team.name IN ["CoolTool", "RadService"]`,
+		`.a IN ["1"] AND .b NOT IN ["2", "with \"quotes\""]`,
+		`.a IN ["1"] AND (.b IN_CONTAINS ["x"] OR .c IN ["y"])`,
+		`.a IN ["1"] OR .b IN ["2"]`,
+	} {
+		ss, err := Parse(expr)
+		require.NoError(t, err, expr)
+		assert.Equal(t, expr, Format(ss))
+	}
+}
+
+func TestFormat(t *testing.T) {
+	// condition sets created as JSON may use several subject sets
+	ss := []*policy.SubjectSet{
+		{ConditionGroups: []*policy.ConditionGroup{{BooleanOperator: or, Conditions: []*policy.Condition{
+			cond(".a", in, "1"), cond(".b", in, "2"),
+		}}}},
+		{ConditionGroups: []*policy.ConditionGroup{{BooleanOperator: and, Conditions: []*policy.Condition{
+			cond(".c", notIn, "3"),
+		}}}},
+	}
+	assert.Equal(t, `(.a IN ["1"] OR .b IN ["2"]) AND .c NOT IN ["3"]`, Format(ss))
+}
+
+func TestEvaluate(t *testing.T) {
+	ss, err := Parse(`.groups[] IN ["admin", "ops"] AND .clearance IN ["secret"] OR NOT .contractor IN ["true"]`)
+	require.NoError(t, err)
+
+	ok, results := Evaluate(ss, map[string][]string{
+		".groups[]":   {"dev", "ops"},
+		".clearance":  {"secret"},
+		".contractor": {"true"},
+	})
+	assert.True(t, ok)
+	assert.Len(t, results, 4)
+
+	ok, _ = Evaluate(ss, map[string][]string{".groups[]": {"dev"}, ".contractor": {"true"}})
+	assert.False(t, ok)
+
+	// a missing claim is not in any list of values
+	ok, _ = Evaluate(ss, map[string][]string{})
+	assert.True(t, ok)
+
+	contains, err := Parse(`.email IN_CONTAINS ["@example.com"]`)
+	require.NoError(t, err)
+	ok, results = Evaluate(contains, map[string][]string{".email": {"alice@example.com"}})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"alice@example.com"}, results[0].Found)
+}
@@ -0,0 +1,64 @@
+package conditions
+
+import (
+	"strings"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// ConditionResult is the outcome of a single condition against a subject.
+type ConditionResult struct {
+	Condition string
+	// Found holds the subject's values at the condition's selector
+	Found   []string
+	Matched bool
+}
+
+// Evaluate resolves subject sets against a subject's values, keyed by flattened selector (e.g. .groups[]), the way
+// the platform resolves subject mappings: all subject sets and all of their condition groups must be met.
+func Evaluate(sets []*policy.SubjectSet, subject map[string][]string) (bool, []ConditionResult) {
+	var results []ConditionResult
+	allSets := true
+	for _, ss := range sets {
+		for _, g := range ss.GetConditionGroups() {
+			isOr := g.GetBooleanOperator() == policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR
+			groupMet := !isOr
+			for _, c := range g.GetConditions() {
+				found := subject[c.GetSubjectExternalSelectorValue()]
+				matched := evaluateCondition(c, found)
+				results = append(results, ConditionResult{Condition: formatCondition(c), Found: found, Matched: matched})
+				if isOr {
+					groupMet = groupMet || matched
+				} else {
+					groupMet = groupMet && matched
+				}
+			}
+			allSets = allSets && groupMet
+		}
+	}
+	return allSets, results
+}
+
+func evaluateCondition(c *policy.Condition, found []string) bool {
+	switch c.GetOperator() {
+	case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN:
+		return anyMatch(found, c.GetSubjectExternalValues(), func(f, v string) bool { return f == v })
+	case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN:
+		return !anyMatch(found, c.GetSubjectExternalValues(), func(f, v string) bool { return f == v })
+	case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN_CONTAINS:
+		return anyMatch(found, c.GetSubjectExternalValues(), strings.Contains)
+	default:
+		return false
+	}
+}
+
+func anyMatch(found, values []string, match func(found, value string) bool) bool {
+	for _, f := range found {
+		for _, v := range values {
+			if match(f, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,237 @@
+package conditions
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokSelector
+	tokString
+	tokWord
+	tokLParen
+	tokRParen
+	tokLBracket
+	tokRBracket
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lex(expr string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(expr); {
+		r := rune(expr[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, token{tokLParen, "(", i})
+			i++
+		case r == ')':
+			toks = append(toks, token{tokRParen, ")", i})
+			i++
+		case r == '[':
+			toks = append(toks, token{tokLBracket, "[", i})
+			i++
+		case r == ']':
+			toks = append(toks, token{tokRBracket, "]", i})
+			i++
+		case r == ',':
+			toks = append(toks, token{tokComma, ",", i})
+			i++
+		case r == '"':
+			end, err := scanString(expr, i)
+			if err != nil {
+				return nil, err
+			}
+			toks = append(toks, token{tokString, expr[i:end], i})
+			i = end
+		case r == '.':
+			// selectors are jq-style paths such as .team.name or .groups[], ending at whitespace or a closing paren
+			end := i
+			for end < len(expr) && !unicode.IsSpace(rune(expr[end])) && expr[end] != ')' {
+				end++
+			}
+			toks = append(toks, token{tokSelector, expr[i:end], i})
+			i = end
+		case unicode.IsLetter(r):
+			end := i
+			for end < len(expr) && (unicode.IsLetter(rune(expr[end])) || expr[end] == '_') {
+				end++
+			}
+			toks = append(toks, token{tokWord, strings.ToUpper(expr[i:end]), i})
+			i = end
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q at position %d", ErrSyntax, r, i)
+		}
+	}
+	return append(toks, token{tokEOF, "", len(expr)}), nil
+}
+
+// scanString returns the end of the JSON string starting at expr[start]
+func scanString(expr string, start int) (int, error) {
+	for i := start + 1; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: unterminated string at position %d", ErrSyntax, start)
+}
+
+type parser struct {
+	toks []token
+	i    int
+}
+
+func (p *parser) peek() token {
+	return p.toks[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, what string) (token, error) {
+	t := p.next()
+	if t.kind == kind {
+		return t, nil
+	}
+	if t.kind == tokEOF {
+		return t, fmt.Errorf("%w: %w, expected %s", ErrSyntax, errUnexpectedEnd, what)
+	}
+	return t, fmt.Errorf("%w: expected %s at position %d, found %q", ErrSyntax, what, t.pos, t.text)
+}
+
+func (p *parser) isWord(w string) bool {
+	t := p.peek()
+	return t.kind == tokWord && t.text == w
+}
+
+// parseOr parses: and ('OR' and)*
+func (p *parser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isWord("OR") {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = &orNode{l, r}
+	}
+	return l, nil
+}
+
+// parseAnd parses: unary ('AND' unary)*
+func (p *parser) parseAnd() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isWord("AND") {
+		p.next()
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = &andNode{l, r}
+	}
+	return l, nil
+}
+
+// parseUnary parses: 'NOT' unary | '(' or ')' | condition
+func (p *parser) parseUnary() (node, error) {
+	switch {
+	case p.isWord("NOT"):
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{x}, nil
+	case p.peek().kind == tokLParen:
+		p.next()
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokRParen, "')'"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return p.parseCondition()
+}
+
+// parseCondition parses: selector operator '[' string (',' string)* ']'
+func (p *parser) parseCondition() (node, error) {
+	sel, err := p.expect(tokSelector, "a selector such as .team.name")
+	if err != nil {
+		return nil, err
+	}
+	c := &policy.Condition{SubjectExternalSelectorValue: sel.text}
+
+	op, err := p.expect(tokWord, "an operator (IN, NOT IN, IN_CONTAINS)")
+	if err != nil {
+		return nil, err
+	}
+	switch op.text {
+	case "IN":
+		c.Operator = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN
+	case "IN_CONTAINS", "CONTAINS":
+		c.Operator = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN_CONTAINS
+	case "NOT":
+		if !p.isWord("IN") {
+			return nil, fmt.Errorf("%w: expected IN after NOT at position %d", ErrSyntax, p.peek().pos)
+		}
+		p.next()
+		c.Operator = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN
+	default:
+		return nil, fmt.Errorf("%w: unknown operator %q at position %d", ErrSyntax, op.text, op.pos)
+	}
+
+	if _, err := p.expect(tokLBracket, "'['"); err != nil {
+		return nil, err
+	}
+	for {
+		t, err := p.expect(tokString, "a quoted value")
+		if err != nil {
+			return nil, err
+		}
+		var v string
+		if err := json.Unmarshal([]byte(t.text), &v); err != nil {
+			return nil, fmt.Errorf("%w: invalid string at position %d: %w", ErrSyntax, t.pos, err)
+		}
+		c.SubjectExternalValues = append(c.SubjectExternalValues, v)
+
+		if p.peek().kind != tokComma {
+			break
+		}
+		p.next()
+	}
+	if _, err := p.expect(tokRBracket, "']'"); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
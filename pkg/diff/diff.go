@@ -0,0 +1,365 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+const (
+	KindNamespace                = "namespace"
+	KindAttribute                = "attribute"
+	KindValue                    = "value"
+	KindSubjectMapping           = "subject-mapping"
+	KindSubjectConditionSet      = "subject-condition-set"
+	KindAction                   = "action"
+	KindObligation               = "obligation"
+	KindObligationValue          = "obligation-value"
+	KindKeyAccessServer          = "kas"
+	KindKasKey                   = "kas-key"
+	KindBaseKey                  = "base-key"
+	KindRegisteredResource       = "registered-resource"
+	KindRegisteredResourceValue  = "registered-resource-value"
+	listSeparator                = ", "
+	conditionSetDescriptionEmpty = "(no conditions)"
+)
+
+// Object is a policy object reduced to an identity that is stable across platforms (an FQN or name, never a UUID)
+// and the fields which are compared. Several objects may share a key, such as the subject mappings of a value. Those
+// are paired by ID first, which only matches within the same platform, and then by their fields.
+type Object struct {
+	Kind   string
+	Key    string
+	ID     string
+	Fields map[string]string
+}
+
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type Change struct {
+	Kind   string        `json:"kind"`
+	Key    string        `json:"key"`
+	Type   string        `json:"type"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// Compare returns the changes needed to go from the policy in 'from' to the policy in 'to', ordered by kind and key.
+func Compare(from, to *handlers.PolicySnapshot) []Change {
+	a := index(Flatten(from))
+	b := index(Flatten(to))
+
+	var changes []Change
+	for k, as := range a {
+		changes = append(changes, compareGroup(as, b[k])...)
+	}
+	for k, bs := range b {
+		if _, ok := a[k]; !ok {
+			changes = append(changes, compareGroup(nil, bs)...)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Kind != changes[j].Kind {
+			return kindOrder(changes[i].Kind) < kindOrder(changes[j].Kind)
+		}
+		if changes[i].Key != changes[j].Key {
+			return changes[i].Key < changes[j].Key
+		}
+		return changes[i].Type < changes[j].Type
+	})
+	return changes
+}
+
+// compareGroup compares the objects sharing a kind and key. They are paired by ID, then objects with identical
+// fields are paired, and the rest are paired in order as changed. Whatever is left over was removed or added.
+func compareGroup(as, bs []Object) []Change {
+	as = append([]Object(nil), as...)
+	bs = append([]Object(nil), bs...)
+	var pairs [][2]Object
+	pair := func(match func(oa, ob Object) bool) {
+		for i := 0; i < len(as); i++ {
+			for j := range bs {
+				if match(as[i], bs[j]) {
+					pairs = append(pairs, [2]Object{as[i], bs[j]})
+					as = append(as[:i], as[i+1:]...)
+					bs = append(bs[:j], bs[j+1:]...)
+					i--
+					break
+				}
+			}
+		}
+	}
+	pair(func(oa, ob Object) bool { return oa.ID != "" && oa.ID == ob.ID })
+	pair(func(oa, ob Object) bool { return len(compareFields(oa.Fields, ob.Fields)) == 0 })
+	pair(func(Object, Object) bool { return true })
+
+	var changes []Change
+	for _, p := range pairs {
+		if fields := compareFields(p[0].Fields, p[1].Fields); len(fields) > 0 {
+			changes = append(changes, Change{Kind: p[0].Kind, Key: p[0].Key, Type: ChangeChanged, Fields: fields})
+		}
+	}
+	for _, oa := range as {
+		changes = append(changes, Change{Kind: oa.Kind, Key: oa.Key, Type: ChangeRemoved})
+	}
+	for _, ob := range bs {
+		changes = append(changes, Change{Kind: ob.Kind, Key: ob.Key, Type: ChangeAdded})
+	}
+	return changes
+}
+
+var kinds = []string{
+	KindNamespace, KindAttribute, KindValue, KindSubjectConditionSet, KindSubjectMapping, KindAction,
+	KindObligation, KindObligationValue, KindKeyAccessServer, KindKasKey, KindBaseKey,
+	KindRegisteredResource, KindRegisteredResourceValue,
+}
+
+func kindOrder(k string) int {
+	for i, kind := range kinds {
+		if kind == k {
+			return i
+		}
+	}
+	return len(kinds)
+}
+
+// index groups the objects by kind and key, each group sorted by fields so pairing is deterministic
+func index(objs []Object) map[string][]Object {
+	m := make(map[string][]Object, len(objs))
+	for _, o := range objs {
+		k := o.Kind + "\x00" + o.Key
+		m[k] = append(m[k], o)
+	}
+	for _, group := range m {
+		sort.SliceStable(group, func(i, j int) bool { return fieldsSignature(group[i].Fields) < fieldsSignature(group[j].Fields) })
+	}
+	return m
+}
+
+func fieldsSignature(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for k := range fields {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, k := range names {
+		b.WriteString(k + "=" + fields[k] + "\x00")
+	}
+	return b.String()
+}
+
+func compareFields(a, b map[string]string) []FieldChange {
+	names := map[string]bool{}
+	for k := range a {
+		names[k] = true
+	}
+	for k := range b {
+		names[k] = true
+	}
+	var changes []FieldChange
+	for k := range names {
+		if a[k] != b[k] {
+			changes = append(changes, FieldChange{Field: k, From: a[k], To: b[k]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func active(b *wrapperspb.BoolValue) string {
+	if b == nil || b.GetValue() {
+		return "true"
+	}
+	return "false"
+}
+
+func sortedList(items []string) string {
+	items = append([]string(nil), items...)
+	sort.Strings(items)
+	return strings.Join(items, listSeparator)
+}
+
+func simpleKeys(keys []*policy.SimpleKasKey) string {
+	out := make([]string, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, k.GetKasUri()+"#"+k.GetPublicKey().GetKid())
+	}
+	return sortedList(out)
+}
+
+func actionNames(actions []*policy.Action) string {
+	out := make([]string, 0, len(actions))
+	for _, a := range actions {
+		out = append(out, a.GetName())
+	}
+	return sortedList(out)
+}
+
+// DescribeConditionSet renders subject sets in a stable, readable form independent of condition order.
+func DescribeConditionSet(sets []*policy.SubjectSet) string {
+	setStrs := make([]string, 0, len(sets))
+	for _, ss := range sets {
+		groups := make([]string, 0, len(ss.GetConditionGroups()))
+		for _, g := range ss.GetConditionGroups() {
+			conds := make([]string, 0, len(g.GetConditions()))
+			for _, c := range g.GetConditions() {
+				vals := append([]string(nil), c.GetSubjectExternalValues()...)
+				sort.Strings(vals)
+				op := strings.TrimPrefix(c.GetOperator().String(), "SUBJECT_MAPPING_OPERATOR_ENUM_")
+				conds = append(conds, fmt.Sprintf("%s %s %q", c.GetSubjectExternalSelectorValue(), op, vals))
+			}
+			sort.Strings(conds)
+			boolOp := strings.TrimPrefix(g.GetBooleanOperator().String(), "CONDITION_BOOLEAN_TYPE_ENUM_")
+			groups = append(groups, "("+strings.Join(conds, " "+boolOp+" ")+")")
+		}
+		sort.Strings(groups)
+		setStrs = append(setStrs, strings.Join(groups, " AND "))
+	}
+	sort.Strings(setStrs)
+	if len(setStrs) == 0 {
+		return conditionSetDescriptionEmpty
+	}
+	return strings.Join(setStrs, " AND ")
+}
+
+// Flatten reduces a snapshot to comparable objects.
+//
+//nolint:funlen // a flat walk over each object type
+func Flatten(s *handlers.PolicySnapshot) []Object {
+	var objs []Object
+	add := func(kind, key string, fields map[string]string) {
+		objs = append(objs, Object{Kind: kind, Key: key, Fields: fields})
+	}
+	addWithID := func(kind, key, id string, fields map[string]string) {
+		objs = append(objs, Object{Kind: kind, Key: key, ID: id, Fields: fields})
+	}
+
+	for _, ns := range s.Namespaces {
+		add(KindNamespace, ns.GetFqn(), map[string]string{
+			"active": active(ns.GetActive()),
+			"keys":   simpleKeys(ns.GetKasKeys()),
+		})
+	}
+
+	for _, a := range s.Attributes {
+		values := make([]string, 0, len(a.GetValues()))
+		for _, v := range a.GetValues() {
+			values = append(values, v.GetValue())
+			add(KindValue, v.GetFqn(), map[string]string{
+				"active": active(v.GetActive()),
+				"keys":   simpleKeys(v.GetKasKeys()),
+			})
+		}
+		add(KindAttribute, a.GetFqn(), map[string]string{
+			"rule":            strings.TrimPrefix(a.GetRule().String(), "ATTRIBUTE_RULE_TYPE_ENUM_"),
+			"active":          active(a.GetActive()),
+			"allow_traversal": fmt.Sprint(a.GetAllowTraversal().GetValue()),
+			"values_order":    strings.Join(values, listSeparator),
+			"keys":            simpleKeys(a.GetKasKeys()),
+		})
+	}
+
+	// condition sets have no name, so they are identified by their conditions, and duplicates are counted
+	for _, scs := range s.SubjectConditionSets {
+		addWithID(KindSubjectConditionSet, DescribeConditionSet(scs.GetSubjectSets()), scs.GetId(), map[string]string{})
+	}
+	// mappings are identified by their value, paired by condition set ID when both sides share the set
+	for _, sm := range s.SubjectMappings {
+		addWithID(KindSubjectMapping, sm.GetAttributeValue().GetFqn(), sm.GetSubjectConditionSet().GetId(), map[string]string{
+			"conditions": DescribeConditionSet(sm.GetSubjectConditionSet().GetSubjectSets()),
+			"actions":    actionNames(sm.GetActions()),
+		})
+	}
+
+	for _, a := range s.Actions {
+		add(KindAction, a.GetName(), map[string]string{})
+	}
+
+	triggers := map[string][]string{}
+	for _, t := range s.ObligationTriggers {
+		desc := t.GetAttributeValue().GetFqn() + " on " + t.GetAction().GetName()
+		var clients []string
+		for _, rc := range t.GetContext() {
+			if id := rc.GetPep().GetClientId(); id != "" {
+				clients = append(clients, id)
+			}
+		}
+		if len(clients) > 0 {
+			desc += " for " + sortedList(clients)
+		}
+		triggers[t.GetObligationValue().GetFqn()] = append(triggers[t.GetObligationValue().GetFqn()], desc)
+	}
+	for _, o := range s.Obligations {
+		values := make([]string, 0, len(o.GetValues()))
+		for _, v := range o.GetValues() {
+			values = append(values, v.GetValue())
+			add(KindObligationValue, v.GetFqn(), map[string]string{
+				"triggers": sortedList(triggers[v.GetFqn()]),
+			})
+		}
+		add(KindObligation, o.GetFqn(), map[string]string{
+			"values": sortedList(values),
+		})
+	}
+
+	kasURI := map[string]string{}
+	for _, kas := range s.KeyAccessServers {
+		kasURI[kas.GetId()] = kas.GetUri()
+		add(KindKeyAccessServer, kas.GetUri(), map[string]string{
+			"name": kas.GetName(),
+		})
+	}
+	for _, k := range s.KasKeys {
+		uri := k.GetKasUri()
+		if uri == "" {
+			uri = kasURI[k.GetKasId()]
+		}
+		key := k.GetKey()
+		add(KindKasKey, uri+"#"+key.GetKeyId(), map[string]string{
+			"algorithm": key.GetKeyAlgorithm().String(),
+			"status":    key.GetKeyStatus().String(),
+			"mode":      key.GetKeyMode().String(),
+			"legacy":    fmt.Sprint(key.GetLegacy()),
+		})
+	}
+	if s.BaseKey != nil {
+		add(KindBaseKey, "base key", map[string]string{
+			"key": s.BaseKey.GetKasUri() + "#" + s.BaseKey.GetPublicKey().GetKid(),
+		})
+	}
+
+	for _, r := range s.RegisteredResources {
+		rKey := r.GetNamespace().GetFqn() + "/" + r.GetName()
+		values := make([]string, 0, len(r.GetValues()))
+		for _, v := range r.GetValues() {
+			values = append(values, v.GetValue())
+			aavs := make([]string, 0, len(v.GetActionAttributeValues()))
+			for _, aav := range v.GetActionAttributeValues() {
+				aavs = append(aavs, aav.GetAction().GetName()+" "+aav.GetAttributeValue().GetFqn())
+			}
+			add(KindRegisteredResourceValue, rKey+"/"+v.GetValue(), map[string]string{
+				"action_attribute_values": sortedList(aavs),
+			})
+		}
+		add(KindRegisteredResource, rKey, map[string]string{
+			"values": sortedList(values),
+		})
+	}
+
+	return objs
+}
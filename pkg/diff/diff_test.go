@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func snapshot(values []string, actions ...string) *handlers.PolicySnapshot {
+	attr := &policy.Attribute{
+		Id:     "attr-" + values[0],
+		Fqn:    "https://example.com/attr/level",
+		Rule:   policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY,
+		Active: wrapperspb.Bool(true),
+	}
+	for _, v := range values {
+		attr.Values = append(attr.Values, &policy.Value{Id: "id-" + v, Value: v, Fqn: attr.GetFqn() + "/value/" + v})
+	}
+	scs := &policy.SubjectConditionSet{SubjectSets: []*policy.SubjectSet{{
+		ConditionGroups: []*policy.ConditionGroup{{
+			BooleanOperator: policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND,
+			Conditions: []*policy.Condition{{
+				SubjectExternalSelectorValue: ".team",
+				Operator:                     policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN,
+				SubjectExternalValues:        []string{"b", "a"},
+			}},
+		}},
+	}}}
+	sm := &policy.SubjectMapping{AttributeValue: attr.GetValues()[0], SubjectConditionSet: scs}
+	for _, a := range actions {
+		sm.Actions = append(sm.Actions, &policy.Action{Name: a})
+	}
+	return &handlers.PolicySnapshot{
+		Attributes:           []*policy.Attribute{attr},
+		SubjectConditionSets: []*policy.SubjectConditionSet{scs},
+		SubjectMappings:      []*policy.SubjectMapping{sm},
+	}
+}
+
+func TestCompareNoDrift(t *testing.T) {
+	// identifiers differ between platforms, but the policy is the same
+	a := snapshot([]string{"high", "low"}, "read", "create")
+	b := snapshot([]string{"high", "low"}, "create", "read")
+	b.Attributes[0].Id = "other-id"
+	assert.Empty(t, Compare(a, b))
+}
+
+func TestCompare(t *testing.T) {
+	a := snapshot([]string{"high", "mid", "low"}, "read")
+	b := snapshot([]string{"high", "low", "mid"}, "read", "create")
+	b.Attributes[0].Values = b.Attributes[0].Values[:2]
+	b.Actions = []*policy.Action{{Name: "approve"}}
+
+	changes := Compare(a, b)
+	require.Len(t, changes, 4)
+
+	assert.Equal(t, Change{
+		Kind: KindAttribute, Key: "https://example.com/attr/level", Type: ChangeChanged,
+		Fields: []FieldChange{{Field: "values_order", From: "high, mid, low", To: "high, low"}},
+	}, changes[0])
+	assert.Equal(t, Change{Kind: KindValue, Key: "https://example.com/attr/level/value/mid", Type: ChangeRemoved}, changes[1])
+	assert.Equal(t, Change{
+		Kind: KindSubjectMapping, Key: "https://example.com/attr/level/value/high", Type: ChangeChanged,
+		Fields: []FieldChange{{Field: "actions", From: "read", To: "create, read"}},
+	}, changes[2])
+	assert.Equal(t, Change{Kind: KindAction, Key: "approve", Type: ChangeAdded}, changes[3])
+}
+
+func TestCompareConditionChange(t *testing.T) {
+	a := snapshot([]string{"high"}, "read")
+	b := snapshot([]string{"high"}, "read")
+	b.SubjectMappings[0].GetSubjectConditionSet().GetSubjectSets()[0].GetConditionGroups()[0].GetConditions()[0].SubjectExternalValues = []string{"c"}
+
+	changes := Compare(a, b)
+	require.Len(t, changes, 3)
+	assert.Equal(t, Change{Kind: KindSubjectConditionSet, Key: `(.team IN ["a" "b"])`, Type: ChangeRemoved}, changes[0])
+	assert.Equal(t, Change{Kind: KindSubjectConditionSet, Key: `(.team IN ["c"])`, Type: ChangeAdded}, changes[1])
+	assert.Equal(t, Change{
+		Kind: KindSubjectMapping, Key: "https://example.com/attr/level/value/high", Type: ChangeChanged,
+		Fields: []FieldChange{{Field: "conditions", From: `(.team IN ["a" "b"])`, To: `(.team IN ["c"])`}},
+	}, changes[2])
+}
+
+func TestCompareDuplicates(t *testing.T) {
+	a := snapshot([]string{"high"}, "read")
+	b := snapshot([]string{"high"}, "read")
+	// a second, identical condition set and mapping
+	b.SubjectConditionSets = append(b.SubjectConditionSets, b.SubjectConditionSets[0])
+	b.SubjectMappings = append(b.SubjectMappings, b.SubjectMappings[0])
+
+	changes := Compare(a, b)
+	require.Len(t, changes, 2)
+	assert.Equal(t, Change{Kind: KindSubjectConditionSet, Key: `(.team IN ["a" "b"])`, Type: ChangeAdded}, changes[0])
+	assert.Equal(t, Change{Kind: KindSubjectMapping, Key: "https://example.com/attr/level/value/high", Type: ChangeAdded}, changes[1])
+
+	changes = Compare(b, a)
+	require.Len(t, changes, 2)
+	assert.Equal(t, ChangeRemoved, changes[0].Type)
+	assert.Equal(t, ChangeRemoved, changes[1].Type)
+}
+
+func TestComparePairsByID(t *testing.T) {
+	sm := func(id, action string) *policy.SubjectMapping {
+		return &policy.SubjectMapping{
+			AttributeValue:      &policy.Value{Fqn: "https://example.com/attr/level/value/high"},
+			SubjectConditionSet: &policy.SubjectConditionSet{Id: id},
+			Actions:             []*policy.Action{{Name: action}},
+		}
+	}
+	a := &handlers.PolicySnapshot{SubjectMappings: []*policy.SubjectMapping{sm("scs-1", "read"), sm("scs-2", "update")}}
+	b := &handlers.PolicySnapshot{SubjectMappings: []*policy.SubjectMapping{sm("scs-1", "update"), sm("scs-2", "read")}}
+
+	// by fields alone the mappings would look unchanged, but each condition set had its actions swapped
+	changes := Compare(a, b)
+	require.Len(t, changes, 2)
+	assert.ElementsMatch(t, [][]FieldChange{
+		{{Field: "actions", From: "read", To: "update"}},
+		{{Field: "actions", From: "update", To: "read"}},
+	}, [][]FieldChange{changes[0].Fields, changes[1].Fields})
+
+	// without shared IDs, as between platforms, the mappings pair by fields
+	b.SubjectMappings[0].SubjectConditionSet.Id = "other-1"
+	b.SubjectMappings[1].SubjectConditionSet.Id = "other-2"
+	assert.Empty(t, Compare(a, b))
+}
@@ -0,0 +1,400 @@
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	StatusPass = "pass"
+	StatusWarn = "warn"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+
+	WellKnownPath       = "/.well-known/opentdf-configuration"
+	OIDCDiscoveryPath   = "/.well-known/openid-configuration"
+	KASPublicKeyRPCPath = "/kas.AccessService/PublicKey"
+
+	// certificates expiring within this window are reported as a warning
+	certExpiryWarnWindow = 30 * 24 * time.Hour
+
+	clockSkewWarn = 30 * time.Second
+	clockSkewFail = 5 * time.Minute
+
+	maxResponseBytes = 1 << 20
+)
+
+// Check is the outcome of a single diagnostic.
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+func pass(name, format string, a ...any) Check {
+	return Check{Name: name, Status: StatusPass, Message: fmt.Sprintf(format, a...)}
+}
+
+func warn(name, format string, a ...any) Check {
+	return Check{Name: name, Status: StatusWarn, Message: fmt.Sprintf(format, a...)}
+}
+
+func fail(name string, err error, format string, a ...any) Check {
+	msg := fmt.Sprintf(format, a...)
+	if err != nil {
+		msg += ": " + err.Error()
+	}
+	return Check{Name: name, Status: StatusFail, Message: msg}
+}
+
+// Skip records a check which could not run because an earlier check did not succeed.
+func Skip(name, reason string) Check {
+	return Check{Name: name, Status: StatusSkip, Message: reason}
+}
+
+// Report is the ordered list of checks run against an endpoint.
+type Report struct {
+	Profile  string  `json:"profile"`
+	Endpoint string  `json:"endpoint"`
+	Checks   []Check `json:"checks"`
+}
+
+func (r *Report) Add(c Check) Check {
+	r.Checks = append(r.Checks, c)
+	return c
+}
+
+// Failed reports whether any check failed.
+func (r *Report) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Counts returns the number of checks per status.
+func (r *Report) Counts() map[string]int {
+	counts := map[string]int{}
+	for _, c := range r.Checks {
+		counts[c.Status]++
+	}
+	return counts
+}
+
+// CheckDNS resolves the host of the endpoint.
+func CheckDNS(ctx context.Context, u *url.URL) Check {
+	const name = "DNS"
+	host := u.Hostname()
+	if net.ParseIP(host) != nil {
+		return pass(name, "%s is an IP address", host)
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return fail(name, err, "could not resolve %s", host)
+	}
+	return pass(name, "%s resolved to %s", host, strings.Join(addrs, ", "))
+}
+
+// CheckTCP dials the host and port of the endpoint.
+func CheckTCP(ctx context.Context, u *url.URL, timeout time.Duration) Check {
+	const name = "TCP"
+	d := net.Dialer{Timeout: timeout}
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return fail(name, err, "could not connect to %s", u.Host)
+	}
+	conn.Close()
+	return pass(name, "connected to %s in %s", u.Host, time.Since(start).Round(time.Millisecond))
+}
+
+// CheckTLS performs a TLS handshake with the endpoint and inspects the served certificate chain.
+func CheckTLS(ctx context.Context, u *url.URL, tlsNoVerify bool, timeout time.Duration, now time.Time) Check {
+	const name = "TLS"
+	if u.Scheme == "http" {
+		return warn(name, "plaintext connection, TLS is not in use")
+	}
+
+	state, verifyErr := tlsHandshake(ctx, u, false, timeout)
+	if verifyErr != nil {
+		var certErr *tls.CertificateVerificationError
+		if !errors.As(verifyErr, &certErr) {
+			return fail(name, verifyErr, "TLS handshake with %s failed", u.Host)
+		}
+		if !tlsNoVerify {
+			return fail(name, verifyErr, "certificate chain could not be verified (use --tls-no-verify only if the host is trusted)")
+		}
+		var err error
+		if state, err = tlsHandshake(ctx, u, true, timeout); err != nil {
+			return fail(name, err, "TLS handshake with %s failed", u.Host)
+		}
+	}
+
+	if len(state.PeerCertificates) == 0 {
+		return fail(name, nil, "no certificates were served by %s", u.Host)
+	}
+	leaf := state.PeerCertificates[0]
+	summary := fmt.Sprintf("%s, issued by %s, expires %s", tls.VersionName(state.Version), leaf.Issuer.CommonName, leaf.NotAfter.Format(time.RFC3339))
+
+	switch {
+	case now.After(leaf.NotAfter):
+		return fail(name, nil, "certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))
+	case verifyErr != nil:
+		return warn(name, "certificate verification skipped (--tls-no-verify): %s; %s", verifyErr, summary)
+	case leaf.NotAfter.Sub(now) < certExpiryWarnWindow:
+		return warn(name, "certificate expires soon; %s", summary)
+	}
+	return pass(name, "chain verified; %s", summary)
+}
+
+func tlsHandshake(ctx context.Context, u *url.URL, insecure bool, timeout time.Duration) (tls.ConnectionState, error) {
+	d := tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config: &tls.Config{
+			ServerName: u.Hostname(),
+			//nolint:gosec // only used to inspect the chain after verification has already failed with --tls-no-verify
+			InsecureSkipVerify: insecure,
+		},
+	}
+	conn, err := d.DialContext(ctx, "tcp", u.Host)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer conn.Close()
+	//nolint:forcetypeassert // tls.Dialer always returns a *tls.Conn
+	return conn.(*tls.Conn).ConnectionState(), nil
+}
+
+// WellKnown holds the values of the platform well-known configuration used by later checks.
+type WellKnown struct {
+	Issuer        string
+	TokenEndpoint string
+	Version       string
+	Configuration map[string]any
+}
+
+// CheckWellKnown fetches and parses the platform well-known configuration.
+func CheckWellKnown(ctx context.Context, client *http.Client, endpoint string) (WellKnown, Check) {
+	const name = "Well-known configuration"
+	wk := WellKnown{}
+
+	var body struct {
+		Configuration map[string]any `json:"configuration"`
+	}
+	if err := getJSON(ctx, client, strings.TrimSuffix(endpoint, "/")+WellKnownPath, &body); err != nil {
+		return wk, fail(name, err, "could not fetch %s", WellKnownPath)
+	}
+	if body.Configuration == nil {
+		return wk, fail(name, nil, "response did not contain a configuration")
+	}
+	wk.Configuration = body.Configuration
+
+	if idp, ok := body.Configuration["idp"].(map[string]any); ok {
+		wk.Issuer, _ = idp["issuer"].(string)
+		wk.TokenEndpoint, _ = idp["token_endpoint"].(string)
+	}
+	for _, k := range []string{"version", "platform_version"} {
+		if v, ok := body.Configuration[k].(string); ok && v != "" {
+			wk.Version = v
+			break
+		}
+	}
+
+	if wk.Issuer == "" {
+		return wk, fail(name, nil, "configuration does not define an IdP issuer")
+	}
+	return wk, pass(name, "IdP issuer %s", wk.Issuer)
+}
+
+// CheckIDPDiscovery fetches the OpenID Connect discovery document of the IdP issuer.
+func CheckIDPDiscovery(ctx context.Context, client *http.Client, issuer string) Check {
+	const name = "IdP discovery"
+	var doc struct {
+		Issuer        string `json:"issuer"`
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+OIDCDiscoveryPath, &doc); err != nil {
+		return fail(name, err, "could not fetch the discovery document of %s", issuer)
+	}
+	if doc.TokenEndpoint == "" {
+		return fail(name, nil, "discovery document does not define a token endpoint")
+	}
+	if strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return warn(name, "discovery document issuer %q does not match the platform configured issuer %q", doc.Issuer, issuer)
+	}
+	return pass(name, "token endpoint %s", doc.TokenEndpoint)
+}
+
+// CheckClockSkew compares the issued-at time of a freshly issued token with the local clock.
+func CheckClockSkew(issuedAt, now time.Time) Check {
+	const name = "Clock skew"
+	skew := issuedAt.Sub(now)
+	abs := skew
+	if abs < 0 {
+		abs = -abs
+	}
+	direction := "ahead of"
+	if skew < 0 {
+		direction = "behind"
+	}
+	rounded := abs.Round(time.Second)
+	switch {
+	case abs > clockSkewFail:
+		return fail(name, nil, "the IdP clock is %s %s the local clock, tokens may be rejected as expired or not yet valid", rounded, direction)
+	case abs > clockSkewWarn:
+		return warn(name, "the IdP clock is %s %s the local clock", rounded, direction)
+	}
+	return pass(name, "within %s", clockSkewWarn)
+}
+
+// CheckSDKVersion compares the version reported by the platform with the SDK version of this build.
+func CheckSDKVersion(platformVersion, sdkVersion string) Check {
+	const name = "SDK compatibility"
+	if platformVersion == "" {
+		return warn(name, "the platform did not report a version, compatibility with SDK %s could not be determined", sdkVersion)
+	}
+	pMajor, pMinor, pOK := majorMinorVersion(platformVersion)
+	sMajor, sMinor, sOK := majorMinorVersion(sdkVersion)
+	if !pOK || !sOK {
+		return warn(name, "could not compare platform version %q with SDK version %q", platformVersion, sdkVersion)
+	}
+	if pMajor != sMajor {
+		return warn(name, "platform version %s and SDK version %s have different major versions", platformVersion, sdkVersion)
+	}
+	// before 1.0 a minor release may break compatibility
+	if pMajor == 0 && pMinor != sMinor {
+		return warn(name, "platform version %s and SDK version %s have different minor versions", platformVersion, sdkVersion)
+	}
+	return pass(name, "platform %s, SDK %s", platformVersion, sdkVersion)
+}
+
+func majorMinorVersion(v string) (int, int, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	majorPart, rest, ok := strings.Cut(v, ".")
+	if !ok {
+		return 0, 0, false
+	}
+	minorPart, _, _ := strings.Cut(rest, ".")
+	major, err := strconv.Atoi(majorPart)
+	if err != nil {
+		return 0, 0, false
+	}
+	minor, err := strconv.Atoi(minorPart)
+	if err != nil {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
+// CheckKASPublicKey requests the public key from a KAS and verifies a PEM encoded key is returned.
+func CheckKASPublicKey(ctx context.Context, client *http.Client, kasURI string) Check {
+	name := "KAS " + kasURI
+	pub, kid, err := FetchKASPublicKey(ctx, client, kasURI, "")
+	if err != nil {
+		return fail(name, err, "public key endpoint did not respond")
+	}
+	block, _ := pem.Decode([]byte(pub))
+	if block == nil {
+		return fail(name, nil, "public key is not PEM encoded")
+	}
+	if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		if _, certErr := x509.ParseCertificate(block.Bytes); certErr != nil {
+			return fail(name, err, "public key could not be parsed")
+		}
+	}
+	if kid == "" {
+		return pass(name, "public key served")
+	}
+	return pass(name, "public key served (kid %s)", kid)
+}
+
+// FetchKASPublicKey calls the KAS PublicKey RPC with the Connect JSON protocol. The algorithm is optional.
+func FetchKASPublicKey(ctx context.Context, client *http.Client, kasURI, algorithm string) (string, string, error) {
+	u, err := url.Parse(kasURI)
+	if err != nil {
+		return "", "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", "", fmt.Errorf("invalid KAS URI %q", kasURI)
+	}
+	// a KAS may be served under a path prefix, e.g. https://platform.example.com/kas
+	rpc := (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: strings.TrimSuffix(u.Path, "/") + KASPublicKeyRPCPath}).String()
+
+	reqBody := "{}"
+	if algorithm != "" {
+		b, err := json.Marshal(map[string]string{"algorithm": algorithm})
+		if err != nil {
+			return "", "", err
+		}
+		reqBody = string(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rpc, strings.NewReader(reqBody))
+	if err != nil {
+		return "", "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var resp struct {
+		PublicKey string `json:"publicKey"`
+		Kid       string `json:"kid"`
+	}
+	if err := doJSON(client, req, &resp); err != nil {
+		return "", "", err
+	}
+	if resp.PublicKey == "" {
+		return "", "", errors.New("response did not contain a public key")
+	}
+	return resp.PublicKey, resp.Kid, nil
+}
+
+// StatusError is returned when a server answers with a status other than 200 OK.
+type StatusError struct {
+	Code int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.Code)
+}
+
+func getJSON(ctx context.Context, client *http.Client, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	return doJSON(client, req, v)
+}
+
+func doJSON(client *http.Client, req *http.Request, v any) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{Code: resp.StatusCode}
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("invalid JSON response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/opentdf/otdfctl/pkg/internal/kastest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPlatformServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	mux.HandleFunc(WellKnownPath, func(w http.ResponseWriter, _ *http.Request) {
+		//nolint:errcheck // test response
+		json.NewEncoder(w).Encode(map[string]any{
+			"configuration": map[string]any{
+				"idp":     map[string]any{"issuer": srv.URL + "/idp", "token_endpoint": srv.URL + "/idp/token"},
+				"version": "0.9.0",
+			},
+		})
+	})
+	mux.HandleFunc("/idp"+OIDCDiscoveryPath, func(w http.ResponseWriter, _ *http.Request) {
+		//nolint:errcheck // test response
+		json.NewEncoder(w).Encode(map[string]any{"issuer": srv.URL + "/idp", "token_endpoint": srv.URL + "/idp/token"})
+	})
+	mux.Handle("/kas"+KASPublicKeyRPCPath, kastest.PublicKeyHandler(t, map[string]kastest.Key{
+		"": {KID: "e1", PEM: kastest.PublicKeyPEM(t)},
+	}))
+	return srv
+}
+
+func TestPlatformChecks(t *testing.T) {
+	srv := newPlatformServer(t)
+	ctx := context.Background()
+
+	wk, c := CheckWellKnown(ctx, srv.Client(), srv.URL)
+	assert.Equal(t, StatusPass, c.Status, c.Message)
+	assert.Equal(t, srv.URL+"/idp", wk.Issuer)
+	assert.Equal(t, "0.9.0", wk.Version)
+
+	c = CheckIDPDiscovery(ctx, srv.Client(), wk.Issuer)
+	assert.Equal(t, StatusPass, c.Status, c.Message)
+
+	c = CheckKASPublicKey(ctx, srv.Client(), srv.URL+"/kas")
+	assert.Equal(t, StatusPass, c.Status, c.Message)
+	assert.Contains(t, c.Message, "e1")
+	// the path prefix of the KAS URI is kept
+	c = CheckKASPublicKey(ctx, srv.Client(), srv.URL+"/kas/")
+	assert.Equal(t, StatusPass, c.Status, c.Message)
+	c = CheckKASPublicKey(ctx, srv.Client(), srv.URL)
+	assert.Equal(t, StatusFail, c.Status, c.Message)
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	assert.Equal(t, StatusPass, CheckDNS(ctx, u).Status)
+	assert.Equal(t, StatusPass, CheckTCP(ctx, u, time.Second).Status)
+	assert.Equal(t, StatusWarn, CheckTLS(ctx, u, false, time.Second, time.Now()).Status)
+}
+
+func TestCheckWellKnown_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	_, c := CheckWellKnown(context.Background(), srv.Client(), srv.URL)
+	assert.Equal(t, StatusFail, c.Status)
+	assert.Contains(t, c.Message, "404")
+}
+
+func TestCheckTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// the test server certificate is self-signed
+	assert.Equal(t, StatusFail, CheckTLS(ctx, u, false, time.Second, time.Now()).Status)
+	assert.Equal(t, StatusWarn, CheckTLS(ctx, u, true, time.Second, time.Now()).Status)
+	assert.Equal(t, StatusFail, CheckTLS(ctx, u, true, time.Second, srv.Certificate().NotAfter.Add(time.Hour)).Status)
+}
+
+func TestCheckClockSkew(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, StatusPass, CheckClockSkew(now.Add(-2*time.Second), now).Status)
+	assert.Equal(t, StatusWarn, CheckClockSkew(now.Add(time.Minute), now).Status)
+	assert.Equal(t, StatusFail, CheckClockSkew(now.Add(-10*time.Minute), now).Status)
+}
+
+func TestCheckSDKVersion(t *testing.T) {
+	tests := []struct {
+		platform, sdk string
+		want          string
+	}{
+		{"v0.7.3", "0.7.0", StatusPass},
+		{"v0.9.1", "0.7.0", StatusWarn},
+		{"0.10.0", "0.1.0", StatusWarn},
+		{"1.2.0", "1.4.1", StatusPass},
+		{"1.0.0", "0.7.0", StatusWarn},
+		{"", "0.7.0", StatusWarn},
+		{"0", "0.7.0", StatusWarn},
+		{"0.x.1", "0.7.0", StatusWarn},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CheckSDKVersion(tt.platform, tt.sdk).Status, "platform %q, SDK %q", tt.platform, tt.sdk)
+	}
+	assert.Contains(t, CheckSDKVersion("0.9.1", "0.7.0").Message, "different minor versions")
+}
+
+func TestReportFailed(t *testing.T) {
+	r := Report{}
+	r.Add(pass("a", "ok"))
+	r.Add(warn("b", "meh"))
+	assert.False(t, r.Failed())
+	r.Add(fail("c", nil, "bad"))
+	assert.True(t, r.Failed())
+	assert.Equal(t, map[string]int{StatusPass: 1, StatusWarn: 1, StatusFail: 1}, r.Counts())
+}
@@ -0,0 +1,335 @@
+package graph
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+type NodeKind string
+
+const (
+	KindNamespace             NodeKind = "namespace"
+	KindAttribute             NodeKind = "attribute"
+	KindValue                 NodeKind = "value"
+	KindSubjectMapping        NodeKind = "subject-mapping"
+	KindSubjectConditionSet   NodeKind = "subject-condition-set"
+	KindAction                NodeKind = "action"
+	KindObligation            NodeKind = "obligation"
+	KindObligationValue       NodeKind = "obligation-value"
+	KindObligationTrigger     NodeKind = "obligation-trigger"
+	KindKeyAccessServer       NodeKind = "kas"
+	KindKasKey                NodeKind = "kas-key"
+	KindRegisteredResource    NodeKind = "registered-resource"
+	KindRegisteredResourceVal NodeKind = "registered-resource-value"
+)
+
+type EdgeKind string
+
+const (
+	EdgeContains  EdgeKind = "contains"
+	EdgeMappedBy  EdgeKind = "mapped-by"
+	EdgeCondition EdgeKind = "condition"
+	EdgePermits   EdgeKind = "permits"
+	EdgeTriggers  EdgeKind = "triggers"
+	EdgeRequires  EdgeKind = "requires"
+	EdgeOwnsKey   EdgeKind = "owns-key"
+	EdgeKey       EdgeKind = "key"
+	EdgeEntitles  EdgeKind = "entitles"
+)
+
+type Node struct {
+	ID    string   `json:"id"`
+	Kind  NodeKind `json:"kind"`
+	Label string   `json:"label"`
+	// PolicyID is the platform ID of the object, empty for keys which are identified by KAS and key ID
+	PolicyID string `json:"policy_id,omitempty"`
+
+	// names are alternate identifiers accepted as roots
+	names []string
+}
+
+type Edge struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Kind  EdgeKind `json:"kind"`
+	Label string   `json:"label,omitempty"`
+}
+
+// Graph is a directed graph of policy objects. Edges point from the containing or referencing object to the object
+// it contains or references, e.g. namespace -> attribute -> value -> subject mapping -> condition set.
+type Graph struct {
+	Nodes []Node `json:"nodes"`
+	Edges []Edge `json:"edges"`
+
+	index map[string]int
+	edges map[Edge]bool
+}
+
+func New() *Graph {
+	return &Graph{index: map[string]int{}, edges: map[Edge]bool{}}
+}
+
+// AddNode adds a node, keeping the first label seen for an ID
+func (g *Graph) AddNode(n Node) {
+	if n.ID == "" {
+		return
+	}
+	if _, ok := g.index[n.ID]; ok {
+		return
+	}
+	g.index[n.ID] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, n)
+}
+
+func (g *Graph) AddEdge(e Edge) {
+	if e.From == "" || e.To == "" || g.edges[e] {
+		return
+	}
+	g.edges[e] = true
+	g.Edges = append(g.Edges, e)
+}
+
+func (g *Graph) Node(id string) (Node, bool) {
+	i, ok := g.index[id]
+	if !ok {
+		return Node{}, false
+	}
+	return g.Nodes[i], true
+}
+
+func nodeID(kind NodeKind, id string) string {
+	if id == "" {
+		return ""
+	}
+	return string(kind) + ":" + id
+}
+
+func keyNodeID(kasID, kid string) string {
+	return nodeID(KindKasKey, kasID+"/"+kid)
+}
+
+// Build creates the graph of every object in the snapshot.
+//
+//nolint:gocognit // a flat walk over each object type
+func Build(s *handlers.PolicySnapshot) *Graph {
+	g := New()
+
+	for _, kas := range s.KeyAccessServers {
+		label := kas.GetUri()
+		if kas.GetName() != "" {
+			label = kas.GetName() + " (" + kas.GetUri() + ")"
+		}
+		g.AddNode(Node{
+			ID:       nodeID(KindKeyAccessServer, kas.GetId()),
+			Kind:     KindKeyAccessServer,
+			Label:    label,
+			PolicyID: kas.GetId(),
+			names:    []string{kas.GetName(), kas.GetUri()},
+		})
+	}
+	for _, k := range s.KasKeys {
+		id := keyNodeID(k.GetKasId(), k.GetKey().GetKeyId())
+		g.AddNode(Node{ID: id, Kind: KindKasKey, Label: k.GetKey().GetKeyId() + " " + k.GetKey().GetKeyAlgorithm().String(), PolicyID: k.GetKey().GetId()})
+		g.AddEdge(Edge{From: nodeID(KindKeyAccessServer, k.GetKasId()), To: id, Kind: EdgeOwnsKey})
+	}
+	addKeyEdges := func(from string, keys []*policy.SimpleKasKey) {
+		for _, k := range keys {
+			id := keyNodeID(k.GetKasId(), k.GetPublicKey().GetKid())
+			g.AddNode(Node{ID: id, Kind: KindKasKey, Label: k.GetPublicKey().GetKid()})
+			g.AddEdge(Edge{From: nodeID(KindKeyAccessServer, k.GetKasId()), To: id, Kind: EdgeOwnsKey})
+			g.AddEdge(Edge{From: from, To: id, Kind: EdgeKey})
+		}
+	}
+
+	for _, ns := range s.Namespaces {
+		id := nodeID(KindNamespace, ns.GetId())
+		g.AddNode(Node{ID: id, Kind: KindNamespace, Label: ns.GetFqn(), PolicyID: ns.GetId()})
+		addKeyEdges(id, ns.GetKasKeys())
+	}
+
+	for _, a := range s.Attributes {
+		id := nodeID(KindAttribute, a.GetId())
+		g.AddNode(Node{ID: id, Kind: KindAttribute, Label: a.GetFqn(), PolicyID: a.GetId()})
+		g.AddEdge(Edge{From: nodeID(KindNamespace, a.GetNamespace().GetId()), To: id, Kind: EdgeContains})
+		addKeyEdges(id, a.GetKasKeys())
+		for i, v := range a.GetValues() {
+			vid := nodeID(KindValue, v.GetId())
+			g.AddNode(Node{ID: vid, Kind: KindValue, Label: v.GetFqn(), PolicyID: v.GetId()})
+			e := Edge{From: id, To: vid, Kind: EdgeContains}
+			if a.GetRule() == policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY {
+				e.Label = rankLabel(i)
+			}
+			g.AddEdge(e)
+			addKeyEdges(vid, v.GetKasKeys())
+		}
+	}
+
+	for _, a := range s.Actions {
+		g.AddNode(Node{ID: nodeID(KindAction, a.GetId()), Kind: KindAction, Label: a.GetName(), PolicyID: a.GetId()})
+	}
+	actionNode := func(a *policy.Action) string {
+		id := nodeID(KindAction, a.GetId())
+		g.AddNode(Node{ID: id, Kind: KindAction, Label: a.GetName(), PolicyID: a.GetId()})
+		return id
+	}
+
+	for _, scs := range s.SubjectConditionSets {
+		g.AddNode(Node{ID: nodeID(KindSubjectConditionSet, scs.GetId()), Kind: KindSubjectConditionSet, Label: scs.GetId(), PolicyID: scs.GetId()})
+	}
+	for _, sm := range s.SubjectMappings {
+		id := nodeID(KindSubjectMapping, sm.GetId())
+		g.AddNode(Node{ID: id, Kind: KindSubjectMapping, Label: sm.GetId(), PolicyID: sm.GetId()})
+		g.AddEdge(Edge{From: nodeID(KindValue, sm.GetAttributeValue().GetId()), To: id, Kind: EdgeMappedBy})
+		scsID := nodeID(KindSubjectConditionSet, sm.GetSubjectConditionSet().GetId())
+		g.AddNode(Node{ID: scsID, Kind: KindSubjectConditionSet, Label: sm.GetSubjectConditionSet().GetId(), PolicyID: sm.GetSubjectConditionSet().GetId()})
+		g.AddEdge(Edge{From: id, To: scsID, Kind: EdgeCondition})
+		for _, a := range sm.GetActions() {
+			g.AddEdge(Edge{From: id, To: actionNode(a), Kind: EdgePermits})
+		}
+	}
+
+	for _, o := range s.Obligations {
+		id := nodeID(KindObligation, o.GetId())
+		g.AddNode(Node{ID: id, Kind: KindObligation, Label: o.GetFqn(), PolicyID: o.GetId()})
+		g.AddEdge(Edge{From: nodeID(KindNamespace, o.GetNamespace().GetId()), To: id, Kind: EdgeContains})
+		for _, v := range o.GetValues() {
+			vid := nodeID(KindObligationValue, v.GetId())
+			g.AddNode(Node{ID: vid, Kind: KindObligationValue, Label: v.GetFqn(), PolicyID: v.GetId()})
+			g.AddEdge(Edge{From: id, To: vid, Kind: EdgeContains})
+		}
+	}
+	for _, t := range s.ObligationTriggers {
+		id := nodeID(KindObligationTrigger, t.GetId())
+		label := t.GetAction().GetName()
+		if t.GetAttributeValue().GetFqn() != "" {
+			label = t.GetAttributeValue().GetFqn() + " on " + label
+		}
+		g.AddNode(Node{ID: id, Kind: KindObligationTrigger, Label: label, PolicyID: t.GetId()})
+		g.AddEdge(Edge{From: nodeID(KindValue, t.GetAttributeValue().GetId()), To: id, Kind: EdgeTriggers})
+		g.AddEdge(Edge{From: id, To: actionNode(t.GetAction()), Kind: EdgePermits})
+		g.AddEdge(Edge{From: id, To: nodeID(KindObligationValue, t.GetObligationValue().GetId()), Kind: EdgeRequires})
+	}
+
+	for _, r := range s.RegisteredResources {
+		id := nodeID(KindRegisteredResource, r.GetId())
+		g.AddNode(Node{ID: id, Kind: KindRegisteredResource, Label: r.GetName(), PolicyID: r.GetId()})
+		g.AddEdge(Edge{From: nodeID(KindNamespace, r.GetNamespace().GetId()), To: id, Kind: EdgeContains})
+		for _, v := range r.GetValues() {
+			vid := nodeID(KindRegisteredResourceVal, v.GetId())
+			g.AddNode(Node{ID: vid, Kind: KindRegisteredResourceVal, Label: r.GetName() + "/" + v.GetValue(), PolicyID: v.GetId()})
+			g.AddEdge(Edge{From: id, To: vid, Kind: EdgeContains})
+			for _, aav := range v.GetActionAttributeValues() {
+				g.AddEdge(Edge{From: vid, To: nodeID(KindValue, aav.GetAttributeValue().GetId()), Kind: EdgeEntitles, Label: aav.GetAction().GetName()})
+			}
+		}
+	}
+
+	// drop edges whose endpoints were never loaded, e.g. a namespace missing from a filtered list
+	kept := g.Edges[:0]
+	for _, e := range g.Edges {
+		_, fromOK := g.index[e.From]
+		_, toOK := g.index[e.To]
+		if fromOK && toOK {
+			kept = append(kept, e)
+		} else {
+			delete(g.edges, e)
+		}
+	}
+	g.Edges = kept
+	return g
+}
+
+func rankLabel(i int) string {
+	return "rank " + strconv.Itoa(i+1)
+}
+
+// Subgraph returns the objects reachable from the roots by following edges forward. Keys reached this way are
+// shown with their owning KAS. From a KAS root, key edges are also followed backward so the subgraph includes the
+// policy objects its keys are assigned to.
+func (g *Graph) Subgraph(roots []string) *Graph {
+	out := map[string][]Edge{}
+	in := map[string][]Edge{}
+	for _, e := range g.Edges {
+		out[e.From] = append(out[e.From], e)
+		in[e.To] = append(in[e.To], e)
+	}
+
+	seen := map[string]bool{}
+	keptEdges := map[Edge]bool{}
+	var queue []string
+	enqueue := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			queue = append(queue, id)
+		}
+	}
+	kasRoots := map[string]bool{}
+	for _, r := range roots {
+		if n, ok := g.Node(r); ok {
+			kasRoots[r] = n.Kind == KindKeyAccessServer
+			enqueue(r)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, e := range out[id] {
+			keptEdges[e] = true
+			enqueue(e.To)
+		}
+
+		n, _ := g.Node(id)
+		if n.Kind != KindKasKey {
+			continue
+		}
+		for _, e := range in[id] {
+			switch {
+			case e.Kind == EdgeOwnsKey:
+				// show the owning KAS without pulling in its other keys
+				keptEdges[e] = true
+				seen[e.From] = true
+			case e.Kind == EdgeKey && ownedByRoot(in[id], kasRoots):
+				keptEdges[e] = true
+				enqueue(e.From)
+			}
+		}
+	}
+
+	sub := New()
+	for _, n := range g.Nodes {
+		if seen[n.ID] {
+			sub.AddNode(n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keptEdges[e] {
+			sub.AddEdge(e)
+		}
+	}
+	return sub
+}
+
+func ownedByRoot(keyIn []Edge, kasRoots map[string]bool) bool {
+	for _, e := range keyIn {
+		if e.Kind == EdgeOwnsKey && kasRoots[e.From] {
+			return true
+		}
+	}
+	return false
+}
+
+// FindRoots resolves a filter to the IDs of nodes of the given kind, matching the policy ID, the label, or for a
+// KAS its name or URI.
+func (g *Graph) FindRoots(kind NodeKind, filter string) []string {
+	var ids []string
+	for _, n := range g.Nodes {
+		if n.Kind == kind && (n.PolicyID == filter || n.Label == filter || slices.Contains(n.names, filter)) {
+			ids = append(ids, n.ID)
+		}
+	}
+	return ids
+}
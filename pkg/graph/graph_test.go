@@ -0,0 +1,74 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/stretchr/testify/assert"
+)
+
+func testSnapshot() *handlers.PolicySnapshot {
+	ns1 := &policy.Namespace{Id: "ns1", Fqn: "https://one.com"}
+	ns2 := &policy.Namespace{Id: "ns2", Fqn: "https://two.com"}
+	v1 := &policy.Value{Id: "v1", Fqn: "https://one.com/attr/a/value/x"}
+	v2 := &policy.Value{Id: "v2", Fqn: "https://two.com/attr/b/value/y"}
+	read := &policy.Action{Id: "read", Name: "read"}
+	key := &policy.SimpleKasKey{KasId: "kas1", KasUri: "https://kas.one.com", PublicKey: &policy.SimpleKasPublicKey{Kid: "k1"}}
+
+	return &handlers.PolicySnapshot{
+		Namespaces: []*policy.Namespace{ns1, ns2},
+		Attributes: []*policy.Attribute{
+			{Id: "a1", Fqn: "https://one.com/attr/a", Namespace: ns1, Values: []*policy.Value{v1}, KasKeys: []*policy.SimpleKasKey{key}},
+			{Id: "a2", Fqn: "https://two.com/attr/b", Namespace: ns2, Values: []*policy.Value{v2}},
+		},
+		Actions: []*policy.Action{read},
+		SubjectMappings: []*policy.SubjectMapping{
+			{Id: "sm1", AttributeValue: v1, SubjectConditionSet: &policy.SubjectConditionSet{Id: "scs1"}, Actions: []*policy.Action{read}},
+			{Id: "sm2", AttributeValue: v2, SubjectConditionSet: &policy.SubjectConditionSet{Id: "scs2"}, Actions: []*policy.Action{read}},
+		},
+		KeyAccessServers: []*policy.KeyAccessServer{{Id: "kas1", Uri: "https://kas.one.com", Name: "one"}},
+	}
+}
+
+func ids(g *Graph) []string {
+	out := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		out = append(out, n.ID)
+	}
+	return out
+}
+
+func TestSubgraph_Namespace(t *testing.T) {
+	g := Build(testSnapshot())
+	roots := g.FindRoots(KindNamespace, "https://one.com")
+	assert.Equal(t, []string{"namespace:ns1"}, roots)
+
+	sub := g.Subgraph(roots)
+	assert.ElementsMatch(t, []string{
+		"namespace:ns1", "attribute:a1", "value:v1", "subject-mapping:sm1", "subject-condition-set:scs1",
+		"action:read", "kas-key:kas1/k1", "kas:kas1",
+	}, ids(sub))
+}
+
+func TestSubgraph_KAS(t *testing.T) {
+	g := Build(testSnapshot())
+	roots := g.FindRoots(KindKeyAccessServer, "https://kas.one.com")
+	assert.Equal(t, roots, g.FindRoots(KindKeyAccessServer, "one"))
+
+	sub := g.Subgraph(roots)
+	assert.Contains(t, ids(sub), "attribute:a1")
+	assert.NotContains(t, ids(sub), "attribute:a2")
+}
+
+func TestRender(t *testing.T) {
+	g := Build(testSnapshot()).Subgraph([]string{"attribute:a1"})
+	dot := g.DOT()
+	assert.Contains(t, dot, `"attribute:a1" -> "value:v1" [label="contains"];`)
+	assert.Contains(t, dot, `label="attribute\nhttps://one.com/attr/a"`)
+
+	mermaid := g.Mermaid()
+	assert.Contains(t, mermaid, "flowchart LR")
+	assert.Contains(t, mermaid, `["attribute<br/>https://one.com/attr/a"]`)
+	assert.Contains(t, mermaid, `-->|"contains"|`)
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	FormatDOT     = "dot"
+	FormatMermaid = "mermaid"
+	FormatJSON    = "json"
+)
+
+var dotShapes = map[NodeKind]string{
+	KindNamespace:             "folder",
+	KindAttribute:             "box",
+	KindValue:                 "ellipse",
+	KindSubjectMapping:        "diamond",
+	KindSubjectConditionSet:   "note",
+	KindAction:                "cds",
+	KindObligation:            "box3d",
+	KindObligationValue:       "ellipse",
+	KindObligationTrigger:     "hexagon",
+	KindKeyAccessServer:       "component",
+	KindKasKey:                "septagon",
+	KindRegisteredResource:    "tab",
+	KindRegisteredResourceVal: "ellipse",
+}
+
+// DOT renders the graph in Graphviz DOT format.
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph policy {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=8];\n")
+	for _, n := range g.Nodes {
+		shape := dotShapes[n.Kind]
+		if shape == "" {
+			shape = "box"
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=%s];\n", dotQuote(n.ID), dotQuote(string(n.Kind)+"\n"+n.Label), shape)
+	}
+	for _, e := range g.Edges {
+		label := string(e.Kind)
+		if e.Label != "" {
+			label += ": " + e.Label
+		}
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(label))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// Mermaid renders the graph as a Mermaid flowchart. Node IDs are replaced with positional identifiers as Mermaid
+// does not accept the characters used in policy IDs and FQNs.
+func (g *Graph) Mermaid() string {
+	ids := make(map[string]string, len(g.Nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range g.Nodes {
+		ids[n.ID] = fmt.Sprintf("n%d", i)
+		open, closing := mermaidShape(n.Kind)
+		fmt.Fprintf(&b, "  %s%s\"%s<br/>%s\"%s\n", ids[n.ID], open, n.Kind, mermaidEscape(n.Label), closing)
+	}
+	for _, e := range g.Edges {
+		label := string(e.Kind)
+		if e.Label != "" {
+			label += ": " + e.Label
+		}
+		fmt.Fprintf(&b, "  %s -->|\"%s\"| %s\n", ids[e.From], mermaidEscape(label), ids[e.To])
+	}
+	return b.String()
+}
+
+func mermaidShape(k NodeKind) (string, string) {
+	switch k {
+	case KindNamespace, KindObligation, KindRegisteredResource:
+		return "[[", "]]"
+	case KindValue, KindObligationValue, KindRegisteredResourceVal:
+		return "(", ")"
+	case KindSubjectMapping:
+		return "{", "}"
+	case KindAction:
+		return ">", "]"
+	case KindKeyAccessServer, KindKasKey:
+		return "[(", ")]"
+	case KindObligationTrigger:
+		return "{{", "}}"
+	}
+	return "[", "]"
+}
+
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
@@ -64,6 +64,14 @@ func (h Handler) ListAttributes(ctx context.Context, state common.ActiveStateEnu
 	})
 }
 
+// ListAllAttributes pages through every active attribute with its values
+func (h Handler) ListAllAttributes(ctx context.Context) ([]*policy.Attribute, error) {
+	return listAll(func(limit, offset int32) ([]*policy.Attribute, error) {
+		resp, err := h.ListAttributes(ctx, common.ActiveStateEnum_ACTIVE_STATE_ENUM_ACTIVE, limit, offset)
+		return resp.GetAttributes(), err
+	})
+}
+
 // Creates and returns the created attribute
 func (h Handler) CreateAttribute(ctx context.Context, name string, rule string, namespace string, values []string, metadata *common.MetadataMutable, allowTraversal *wrapperspb.BoolValue) (*policy.Attribute, error) {
 	r, err := GetAttributeRuleFromReadableString(rule)
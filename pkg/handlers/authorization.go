@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	authorizationv2 "github.com/opentdf/platform/protocol/go/authorization/v2"
+	"github.com/opentdf/platform/protocol/go/entity"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// GetEntitlementsForToken resolves the attribute values and actions the entity represented by the access token
+// is entitled to.
+func (h Handler) GetEntitlementsForToken(ctx context.Context, accessToken string, comprehensiveHierarchy bool) ([]*authorizationv2.EntityEntitlements, error) {
+	return h.getEntitlements(ctx, &authorizationv2.EntityIdentifier{
+		Identifier: &authorizationv2.EntityIdentifier_Token{
+			Token: &entity.Token{
+				EphemeralId: "whoami",
+				Jwt:         accessToken,
+			},
+		},
+	}, comprehensiveHierarchy)
+}
+
+// GetEntitlementsForSubject resolves the entitlements of a subject given as a JWT or as JSON claims, returning the
+// entitled action names per attribute value FQN.
+func (h Handler) GetEntitlementsForSubject(ctx context.Context, subject string, comprehensiveHierarchy bool) (map[string][]string, error) {
+	var id *authorizationv2.EntityIdentifier
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal([]byte(subject), &claims); err == nil {
+		s, err := structpb.NewStruct(claims)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subject claims: %w", err)
+		}
+		a, err := anypb.New(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid subject claims: %w", err)
+		}
+		id = &authorizationv2.EntityIdentifier{
+			Identifier: &authorizationv2.EntityIdentifier_EntityChain{
+				EntityChain: &entity.EntityChain{
+					EphemeralId: "subject",
+					Entities: []*entity.Entity{{
+						EphemeralId: "subject",
+						EntityType:  &entity.Entity_Claims{Claims: a},
+						Category:    entity.Entity_CATEGORY_SUBJECT,
+					}},
+				},
+			},
+		}
+	} else {
+		id = &authorizationv2.EntityIdentifier{
+			Identifier: &authorizationv2.EntityIdentifier_Token{
+				Token: &entity.Token{EphemeralId: "subject", Jwt: subject},
+			},
+		}
+	}
+
+	entitlements, err := h.getEntitlements(ctx, id, comprehensiveHierarchy)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string][]string{}
+	for _, e := range entitlements {
+		for fqn, actions := range e.GetActionsPerAttributeValueFqn() {
+			for _, a := range actions.GetActions() {
+				out[fqn] = append(out[fqn], a.GetName())
+			}
+		}
+	}
+	return out, nil
+}
+
+func (h Handler) getEntitlements(ctx context.Context, id *authorizationv2.EntityIdentifier, comprehensiveHierarchy bool) ([]*authorizationv2.EntityEntitlements, error) {
+	resp, err := h.sdk.AuthorizationV2.GetEntitlements(ctx, &authorizationv2.GetEntitlementsRequest{
+		EntityIdentifier:           id,
+		WithComprehensiveHierarchy: &comprehensiveHierarchy,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetEntitlements(), nil
+}
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/profiles"
+	"google.golang.org/protobuf/proto"
+)
+
+// Journal opens the change journal of the profile the handler was created with
+func (h Handler) Journal() (*journal.Journal, error) {
+	dir, err := profiles.UserConfigDirectory()
+	if err != nil {
+		return nil, err
+	}
+	return journal.ForProfile(dir, h.ProfileName()), nil
+}
+
+// RecordChange records a change made through the handler in the journal of its profile. undoOf is the entry the change
+// reverted, or 0.
+func (h Handler) RecordChange(operation, kind string, before, after proto.Message, undoOf int) error {
+	j, err := h.Journal()
+	if err != nil {
+		return err
+	}
+	e, err := journal.NewEntry(h.PlatformEndpoint(), operation, kind, before, after)
+	if err != nil {
+		return err
+	}
+	e.UndoOf = undoOf
+	return j.Append(e)
+}
@@ -118,6 +118,14 @@ func (h Handler) ListKasKeys(
 	return h.sdk.KeyAccessServerRegistry.ListKeys(ctx, &req)
 }
 
+// ListAllKasKeys pages through the keys of every registered KAS
+func (h Handler) ListAllKasKeys(ctx context.Context) ([]*policy.KasKey, error) {
+	return listAll(func(limit, offset int32) ([]*policy.KasKey, error) {
+		resp, err := h.ListKasKeys(ctx, limit, offset, policy.Algorithm_ALGORITHM_UNSPECIFIED, KasIdentifier{}, nil)
+		return resp.GetKasKeys(), err
+	})
+}
+
 func (h Handler) ListKeyMappings(
 	ctx context.Context,
 	limit, offset int32,
@@ -150,6 +158,15 @@ func (h Handler) ListKeyMappings(
 	return resp, nil
 }
 
+// ListAllKeyMappings pages through the namespaces, attributes and values mapped to a key, by its system ID, or to every
+// key when the ID is empty
+func (h Handler) ListAllKeyMappings(ctx context.Context, keySystemID string) ([]*kasregistry.KeyMapping, error) {
+	return listAll(func(limit, offset int32) ([]*kasregistry.KeyMapping, error) {
+		resp, err := h.ListKeyMappings(ctx, limit, offset, keySystemID, nil)
+		return resp.GetKeyMappings(), err
+	})
+}
+
 func (h Handler) RotateKasKey(
 	ctx context.Context,
 	oldKeyID string,
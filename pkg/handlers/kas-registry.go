@@ -51,6 +51,14 @@ func (h Handler) ListKasRegistryEntries(ctx context.Context, limit, offset int32
 	})
 }
 
+// ListAllKasRegistryEntries pages through the registry and returns every KAS entry
+func (h Handler) ListAllKasRegistryEntries(ctx context.Context) ([]*policy.KeyAccessServer, error) {
+	return listAll(func(limit, offset int32) ([]*policy.KeyAccessServer, error) {
+		resp, err := h.ListKasRegistryEntries(ctx, limit, offset)
+		return resp.GetKeyAccessServers(), err
+	})
+}
+
 // Creates the KAS registry and then returns the KAS
 func (h Handler) CreateKasRegistryEntry(ctx context.Context, uri string, name string, metadata *common.MetadataMutable) (*policy.KeyAccessServer, error) {
 	req := &kasregistry.CreateKeyAccessServerRequest{
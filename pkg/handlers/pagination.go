@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+)
+
+const listAllPageSize int32 = 100
+
+var ErrListLimitExceeded = errors.New("list count exceeded safe limit")
+
+// listAll pages through a list RPC until a page comes back short, returning every item
+func listAll[T any](page func(limit, offset int32) ([]T, error)) ([]T, error) {
+	var (
+		all    []T
+		offset int32
+	)
+	for {
+		items, err := page(listAllPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, items...)
+
+		qty := len(items)
+		if qty > math.MaxInt32 || offset+int32(qty) < 0 {
+			return nil, ErrListLimitExceeded
+		}
+		offset += int32(qty)
+
+		if int32(qty) < listAllPageSize {
+			return all, nil
+		}
+	}
+}
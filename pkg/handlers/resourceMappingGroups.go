@@ -42,6 +42,14 @@ func (h *Handler) ListResourceMappingGroups(ctx context.Context, limit, offset i
 	})
 }
 
+// ListAllResourceMappingGroups pages through every resource mapping group
+func (h *Handler) ListAllResourceMappingGroups(ctx context.Context) ([]*policy.ResourceMappingGroup, error) {
+	return listAll(func(limit, offset int32) ([]*policy.ResourceMappingGroup, error) {
+		resp, err := h.ListResourceMappingGroups(ctx, limit, offset)
+		return resp.GetResourceMappingGroups(), err
+	})
+}
+
 // TODO: verify updation behavior
 // Updates and returns the updated resource mapping
 func (h *Handler) UpdateResourceMappingGroup(ctx context.Context, id string, namespaceID string, name string, metadata *common.MetadataMutable, behavior common.MetadataUpdateEnum) (*policy.ResourceMappingGroup, error) {
@@ -43,6 +43,14 @@ func (h *Handler) ListResourceMappings(ctx context.Context, limit, offset int32)
 	})
 }
 
+// ListAllResourceMappings pages through every resource mapping
+func (h *Handler) ListAllResourceMappings(ctx context.Context) ([]*policy.ResourceMapping, error) {
+	return listAll(func(limit, offset int32) ([]*policy.ResourceMapping, error) {
+		resp, err := h.ListResourceMappings(ctx, limit, offset)
+		return resp.GetResourceMappings(), err
+	})
+}
+
 // TODO: verify updation behavior
 // Updates and returns the updated resource mapping
 func (h *Handler) UpdateResourceMapping(id string, attrValueID string, grpID string, terms []string, metadata *common.MetadataMutable, behavior common.MetadataUpdateEnum) (*policy.ResourceMapping, error) {
@@ -20,6 +20,8 @@ var (
 type Handler struct {
 	sdk              *sdk.SDK
 	platformEndpoint string
+	profileName      string
+	tlsNoVerify      bool
 }
 
 type handlerOpts struct {
@@ -102,16 +104,36 @@ func New(opts ...handlerOptsFunc) (Handler, error) {
 		return Handler{}, err
 	}
 
-	return Handler{
+	h := Handler{
 		sdk:              s,
 		platformEndpoint: o.endpoint,
-	}, nil
+		tlsNoVerify:      o.TLSNoVerify,
+	}
+	if o.profile != nil {
+		h.profileName = o.profile.Name()
+	}
+	return h, nil
 }
 
 func (h Handler) Close() error {
 	return h.sdk.Close()
 }
 
+// PlatformEndpoint is the endpoint of the platform the handler talks to
+func (h Handler) PlatformEndpoint() string {
+	return h.platformEndpoint
+}
+
+// ProfileName is the name of the profile the handler was created with, if any
+func (h Handler) ProfileName() string {
+	return h.profileName
+}
+
+// TLSNoVerify reports whether the handler skips verification of the certificates served by the platform
+func (h Handler) TLSNoVerify() bool {
+	return h.tlsNoVerify
+}
+
 func (h Handler) Direct() *sdk.SDK {
 	return h.sdk
 }
@@ -46,3 +46,17 @@ func FlattenSubjectContext(subject string) ([]flat.Item, error) {
 
 	return flattened.Items, nil
 }
+
+// FlattenSubjectValues flattens a subject into the values found at each selector, the form in which subject
+// condition sets are evaluated
+func FlattenSubjectValues(subject string) (map[string][]string, error) {
+	items, err := FlattenSubjectContext(subject)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string][]string, len(items))
+	for _, item := range items {
+		values[item.Key] = append(values[item.Key], fmt.Sprintf("%v", item.Value))
+	}
+	return values, nil
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/opentdf/platform/protocol/go/common"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// PolicySnapshot is the complete live policy of a platform, loaded through the list RPCs.
+type PolicySnapshot struct {
+	Namespaces           []*policy.Namespace
+	Attributes           []*policy.Attribute
+	SubjectMappings      []*policy.SubjectMapping
+	SubjectConditionSets []*policy.SubjectConditionSet
+	Actions              []*policy.Action
+	Obligations          []*policy.Obligation
+	ObligationTriggers   []*policy.ObligationTrigger
+	KeyAccessServers     []*policy.KeyAccessServer
+	KasKeys              []*policy.KasKey
+	RegisteredResources  []*policy.RegisteredResource
+	BaseKey              *policy.SimpleKasKey
+}
+
+// LoadPolicySnapshot pages through every policy object, including inactive namespaces, attributes and values.
+func (h Handler) LoadPolicySnapshot(ctx context.Context) (*PolicySnapshot, error) {
+	s := &PolicySnapshot{}
+	var err error
+
+	if s.Namespaces, err = listAll(func(limit, offset int32) ([]*policy.Namespace, error) {
+		resp, err := h.ListNamespaces(ctx, common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY, limit, offset)
+		return resp.GetNamespaces(), err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	if s.Attributes, err = listAll(func(limit, offset int32) ([]*policy.Attribute, error) {
+		resp, err := h.ListAttributes(ctx, common.ActiveStateEnum_ACTIVE_STATE_ENUM_ANY, limit, offset)
+		return resp.GetAttributes(), err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list attributes: %w", err)
+	}
+
+	if s.SubjectMappings, err = listAll(func(limit, offset int32) ([]*policy.SubjectMapping, error) {
+		resp, err := h.ListSubjectMappings(ctx, limit, offset, "")
+		return resp.GetSubjectMappings(), err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list subject mappings: %w", err)
+	}
+
+	if s.SubjectConditionSets, err = listAll(func(limit, offset int32) ([]*policy.SubjectConditionSet, error) {
+		resp, err := h.ListSubjectConditionSets(ctx, limit, offset, "")
+		return resp.GetSubjectConditionSets(), err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list subject condition sets: %w", err)
+	}
+
+	// standard actions are returned on every page, so only the custom actions are paged
+	var standard []*policy.Action
+	custom, err := listAll(func(limit, offset int32) ([]*policy.Action, error) {
+		resp, err := h.ListActions(ctx, limit, offset, "")
+		if offset == 0 {
+			standard = resp.GetActionsStandard()
+		}
+		return resp.GetActionsCustom(), err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list actions: %w", err)
+	}
+	s.Actions = append(standard, custom...)
+
+	if s.Obligations, err = listAll(func(limit, offset int32) ([]*policy.Obligation, error) {
+		resp, err := h.ListObligations(ctx, limit, offset, "")
+		return resp.GetObligations(), err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list obligations: %w", err)
+	}
+
+	if s.ObligationTriggers, err = listAll(func(limit, offset int32) ([]*policy.ObligationTrigger, error) {
+		resp, err := h.ListObligationTriggers(ctx, "", limit, offset)
+		return resp.GetTriggers(), err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list obligation triggers: %w", err)
+	}
+
+	if s.KeyAccessServers, err = h.ListAllKasRegistryEntries(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list key access servers: %w", err)
+	}
+
+	if s.KasKeys, err = h.ListAllKasKeys(ctx); err != nil {
+		return nil, fmt.Errorf("failed to list KAS keys: %w", err)
+	}
+
+	if s.RegisteredResources, err = listAll(func(limit, offset int32) ([]*policy.RegisteredResource, error) {
+		resp, err := h.ListRegisteredResources(ctx, limit, offset, "")
+		return resp.GetResources(), err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list registered resources: %w", err)
+	}
+
+	if s.BaseKey, err = h.GetBaseKey(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get base key: %w", err)
+	}
+
+	return s, nil
+}
+
+// policySnapshotFileVersion is bumped when the snapshot file layout changes incompatibly
+const policySnapshotFileVersion = 1
+
+type policySnapshotFile struct {
+	Version              int               `json:"version"`
+	Namespaces           []json.RawMessage `json:"namespaces"`
+	Attributes           []json.RawMessage `json:"attributes"`
+	SubjectMappings      []json.RawMessage `json:"subject_mappings"`
+	SubjectConditionSets []json.RawMessage `json:"subject_condition_sets"`
+	Actions              []json.RawMessage `json:"actions"`
+	Obligations          []json.RawMessage `json:"obligations"`
+	ObligationTriggers   []json.RawMessage `json:"obligation_triggers"`
+	KeyAccessServers     []json.RawMessage `json:"key_access_servers"`
+	KasKeys              []json.RawMessage `json:"kas_keys"`
+	RegisteredResources  []json.RawMessage `json:"registered_resources"`
+	BaseKey              json.RawMessage   `json:"base_key,omitempty"`
+}
+
+func marshalProtoList[T proto.Message](items []T) ([]json.RawMessage, error) {
+	out := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		b, err := protojson.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func unmarshalProtoList[T proto.Message](raw []json.RawMessage, newT func() T) ([]T, error) {
+	out := make([]T, 0, len(raw))
+	opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+	for _, r := range raw {
+		item := newT()
+		if err := opts.Unmarshal(r, item); err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// MarshalJSON writes the snapshot with each policy object in its protojson form.
+func (s *PolicySnapshot) MarshalJSON() ([]byte, error) {
+	f := policySnapshotFile{Version: policySnapshotFileVersion}
+	var err error
+	if f.Namespaces, err = marshalProtoList(s.Namespaces); err != nil {
+		return nil, err
+	}
+	if f.Attributes, err = marshalProtoList(s.Attributes); err != nil {
+		return nil, err
+	}
+	if f.SubjectMappings, err = marshalProtoList(s.SubjectMappings); err != nil {
+		return nil, err
+	}
+	if f.SubjectConditionSets, err = marshalProtoList(s.SubjectConditionSets); err != nil {
+		return nil, err
+	}
+	if f.Actions, err = marshalProtoList(s.Actions); err != nil {
+		return nil, err
+	}
+	if f.Obligations, err = marshalProtoList(s.Obligations); err != nil {
+		return nil, err
+	}
+	if f.ObligationTriggers, err = marshalProtoList(s.ObligationTriggers); err != nil {
+		return nil, err
+	}
+	if f.KeyAccessServers, err = marshalProtoList(s.KeyAccessServers); err != nil {
+		return nil, err
+	}
+	if f.KasKeys, err = marshalProtoList(s.KasKeys); err != nil {
+		return nil, err
+	}
+	if f.RegisteredResources, err = marshalProtoList(s.RegisteredResources); err != nil {
+		return nil, err
+	}
+	if s.BaseKey != nil {
+		if f.BaseKey, err = protojson.Marshal(s.BaseKey); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(f)
+}
+
+// UnmarshalJSON reads a snapshot written by MarshalJSON.
+func (s *PolicySnapshot) UnmarshalJSON(b []byte) error {
+	var f policySnapshotFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return err
+	}
+	if f.Version != policySnapshotFileVersion {
+		return fmt.Errorf("unsupported policy snapshot version %d", f.Version)
+	}
+
+	var err error
+	if s.Namespaces, err = unmarshalProtoList(f.Namespaces, func() *policy.Namespace { return &policy.Namespace{} }); err != nil {
+		return fmt.Errorf("invalid namespaces: %w", err)
+	}
+	if s.Attributes, err = unmarshalProtoList(f.Attributes, func() *policy.Attribute { return &policy.Attribute{} }); err != nil {
+		return fmt.Errorf("invalid attributes: %w", err)
+	}
+	if s.SubjectMappings, err = unmarshalProtoList(f.SubjectMappings, func() *policy.SubjectMapping { return &policy.SubjectMapping{} }); err != nil {
+		return fmt.Errorf("invalid subject mappings: %w", err)
+	}
+	if s.SubjectConditionSets, err = unmarshalProtoList(f.SubjectConditionSets, func() *policy.SubjectConditionSet { return &policy.SubjectConditionSet{} }); err != nil {
+		return fmt.Errorf("invalid subject condition sets: %w", err)
+	}
+	if s.Actions, err = unmarshalProtoList(f.Actions, func() *policy.Action { return &policy.Action{} }); err != nil {
+		return fmt.Errorf("invalid actions: %w", err)
+	}
+	if s.Obligations, err = unmarshalProtoList(f.Obligations, func() *policy.Obligation { return &policy.Obligation{} }); err != nil {
+		return fmt.Errorf("invalid obligations: %w", err)
+	}
+	if s.ObligationTriggers, err = unmarshalProtoList(f.ObligationTriggers, func() *policy.ObligationTrigger { return &policy.ObligationTrigger{} }); err != nil {
+		return fmt.Errorf("invalid obligation triggers: %w", err)
+	}
+	if s.KeyAccessServers, err = unmarshalProtoList(f.KeyAccessServers, func() *policy.KeyAccessServer { return &policy.KeyAccessServer{} }); err != nil {
+		return fmt.Errorf("invalid key access servers: %w", err)
+	}
+	if s.KasKeys, err = unmarshalProtoList(f.KasKeys, func() *policy.KasKey { return &policy.KasKey{} }); err != nil {
+		return fmt.Errorf("invalid KAS keys: %w", err)
+	}
+	if s.RegisteredResources, err = unmarshalProtoList(f.RegisteredResources, func() *policy.RegisteredResource { return &policy.RegisteredResource{} }); err != nil {
+		return fmt.Errorf("invalid registered resources: %w", err)
+	}
+	s.BaseKey = nil
+	if len(f.BaseKey) > 0 {
+		s.BaseKey = &policy.SimpleKasKey{}
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(f.BaseKey, s.BaseKey); err != nil {
+			return fmt.Errorf("invalid base key: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,331 @@
+// Package impact works out which policy objects a deactivation or deletion would affect, so that the blast radius of a
+// destructive command can be shown before it runs.
+package impact
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/labels"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	OperationDeactivate = "deactivate"
+	OperationDelete     = "delete"
+)
+
+// kinds of affected objects that have no labels, so are not among the labels kinds
+const (
+	KindResourceMapping      = "resource-mapping"
+	KindResourceMappingGroup = "resource-mapping-group"
+	KindObligationTrigger    = "obligation-trigger"
+	// KindActionAttributeValue is the pairing of an action and an attribute value on a registered resource value
+	KindActionAttributeValue = "registered-resource-action-attribute-value"
+	// KindKeyMapping is a KAS key assigned to a namespace, attribute or value
+	KindKeyMapping = "key-mapping"
+)
+
+var (
+	ErrUnsupported = errors.New("impact analysis is not supported")
+	ErrNotFound    = errors.New("object not found in policy")
+)
+
+// Policy is the policy to analyze. Resource mappings and their groups are not part of a policy snapshot, so are
+// loaded alongside it.
+type Policy struct {
+	*handlers.PolicySnapshot
+	ResourceMappings      []*policy.ResourceMapping
+	ResourceMappingGroups []*policy.ResourceMappingGroup
+}
+
+// Group is the affected objects of one kind. Items are the FQNs of the objects, or a description of the object when
+// it has no FQN.
+type Group struct {
+	Kind  string   `json:"kind"`
+	Count int      `json:"count"`
+	Items []string `json:"items"`
+}
+
+// Report is the impact of an operation on a namespace, attribute or value.
+type Report struct {
+	Operation string  `json:"operation"`
+	Kind      string  `json:"kind"`
+	ID        string  `json:"id"`
+	FQN       string  `json:"fqn"`
+	Affected  []Group `json:"affected"`
+}
+
+// Total is the number of affected objects, not counting the target itself.
+func (r *Report) Total() int {
+	total := 0
+	for _, g := range r.Affected {
+		total += g.Count
+	}
+	return total
+}
+
+type analysis struct {
+	p      Policy
+	report *Report
+	// values maps the ID of each value in the blast radius to its FQN
+	values map[string]string
+	groups map[string]map[string]bool
+}
+
+// Analyze reports what deactivating or deleting a namespace, attribute or value would affect.
+//
+// A deactivation cascades to the active attributes and values beneath the target, and leaves the mappings, triggers
+// and registered resource entitlements of those values without effect. A deletion cascades to every attribute and
+// value beneath the target and removes their mappings, triggers, registered resource entitlements and key mappings.
+// Deleting a namespace also removes the objects defined within it.
+func Analyze(p Policy, operation, kind, id string) (*Report, error) {
+	if operation != OperationDeactivate && operation != OperationDelete {
+		return nil, fmt.Errorf("%w for operation '%s'", ErrUnsupported, operation)
+	}
+	a := &analysis{
+		p:      p,
+		report: &Report{Operation: operation, Kind: kind, ID: id},
+		values: map[string]string{},
+		groups: map[string]map[string]bool{},
+	}
+	deactivate := operation == OperationDeactivate
+
+	switch kind {
+	case labels.KindNamespace:
+		ns := a.namespace(id)
+		if ns == nil {
+			return nil, fmt.Errorf("%w: namespace %s", ErrNotFound, id)
+		}
+		a.report.FQN = ns.GetFqn()
+		if !deactivate {
+			a.addKeyMappings(ns.GetFqn(), ns.GetKasKeys())
+		}
+		for _, attr := range p.Attributes {
+			if attr.GetNamespace().GetId() == id {
+				a.addAttribute(attr, deactivate, true)
+			}
+		}
+		if !deactivate {
+			a.addNamespaced(id, ns.GetFqn())
+		}
+	case labels.KindAttribute:
+		attr := a.attribute(id)
+		if attr == nil {
+			return nil, fmt.Errorf("%w: attribute %s", ErrNotFound, id)
+		}
+		a.report.FQN = attr.GetFqn()
+		a.addAttribute(attr, deactivate, false)
+	case labels.KindValue:
+		attr, v := a.value(id)
+		if v == nil {
+			return nil, fmt.Errorf("%w: attribute value %s", ErrNotFound, id)
+		}
+		a.report.FQN = v.GetFqn()
+		a.addValue(attr, v, deactivate, false)
+	default:
+		return nil, fmt.Errorf("%w for '%s' objects", ErrUnsupported, kind)
+	}
+
+	a.addReferences()
+	a.finish()
+	return a.report, nil
+}
+
+func (a *analysis) namespace(id string) *policy.Namespace {
+	for _, ns := range a.p.Namespaces {
+		if ns.GetId() == id {
+			return ns
+		}
+	}
+	return nil
+}
+
+func (a *analysis) attribute(id string) *policy.Attribute {
+	for _, attr := range a.p.Attributes {
+		if attr.GetId() == id {
+			return attr
+		}
+	}
+	return nil
+}
+
+func (a *analysis) value(id string) (*policy.Attribute, *policy.Value) {
+	for _, attr := range a.p.Attributes {
+		for _, v := range attr.GetValues() {
+			if v.GetId() == id {
+				return attr, v
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (a *analysis) add(kind, item string) {
+	if a.groups[kind] == nil {
+		a.groups[kind] = map[string]bool{}
+	}
+	a.groups[kind][item] = true
+}
+
+// addAttribute adds the attribute, unless it is the target, and its values. A deactivation skips what is already
+// inactive, as it would not change.
+func (a *analysis) addAttribute(attr *policy.Attribute, deactivate, listAttr bool) {
+	if deactivate && listAttr && inactive(attr.GetActive()) {
+		return
+	}
+	if listAttr {
+		a.add(labels.KindAttribute, attr.GetFqn())
+	}
+	if !deactivate {
+		a.addKeyMappings(attr.GetFqn(), attr.GetKasKeys())
+	}
+	for _, v := range attr.GetValues() {
+		a.addValue(attr, v, deactivate, true)
+	}
+}
+
+func (a *analysis) addValue(attr *policy.Attribute, v *policy.Value, deactivate, listValue bool) {
+	if deactivate && listValue && inactive(v.GetActive()) {
+		return
+	}
+	fqn := v.GetFqn()
+	if fqn == "" {
+		fqn = attr.GetFqn() + "/value/" + v.GetValue()
+	}
+	if listValue {
+		a.add(labels.KindValue, fqn)
+	}
+	if !deactivate {
+		a.addKeyMappings(fqn, v.GetKasKeys())
+	}
+	a.values[v.GetId()] = fqn
+}
+
+// inactive treats an unset active state as active, as it is not always returned on nested objects
+func inactive(active *wrapperspb.BoolValue) bool {
+	return active != nil && !active.GetValue()
+}
+
+func (a *analysis) addKeyMappings(fqn string, keys []*policy.SimpleKasKey) {
+	for _, k := range keys {
+		a.add(KindKeyMapping, fmt.Sprintf("%s (%s) on %s", k.GetPublicKey().GetKid(), k.GetKasUri(), fqn))
+	}
+}
+
+// addReferences adds the objects that refer to a value in the blast radius
+func (a *analysis) addReferences() {
+	for _, sm := range a.p.SubjectMappings {
+		if fqn, ok := a.values[sm.GetAttributeValue().GetId()]; ok {
+			a.add(labels.KindSubjectMapping, fmt.Sprintf("%s (%s)", fqn, sm.GetId()))
+		}
+	}
+	for _, rm := range a.p.ResourceMappings {
+		if fqn, ok := a.values[rm.GetAttributeValue().GetId()]; ok {
+			a.add(KindResourceMapping, fmt.Sprintf("%s (%s: %s)", fqn, rm.GetId(), strings.Join(rm.GetTerms(), ", ")))
+		}
+	}
+	for _, t := range a.p.ObligationTriggers {
+		if fqn, ok := a.values[t.GetAttributeValue().GetId()]; ok {
+			a.add(KindObligationTrigger, triggerItem(t, fqn))
+		}
+	}
+	for _, r := range a.p.RegisteredResources {
+		for _, v := range r.GetValues() {
+			for _, aav := range v.GetActionAttributeValues() {
+				if fqn, ok := a.values[aav.GetAttributeValue().GetId()]; ok {
+					a.add(KindActionAttributeValue, fmt.Sprintf("%s/%s: %s on %s", r.GetName(), v.GetValue(), aav.GetAction().GetName(), fqn))
+				}
+			}
+		}
+	}
+}
+
+// addNamespaced adds the objects defined within a deleted namespace
+func (a *analysis) addNamespaced(nsID, nsFQN string) {
+	inNamespace := func(ns *policy.Namespace) bool {
+		return ns.GetId() == nsID || (ns.GetId() == "" && ns.GetFqn() != "" && ns.GetFqn() == nsFQN)
+	}
+	for _, o := range a.p.Obligations {
+		if !inNamespace(o.GetNamespace()) {
+			continue
+		}
+		a.add(labels.KindObligation, o.GetFqn())
+		for _, v := range o.GetValues() {
+			a.add(labels.KindObligationValue, v.GetFqn())
+			for _, t := range v.GetTriggers() {
+				a.add(KindObligationTrigger, triggerItem(t, t.GetAttributeValue().GetFqn()))
+			}
+		}
+	}
+	for _, t := range a.p.ObligationTriggers {
+		if inNamespace(t.GetObligationValue().GetObligation().GetNamespace()) {
+			a.add(KindObligationTrigger, triggerItem(t, t.GetAttributeValue().GetFqn()))
+		}
+	}
+	for _, scs := range a.p.SubjectConditionSets {
+		if inNamespace(scs.GetNamespace()) {
+			a.add(labels.KindSubjectConditionSet, scs.GetId())
+		}
+	}
+	for _, act := range a.p.Actions {
+		if inNamespace(act.GetNamespace()) {
+			a.add(labels.KindAction, act.GetName())
+		}
+	}
+	for _, r := range a.p.RegisteredResources {
+		if !inNamespace(r.GetNamespace()) {
+			continue
+		}
+		a.add(labels.KindRegisteredResource, r.GetName())
+		for _, v := range r.GetValues() {
+			a.add(labels.KindRegisteredResourceValue, r.GetName()+"/"+v.GetValue())
+		}
+	}
+	for _, g := range a.p.ResourceMappingGroups {
+		if g.GetNamespaceId() == nsID {
+			a.add(KindResourceMappingGroup, g.GetName())
+		}
+	}
+}
+
+func triggerItem(t *policy.ObligationTrigger, valueFQN string) string {
+	return fmt.Sprintf("%s on %s requires %s", t.GetAction().GetName(), valueFQN, t.GetObligationValue().GetFqn())
+}
+
+// order lists the kinds from the target outwards
+var order = []string{
+	labels.KindAttribute,
+	labels.KindValue,
+	labels.KindSubjectMapping,
+	KindResourceMapping,
+	KindObligationTrigger,
+	KindActionAttributeValue,
+	KindKeyMapping,
+	labels.KindObligation,
+	labels.KindObligationValue,
+	labels.KindSubjectConditionSet,
+	labels.KindAction,
+	labels.KindRegisteredResource,
+	labels.KindRegisteredResourceValue,
+	KindResourceMappingGroup,
+}
+
+func (a *analysis) finish() {
+	a.report.Affected = []Group{}
+	for _, kind := range order {
+		items := make([]string, 0, len(a.groups[kind]))
+		for item := range a.groups[kind] {
+			items = append(items, item)
+		}
+		if len(items) == 0 {
+			continue
+		}
+		sort.Strings(items)
+		a.report.Affected = append(a.report.Affected, Group{Kind: kind, Count: len(items), Items: items})
+	}
+}
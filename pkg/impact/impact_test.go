@@ -0,0 +1,127 @@
+package impact
+
+import (
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/labels"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func testPolicy() Policy {
+	ns := &policy.Namespace{Id: "ns1", Fqn: "https://one.com"}
+	other := &policy.Namespace{Id: "ns2", Fqn: "https://two.com"}
+	high := &policy.Value{Id: "v1", Fqn: "https://one.com/attr/level/value/high", Active: wrapperspb.Bool(true)}
+	low := &policy.Value{Id: "v2", Fqn: "https://one.com/attr/level/value/low", Active: wrapperspb.Bool(false)}
+	otherVal := &policy.Value{Id: "v3", Fqn: "https://two.com/attr/x/value/y"}
+	read := &policy.Action{Id: "read", Name: "read"}
+	key := &policy.SimpleKasKey{KasUri: "https://kas.one.com", PublicKey: &policy.SimpleKasPublicKey{Kid: "k1"}}
+	oblVal := &policy.ObligationValue{
+		Id:         "ov1",
+		Fqn:        "https://one.com/obl/watermark/value/visible",
+		Obligation: &policy.Obligation{Id: "o1", Namespace: ns},
+	}
+
+	return Policy{
+		PolicySnapshot: &handlers.PolicySnapshot{
+			Namespaces: []*policy.Namespace{ns, other},
+			Attributes: []*policy.Attribute{
+				{Id: "a1", Fqn: "https://one.com/attr/level", Namespace: ns, Values: []*policy.Value{high, low}, KasKeys: []*policy.SimpleKasKey{key}},
+				{Id: "a2", Fqn: "https://two.com/attr/x", Namespace: other, Values: []*policy.Value{otherVal}},
+			},
+			Actions: []*policy.Action{read, {Id: "custom", Name: "export", Namespace: ns}},
+			SubjectMappings: []*policy.SubjectMapping{
+				{Id: "sm1", AttributeValue: high},
+				{Id: "sm2", AttributeValue: low},
+				{Id: "sm3", AttributeValue: otherVal},
+			},
+			Obligations: []*policy.Obligation{
+				{Id: "o1", Fqn: "https://one.com/obl/watermark", Namespace: ns, Values: []*policy.ObligationValue{oblVal}},
+			},
+			ObligationTriggers: []*policy.ObligationTrigger{
+				{Id: "t1", AttributeValue: otherVal, Action: read, ObligationValue: oblVal},
+			},
+			RegisteredResources: []*policy.RegisteredResource{
+				{Id: "rr1", Name: "app", Namespace: other, Values: []*policy.RegisteredResourceValue{{
+					Id:    "rrv1",
+					Value: "prod",
+					ActionAttributeValues: []*policy.RegisteredResourceValue_ActionAttributeValue{
+						{Action: read, AttributeValue: high},
+					},
+				}}},
+			},
+		},
+		ResourceMappings: []*policy.ResourceMapping{
+			{Id: "rm1", AttributeValue: high, Terms: []string{"HIGH"}},
+		},
+		ResourceMappingGroups: []*policy.ResourceMappingGroup{
+			{Id: "g1", NamespaceId: "ns1", Name: "levels"},
+			{Id: "g2", NamespaceId: "ns2", Name: "others"},
+		},
+	}
+}
+
+func counts(r *Report) map[string]int {
+	m := map[string]int{}
+	for _, g := range r.Affected {
+		m[g.Kind] = g.Count
+	}
+	return m
+}
+
+func TestAnalyze_DeleteNamespace(t *testing.T) {
+	r, err := Analyze(testPolicy(), OperationDelete, labels.KindNamespace, "ns1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://one.com", r.FQN)
+	assert.Equal(t, map[string]int{
+		labels.KindAttribute:       1,
+		labels.KindValue:           2,
+		labels.KindSubjectMapping:  2,
+		KindResourceMapping:        1,
+		KindObligationTrigger:      1,
+		KindActionAttributeValue:   1,
+		KindKeyMapping:             1,
+		labels.KindObligation:      1,
+		labels.KindObligationValue: 1,
+		labels.KindAction:          1,
+		KindResourceMappingGroup:   1,
+	}, counts(r))
+	assert.Equal(t, 13, r.Total())
+}
+
+func TestAnalyze_DeactivateAttribute(t *testing.T) {
+	r, err := Analyze(testPolicy(), OperationDeactivate, labels.KindAttribute, "a1")
+	require.NoError(t, err)
+	// the inactive value and its mapping are unaffected, and nothing is deleted
+	assert.Equal(t, map[string]int{
+		labels.KindValue:          1,
+		labels.KindSubjectMapping: 1,
+		KindResourceMapping:       1,
+		KindActionAttributeValue:  1,
+	}, counts(r))
+	assert.Equal(t, []string{"https://one.com/attr/level/value/high"}, r.Affected[0].Items)
+}
+
+func TestAnalyze_DeleteValue(t *testing.T) {
+	r, err := Analyze(testPolicy(), OperationDelete, labels.KindValue, "v3")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		labels.KindSubjectMapping: 1,
+		KindObligationTrigger:     1,
+	}, counts(r))
+	assert.Equal(t, []string{"read on https://two.com/attr/x/value/y requires https://one.com/obl/watermark/value/visible"}, r.Affected[1].Items)
+}
+
+func TestAnalyze_Errors(t *testing.T) {
+	_, err := Analyze(testPolicy(), OperationDelete, labels.KindAttribute, "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	_, err = Analyze(testPolicy(), OperationDelete, labels.KindAction, "read")
+	require.ErrorIs(t, err, ErrUnsupported)
+
+	_, err = Analyze(testPolicy(), "update", labels.KindNamespace, "ns1")
+	require.ErrorIs(t, err, ErrUnsupported)
+}
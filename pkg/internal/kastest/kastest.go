@@ -0,0 +1,65 @@
+// Package kastest stands in for a KAS in tests, serving public keys through the Connect JSON PublicKey RPC.
+package kastest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Key is a public key the KAS serves.
+type Key struct {
+	KID string
+	PEM string
+}
+
+// PublicKeyPEM returns a new EC P-256 public key as PEM.
+func PublicKeyPEM(t testing.TB) string {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&k.PublicKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// PublicKeyHandler answers the PublicKey RPC with the key of the requested algorithm, where the key for the empty
+// algorithm is the default, and with 404 when it serves no key of the algorithm.
+func PublicKeyHandler(t testing.TB, keys map[string]Key) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, `{"code":"unimplemented"}`, http.StatusMethodNotAllowed)
+			return
+		}
+		var req struct {
+			Algorithm string `json:"algorithm"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		k, ok := keys[req.Algorithm]
+		if !ok {
+			http.Error(w, `{"code":"not_found"}`, http.StatusNotFound)
+			return
+		}
+		//nolint:errcheck // test response
+		json.NewEncoder(w).Encode(map[string]any{"publicKey": k.PEM, "kid": k.KID})
+	}
+}
+
+// NewServer starts a KAS serving the keys at rpcPath, closed when the test ends.
+func NewServer(t testing.TB, rpcPath string, keys map[string]Key) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.Handle(rpcPath, PublicKeyHandler(t, keys))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
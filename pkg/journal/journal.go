@@ -0,0 +1,260 @@
+// Package journal keeps a local, per-profile record of the policy changes made through otdfctl, with the state of each
+// object before and after the change, so changes can be reviewed and undone.
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	OperationCreate     = "create"
+	OperationUpdate     = "update"
+	OperationDeactivate = "deactivate"
+	OperationReactivate = "reactivate"
+	OperationDelete     = "delete"
+	// OperationUnsafeUpdate changes the name, value, rule or order of an object rather than only its metadata
+	OperationUnsafeUpdate = "unsafe-update"
+	// OperationAssign and OperationUnassign map a key to, or remove it from, a namespace, attribute or value
+	OperationAssign   = "assign"
+	OperationUnassign = "unassign"
+	OperationRotate   = "rotate"
+)
+
+const (
+	dirName = "journal"
+
+	dirMode  = 0o700
+	fileMode = 0o600
+)
+
+var (
+	ErrEntryNotFound = errors.New("journal entry not found")
+
+	unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+	// secretFields are cleared wherever they appear in a journaled object, so key material and provider credentials
+	// are never written to the journal
+	secretFields = map[protoreflect.Name]bool{"wrapped_key": true, "config_json": true}
+
+	// appendMu numbers and writes entries one at a time, as bulk commands journal their changes concurrently
+	appendMu sync.Mutex
+)
+
+// Entry is one recorded change. Before and After are the protojson of the object, absent when it did not exist.
+type Entry struct {
+	ID        int             `json:"id"`
+	Time      time.Time       `json:"time"`
+	Endpoint  string          `json:"endpoint"`
+	Operation string          `json:"operation"`
+	Kind      string          `json:"kind"`
+	ObjectID  string          `json:"object_id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Before    json.RawMessage `json:"before,omitempty"`
+	After     json.RawMessage `json:"after,omitempty"`
+	// UndoOf is the entry an undo entry reverted
+	UndoOf int `json:"undo_of,omitempty"`
+}
+
+// Journal is an append-only file of entries, one JSON object per line.
+type Journal struct {
+	path string
+}
+
+// New opens the journal at a path, which is created on the first append.
+func New(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// ForProfile opens the journal of a profile within the config directory.
+func ForProfile(configDir, profile string) *Journal {
+	if profile == "" {
+		profile = "default"
+	}
+	name := unsafeFileChars.ReplaceAllString(profile, "_") + ".jsonl"
+	return New(filepath.Join(configDir, dirName, name))
+}
+
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// NewEntry describes a change to an object from its state before and after, either of which may be nil. Wrapped keys
+// and provider configurations are left out of both.
+func NewEntry(endpoint, operation, kind string, before, after proto.Message) (*Entry, error) {
+	e := &Entry{Time: time.Now().UTC(), Endpoint: endpoint, Operation: operation, Kind: kind}
+	var err error
+	if e.Before, err = marshal(before); err != nil {
+		return nil, err
+	}
+	if e.After, err = marshal(after); err != nil {
+		return nil, err
+	}
+	for _, m := range []proto.Message{after, before} {
+		if !isSet(m) {
+			continue
+		}
+		if e.ObjectID == "" {
+			e.ObjectID = firstStringField(m, "id", "namespace_id", "attribute_id", "value_id", "kas_id")
+		}
+		if e.Name == "" {
+			e.Name = firstStringField(m, "fqn", "name", "uri", "value", "key_id", "kas_uri")
+		}
+	}
+	return e, nil
+}
+
+// Append numbers the entry after the last one and writes it to the journal.
+func (j *Journal) Append(e *Entry) error {
+	appendMu.Lock()
+	defer appendMu.Unlock()
+
+	entries, err := j.Entries()
+	if err != nil {
+		return err
+	}
+	e.ID = 1
+	if len(entries) > 0 {
+		e.ID = entries[len(entries)-1].ID + 1
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.path), dirMode); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileMode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(b, '\n'))
+	return err
+}
+
+// Entries reads every entry, oldest first. A missing journal has no entries.
+func (j *Journal) Entries() ([]Entry, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<24) //nolint:mnd // entries hold whole objects, so allow long lines
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("invalid journal entry on line %d of %s: %w", line, j.path, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Entry finds an entry by ID.
+func (j *Journal) Entry(id int) (*Entry, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].ID == id {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", ErrEntryNotFound, id)
+}
+
+// UndoneBy maps the ID of each undone entry to the ID of the entry that undid it.
+func UndoneBy(entries []Entry) map[int]int {
+	undone := map[int]int{}
+	for _, e := range entries {
+		if e.UndoOf != 0 {
+			undone[e.UndoOf] = e.ID
+		}
+	}
+	return undone
+}
+
+// Unmarshal reads the before or after state of an entry into m.
+func Unmarshal(raw json.RawMessage, m proto.Message) error {
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(raw, m)
+}
+
+func marshal(m proto.Message) (json.RawMessage, error) {
+	if !isSet(m) {
+		return nil, nil
+	}
+	m = proto.Clone(m)
+	redact(m.ProtoReflect(), secretFields)
+	return protojson.Marshal(m)
+}
+
+// redact clears the named fields of a message and of every message within it
+func redact(r protoreflect.Message, fields map[protoreflect.Name]bool) {
+	r.Range(func(f protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fields[f.Name()]:
+			r.Clear(f)
+		case f.Message() == nil:
+		case f.IsList():
+			for i := 0; i < v.List().Len(); i++ {
+				redact(v.List().Get(i).Message(), fields)
+			}
+		case f.IsMap():
+			if f.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+					redact(mv.Message(), fields)
+					return true
+				})
+			}
+		default:
+			redact(v.Message(), fields)
+		}
+		return true
+	})
+}
+
+// isSet reports whether a message is present, as a typed nil pointer is still a non-nil interface
+func isSet(m proto.Message) bool {
+	return m != nil && m.ProtoReflect().IsValid()
+}
+
+func firstStringField(m proto.Message, names ...string) string {
+	for _, name := range names {
+		if v := stringField(m, name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func stringField(m proto.Message, name string) string {
+	r := m.ProtoReflect()
+	f := r.Descriptor().Fields().ByName(protoreflect.Name(name))
+	if f == nil || f.Kind() != protoreflect.StringKind || f.IsList() {
+		return ""
+	}
+	return r.Get(f).String()
+}
@@ -0,0 +1,119 @@
+package journal
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/apipb"
+)
+
+func TestForProfile(t *testing.T) {
+	j := ForProfile("/config", "my profile/../x")
+	assert.Equal(t, filepath.Join("/config", "journal", "my_profile_.._x.jsonl"), j.Path())
+
+	assert.Equal(t, filepath.Join("/config", "journal", "default.jsonl"), ForProfile("/config", "").Path())
+}
+
+func TestNewEntry(t *testing.T) {
+	before := &apipb.Method{Name: "old", RequestTypeUrl: "a"}
+	after := &apipb.Method{Name: "new", RequestTypeUrl: "a"}
+
+	e, err := NewEntry("http://localhost:8080", OperationUpdate, "method", before, after)
+	require.NoError(t, err)
+	assert.Equal(t, "new", e.Name, "the name is taken from the object after the change")
+	assert.JSONEq(t, `{"name":"old","requestTypeUrl":"a"}`, string(e.Before))
+	assert.JSONEq(t, `{"name":"new","requestTypeUrl":"a"}`, string(e.After))
+
+	var nilMethod *apipb.Method
+	e, err = NewEntry("http://localhost:8080", OperationDelete, "method", before, nilMethod)
+	require.NoError(t, err)
+	assert.Equal(t, "old", e.Name)
+	assert.Nil(t, e.After, "a typed nil object is absent")
+
+	restored := &apipb.Method{}
+	require.NoError(t, Unmarshal(e.Before, restored))
+	assert.True(t, proto.Equal(before, restored))
+}
+
+func TestRedact(t *testing.T) {
+	api := &apipb.Api{
+		Name:    "api",
+		Methods: []*apipb.Method{{Name: "get", RequestTypeUrl: "secret"}},
+		Mixins:  []*apipb.Mixin{{Name: "mixin", Root: "secret"}},
+	}
+	m := proto.Clone(api)
+	redact(m.ProtoReflect(), map[protoreflect.Name]bool{"request_type_url": true, "root": true})
+
+	assert.True(t, proto.Equal(&apipb.Api{
+		Name:    "api",
+		Methods: []*apipb.Method{{Name: "get"}},
+		Mixins:  []*apipb.Mixin{{Name: "mixin"}},
+	}, m), "the fields are cleared within nested and repeated messages")
+	assert.Equal(t, "secret", api.GetMethods()[0].GetRequestTypeUrl(), "redacting a copy leaves the original unchanged")
+}
+
+func TestAppendAndRead(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "journal", "p.jsonl"))
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, entries, "a missing journal has no entries")
+
+	for _, name := range []string{"a", "b"} {
+		e, err := NewEntry("http://localhost:8080", OperationCreate, "method", nil, &apipb.Method{Name: name})
+		require.NoError(t, err)
+		require.NoError(t, j.Append(e))
+	}
+	undo, err := NewEntry("http://localhost:8080", OperationDelete, "method", &apipb.Method{Name: "a"}, nil)
+	require.NoError(t, err)
+	undo.UndoOf = 1
+	require.NoError(t, j.Append(undo))
+	assert.Equal(t, 3, undo.ID)
+
+	entries, err = j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{entries[0].ID, entries[1].ID, entries[2].ID})
+	assert.Equal(t, map[int]int{1: 3}, UndoneBy(entries))
+
+	e, err := j.Entry(2)
+	require.NoError(t, err)
+	assert.Equal(t, "b", e.Name)
+
+	_, err = j.Entry(9)
+	require.ErrorIs(t, err, ErrEntryNotFound)
+
+	info, err := os.Stat(j.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(fileMode), info.Mode().Perm())
+}
+
+func TestAppendConcurrently(t *testing.T) {
+	j := New(filepath.Join(t.TempDir(), "p.jsonl"))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e, err := NewEntry("http://localhost:8080", OperationUpdate, "method", nil, &apipb.Method{Name: "m"})
+			assert.NoError(t, err)
+			assert.NoError(t, j.Append(e))
+		}()
+	}
+	wg.Wait()
+
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	ids := map[int]bool{}
+	for _, e := range entries {
+		ids[e.ID] = true
+	}
+	assert.Len(t, ids, 10, "every entry is numbered once")
+}
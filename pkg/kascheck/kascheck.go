@@ -0,0 +1,238 @@
+// Package kascheck compares the keys registered for a KAS with the keys the KAS actually serves from its public key
+// endpoint.
+package kascheck
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/doctor"
+)
+
+const (
+	// StatusOK is a served key that is registered with the same public key
+	StatusOK = "ok"
+	// StatusUnverified is an active registered key the KAS does not serve as its current key for the algorithm, so
+	// it can't be compared, although the KAS may still use it to decrypt
+	StatusUnverified = "unverified"
+	// StatusMismatch is a served key whose public key differs from the registered one, or which is not active
+	StatusMismatch = "mismatch"
+	// StatusExtra is a served key which is not registered
+	StatusExtra = "extra"
+	// StatusMissing is an active registered key whose algorithm the KAS does not serve
+	StatusMissing = "missing"
+	// StatusUnreachable is a KAS whose public key endpoint could not be called
+	StatusUnreachable = "unreachable"
+	// StatusTLS is a KAS whose certificate could not be verified or which does not speak TLS
+	StatusTLS = "tls"
+
+	keyStatusActive = "active"
+)
+
+// Key is a key registered for a KAS. The PEM may be base64 encoded, as stored by the registry.
+type Key struct {
+	KeyID     string
+	Algorithm string
+	PEM       string
+	Status    string
+}
+
+type Finding struct {
+	Status    string `json:"status"`
+	Algorithm string `json:"algorithm,omitempty"`
+	KeyID     string `json:"key_id,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Failed reports whether the finding needs attention.
+func (f Finding) Failed() bool {
+	return f.Status != StatusOK && f.Status != StatusUnverified
+}
+
+type Result struct {
+	KasID    string    `json:"kas_id"`
+	KasURI   string    `json:"kas_uri"`
+	KasName  string    `json:"kas_name,omitempty"`
+	Findings []Finding `json:"findings"`
+}
+
+// Failed reports whether any finding needs attention.
+func (r Result) Failed() bool {
+	for _, f := range r.Findings {
+		if f.Failed() {
+			return true
+		}
+	}
+	return false
+}
+
+// Check asks the KAS for its current key of every algorithm it has active keys registered for, and for its default
+// key, and compares what it serves with the registered keys.
+func Check(ctx context.Context, client *http.Client, kasURI string, keys []Key) []Finding {
+	byID := make(map[string]Key, len(keys))
+	active := map[string][]Key{}
+	for _, k := range keys {
+		byID[k.KeyID] = k
+		if k.Status == keyStatusActive {
+			active[k.Algorithm] = append(active[k.Algorithm], k)
+		}
+	}
+	algorithms := make([]string, 0, len(active))
+	for alg := range active {
+		algorithms = append(algorithms, alg)
+	}
+	sort.Strings(algorithms)
+	// the default key catches a served key whose algorithm has nothing registered
+	algorithms = append(algorithms, "")
+
+	var findings []Finding
+	served := map[string]bool{}
+	for _, alg := range algorithms {
+		pub, kid, err := doctor.FetchKASPublicKey(ctx, client, kasURI, alg)
+		if err != nil {
+			var statusErr *doctor.StatusError
+			if errors.As(err, &statusErr) && statusErr.Code == http.StatusNotFound {
+				// a KAS without a default key answers the request without an algorithm the same way
+				for _, k := range active[alg] {
+					findings = append(findings, Finding{
+						Status: StatusMissing, Algorithm: alg, KeyID: k.KeyID,
+						Message: "registered as active, but the KAS serves no " + alg + " key",
+					})
+				}
+				continue
+			}
+			// nothing more can be learned from a KAS that can't be called
+			return append(findings, unreachable(kasURI, err))
+		}
+
+		if kid == "" {
+			kid = matchByPEM(pub, keys)
+		}
+		if served[kid] {
+			continue
+		}
+		served[kid] = true
+		findings = append(findings, compare(alg, kid, pub, byID))
+	}
+
+	for _, alg := range algorithms {
+		for _, k := range active[alg] {
+			if served[k.KeyID] || hasFinding(findings, k.KeyID) {
+				continue
+			}
+			findings = append(findings, Finding{
+				Status: StatusUnverified, Algorithm: alg, KeyID: k.KeyID,
+				Message: "registered as active, but not served as the current " + alg + " key",
+			})
+		}
+	}
+	return findings
+}
+
+func compare(alg, kid, pub string, byID map[string]Key) Finding {
+	if kid == "" {
+		return Finding{Status: StatusExtra, Algorithm: alg, Message: "the KAS serves a key without a key ID that is not registered"}
+	}
+	k, ok := byID[kid]
+	if !ok {
+		return Finding{Status: StatusExtra, Algorithm: alg, KeyID: kid, Message: "the KAS serves a key that is not registered"}
+	}
+	if alg == "" {
+		alg = k.Algorithm
+	}
+	same, err := SamePublicKey(pub, k.PEM)
+	switch {
+	case err != nil:
+		return Finding{Status: StatusMismatch, Algorithm: alg, KeyID: kid, Message: "could not compare public keys: " + err.Error()}
+	case !same:
+		return Finding{Status: StatusMismatch, Algorithm: alg, KeyID: kid, Message: "the served public key differs from the registered one"}
+	case k.Status != keyStatusActive:
+		return Finding{Status: StatusMismatch, Algorithm: alg, KeyID: kid, Message: fmt.Sprintf("served as the current key, but registered as %s", k.Status)}
+	}
+	return Finding{Status: StatusOK, Algorithm: alg, KeyID: kid, Message: "served and registered public keys match"}
+}
+
+// matchByPEM finds the registered key of a KAS which does not send key IDs.
+func matchByPEM(pub string, keys []Key) string {
+	for _, k := range keys {
+		if same, err := SamePublicKey(pub, k.PEM); err == nil && same {
+			return k.KeyID
+		}
+	}
+	return ""
+}
+
+func hasFinding(findings []Finding, kid string) bool {
+	for _, f := range findings {
+		if f.KeyID == kid {
+			return true
+		}
+	}
+	return false
+}
+
+func unreachable(kasURI string, err error) Finding {
+	var (
+		certErr      *tls.CertificateVerificationError
+		unknownAuth  x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+		recordHdrErr tls.RecordHeaderError
+	)
+	switch {
+	case errors.As(err, &certErr), errors.As(err, &unknownAuth), errors.As(err, &hostnameErr), errors.As(err, &invalidErr):
+		return Finding{Status: StatusTLS, Message: "the certificate of " + kasURI + " could not be verified: " + err.Error()}
+	// net/http replaces the record header error with its own when the server answers in plain HTTP
+	case errors.As(err, &recordHdrErr), strings.Contains(err.Error(), "server gave HTTP response to HTTPS client"):
+		return Finding{Status: StatusTLS, Message: kasURI + " did not answer with TLS: " + err.Error()}
+	}
+	return Finding{Status: StatusUnreachable, Message: "could not fetch the public key of " + kasURI + ": " + err.Error()}
+}
+
+// SamePublicKey compares two PEM encoded public keys or certificates by their public key, ignoring encoding
+// differences such as line breaks or a certificate wrapping the key.
+func SamePublicKey(a, b string) (bool, error) {
+	da, err := publicKeyDER(a)
+	if err != nil {
+		return false, err
+	}
+	db, err := publicKeyDER(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(da, db), nil
+}
+
+func publicKeyDER(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	// the registry stores the PEM base64 encoded
+	if !strings.HasPrefix(s, "-----BEGIN") {
+		if decoded, err := base64.StdEncoding.DecodeString(s); err == nil {
+			s = string(decoded)
+		}
+	}
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("not a PEM encoded key")
+	}
+	if block.Type == "CERTIFICATE" {
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return x509.MarshalPKIXPublicKey(cert.PublicKey)
+	}
+	if _, err := x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+		return nil, err
+	}
+	return block.Bytes, nil
+}
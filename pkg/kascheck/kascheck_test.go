@@ -0,0 +1,147 @@
+package kascheck
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/doctor"
+	"github.com/opentdf/otdfctl/pkg/internal/kastest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newKAS stands in for a KAS, serving a key per algorithm. The key for the empty algorithm is the default.
+func newKAS(t *testing.T, keys map[string]kastest.Key) *httptest.Server {
+	t.Helper()
+	return kastest.NewServer(t, doctor.KASPublicKeyRPCPath, keys)
+}
+
+func byStatus(findings []Finding) map[string][]string {
+	m := map[string][]string{}
+	for _, f := range findings {
+		m[f.Status] = append(m[f.Status], f.KeyID)
+	}
+	return m
+}
+
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+func TestCheck(t *testing.T) {
+	ecPEM, rsaPEM, otherPEM := kastest.PublicKeyPEM(t), kastest.PublicKeyPEM(t), kastest.PublicKeyPEM(t)
+	srv := newKAS(t, map[string]kastest.Key{
+		"ec:secp256r1": {KID: "e1", PEM: ecPEM},
+		"rsa:2048":     {KID: "r1", PEM: otherPEM},
+		"":             {KID: "r1", PEM: otherPEM},
+	})
+
+	findings := Check(context.Background(), srv.Client(), srv.URL, []Key{
+		{KeyID: "e1", Algorithm: "ec:secp256r1", PEM: b64(ecPEM), Status: "active"},
+		{KeyID: "e0", Algorithm: "ec:secp256r1", PEM: b64(kastest.PublicKeyPEM(t)), Status: "active"},
+		{KeyID: "r1", Algorithm: "rsa:2048", PEM: b64(rsaPEM), Status: "active"},
+		{KeyID: "p1", Algorithm: "ec:secp384r1", PEM: b64(kastest.PublicKeyPEM(t)), Status: "active"},
+		{KeyID: "old", Algorithm: "ec:secp384r1", PEM: b64(kastest.PublicKeyPEM(t)), Status: "rotated"},
+	})
+
+	assert.Equal(t, map[string][]string{
+		StatusOK:         {"e1"},
+		StatusMissing:    {"p1"},
+		StatusMismatch:   {"r1"},
+		StatusUnverified: {"e0"},
+	}, byStatus(findings))
+	assert.True(t, Result{Findings: findings}.Failed())
+}
+
+func TestCheck_Extra(t *testing.T) {
+	pub := kastest.PublicKeyPEM(t)
+	srv := newKAS(t, map[string]kastest.Key{
+		"ec:secp256r1": {KID: "e1", PEM: pub},
+		"":             {KID: "unregistered", PEM: kastest.PublicKeyPEM(t)},
+	})
+
+	findings := Check(context.Background(), srv.Client(), srv.URL, []Key{
+		{KeyID: "e1", Algorithm: "ec:secp256r1", PEM: pub, Status: "active"},
+	})
+	assert.Equal(t, map[string][]string{StatusOK: {"e1"}, StatusExtra: {"unregistered"}}, byStatus(findings))
+}
+
+func TestCheck_ServedRotatedKey(t *testing.T) {
+	pub := kastest.PublicKeyPEM(t)
+	srv := newKAS(t, map[string]kastest.Key{"": {KID: "old", PEM: pub}})
+
+	findings := Check(context.Background(), srv.Client(), srv.URL, []Key{
+		{KeyID: "old", Algorithm: "ec:secp256r1", PEM: b64(pub), Status: "rotated"},
+	})
+	require.Len(t, findings, 1)
+	assert.Equal(t, StatusMismatch, findings[0].Status)
+	assert.Contains(t, findings[0].Message, "rotated")
+}
+
+func TestCheck_NoKeyIDs(t *testing.T) {
+	pub := kastest.PublicKeyPEM(t)
+	srv := newKAS(t, map[string]kastest.Key{"ec:secp256r1": {PEM: pub}, "": {PEM: pub}})
+
+	findings := Check(context.Background(), srv.Client(), srv.URL, []Key{
+		{KeyID: "e1", Algorithm: "ec:secp256r1", PEM: b64(pub), Status: "active"},
+	})
+	assert.Equal(t, map[string][]string{StatusOK: {"e1"}}, byStatus(findings), "keys are matched by public key")
+}
+
+func TestCheck_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	findings := Check(context.Background(), http.DefaultClient, url, []Key{
+		{KeyID: "e1", Algorithm: "ec:secp256r1", Status: "active"},
+	})
+	require.Len(t, findings, 1)
+	assert.Equal(t, StatusUnreachable, findings[0].Status)
+}
+
+func TestCheck_ServerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(srv.Close)
+
+	findings := Check(context.Background(), srv.Client(), srv.URL, nil)
+	require.Len(t, findings, 1)
+	assert.Equal(t, StatusUnreachable, findings[0].Status)
+	assert.Contains(t, findings[0].Message, "500")
+}
+
+func TestCheck_TLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+
+	// the default client does not trust the certificate of the test server
+	findings := Check(context.Background(), &http.Client{}, srv.URL, nil)
+	require.Len(t, findings, 1)
+	assert.Equal(t, StatusTLS, findings[0].Status)
+
+	plain := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(plain.Close)
+	findings = Check(context.Background(), &http.Client{}, "https://"+plain.Listener.Addr().String(), nil)
+	require.Len(t, findings, 1)
+	assert.Equal(t, StatusTLS, findings[0].Status)
+}
+
+func TestSamePublicKey(t *testing.T) {
+	pub := kastest.PublicKeyPEM(t)
+
+	same, err := SamePublicKey(pub, b64(pub))
+	require.NoError(t, err)
+	assert.True(t, same, "base64 encoded and plain PEM")
+
+	same, err = SamePublicKey(pub, kastest.PublicKeyPEM(t))
+	require.NoError(t, err)
+	assert.False(t, same)
+
+	_, err = SamePublicKey(pub, "not a key")
+	require.Error(t, err)
+}
@@ -0,0 +1,422 @@
+// Package keymanager is a local emulator of a key provider, for creating key material of the provider root key and
+// remote key modes without a cloud KMS. It keeps an AES-256 root key and the key pairs it creates in one file: the root
+// key wraps and unwraps key material for the provider root key mode, and the key pairs, stored wrapped by the root key,
+// decrypt for the remote key mode while only their public keys leave the emulator.
+//
+// It serves its own HTTP protocol, not the API of any real key provider, and is meant for development and tests only:
+// the root key is stored in the clear, protected by file permissions.
+package keymanager
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // the KAS wraps keys with RSA-OAEP over SHA-1
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	AlgorithmRSA2048 = "rsa:2048"
+	AlgorithmRSA4096 = "rsa:4096"
+	AlgorithmECP256  = "ec:secp256r1"
+	AlgorithmECP384  = "ec:secp384r1"
+	AlgorithmECP521  = "ec:secp521r1"
+
+	rootKeySize = 32
+	nonceSize   = 12
+	fileMode    = 0o600
+	dirMode     = 0o700
+)
+
+var (
+	ErrKeyNotFound         = errors.New("key not found")
+	ErrKeyExists           = errors.New("key already exists")
+	ErrUnsupportedAlg      = errors.New("unsupported algorithm")
+	ErrInvalidCiphertext   = errors.New("invalid ciphertext")
+	ErrInvalidEphemeralKey = errors.New("invalid ephemeral public key")
+)
+
+var rsaBits = map[string]int{AlgorithmRSA2048: 2048, AlgorithmRSA4096: 4096}
+
+var curves = map[string]elliptic.Curve{
+	AlgorithmECP256: elliptic.P256(),
+	AlgorithmECP384: elliptic.P384(),
+	AlgorithmECP521: elliptic.P521(),
+}
+
+// Algorithms lists the algorithms of the key pairs the emulator creates.
+func Algorithms() []string {
+	return []string{AlgorithmRSA2048, AlgorithmRSA4096, AlgorithmECP256, AlgorithmECP384, AlgorithmECP521}
+}
+
+// Key is a key pair created by the emulator. The private key is stored wrapped by the root key.
+type Key struct {
+	KeyID             string    `json:"key_id"`
+	Algorithm         string    `json:"algorithm"`
+	PublicKeyPEM      string    `json:"public_key_pem"`
+	WrappedPrivateKey string    `json:"wrapped_private_key"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type state struct {
+	RootKeyID string          `json:"root_key_id"`
+	RootKey   string          `json:"root_key"`
+	Keys      map[string]*Key `json:"keys"`
+}
+
+// KeyManager is the emulated key provider backed by a file. The file is read again on every operation, so key pairs
+// created by another process, such as the CLI while the emulator serves, are seen.
+type KeyManager struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Open opens the key manager file, creating it with a new random root key when it does not exist.
+func Open(path string) (*KeyManager, error) {
+	km := &KeyManager{path: path}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		root := make([]byte, rootKeySize)
+		if _, err := rand.Read(root); err != nil {
+			return nil, err
+		}
+		id := make([]byte, 4) //nolint:mnd // a short random suffix
+		if _, err := rand.Read(id); err != nil {
+			return nil, err
+		}
+		s := &state{RootKeyID: "root-" + hex.EncodeToString(id), RootKey: hex.EncodeToString(root), Keys: map[string]*Key{}}
+		if err := km.save(s); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := km.load(); err != nil {
+		return nil, err
+	}
+	return km, nil
+}
+
+func (km *KeyManager) Path() string {
+	return km.path
+}
+
+func (km *KeyManager) load() (*state, error) {
+	b, err := os.ReadFile(km.path)
+	if err != nil {
+		return nil, err
+	}
+	var s state
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("invalid key manager file %s: %w", km.path, err)
+	}
+	if root, err := hex.DecodeString(s.RootKey); err != nil || len(root) != rootKeySize {
+		return nil, fmt.Errorf("invalid key manager file %s: the root key must be %d hex encoded bytes", km.path, rootKeySize)
+	}
+	if s.Keys == nil {
+		s.Keys = map[string]*Key{}
+	}
+	return &s, nil
+}
+
+// save writes the state through a temporary file, so an interruption never leaves a partly written file.
+func (km *KeyManager) save(s *state) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(km.path)
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".key-manager-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(fileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), km.path)
+}
+
+func (s *state) aead() (cipher.AEAD, error) {
+	root, err := hex.DecodeString(s.RootKey)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(root)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// wrap encrypts with AES-256-GCM under the root key, as the nonce followed by the ciphertext and tag
+func (s *state) wrap(plaintext []byte) ([]byte, error) {
+	aead, err := s.aead()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (s *state) unwrap(wrapped []byte) ([]byte, error) {
+	aead, err := s.aead()
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < nonceSize+aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	plaintext, err := aead.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCiphertext, err)
+	}
+	return plaintext, nil
+}
+
+// RootKeyID identifies the root key, as the wrapping key ID of the keys it wraps.
+func (km *KeyManager) RootKeyID() (string, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	s, err := km.load()
+	if err != nil {
+		return "", err
+	}
+	return s.RootKeyID, nil
+}
+
+// Wrap encrypts key material with the root key.
+func (km *KeyManager) Wrap(plaintext []byte) ([]byte, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	s, err := km.load()
+	if err != nil {
+		return nil, err
+	}
+	return s.wrap(plaintext)
+}
+
+// Unwrap decrypts key material wrapped with the root key.
+func (km *KeyManager) Unwrap(wrapped []byte) ([]byte, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	s, err := km.load()
+	if err != nil {
+		return nil, err
+	}
+	return s.unwrap(wrapped)
+}
+
+// CreateKey creates a key pair of the algorithm and stores it with its private key wrapped by the root key.
+func (km *KeyManager) CreateKey(keyID, algorithm string) (*Key, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	s, err := km.load()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.Keys[keyID]; ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyExists, keyID)
+	}
+
+	priv, err := generate(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(priv.(crypto.Signer).Public()) //nolint:forcetypeassert // RSA and ECDSA keys are signers
+	if err != nil {
+		return nil, err
+	}
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	wrapped, err := s.wrap(privPEM)
+	clear(privPEM)
+	clear(privDER)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &Key{
+		KeyID:             keyID,
+		Algorithm:         algorithm,
+		PublicKeyPEM:      string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})),
+		WrappedPrivateKey: base64.StdEncoding.EncodeToString(wrapped),
+		CreatedAt:         time.Now().UTC(),
+	}
+	s.Keys[keyID] = k
+	if err := km.save(s); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func generate(algorithm string) (crypto.PrivateKey, error) {
+	if bits, ok := rsaBits[algorithm]; ok {
+		return rsa.GenerateKey(rand.Reader, bits)
+	}
+	if curve, ok := curves[algorithm]; ok {
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	}
+	return nil, fmt.Errorf("%w '%s'", ErrUnsupportedAlg, algorithm)
+}
+
+// Key returns a key pair by its key ID.
+func (km *KeyManager) Key(keyID string) (*Key, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	s, err := km.load()
+	if err != nil {
+		return nil, err
+	}
+	k, ok := s.Keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+	return k, nil
+}
+
+// Keys lists the key pairs by key ID.
+func (km *KeyManager) Keys() ([]*Key, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	s, err := km.load()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]*Key, 0, len(s.Keys))
+	for _, k := range s.Keys {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].KeyID < keys[j].KeyID })
+	return keys, nil
+}
+
+func (km *KeyManager) privateKey(keyID string) (crypto.PrivateKey, error) {
+	km.mu.Lock()
+	defer km.mu.Unlock()
+	s, err := km.load()
+	if err != nil {
+		return nil, err
+	}
+	k, ok := s.Keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(k.WrappedPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped private key of %s: %w", keyID, err)
+	}
+	privPEM, err := s.unwrap(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(privPEM)
+	block, _ := pem.Decode(privPEM)
+	if block == nil {
+		return nil, fmt.Errorf("invalid private key of %s", keyID)
+	}
+	defer clear(block.Bytes)
+	return x509.ParsePKCS8PrivateKey(block.Bytes)
+}
+
+// Decrypt decrypts with the private key of an RSA key pair, using RSA-OAEP over SHA-1 as the KAS does.
+func (km *KeyManager) Decrypt(keyID string, ciphertext []byte) ([]byte, error) {
+	priv, err := km.privateKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := priv.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not an RSA key, derive a shared secret instead", ErrUnsupportedAlg, keyID)
+	}
+	plaintext, err := rsa.DecryptOAEP(sha1.New(), rand.Reader, rsaKey, ciphertext, nil) //nolint:gosec // see the import
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCiphertext, err)
+	}
+	return plaintext, nil
+}
+
+// DeriveSharedSecret computes the ECDH shared secret of an EC key pair with an ephemeral public key, given as PEM or
+// as a compressed or uncompressed point.
+func (km *KeyManager) DeriveSharedSecret(keyID string, ephemeral []byte) ([]byte, error) {
+	priv, err := km.privateKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	ecKey, ok := priv.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not an EC key, decrypt instead", ErrUnsupportedAlg, keyID)
+	}
+	ecdhKey, err := ecKey.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	pub, err := ephemeralKey(ecKey.Curve, ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	return ecdhKey.ECDH(pub)
+}
+
+func ephemeralKey(curve elliptic.Curve, b []byte) (*ecdh.PublicKey, error) {
+	if block, _ := pem.Decode(b); block != nil {
+		pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidEphemeralKey, err)
+		}
+		ecPub, ok := pub.(*ecdsa.PublicKey)
+		if !ok || ecPub.Curve != curve {
+			return nil, fmt.Errorf("%w: not a key on %s", ErrInvalidEphemeralKey, curve.Params().Name)
+		}
+		return ecPub.ECDH()
+	}
+	if x, y := elliptic.UnmarshalCompressed(curve, b); x != nil {
+		b = elliptic.Marshal(curve, x, y) //nolint:staticcheck // the uncompressed point is what crypto/ecdh reads
+	}
+	var c ecdh.Curve
+	switch curve {
+	case elliptic.P256():
+		c = ecdh.P256()
+	case elliptic.P384():
+		c = ecdh.P384()
+	default:
+		c = ecdh.P521()
+	}
+	pub, err := c.NewPublicKey(b)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEphemeralKey, err)
+	}
+	return pub, nil
+}
@@ -0,0 +1,222 @@
+package keymanager
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // the KAS wraps keys with RSA-OAEP over SHA-1
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTemp(t *testing.T) *KeyManager {
+	t.Helper()
+	km, err := Open(filepath.Join(t.TempDir(), "key-manager.json"))
+	require.NoError(t, err)
+	return km
+}
+
+func TestOpen(t *testing.T) {
+	km := openTemp(t)
+	info, err := os.Stat(km.Path())
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(fileMode), info.Mode().Perm())
+
+	id, err := km.RootKeyID()
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(id, "root-"))
+
+	// opening again keeps the root key
+	reopened, err := Open(km.Path())
+	require.NoError(t, err)
+	again, err := reopened.RootKeyID()
+	require.NoError(t, err)
+	assert.Equal(t, id, again)
+
+	require.NoError(t, os.WriteFile(km.Path(), []byte(`{"root_key_id":"r","root_key":"abcd"}`), fileMode))
+	_, err = Open(km.Path())
+	require.ErrorContains(t, err, "root key must be 32 hex encoded bytes")
+}
+
+func TestWrap(t *testing.T) {
+	km := openTemp(t)
+	wrapped, err := km.Wrap([]byte("key material"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(wrapped), "key material")
+
+	plaintext, err := km.Unwrap(wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, "key material", string(plaintext))
+
+	wrapped[len(wrapped)-1] ^= 0xff
+	_, err = km.Unwrap(wrapped)
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+	_, err = km.Unwrap([]byte("short"))
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	// another root key cannot unwrap
+	wrapped, err = km.Wrap([]byte("key material"))
+	require.NoError(t, err)
+	_, err = openTemp(t).Unwrap(wrapped)
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func TestCreateKey(t *testing.T) {
+	km := openTemp(t)
+
+	k, err := km.CreateKey("rsa-key", AlgorithmRSA2048)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(k.PublicKeyPEM, "-----BEGIN PUBLIC KEY-----"))
+
+	// the wrapped private key unwraps with the root key to the private key of the public key
+	wrapped, err := base64.StdEncoding.DecodeString(k.WrappedPrivateKey)
+	require.NoError(t, err)
+	privPEM, err := km.Unwrap(wrapped)
+	require.NoError(t, err)
+	block, _ := pem.Decode(privPEM)
+	require.NotNil(t, block)
+	assert.Equal(t, "PRIVATE KEY", block.Type)
+
+	_, err = km.CreateKey("rsa-key", AlgorithmRSA2048)
+	require.ErrorIs(t, err, ErrKeyExists)
+	_, err = km.CreateKey("other", "rsa:1024")
+	require.ErrorIs(t, err, ErrUnsupportedAlg)
+
+	// keys created by another process using the same file are seen
+	other, err := Open(km.Path())
+	require.NoError(t, err)
+	_, err = other.CreateKey("ec-key", AlgorithmECP256)
+	require.NoError(t, err)
+	keys, err := km.Keys()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "ec-key", keys[0].KeyID)
+
+	_, err = km.Key("missing")
+	require.ErrorIs(t, err, ErrKeyNotFound)
+}
+
+func TestDecrypt(t *testing.T) {
+	km := openTemp(t)
+	k, err := km.CreateKey("rsa-key", AlgorithmRSA2048)
+	require.NoError(t, err)
+
+	block, _ := pem.Decode([]byte(k.PublicKeyPEM))
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	ciphertext, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, pub.(*rsa.PublicKey), []byte("dek"), nil) //nolint:gosec // see the import
+	require.NoError(t, err)
+
+	plaintext, err := km.Decrypt("rsa-key", ciphertext)
+	require.NoError(t, err)
+	assert.Equal(t, "dek", string(plaintext))
+
+	_, err = km.Decrypt("rsa-key", []byte("garbage"))
+	require.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func TestDeriveSharedSecret(t *testing.T) {
+	km := openTemp(t)
+	k, err := km.CreateKey("ec-key", AlgorithmECP256)
+	require.NoError(t, err)
+
+	ephemeral, err := ecdh.P256().GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, _ := pem.Decode([]byte(k.PublicKeyPEM))
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	require.NoError(t, err)
+	ecdhPub, err := pub.(*ecdsa.PublicKey).ECDH()
+	require.NoError(t, err)
+	want, err := ephemeral.ECDH(ecdhPub)
+	require.NoError(t, err)
+
+	got, err := km.DeriveSharedSecret("ec-key", ephemeral.PublicKey().Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	der, err := x509.MarshalPKIXPublicKey(ephemeral.PublicKey())
+	require.NoError(t, err)
+	got, err = km.DeriveSharedSecret("ec-key", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = km.DeriveSharedSecret("ec-key", []byte("not a point"))
+	require.ErrorIs(t, err, ErrInvalidEphemeralKey)
+	_, err = km.Decrypt("ec-key", []byte("x"))
+	require.ErrorIs(t, err, ErrUnsupportedAlg)
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, token string, body any) (int, map[string]any) {
+	t.Helper()
+	var b []byte
+	if body != nil {
+		var err error
+		b, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+	req, err := http.NewRequest(method, srv.URL+path, bytes.NewReader(b))
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	out := map[string]any{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return resp.StatusCode, out
+}
+
+func TestHandler(t *testing.T) {
+	km := openTemp(t)
+	srv := httptest.NewServer(NewHandler(km, "secret"))
+	t.Cleanup(srv.Close)
+
+	code, _ := do(t, srv, http.MethodGet, HealthPath, "", nil)
+	assert.Equal(t, http.StatusOK, code)
+	code, out := do(t, srv, http.MethodGet, KeysPath, "", nil)
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Contains(t, out["error"], "bearer token")
+
+	code, out = do(t, srv, http.MethodPost, KeysPath, "secret", map[string]string{"key_id": "k1", "algorithm": AlgorithmRSA2048})
+	require.Equal(t, http.StatusCreated, code)
+	assert.NotEmpty(t, out["wrapped_private_key"])
+	code, _ = do(t, srv, http.MethodPost, KeysPath, "secret", map[string]string{"key_id": "k1", "algorithm": AlgorithmRSA2048})
+	assert.Equal(t, http.StatusConflict, code)
+
+	code, out = do(t, srv, http.MethodGet, KeysPath+"/k1", "secret", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Contains(t, out["public_key_pem"], "BEGIN PUBLIC KEY")
+	assert.NotContains(t, out, "wrapped_private_key")
+	code, _ = do(t, srv, http.MethodGet, KeysPath+"/missing", "secret", nil)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	code, out = do(t, srv, http.MethodPost, WrapPath, "secret", map[string]string{"plaintext": base64.StdEncoding.EncodeToString([]byte("dek"))})
+	require.Equal(t, http.StatusOK, code)
+	rootKeyID, err := km.RootKeyID()
+	require.NoError(t, err)
+	assert.Equal(t, rootKeyID, out["root_key_id"])
+	code, out = do(t, srv, http.MethodPost, UnwrapPath, "secret", map[string]any{"ciphertext": out["ciphertext"]})
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("dek")), out["plaintext"])
+
+	code, _ = do(t, srv, http.MethodPost, UnwrapPath, "secret", map[string]string{"ciphertext": "not base64!"})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = do(t, srv, http.MethodPost, KeysPath+"/k1/decrypt", "secret", map[string]string{})
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = do(t, srv, http.MethodPost, KeysPath+"/k1/decrypt", "secret", map[string]string{"unknown": "x"})
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,277 @@
+package keymanager
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+const (
+	HealthPath = "/healthz"
+	KeysPath   = "/v1/keys"
+	WrapPath   = "/v1/wrap"
+	UnwrapPath = "/v1/unwrap"
+
+	maxRequestBytes = 1 << 20
+)
+
+// ProviderConfig is the config to register the emulator with 'key-management provider create', for the
+// dev-key-manager provider type.
+type ProviderConfig struct {
+	Endpoint  string `json:"endpoint"`
+	RootKeyID string `json:"root_key_id"`
+	Token     string `json:"token,omitempty"`
+}
+
+type createKeyRequest struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+}
+
+// the binary fields of requests and responses are standard base64
+type wrapRequest struct {
+	Plaintext string `json:"plaintext"`
+}
+
+type wrapResponse struct {
+	RootKeyID  string `json:"root_key_id"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+type unwrapRequest struct {
+	Ciphertext string `json:"ciphertext"`
+}
+
+type unwrapResponse struct {
+	Plaintext string `json:"plaintext"`
+}
+
+type decryptRequest struct {
+	Ciphertext         string `json:"ciphertext,omitempty"`
+	EphemeralPublicKey string `json:"ephemeral_public_key,omitempty"`
+}
+
+type decryptResponse struct {
+	Plaintext    string `json:"plaintext,omitempty"`
+	SharedSecret string `json:"shared_secret,omitempty"`
+}
+
+type publicKey struct {
+	KeyID        string `json:"key_id"`
+	Algorithm    string `json:"algorithm"`
+	PublicKeyPEM string `json:"public_key_pem"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler serves the key manager over HTTP with JSON bodies:
+//
+//	GET  /healthz                   health check
+//	GET  /v1/keys                   list the public keys
+//	POST /v1/keys                   create a key pair: {key_id, algorithm}
+//	GET  /v1/keys/{key_id}          get a public key
+//	POST /v1/keys/{key_id}/decrypt  decrypt with an RSA key: {ciphertext}, or derive the ECDH shared secret of an EC
+//	                                key: {ephemeral_public_key}
+//	POST /v1/wrap                   wrap with the root key: {plaintext}
+//	POST /v1/unwrap                 unwrap with the root key: {ciphertext}
+//
+// Creating a key pair also returns its private key wrapped by the root key, for the provider root key mode. Every
+// request but the health check needs the token as a bearer token, when one is given.
+func NewHandler(km *KeyManager, token string) http.Handler {
+	s := &server{km: km, token: token}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+HealthPath, func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET "+KeysPath, s.authorized(s.listKeys))
+	mux.HandleFunc("POST "+KeysPath, s.authorized(s.createKey))
+	mux.HandleFunc("GET "+KeysPath+"/{key_id}", s.authorized(s.getKey))
+	mux.HandleFunc("POST "+KeysPath+"/{key_id}/decrypt", s.authorized(s.decrypt))
+	mux.HandleFunc("POST "+WrapPath, s.authorized(s.wrap))
+	mux.HandleFunc("POST "+UnwrapPath, s.authorized(s.unwrap))
+	return mux
+}
+
+type server struct {
+	km    *KeyManager
+	token string
+}
+
+func (s *server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(s.token)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+				return
+			}
+		}
+		next(w, r)
+	}
+}
+
+func (s *server) listKeys(w http.ResponseWriter, _ *http.Request) {
+	keys, err := s.km.Keys()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	out := make([]publicKey, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, publicKey{KeyID: k.KeyID, Algorithm: k.Algorithm, PublicKeyPEM: k.PublicKeyPEM})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"keys": out})
+}
+
+func (s *server) createKey(w http.ResponseWriter, r *http.Request) {
+	var req createKeyRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.KeyID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("key_id is required"))
+		return
+	}
+	k, err := s.km.CreateKey(req.KeyID, req.Algorithm)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, k)
+}
+
+func (s *server) getKey(w http.ResponseWriter, r *http.Request) {
+	k, err := s.km.Key(r.PathValue("key_id"))
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, publicKey{KeyID: k.KeyID, Algorithm: k.Algorithm, PublicKeyPEM: k.PublicKeyPEM})
+}
+
+func (s *server) decrypt(w http.ResponseWriter, r *http.Request) {
+	var req decryptRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	keyID := r.PathValue("key_id")
+	switch {
+	case req.Ciphertext != "":
+		ciphertext, ok := decodeField(w, "ciphertext", req.Ciphertext)
+		if !ok {
+			return
+		}
+		plaintext, err := s.km.Decrypt(keyID, ciphertext)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, decryptResponse{Plaintext: base64.StdEncoding.EncodeToString(plaintext)})
+	case req.EphemeralPublicKey != "":
+		ephemeral := []byte(req.EphemeralPublicKey)
+		if !strings.HasPrefix(req.EphemeralPublicKey, "-----BEGIN") {
+			var ok bool
+			if ephemeral, ok = decodeField(w, "ephemeral_public_key", req.EphemeralPublicKey); !ok {
+				return
+			}
+		}
+		secret, err := s.km.DeriveSharedSecret(keyID, ephemeral)
+		if err != nil {
+			writeError(w, statusOf(err), err)
+			return
+		}
+		writeJSON(w, http.StatusOK, decryptResponse{SharedSecret: base64.StdEncoding.EncodeToString(secret)})
+	default:
+		writeError(w, http.StatusBadRequest, errors.New("ciphertext or ephemeral_public_key is required"))
+	}
+}
+
+func (s *server) wrap(w http.ResponseWriter, r *http.Request) {
+	var req wrapRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	plaintext, ok := decodeField(w, "plaintext", req.Plaintext)
+	if !ok {
+		return
+	}
+	rootKeyID, err := s.km.RootKeyID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	wrapped, err := s.km.Wrap(plaintext)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, wrapResponse{RootKeyID: rootKeyID, Ciphertext: base64.StdEncoding.EncodeToString(wrapped)})
+}
+
+func (s *server) unwrap(w http.ResponseWriter, r *http.Request) {
+	var req unwrapRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	wrapped, ok := decodeField(w, "ciphertext", req.Ciphertext)
+	if !ok {
+		return
+	}
+	plaintext, err := s.km.Unwrap(wrapped)
+	if err != nil {
+		writeError(w, statusOf(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, unwrapResponse{Plaintext: base64.StdEncoding.EncodeToString(plaintext)})
+}
+
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, ErrKeyNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrKeyExists):
+		return http.StatusConflict
+	case errors.Is(err, ErrUnsupportedAlg), errors.Is(err, ErrInvalidCiphertext), errors.Is(err, ErrInvalidEphemeralKey):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	d := json.NewDecoder(io.LimitReader(r.Body, maxRequestBytes))
+	d.DisallowUnknownFields()
+	if err := d.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body: "+err.Error()))
+		return false
+	}
+	return true
+}
+
+func decodeField(w http.ResponseWriter, name, value string) ([]byte, bool) {
+	b, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(b) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New(name+" must be non-empty standard base64"))
+		return nil, false
+	}
+	return b, true
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Debug("failed to write key manager response", "error", err)
+	}
+}
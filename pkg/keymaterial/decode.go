@@ -0,0 +1,88 @@
+package keymaterial
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+const (
+	aes128KeySize = 16
+	aes192KeySize = 24
+	aes256KeySize = 32
+)
+
+var (
+	ErrInvalidPEM         = errors.New("must be a PEM block or a base64 encoded PEM block")
+	ErrInvalidWrappingKey = errors.New("must be a 16, 24 or 32 byte AES key, hex or base64 encoded")
+)
+
+var (
+	pemHeader     = []byte("-----BEGIN ")
+	pemLineHeader = []byte("\n-----BEGIN ")
+)
+
+// PEM returns raw PEM from key material that is either PEM or base64 encoded PEM. PEM is kept byte for byte, so a key
+// stored by the platform reads back exactly as given.
+func PEM(b []byte) ([]byte, error) {
+	if bytes.HasPrefix(bytes.TrimSpace(b), pemHeader) {
+		return bytes.Clone(b), nil
+	}
+	decoded, err := decodeBase64(b)
+	if err != nil || !bytes.HasPrefix(bytes.TrimSpace(decoded), pemHeader) {
+		Zero(decoded)
+		return nil, ErrInvalidPEM
+	}
+	return decoded, nil
+}
+
+// Base64 returns key material as base64, encoding it unless it already is. Wrapped keys are opaque, so are passed on
+// unchanged when they are base64 text and encoded when they are raw bytes, e.g. read from a file.
+func Base64(b []byte) string {
+	if decoded, err := decodeBase64(b); err == nil {
+		defer Zero(decoded)
+		return base64.StdEncoding.EncodeToString(decoded)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// WrappingKey returns an AES wrapping key from hex, base64 or, when read from a file, raw key bytes.
+func WrappingKey(b []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(b)
+	decoded := make([]byte, hex.DecodedLen(len(trimmed)))
+	if n, err := hex.Decode(decoded, trimmed); err == nil && validAESKeySize(n) {
+		return decoded[:n], nil
+	}
+	Zero(decoded)
+	if decoded, err := decodeBase64(b); err == nil {
+		if validAESKeySize(len(decoded)) {
+			return decoded, nil
+		}
+		Zero(decoded)
+	}
+	if validAESKeySize(len(b)) {
+		out := make([]byte, len(b))
+		copy(out, b)
+		return out, nil
+	}
+	return nil, fmt.Errorf("%w (%d bytes given)", ErrInvalidWrappingKey, len(b))
+}
+
+func validAESKeySize(n int) bool {
+	return n == aes128KeySize || n == aes192KeySize || n == aes256KeySize
+}
+
+// decodeBase64 decodes standard base64, ignoring line breaks as found in wrapped base64 files
+func decodeBase64(b []byte) ([]byte, error) {
+	compact := bytes.Join(bytes.Fields(b), nil)
+	out := make([]byte, base64.StdEncoding.DecodedLen(len(compact)))
+	n, err := base64.StdEncoding.Decode(out, compact)
+	Zero(compact)
+	if err != nil {
+		Zero(out)
+		return nil, err
+	}
+	return out[:n], nil
+}
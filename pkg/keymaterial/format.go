@@ -0,0 +1,381 @@
+package keymaterial
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-jose/go-jose/v3"
+	"golang.org/x/crypto/pkcs12" //nolint:staticcheck // the only PKCS#12 decoder at hand, and it covers legacy bundles
+	"golang.org/x/crypto/ssh"
+)
+
+// Format is the encoding key material was given in.
+type Format string
+
+const (
+	FormatPEM     Format = "pem"
+	FormatDER     Format = "der"
+	FormatPKCS12  Format = "pkcs12"
+	FormatJWK     Format = "jwk"
+	FormatJWKS    Format = "jwks"
+	FormatOpenSSH Format = "openssh"
+)
+
+// Algorithms in the notation of the --algorithm flag.
+const (
+	AlgorithmRSA2048 = "rsa:2048"
+	AlgorithmRSA4096 = "rsa:4096"
+	AlgorithmECP256  = "ec:secp256r1"
+	AlgorithmECP384  = "ec:secp384r1"
+	AlgorithmECP521  = "ec:secp521r1"
+)
+
+const (
+	rsa2048Bits = 2048
+	rsa4096Bits = 4096
+)
+
+var (
+	ErrUnknownFormat  = errors.New("must be PEM, DER, PKCS#12, JWK, JWKS or an OpenSSH key, raw or base64 encoded")
+	ErrUnsupportedKey = errors.New("unsupported key type")
+	ErrKeyNotFound    = errors.New("key not found")
+	ErrNoPrivateKey   = errors.New("holds no private key")
+	ErrKeyMismatch    = errors.New("private key does not belong to the public key")
+)
+
+// Options control how key material is parsed.
+type Options struct {
+	// KeyID picks a key out of a JWKS by its kid. It may be empty when the set holds a single key.
+	KeyID string
+	// Password is asked for when a PKCS#12 bundle or OpenSSH private key is encrypted.
+	Password func() (string, error)
+}
+
+// Key is a public key, and its private key when the key material holds one, in any of the supported formats.
+type Key struct {
+	Format  Format
+	KeyID   string
+	Public  crypto.PublicKey
+	Private crypto.PrivateKey
+
+	// pem is the key material as given when it was PEM already, so it is passed on unchanged
+	pem []byte
+}
+
+// Parse detects the format of key material, which may also be base64 encoded, and parses it.
+func Parse(b []byte, opts Options) (*Key, error) {
+	key, err := parse(b, opts)
+	if !errors.Is(err, ErrUnknownFormat) {
+		return key, err
+	}
+	decoded, decodeErr := decodeBase64(b)
+	if decodeErr != nil {
+		return nil, err
+	}
+	defer Zero(decoded)
+	return parse(decoded, opts)
+}
+
+func parse(b []byte, opts Options) (*Key, error) {
+	trimmed := bytes.TrimSpace(b)
+	switch {
+	case bytes.HasPrefix(trimmed, pemHeader), bytes.Contains(trimmed, pemLineHeader):
+		// PEM may follow explanatory text, such as the bag attributes 'openssl pkcs12' writes
+		return parsePEM(b, opts)
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return parseJWK(trimmed, opts)
+	case bytes.HasPrefix(trimmed, []byte("ssh-")) || bytes.HasPrefix(trimmed, []byte("ecdsa-sha2-")):
+		pub, _, _, _, err := ssh.ParseAuthorizedKey(trimmed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OpenSSH public key: %w", err)
+		}
+		cryptoPub, ok := pub.(ssh.CryptoPublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedKey, pub.Type())
+		}
+		return &Key{Format: FormatOpenSSH, Public: cryptoPub.CryptoPublicKey()}, nil
+	}
+	if key := parseDER(b); key != nil {
+		return key, nil
+	}
+	return parsePKCS12(b, opts)
+}
+
+// parsePEM reads the key out of PEM, which may hold several blocks such as a private key followed by its
+// certificate chain. A private key is preferred over a public key or certificate, and only the block of the key is
+// kept to be passed on.
+func parsePEM(b []byte, opts Options) (*Key, error) {
+	var (
+		found     *Key
+		foundPEM  []byte
+		firstType string
+	)
+	for rest := b; found == nil || found.Private == nil; {
+		block, next := pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		raw := rest[:len(rest)-len(next)]
+		raw = raw[bytes.Index(raw, pemHeader):]
+		rest = next
+		if firstType == "" {
+			firstType = block.Type
+		}
+
+		switch block.Type {
+		case "OPENSSH PRIVATE KEY":
+			Zero(block.Bytes)
+			return parseOpenSSHPrivateKey(raw, opts)
+		case "ENCRYPTED PRIVATE KEY":
+			Zero(block.Bytes)
+			return nil, errors.New("encrypted PKCS#8 keys are not supported, decrypt it first with 'openssl pkcs8'")
+		}
+		key := parseDER(block.Bytes)
+		Zero(block.Bytes)
+		if key != nil && (found == nil || key.Private != nil) {
+			found, foundPEM = key, raw
+		}
+	}
+
+	switch {
+	case firstType == "":
+		return nil, ErrInvalidPEM
+	case found == nil:
+		return nil, fmt.Errorf("%w: PEM block '%s'", ErrUnsupportedKey, firstType)
+	}
+	found.Format = FormatPEM
+	found.pem = bytes.Clone(foundPEM)
+	return found, nil
+}
+
+func parseOpenSSHPrivateKey(b []byte, opts Options) (*Key, error) {
+	priv, err := ssh.ParseRawPrivateKey(b)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		password, pwErr := askPassword(opts, "OpenSSH private key")
+		if pwErr != nil {
+			return nil, pwErr
+		}
+		priv, err = ssh.ParseRawPrivateKeyWithPassphrase(b, []byte(password))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid OpenSSH private key: %w", err)
+	}
+	return privateKey(FormatOpenSSH, priv)
+}
+
+// parseDER returns nil when der is none of the DER encodings of a key or certificate
+func parseDER(der []byte) *Key {
+	if pub, err := x509.ParsePKIXPublicKey(der); err == nil {
+		return &Key{Format: FormatDER, Public: pub}
+	}
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		return &Key{Format: FormatDER, Public: cert.PublicKey}
+	}
+	if pub, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return &Key{Format: FormatDER, Public: pub}
+	}
+	var priv crypto.PrivateKey
+	var err error
+	if priv, err = x509.ParsePKCS8PrivateKey(der); err != nil {
+		if priv, err = x509.ParsePKCS1PrivateKey(der); err != nil {
+			if priv, err = x509.ParseECPrivateKey(der); err != nil {
+				return nil
+			}
+		}
+	}
+	key, err := privateKey(FormatDER, priv)
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+// parsePKCS12 reads the private key out of a PKCS#12 bundle, which may also hold any number of certificates
+func parsePKCS12(b []byte, opts Options) (*Key, error) {
+	blocks, err := pkcs12.ToPEM(b, "")
+	if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+		password, pwErr := askPassword(opts, "PKCS#12 bundle")
+		if pwErr != nil {
+			return nil, pwErr
+		}
+		blocks, err = pkcs12.ToPEM(b, password)
+	}
+	var notImplemented pkcs12.NotImplementedError
+	switch {
+	case errors.As(err, &notImplemented):
+		return nil, fmt.Errorf("unsupported PKCS#12 bundle, re-export it with 'openssl pkcs12 -export -legacy': %w", err)
+	case errors.Is(err, pkcs12.ErrIncorrectPassword), errors.Is(err, pkcs12.ErrDecryption):
+		return nil, fmt.Errorf("failed to decrypt PKCS#12 bundle: %w", err)
+	case err != nil:
+		// the last format tried, so its error says why the input is none of them when it is not a bundle either
+		return nil, fmt.Errorf("%w (as PKCS#12: %w)", ErrUnknownFormat, err)
+	}
+	defer func() {
+		for _, block := range blocks {
+			Zero(block.Bytes)
+		}
+	}()
+
+	for _, block := range blocks {
+		if block.Type == "CERTIFICATE" {
+			continue
+		}
+		if key := parseDER(block.Bytes); key != nil && key.Private != nil {
+			key.Format = FormatPKCS12
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("PKCS#12 bundle %w", ErrNoPrivateKey)
+}
+
+func parseJWK(b []byte, opts Options) (*Key, error) {
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWK: %w", err)
+	}
+	format := FormatJWK
+	raw := json.RawMessage(b)
+	if set.Keys != nil {
+		format = FormatJWKS
+		var err error
+		if raw, err = selectJWK(set.Keys, opts.KeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	var jwk jose.JSONWebKey
+	if err := jwk.UnmarshalJSON(raw); err != nil {
+		return nil, fmt.Errorf("invalid JWK: %w", err)
+	}
+	if jwk.IsPublic() {
+		return &Key{Format: format, KeyID: jwk.KeyID, Public: jwk.Key}, nil
+	}
+	if _, symmetric := jwk.Key.([]byte); symmetric {
+		return nil, fmt.Errorf("%w: symmetric JWK", ErrUnsupportedKey)
+	}
+	key, err := privateKey(format, jwk.Key)
+	if err != nil {
+		return nil, err
+	}
+	key.KeyID = jwk.KeyID
+	return key, nil
+}
+
+// selectJWK picks the key with the kid out of a set, or its only key when no kid is given
+func selectJWK(keys []json.RawMessage, kid string) (json.RawMessage, error) {
+	kids := make([]string, 0, len(keys))
+	for _, k := range keys {
+		var header struct {
+			Kid string `json:"kid"`
+		}
+		if err := json.Unmarshal(k, &header); err != nil {
+			return nil, fmt.Errorf("invalid JWKS: %w", err)
+		}
+		if kid != "" && header.Kid == kid {
+			return k, nil
+		}
+		kids = append(kids, header.Kid)
+	}
+	switch {
+	case kid != "":
+		return nil, fmt.Errorf("%w: no kid '%s' in JWKS (kids: %s)", ErrKeyNotFound, kid, strings.Join(kids, ", "))
+	case len(keys) == 1:
+		return keys[0], nil
+	case len(keys) == 0:
+		return nil, fmt.Errorf("%w: JWKS is empty", ErrKeyNotFound)
+	default:
+		return nil, fmt.Errorf("%w: JWKS holds %d keys, pick one by kid (kids: %s)", ErrKeyNotFound, len(keys), strings.Join(kids, ", "))
+	}
+}
+
+func privateKey(format Format, priv crypto.PrivateKey) (*Key, error) {
+	signer, ok := priv.(interface{ Public() crypto.PublicKey })
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, priv)
+	}
+	return &Key{Format: format, Public: signer.Public(), Private: priv}, nil
+}
+
+func askPassword(opts Options, what string) (string, error) {
+	if opts.Password == nil {
+		return "", fmt.Errorf("%s is password protected", what)
+	}
+	return opts.Password()
+}
+
+// Algorithm infers the --algorithm of the key.
+func (k *Key) Algorithm() (string, error) {
+	switch pub := k.Public.(type) {
+	case *rsa.PublicKey:
+		switch pub.N.BitLen() {
+		case rsa2048Bits:
+			return AlgorithmRSA2048, nil
+		case rsa4096Bits:
+			return AlgorithmRSA4096, nil
+		}
+		return "", fmt.Errorf("%w: RSA %d", ErrUnsupportedKey, pub.N.BitLen())
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P256():
+			return AlgorithmECP256, nil
+		case elliptic.P384():
+			return AlgorithmECP384, nil
+		case elliptic.P521():
+			return AlgorithmECP521, nil
+		}
+		return "", fmt.Errorf("%w: EC %s", ErrUnsupportedKey, pub.Curve.Params().Name)
+	}
+	return "", fmt.Errorf("%w: %T", ErrUnsupportedKey, k.Public)
+}
+
+// Matches reports whether both keys share the same public key, e.g. a private key and the public key given for it.
+func (k *Key) Matches(other *Key) bool {
+	pub, ok := k.Public.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && pub.Equal(other.Public)
+}
+
+// PublicPEM returns the public key as PEM, unchanged when it was given as a public key or certificate PEM.
+func (k *Key) PublicPEM() ([]byte, error) {
+	if k.pem != nil && k.Private == nil {
+		return bytes.Clone(k.pem), nil
+	}
+	der, err := x509.MarshalPKIXPublicKey(k.Public)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedKey, err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// PrivatePEM returns the private key as PEM, unchanged when it was given as PEM and as PKCS#8 otherwise. The caller
+// should Zero it when done.
+func (k *Key) PrivatePEM() ([]byte, error) {
+	if k.Private == nil {
+		return nil, ErrNoPrivateKey
+	}
+	if k.pem != nil {
+		return bytes.Clone(k.pem), nil
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(k.Private)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrUnsupportedKey, err)
+	}
+	defer Zero(der)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// Zero overwrites the key material held as given. Parsed keys cannot be cleared and are left to the garbage collector.
+func (k *Key) Zero() {
+	Zero(k.pem)
+}
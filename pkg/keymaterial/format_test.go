@@ -0,0 +1,323 @@
+package keymaterial
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// testPKCS12 is an EC P-256 key and self-signed certificate, exported with
+// openssl pkcs12 -export -legacy -passout pass:test
+const testPKCS12 = "" +
+	"MIIDegIBAzCCA0AGCSqGSIb3DQEHAaCCAzEEggMtMIIDKTCCAh8GCSqGSIb3DQEHBqCCAhAwggIMAgEAMIICBQYJKoZIhvcNAQcB" +
+	"MBwGCiqGSIb3DQEMAQYwDgQIcekfQoeMgOUCAggAgIIB2FcAObYkFUWjcfqoWCscus3qc3hC0WP042qkmWdC3LGbYlLB5ZlLZvK8" +
+	"5/0ayOtytFGRd9uw+B1VPA3LmEVKE4Qet3kX/GkSSDEi9QB0dT8t0RPMtF/d5RaA6PY+4XNK4BguO7ufgZrK+9obv4o1UIQp/DhN" +
+	"yW1N9Tm5yMWRebj0+9ixrjKTUixVyZFq9rejJ85RjqO7zd++itqxwJvuFWxV3z5QIVBEjtgieqImrDdalPiGrnKdju153IwK19ZQ" +
+	"1fyC7HddVNNovFRh1dvoG8Ahtq/VURGQa9z/62n0onL52EmvUWKxXmixrOyChZk+7lgiOVlLxqBDWZrh98KqTxuvqttP9A3AqtJ5" +
+	"/o1arfRheO0PLikplB2zj9CtFY2e3fOkDODnAChKuWi+4j7RNs7Scl+/HjU6dyjrUkVYRJpTnwkhOOJ0eN2r+NLe0w7aey5BZvZm" +
+	"ekwftjRG6INFzfvoW2fLTezxhV4A1gV8wmrmuF/shxSx7tf55X1DtEUyhbtpCiZzWr9fl6WM8/i4Qyob5c25T5lg3qC7O0ay/Zqy" +
+	"hHgnsUAhyfoZzhMrFuxQayTop9DeoL1NkuXRam+raE02AWv5qKEMSvjYMv4NLrR25QacBmBWR68wggECBgkqhkiG9w0BBwGggfQE" +
+	"gfEwge4wgesGCyqGSIb3DQEMCgECoIG0MIGxMBwGCiqGSIb3DQEMAQMwDgQIbF1LTA+DpK4CAggABIGQMCtsggD/k34WzR4Nxes9" +
+	"W8ImzewfDNHYp2fi4byMqZjQtGmCiA27Wfrtj28cwv+fWP0uYjKu+DJpx+VL1wRKoH0ktVBPtiJ4FbWbgVw2bOASkquyhdleirkB" +
+	"0mZcT9qkPiQCIAuNh/wqhR0gW9dXx/O0sipuGfFCcU/YJ4ExpdC9bWW8sZnDdK5Ax/5fjxY1MSUwIwYJKoZIhvcNAQkVMRYEFLsp" +
+	"1TUU3GI4Pv0L7Btaj/AZwI7DMDEwITAJBgUrDgMCGgUABBQ+qm1bhFKcIK9CY3M/UuNgcbFATAQIK28Dt2I1vL4CAggA"
+
+// testPKCS12AES is the same bundle exported with the AES and SHA-256 defaults of OpenSSL 3
+const testPKCS12AES = "" +
+	"MIIEDAIBAzCCA8IGCSqGSIb3DQEHAaCCA7MEggOvMIIDqzCCAmIGCSqGSIb3DQEHBqCCAlMwggJPAgEAMIICSAYJKoZIhvcNAQcB" +
+	"MFcGCSqGSIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAitENHzvzkrjwICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQMEASoEEE1e" +
+	"GmPX7SW2sQiCViugHoqAggHgYzTqD9RLieNaOUZaAzDS1gXToCkTBvGFVpDqk2GSSTkeMzYxA4VgNCTKdxT1upIg1amZvl+Vv8CL" +
+	"bt1Abml78GQJaUCL1wRYBxNTwWngeOoYFxoWix+nZDY3sSeIvoyBFxC+PoTifWSBZID4HeDrUZySOnzyLWXVNp1D7CTEvKrEzTLT" +
+	"7FIbE7n8nXSKT4A6d+FYOV4NfqzaONJRc2iRuQ9YH9nESbuROhw5PSX0Ok0fvq3smTcxZubQOvFnxQ+1Ko+cqGrIT3nN+tZ0cRsY" +
+	"kE+IfsrpHbi3seiIBx56CI1wyxIrrgG9HQcIhhFSBjitj/l87TQhqiodj/1XqJSRBMwasijcEKl7UBQHwATZvPnr2HOu1v8WuQ8i" +
+	"OFgpGumbaVvCAYLkJbfY8bAwZsWxpI8AZw8u3jRfp2J7vZWCUq8gh4JHDVlUPlSRhAOkPkiK1G3s9as4KowtOKScsOYKYr3eT0Lr" +
+	"+f4aTNSCDy3soHq+ExbxgPpBLSsAVRXe5IidcXJrDr/nxCpDuFcBKSeFFAF8f4untHaEsSukb8RQ7YHPV0f6xnn6xFPFThn3OctB" +
+	"xZDD+0I8PpX1SpgtXTmmjn7GKddnG98I7vC6Xwj1bYUa0+feBcl79k19isxLBnRQMIIBQQYJKoZIhvcNAQcBoIIBMgSCAS4wggEq" +
+	"MIIBJgYLKoZIhvcNAQwKAQKgge8wgewwVwYJKoZIhvcNAQUNMEowKQYJKoZIhvcNAQUMMBwECK0sgj4qqIb7AgIIADAMBggqhkiG" +
+	"9w0CCQUAMB0GCWCGSAFlAwQBKgQQ4DWFcZK3Gh8QnDpEQarOogSBkNO5kxylTbZvZdAjX+oXMfhtDpKI7/eCA+tU8SBr2obFiHpB" +
+	"6zFM8Axa6CdD3TEHfJKr1N0LMYAllIMc54Pyi6lTyBsJrhY1dHF8HQ0BtzAiWNaYh5XV/16fqu+e2HXUJ1yLdA3nuvTMY1/22vQz" +
+	"xmw6vveWqsZJG1TR5bgUtnF8a3iKnvkBW6lqqU099ToouzElMCMGCSqGSIb3DQEJFTEWBBS7KdU1FNxiOD79C+wbWo/wGcCOwzBB" +
+	"MDEwDQYJYIZIAWUDBAIBBQAEIL/9fzDXw9hsCIwsBsz2VsATLWVlNnmz8E/E2cY9o0B2BAjOLK7yngZoQQICCAA="
+
+// testPKCS12Chain is an EC P-256 key with its certificate and a CA certificate, exported with
+// openssl pkcs12 -export -legacy -certfile ca.pem -passout pass:test
+const testPKCS12Chain = "" +
+	"MIIFEgIBAzCCBNgGCSqGSIb3DQEHAaCCBMkEggTFMIIEwTCCA7cGCSqGSIb3DQEHBqCCA6gwggOkAgEAMIIDnQYJKoZIhvcNAQcB" +
+	"MBwGCiqGSIb3DQEMAQYwDgQIlgg9vpRaGXoCAggAgIIDcLZquj1RW53isBVUoqVWSEmdk8IM/2U56RhzKX3Qv8YxyNxa08DKjGmx" +
+	"UV63+9u0ec2dIX18YHHKE89/qI+LiAPDsxcmGMFa+w/MGaazZhP+WD/y1p2ems7po3q+n4Cwl/vpB9LD1zKCFTYUCRprliOz+9ze" +
+	"M2wdqOhBY7EMrUCJ2KbUNEVcBEJIoCITdOzp/ZIcC0gjTqp2h8Lu/nUJmF6XDcEmwRTdyoB+7CieCcLyMsAuf/529ZwlL9HfKoQW" +
+	"AbZr61pyDtQ0XQ/R9536BVJ5wuuNLesGrO+U0iqXvvxXNKq1BCa08hGDNxRdQM9QHDI+bqWoR6om8T3dhfs96WLoHNRjUHoqpVS0" +
+	"95STgwVxxp20Qh5/HMAs7TkVH2Vnlmukqsnz4SdUriWv5Ciw+me2w8AyDR2gJFFDSY2AkouV2Bqbyuu/MBdPSOQmx2bWgsnkaibE" +
+	"DWA87qDb0YI1jyxukXi/hYhsqJB6RO5pwUpUheHkZspHe+CRTJkDAFloYQGvop//NkaEPbZ7P+ddnxcCOeLfpTAtOFhhOxvHSbTT" +
+	"Zr149GnhkjHYX4Rc3W0keF38IvgcE0ib+okPps5KHl/r1RJorg4ed3WSkIP3rVEF47795rCePaFR+qoFN9t4dHoN0biZNUhwF+wa" +
+	"uMWn6HYDcT/osPV+U/xPvo9zfmYwInDvWkeRA6dVZ0aKLoT7WGreKNsQhFXrwidHbUWJwxv+PAwygrnjGZr2U49ngmRbQ3JA0zIn" +
+	"lFXINXYyLDCvJ78cCmlS6hR+i1jUGGlX6u0MuxrZ89V6crBplUIjQKn6JYO8FEMjZ5OFk1J2Xg1PPXphZ3Fbxe/hK/uXBPsYCiVx" +
+	"U3zrp02G5BqwXfdLfmn4oLYw10U+RwvLgkEJemMGgRYgK69OyWhQGmik1E+s/Q0KKzbJBU4UeZHSllh0lbdwDi7mCxXqUWNEMVxp" +
+	"uluoOfPiATWWJu5LXvirWbByAn+QPBB8JPLVDxEc/odsqUBBHKJ7Qe33iLNZAZldGVEDuOpo8tbDsplm6rNXUR3+yG21VManHQBY" +
+	"wsIm0Qn+HpYVnHe2qjlU153DEkFMua3kyW02z1iU1ucT1d7jZdV7BDjZB9OssGb6pC1jjrsQ/Om9vlsrfURYJalULSkiPnFuVns4" +
+	"OjbCzAV98dO7rvUJsnkwggECBgkqhkiG9w0BBwGggfQEgfEwge4wgesGCyqGSIb3DQEMCgECoIG0MIGxMBwGCiqGSIb3DQEMAQMw" +
+	"DgQICKtaLi4bdHMCAggABIGQh1qdTuuDLgYgxfnTLSTPsF4MyGmP8Nw4AFzx6dd35ryl3pnp19/boEoa7d1Wvj15xd72FYKabQ5t" +
+	"M5nwtS3k/rkunUG91kV8YjAkSde2JGxZbYJSjmEFbx5n1ft9/PWKyk3+xGkkwLT5cYUITZBL3pSyT882XGVp2XW87syM4w1glPoE" +
+	"1zzvf+hRwpFY6AH4MSUwIwYJKoZIhvcNAQkVMRYEFPHBIu7G5VYIgfPx1PWFZYaAgU8dMDEwITAJBgUrDgMCGgUABBSMDaUuYpkI" +
+	"TwyUpThBVKeJ77TqtgQIyeNWLB0NyRoCAggA"
+
+func testECKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	return key
+}
+
+func testPassword(password string) func() (string, error) {
+	return func() (string, error) { return password, nil }
+}
+
+func TestParse_PEM(t *testing.T) {
+	key := testECKey(t, elliptic.P256())
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	for name, in := range map[string][]byte{
+		"raw":    pubPEM,
+		"base64": []byte(base64.StdEncoding.EncodeToString(pubPEM)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(in, Options{})
+			require.NoError(t, err)
+			assert.Equal(t, FormatPEM, got.Format)
+			assert.Nil(t, got.Private)
+			alg, err := got.Algorithm()
+			require.NoError(t, err)
+			assert.Equal(t, AlgorithmECP256, alg)
+			// PEM is passed on as given
+			out, err := got.PublicPEM()
+			require.NoError(t, err)
+			assert.Equal(t, pubPEM, out)
+		})
+	}
+}
+
+func TestParse_PrivatePEM(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, rsa2048Bits)
+	require.NoError(t, err)
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	got, err := Parse(privPEM, Options{})
+	require.NoError(t, err)
+	alg, err := got.Algorithm()
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmRSA2048, alg)
+
+	out, err := got.PrivatePEM()
+	require.NoError(t, err)
+	assert.Equal(t, privPEM, out)
+
+	// the public key is derived from the private key
+	pubPEM, err := got.PublicPEM()
+	require.NoError(t, err)
+	block, _ := pem.Decode(pubPEM)
+	require.NotNil(t, block)
+	assert.Equal(t, "PUBLIC KEY", block.Type)
+}
+
+func TestParse_PEMChain(t *testing.T) {
+	key := testECKey(t, elliptic.P256())
+	privDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	privPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})
+	template := &x509.Certificate{SerialNumber: big.NewInt(1)}
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	// the private key is picked out of the chain, whichever block comes first, and only its block is kept
+	for name, in := range map[string][]byte{
+		"key first":  append(append([]byte{}, privPEM...), certPEM...),
+		"key last":   append(append([]byte("bag attributes\n"), certPEM...), privPEM...),
+		"with notes": append(append([]byte("subject=/CN=leaf\n"), privPEM...), certPEM...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(in, Options{})
+			require.NoError(t, err)
+			assert.NotNil(t, got.Private)
+			out, err := got.PrivatePEM()
+			require.NoError(t, err)
+			assert.Equal(t, privPEM, out)
+		})
+	}
+
+	// a certificate alone is a public key
+	got, err := Parse(certPEM, Options{})
+	require.NoError(t, err)
+	assert.Nil(t, got.Private)
+
+	_, err = Parse([]byte("-----BEGIN X509 CRL-----\nAAAA\n-----END X509 CRL-----\n"), Options{})
+	require.ErrorIs(t, err, ErrUnsupportedKey)
+}
+
+func TestParse_DER(t *testing.T) {
+	key := testECKey(t, elliptic.P384())
+	pubDER, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+	privDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	got, err := Parse(pubDER, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, FormatDER, got.Format)
+	alg, err := got.Algorithm()
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmECP384, alg)
+
+	priv, err := Parse(privDER, Options{})
+	require.NoError(t, err)
+	assert.True(t, priv.Matches(got))
+
+	out, err := priv.PrivatePEM()
+	require.NoError(t, err)
+	block, _ := pem.Decode(out)
+	require.NotNil(t, block)
+	assert.Equal(t, "PRIVATE KEY", block.Type)
+	assert.Equal(t, privDER, block.Bytes)
+}
+
+func TestParse_JWK(t *testing.T) {
+	key := testECKey(t, elliptic.P521())
+	jwk, err := json.Marshal(jose.JSONWebKey{Key: key, KeyID: "k1", Algorithm: "ECDH-ES"})
+	require.NoError(t, err)
+
+	got, err := Parse(jwk, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, FormatJWK, got.Format)
+	assert.Equal(t, "k1", got.KeyID)
+	assert.NotNil(t, got.Private)
+	alg, err := got.Algorithm()
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmECP521, alg)
+
+	_, err = Parse([]byte(`{"kty":"oct","k":"AAAA"}`), Options{})
+	require.ErrorIs(t, err, ErrUnsupportedKey)
+}
+
+func TestParse_JWKS(t *testing.T) {
+	ec := testECKey(t, elliptic.P256())
+	rsaKey, err := rsa.GenerateKey(rand.Reader, rsa2048Bits)
+	require.NoError(t, err)
+	jwks, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: ec.Public(), KeyID: "ec-1"},
+		{Key: rsaKey.Public(), KeyID: "rsa-1"},
+	}})
+	require.NoError(t, err)
+
+	got, err := Parse(jwks, Options{KeyID: "rsa-1"})
+	require.NoError(t, err)
+	assert.Equal(t, FormatJWKS, got.Format)
+	assert.Equal(t, "rsa-1", got.KeyID)
+	alg, err := got.Algorithm()
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmRSA2048, alg)
+
+	_, err = Parse(jwks, Options{})
+	require.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Contains(t, err.Error(), "ec-1, rsa-1")
+	_, err = Parse(jwks, Options{KeyID: "missing"})
+	require.ErrorIs(t, err, ErrKeyNotFound)
+
+	single, err := json.Marshal(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{{Key: ec.Public(), KeyID: "ec-1"}}})
+	require.NoError(t, err)
+	got, err = Parse(single, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "ec-1", got.KeyID)
+}
+
+func TestParse_OpenSSH(t *testing.T) {
+	key := testECKey(t, elliptic.P256())
+	sshPub, err := ssh.NewPublicKey(key.Public())
+	require.NoError(t, err)
+
+	got, err := Parse(ssh.MarshalAuthorizedKey(sshPub), Options{})
+	require.NoError(t, err)
+	assert.Equal(t, FormatOpenSSH, got.Format)
+	alg, err := got.Algorithm()
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmECP256, alg)
+
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(key, "", []byte("test"))
+	require.NoError(t, err)
+	encrypted := pem.EncodeToMemory(block)
+
+	_, err = Parse(encrypted, Options{})
+	require.Error(t, err)
+	priv, err := Parse(encrypted, Options{Password: testPassword("test")})
+	require.NoError(t, err)
+	assert.Equal(t, FormatOpenSSH, priv.Format)
+	assert.True(t, priv.Matches(got))
+}
+
+func TestParse_PKCS12(t *testing.T) {
+	bundle, err := base64.StdEncoding.DecodeString(testPKCS12)
+	require.NoError(t, err)
+
+	got, err := Parse(bundle, Options{Password: testPassword("test")})
+	require.NoError(t, err)
+	assert.Equal(t, FormatPKCS12, got.Format)
+	assert.NotNil(t, got.Private)
+	alg, err := got.Algorithm()
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmECP256, alg)
+
+	// base64 encoded bundles are accepted too
+	_, err = Parse([]byte(testPKCS12), Options{Password: testPassword("test")})
+	require.NoError(t, err)
+
+	_, err = Parse(bundle, Options{Password: testPassword("wrong")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to decrypt PKCS#12 bundle")
+	_, err = Parse(bundle, Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "password protected")
+
+	aes, err := base64.StdEncoding.DecodeString(testPKCS12AES)
+	require.NoError(t, err)
+	_, err = Parse(aes, Options{Password: testPassword("test")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "-legacy")
+}
+
+func TestParse_PKCS12Chain(t *testing.T) {
+	got, err := Parse([]byte(testPKCS12Chain), Options{Password: testPassword("test")})
+	require.NoError(t, err)
+	assert.Equal(t, FormatPKCS12, got.Format)
+	assert.NotNil(t, got.Private)
+	alg, err := got.Algorithm()
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmECP256, alg)
+}
+
+func TestParse_Unknown(t *testing.T) {
+	_, err := Parse([]byte("not a key"), Options{})
+	require.ErrorIs(t, err, ErrUnknownFormat)
+	_, err = Parse([]byte(base64.StdEncoding.EncodeToString([]byte("not a key"))), Options{})
+	require.ErrorIs(t, err, ErrUnknownFormat)
+	// the error of the last format tried is kept
+	assert.Contains(t, err.Error(), "as PKCS#12: pkcs12:")
+}
+
+func TestAlgorithm_Unsupported(t *testing.T) {
+	key := testECKey(t, elliptic.P224())
+	_, err := (&Key{Public: key.Public()}).Algorithm()
+	require.ErrorIs(t, err, ErrUnsupportedKey)
+}
@@ -0,0 +1,49 @@
+package keymaterial
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-jose/go-jose/v3"
+)
+
+// JWK algorithms a KAS wraps data keys with, by key type
+const (
+	jwkAlgorithmRSA = "RSA-OAEP"
+	jwkAlgorithmEC  = "ECDH-ES"
+	jwkUseEncrypt   = "enc"
+)
+
+// JWKS returns the public keys as a JSON Web Key Set, identified by their KeyID. Private keys are never included.
+func JWKS(keys ...*Key) ([]byte, error) {
+	set := jose.JSONWebKeySet{Keys: make([]jose.JSONWebKey, 0, len(keys))}
+	for _, k := range keys {
+		jwk := jose.JSONWebKey{Key: k.Public, KeyID: k.KeyID, Use: jwkUseEncrypt}
+		switch k.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.Algorithm = jwkAlgorithmRSA
+		case *ecdsa.PublicKey:
+			jwk.Algorithm = jwkAlgorithmEC
+		default:
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, k.Public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return json.MarshalIndent(set, "", "  ")
+}
+
+// Fingerprint is the hex SHA-256 of the DER encoded public key, the same as
+// 'openssl pkey -pubin -outform DER | sha256sum'.
+func (k *Key) Fingerprint() (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(k.Public)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnsupportedKey, err)
+	}
+	sum := sha256.Sum256(der)
+	return hex.EncodeToString(sum[:]), nil
+}
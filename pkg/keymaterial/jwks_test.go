@@ -0,0 +1,63 @@
+package keymaterial
+
+import (
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, rsa2048Bits)
+	require.NoError(t, err)
+	ec := testECKey(t, elliptic.P384())
+
+	b, err := JWKS(
+		&Key{KeyID: "rsa-1", Public: rsaKey.Public(), Private: rsaKey},
+		&Key{KeyID: "ec-1", Public: ec.Public(), Private: ec},
+	)
+	require.NoError(t, err)
+
+	var set struct {
+		Keys []map[string]any `json:"keys"`
+	}
+	require.NoError(t, json.Unmarshal(b, &set))
+	require.Len(t, set.Keys, 2)
+	assert.Equal(t, "rsa-1", set.Keys[0]["kid"])
+	assert.Equal(t, "RSA-OAEP", set.Keys[0]["alg"])
+	assert.Equal(t, "ec-1", set.Keys[1]["kid"])
+	assert.Equal(t, "ECDH-ES", set.Keys[1]["alg"])
+	for _, k := range set.Keys {
+		assert.Equal(t, "enc", k["use"])
+		assert.NotContains(t, k, "d", "private keys must not be exported")
+	}
+
+	// the set reads back through Parse
+	got, err := Parse(b, Options{KeyID: "ec-1"})
+	require.NoError(t, err)
+	assert.True(t, got.Matches(&Key{Public: ec.Public()}))
+
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	_, err = JWKS(&Key{Public: pub})
+	require.ErrorIs(t, err, ErrUnsupportedKey)
+}
+
+func TestFingerprint(t *testing.T) {
+	ec := testECKey(t, elliptic.P256())
+	der, err := x509.MarshalPKIXPublicKey(ec.Public())
+	require.NoError(t, err)
+	sum := sha256.Sum256(der)
+
+	got, err := (&Key{Public: ec.Public()}).Fingerprint()
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), got)
+}
@@ -0,0 +1,125 @@
+package keymaterial
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPEM = "-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAE\n-----END PUBLIC KEY-----"
+
+func testSource(stdin string, env map[string]string) *Source {
+	s := NewSource(strings.NewReader(stdin))
+	s.getenv = func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	return s
+}
+
+func TestRead_Inline(t *testing.T) {
+	got, err := testSource("", nil).Read("abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(got))
+}
+
+func TestRead_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, []byte(testPEM+"\n"), 0o600))
+
+	got, err := testSource("", nil).Read("@" + path)
+	require.NoError(t, err)
+	assert.Equal(t, testPEM+"\n", string(got))
+
+	_, err = testSource("", nil).Read("@")
+	require.ErrorIs(t, err, ErrInvalidReference)
+	_, err = testSource("", nil).Read("@" + filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestRead_Stdin(t *testing.T) {
+	s := testSource(testPEM, nil)
+	assert.False(t, s.StdinUsed())
+	got, err := s.Read("-")
+	require.NoError(t, err)
+	assert.Equal(t, testPEM, string(got))
+	assert.True(t, s.StdinUsed())
+
+	_, err = s.Read("-")
+	require.ErrorIs(t, err, ErrStdinUsed)
+}
+
+func TestRead_Env(t *testing.T) {
+	s := testSource("", map[string]string{"ROOT_KEY": "00ff"})
+	got, err := s.Read("env:ROOT_KEY")
+	require.NoError(t, err)
+	assert.Equal(t, "00ff", string(got))
+
+	_, err = s.Read("env:MISSING")
+	require.ErrorIs(t, err, ErrInvalidReference)
+	_, err = s.Read("env:")
+	require.ErrorIs(t, err, ErrInvalidReference)
+}
+
+func TestPEM(t *testing.T) {
+	got, err := PEM([]byte(testPEM + "\n"))
+	require.NoError(t, err)
+	assert.Equal(t, testPEM+"\n", string(got))
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(testPEM))
+	got, err = PEM([]byte(encoded))
+	require.NoError(t, err)
+	assert.Equal(t, testPEM, string(got))
+
+	// base64 files are often wrapped at 64 columns
+	got, err = PEM([]byte(encoded[:64] + "\n" + encoded[64:]))
+	require.NoError(t, err)
+	assert.Equal(t, testPEM, string(got))
+
+	_, err = PEM([]byte("not a key"))
+	require.ErrorIs(t, err, ErrInvalidPEM)
+	_, err = PEM([]byte(base64.StdEncoding.EncodeToString([]byte("not a key"))))
+	require.ErrorIs(t, err, ErrInvalidPEM)
+}
+
+func TestBase64(t *testing.T) {
+	raw := []byte{0x00, 0x01, 0xfe, 0xff}
+	encoded := base64.StdEncoding.EncodeToString(raw)
+	assert.Equal(t, encoded, Base64([]byte(encoded)))
+	assert.Equal(t, encoded, Base64(raw))
+}
+
+func TestWrappingKey(t *testing.T) {
+	// raw keys keep bytes that look like whitespace
+	key := make([]byte, aes256KeySize)
+	for i := range key {
+		key[i] = byte(i)
+	}
+	key[0], key[len(key)-1] = ' ', '\n'
+	for name, in := range map[string][]byte{
+		"hex":    []byte(hex.EncodeToString(key) + "\n"),
+		"base64": []byte(base64.StdEncoding.EncodeToString(key)),
+		"raw":    key,
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := WrappingKey(in)
+			require.NoError(t, err)
+			assert.Equal(t, key, got)
+		})
+	}
+
+	_, err := WrappingKey([]byte(hex.EncodeToString(key[:10])))
+	require.ErrorIs(t, err, ErrInvalidWrappingKey)
+}
+
+func TestZero(t *testing.T) {
+	b := []byte("secret")
+	Zero(b)
+	assert.Equal(t, make([]byte, len("secret")), b)
+}
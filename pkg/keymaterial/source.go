@@ -0,0 +1,85 @@
+// Package keymaterial reads key material given to key-bearing flags, either inline or by reference to a file, stdin
+// or an environment variable, so that keys need not appear in shell history or the process list.
+package keymaterial
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/utils"
+)
+
+// MaxSize is the most key material read from a file or stdin.
+const MaxSize = 1 << 20
+
+const (
+	filePrefix = "@"
+	envPrefix  = "env:"
+	stdinRef   = "-"
+)
+
+var (
+	ErrInvalidReference = errors.New("invalid key material reference")
+	ErrStdinUsed        = errors.New("stdin can only be read by one flag")
+)
+
+// Source resolves references to key material. Stdin can only be read once, by one flag.
+type Source struct {
+	stdin     io.Reader
+	stdinUsed bool
+	getenv    func(string) (string, bool)
+}
+
+func NewSource(stdin io.Reader) *Source {
+	return &Source{stdin: stdin, getenv: os.LookupEnv}
+}
+
+// Read resolves a flag value: '@path' reads a file, '-' reads stdin, 'env:NAME' reads an environment variable, and any
+// other value is the key material itself. Nothing is trimmed, as raw keys are binary; the decoders ignore surrounding
+// whitespace such as the trailing newline of a file. The caller owns the returned buffer and should Zero it when done.
+func (s *Source) Read(value string) ([]byte, error) {
+	switch {
+	case value == stdinRef:
+		if s.stdinUsed {
+			return nil, ErrStdinUsed
+		}
+		s.stdinUsed = true
+		b, err := io.ReadAll(io.LimitReader(s.stdin, MaxSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key material from stdin: %w", err)
+		}
+		if len(b) > MaxSize {
+			Zero(b)
+			return nil, fmt.Errorf("%w: stdin exceeds %d bytes", ErrInvalidReference, MaxSize)
+		}
+		return b, nil
+	case strings.HasPrefix(value, filePrefix):
+		path := strings.TrimPrefix(value, filePrefix)
+		if path == "" {
+			return nil, fmt.Errorf("%w: '@' must be followed by a file path", ErrInvalidReference)
+		}
+		return utils.ReadBytesFromFile(path, MaxSize)
+	case strings.HasPrefix(value, envPrefix):
+		name := strings.TrimPrefix(value, envPrefix)
+		v, ok := s.getenv(name)
+		if name == "" || !ok {
+			return nil, fmt.Errorf("%w: environment variable '%s' is not set", ErrInvalidReference, name)
+		}
+		return []byte(v), nil
+	default:
+		return []byte(value), nil
+	}
+}
+
+// StdinUsed reports whether a flag was read from stdin, which then cannot answer a prompt.
+func (s *Source) StdinUsed() bool {
+	return s.stdinUsed
+}
+
+// Zero overwrites key material once it is no longer needed.
+func Zero(b []byte) {
+	clear(b)
+}
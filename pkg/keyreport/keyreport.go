@@ -0,0 +1,220 @@
+// Package keyreport builds an inventory of the keys of every registered KAS, with their age and what they protect,
+// and flags keys that are past a maximum age or use an algorithm weaker than a minimum security strength.
+package keyreport
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RuleMaxAge        = "max-age"
+	RuleWeakAlgorithm = "weak-algorithm"
+)
+
+const (
+	StatusActive = "active"
+
+	day  = 24 * time.Hour
+	week = 7 * day
+	year = 365 * day
+)
+
+var ErrInvalidAge = errors.New("invalid age")
+
+// strengths are the bits of security of each algorithm, per NIST SP 800-57 Part 1. RSA-4096 falls between the 128
+// bits of RSA-3072 and the 192 bits of RSA-7680.
+var strengths = map[string]int{
+	"rsa:2048":     112,
+	"rsa:4096":     140,
+	"ec:secp256r1": 128,
+	"ec:secp384r1": 192,
+	"ec:secp521r1": 256,
+}
+
+// Strength is the bits of security of an algorithm, and whether the algorithm is known.
+func Strength(algorithm string) (int, bool) {
+	bits, ok := strengths[algorithm]
+	return bits, ok
+}
+
+// Finding is a reason a key needs attention.
+type Finding struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Key is one KAS key and the number of namespaces, attributes and values mapped to it.
+type Key struct {
+	KasID      string     `json:"kas_id"`
+	KasURI     string     `json:"kas_uri"`
+	KasName    string     `json:"kas_name,omitempty"`
+	ID         string     `json:"id"`
+	KeyID      string     `json:"key_id"`
+	Algorithm  string     `json:"algorithm"`
+	Mode       string     `json:"mode"`
+	Status     string     `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	AgeDays    int        `json:"age_days"`
+	RotatedAt  *time.Time `json:"rotated_at,omitempty"`
+	RotatedTo  string     `json:"rotated_to,omitempty"`
+	Legacy     bool       `json:"legacy"`
+	Base       bool       `json:"base"`
+	Namespaces int        `json:"namespaces"`
+	Attributes int        `json:"attributes"`
+	Values     int        `json:"values"`
+	Findings   []Finding  `json:"findings,omitempty"`
+}
+
+type Options struct {
+	// MaxAge flags active keys created longer ago, unless zero
+	MaxAge time.Duration
+	// MinStrength flags keys whose algorithm gives fewer bits of security, unless zero
+	MinStrength int
+	Now         time.Time
+}
+
+type Report struct {
+	GeneratedAt    time.Time      `json:"generated_at"`
+	MaxAgeDays     int            `json:"max_age_days,omitempty"`
+	MinStrength    int            `json:"min_strength,omitempty"`
+	Keys           []Key          `json:"keys"`
+	Flagged        int            `json:"flagged"`
+	FindingsByRule map[string]int `json:"findings_by_rule"`
+}
+
+// Build works out the age and findings of every key, ordered by KAS, then active keys before rotated ones, then
+// oldest first.
+func Build(keys []Key, opts Options) Report {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	r := Report{
+		GeneratedAt:    opts.Now.UTC(),
+		MaxAgeDays:     int(opts.MaxAge / day),
+		MinStrength:    opts.MinStrength,
+		Keys:           make([]Key, 0, len(keys)),
+		FindingsByRule: map[string]int{},
+	}
+	for _, k := range keys {
+		k.Findings = nil
+		if !k.CreatedAt.IsZero() {
+			k.AgeDays = int(opts.Now.Sub(k.CreatedAt) / day)
+		}
+		// a rotated key only decrypts what it already protects, so its age is expected
+		if opts.MaxAge > 0 && k.Status == StatusActive && !k.CreatedAt.IsZero() && opts.Now.Sub(k.CreatedAt) > opts.MaxAge {
+			k.Findings = append(k.Findings, Finding{
+				Rule:    RuleMaxAge,
+				Message: fmt.Sprintf("active for %d days, over the maximum of %d", k.AgeDays, r.MaxAgeDays),
+			})
+		}
+		if opts.MinStrength > 0 {
+			bits, known := Strength(k.Algorithm)
+			switch {
+			case !known:
+				k.Findings = append(k.Findings, Finding{
+					Rule:    RuleWeakAlgorithm,
+					Message: fmt.Sprintf("unknown algorithm '%s'", k.Algorithm),
+				})
+			case bits < opts.MinStrength:
+				k.Findings = append(k.Findings, Finding{
+					Rule:    RuleWeakAlgorithm,
+					Message: fmt.Sprintf("%s gives %d bits of security, under the minimum of %d", k.Algorithm, bits, opts.MinStrength),
+				})
+			}
+		}
+		for _, f := range k.Findings {
+			r.FindingsByRule[f.Rule]++
+		}
+		if len(k.Findings) > 0 {
+			r.Flagged++
+		}
+		r.Keys = append(r.Keys, k)
+	}
+	sort.SliceStable(r.Keys, func(i, j int) bool {
+		a, b := r.Keys[i], r.Keys[j]
+		if a.KasURI != b.KasURI {
+			return a.KasURI < b.KasURI
+		}
+		if (a.Status == StatusActive) != (b.Status == StatusActive) {
+			return a.Status == StatusActive
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+	return r
+}
+
+// FlaggedOnly keeps the keys with findings.
+func (r Report) FlaggedOnly() Report {
+	keys := make([]Key, 0, r.Flagged)
+	for _, k := range r.Keys {
+		if len(k.Findings) > 0 {
+			keys = append(keys, k)
+		}
+	}
+	r.Keys = keys
+	return r
+}
+
+// ParseAge reads an age as a number of days (d), weeks (w) or years (y) of 365 days, or as a Go duration such as
+// 720h.
+func ParseAge(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("%w: empty", ErrInvalidAge)
+	}
+	units := map[byte]time.Duration{'d': day, 'w': week, 'y': year}
+	if unit, ok := units[s[len(s)-1]]; ok {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%w: '%s'", ErrInvalidAge, s)
+		}
+		return time.Duration(n) * unit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%w: '%s', use a number of days (90d), weeks (12w), years (1y) or hours (720h)", ErrInvalidAge, s)
+	}
+	return d, nil
+}
+
+var csvHeader = []string{
+	"kas_uri", "kas_name", "id", "key_id", "algorithm", "mode", "status", "created_at", "age_days", "rotated_at",
+	"rotated_to", "legacy", "base", "namespaces", "attributes", "values", "findings",
+}
+
+// WriteCSV writes one row per key. Times are RFC 3339 and findings are joined with '; '.
+func WriteCSV(w io.Writer, r Report) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, k := range r.Keys {
+		var created, rotated string
+		if !k.CreatedAt.IsZero() {
+			created = k.CreatedAt.UTC().Format(time.RFC3339)
+		}
+		if k.RotatedAt != nil {
+			rotated = k.RotatedAt.UTC().Format(time.RFC3339)
+		}
+		findings := make([]string, 0, len(k.Findings))
+		for _, f := range k.Findings {
+			findings = append(findings, f.Rule+": "+f.Message)
+		}
+		if err := cw.Write([]string{
+			k.KasURI, k.KasName, k.ID, k.KeyID, k.Algorithm, k.Mode, k.Status, created, strconv.Itoa(k.AgeDays), rotated,
+			k.RotatedTo, strconv.FormatBool(k.Legacy), strconv.FormatBool(k.Base),
+			strconv.Itoa(k.Namespaces), strconv.Itoa(k.Attributes), strconv.Itoa(k.Values), strings.Join(findings, "; "),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,116 @@
+package keyreport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(n int) time.Time {
+	return now.Add(-time.Duration(n) * day)
+}
+
+func findingRules(k Key) []string {
+	var rules []string
+	for _, f := range k.Findings {
+		rules = append(rules, f.Rule)
+	}
+	return rules
+}
+
+func TestBuild(t *testing.T) {
+	keys := []Key{
+		{KasURI: "https://b.example.com", KeyID: "b-new", Algorithm: "ec:secp256r1", Status: StatusActive, CreatedAt: daysAgo(10)},
+		{KasURI: "https://a.example.com", KeyID: "a-rotated", Algorithm: "rsa:2048", Status: "rotated", CreatedAt: daysAgo(800)},
+		{KasURI: "https://a.example.com", KeyID: "a-old", Algorithm: "rsa:2048", Status: StatusActive, CreatedAt: daysAgo(400)},
+		{KasURI: "https://a.example.com", KeyID: "a-strong", Algorithm: "ec:secp384r1", Status: StatusActive, CreatedAt: daysAgo(30)},
+	}
+
+	r := Build(keys, Options{MaxAge: year, MinStrength: 128, Now: now})
+
+	var order []string
+	for _, k := range r.Keys {
+		order = append(order, k.KeyID)
+	}
+	assert.Equal(t, []string{"a-old", "a-strong", "a-rotated", "b-new"}, order,
+		"by KAS, active before rotated, oldest first")
+
+	byID := map[string]Key{}
+	for _, k := range r.Keys {
+		byID[k.KeyID] = k
+	}
+	assert.Equal(t, 400, byID["a-old"].AgeDays)
+	assert.Equal(t, []string{RuleMaxAge, RuleWeakAlgorithm}, findingRules(byID["a-old"]))
+	assert.Equal(t, []string{RuleWeakAlgorithm}, findingRules(byID["a-rotated"]), "rotated keys are not flagged for age")
+	assert.Empty(t, byID["a-strong"].Findings)
+	assert.Empty(t, byID["b-new"].Findings)
+
+	assert.Equal(t, 2, r.Flagged)
+	assert.Equal(t, map[string]int{RuleMaxAge: 1, RuleWeakAlgorithm: 2}, r.FindingsByRule)
+	assert.Equal(t, 365, r.MaxAgeDays)
+
+	flagged := r.FlaggedOnly()
+	assert.Len(t, flagged.Keys, 2)
+	assert.Len(t, r.Keys, 4, "filtering leaves the report as it was")
+}
+
+func TestBuild_NoLimits(t *testing.T) {
+	r := Build([]Key{{KeyID: "k", Algorithm: "rsa:2048", Status: StatusActive, CreatedAt: daysAgo(5000)}}, Options{Now: now})
+	assert.Empty(t, r.Keys[0].Findings)
+	assert.Zero(t, r.Flagged)
+}
+
+func TestBuild_UnknownAlgorithm(t *testing.T) {
+	r := Build([]Key{{KeyID: "k", Status: StatusActive}}, Options{MinStrength: 112, Now: now})
+	require.Len(t, r.Keys[0].Findings, 1)
+	assert.Equal(t, RuleWeakAlgorithm, r.Keys[0].Findings[0].Rule)
+	assert.Zero(t, r.Keys[0].AgeDays, "a key without a creation time has no age")
+}
+
+func TestParseAge(t *testing.T) {
+	for in, want := range map[string]time.Duration{
+		"90d":  90 * day,
+		"12w":  12 * week,
+		"1y":   year,
+		"720h": 720 * time.Hour,
+		" 2y ": 2 * year,
+	} {
+		got, err := ParseAge(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "d", "-1d", "1.5y", "soon"} {
+		_, err := ParseAge(in)
+		require.ErrorIs(t, err, ErrInvalidAge, in)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	rotated := daysAgo(1)
+	r := Build([]Key{
+		{
+			KasURI: "https://kas.example.com", KasName: "main", ID: "id-1", KeyID: "k1", Algorithm: "rsa:2048",
+			Mode: "local", Status: "rotated", CreatedAt: daysAgo(2), RotatedAt: &rotated, RotatedTo: "k2",
+			Legacy: true, Base: false, Namespaces: 1, Attributes: 2, Values: 3,
+		},
+	}, Options{MinStrength: 128, Now: now})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, r))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{
+		"https://kas.example.com", "main", "id-1", "k1", "rsa:2048", "local", "rotated", "2026-05-30T12:00:00Z", "2",
+		"2026-05-31T12:00:00Z", "k2", "true", "false", "1", "2", "3",
+		"weak-algorithm: rsa:2048 gives 112 bits of security, under the minimum of 128",
+	}, records[1])
+}
@@ -0,0 +1,287 @@
+// Package labels plans and applies metadata label changes across many policy objects at once.
+package labels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/common"
+)
+
+const (
+	KindNamespace               = "namespace"
+	KindAttribute               = "attribute"
+	KindValue                   = "value"
+	KindSubjectMapping          = "subject-mapping"
+	KindSubjectConditionSet     = "subject-condition-set"
+	KindAction                  = "action"
+	KindObligation              = "obligation"
+	KindObligationValue         = "obligation-value"
+	KindKeyAccessServer         = "kas"
+	KindKasKey                  = "kas-key"
+	KindRegisteredResource      = "registered-resource"
+	KindRegisteredResourceValue = "registered-resource-value"
+)
+
+// Kinds lists every kind of object whose labels can be managed.
+var Kinds = []string{
+	KindNamespace, KindAttribute, KindValue, KindSubjectMapping, KindSubjectConditionSet, KindAction,
+	KindObligation, KindObligationValue, KindKeyAccessServer, KindKasKey, KindRegisteredResource,
+	KindRegisteredResourceValue,
+}
+
+var (
+	ErrInvalidSelector  = errors.New("invalid label selector")
+	ErrInvalidOperation = errors.New("invalid label operation")
+	ErrLabelExists      = errors.New("label already exists")
+)
+
+// Object is a labeled policy object. Name is its FQN, or its URI, name or key ID when it has no FQN.
+type Object struct {
+	Kind      string            `json:"kind"`
+	ID        string            `json:"id"`
+	Name      string            `json:"name,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+// Objects lists every labeled object of a policy snapshot.
+//
+//nolint:funlen // a flat walk over each object type
+func Objects(s *handlers.PolicySnapshot) []Object {
+	var objs []Object
+	add := func(kind, id, name, namespace string, md *common.Metadata) {
+		objs = append(objs, Object{Kind: kind, ID: id, Name: name, Namespace: namespace, Labels: md.GetLabels()})
+	}
+
+	for _, ns := range s.Namespaces {
+		add(KindNamespace, ns.GetId(), ns.GetFqn(), ns.GetFqn(), ns.GetMetadata())
+	}
+	for _, a := range s.Attributes {
+		ns := a.GetNamespace().GetFqn()
+		add(KindAttribute, a.GetId(), a.GetFqn(), ns, a.GetMetadata())
+		for _, v := range a.GetValues() {
+			add(KindValue, v.GetId(), v.GetFqn(), ns, v.GetMetadata())
+		}
+	}
+	for _, sm := range s.SubjectMappings {
+		add(KindSubjectMapping, sm.GetId(), sm.GetAttributeValue().GetFqn(), sm.GetNamespace().GetFqn(), sm.GetMetadata())
+	}
+	for _, scs := range s.SubjectConditionSets {
+		add(KindSubjectConditionSet, scs.GetId(), "", scs.GetNamespace().GetFqn(), scs.GetMetadata())
+	}
+	for _, a := range s.Actions {
+		add(KindAction, a.GetId(), a.GetName(), a.GetNamespace().GetFqn(), a.GetMetadata())
+	}
+	for _, o := range s.Obligations {
+		ns := o.GetNamespace().GetFqn()
+		add(KindObligation, o.GetId(), o.GetFqn(), ns, o.GetMetadata())
+		for _, v := range o.GetValues() {
+			add(KindObligationValue, v.GetId(), v.GetFqn(), ns, v.GetMetadata())
+		}
+	}
+	for _, kas := range s.KeyAccessServers {
+		add(KindKeyAccessServer, kas.GetId(), kas.GetUri(), "", kas.GetMetadata())
+	}
+	for _, k := range s.KasKeys {
+		add(KindKasKey, k.GetKey().GetId(), k.GetKasUri()+"#"+k.GetKey().GetKeyId(), "", k.GetKey().GetMetadata())
+	}
+	for _, r := range s.RegisteredResources {
+		ns := r.GetNamespace().GetFqn()
+		add(KindRegisteredResource, r.GetId(), r.GetName(), ns, r.GetMetadata())
+		for _, v := range r.GetValues() {
+			add(KindRegisteredResourceValue, v.GetId(), r.GetName()+"/"+v.GetValue(), ns, v.GetMetadata())
+		}
+	}
+	return objs
+}
+
+// Selector matches objects. Empty fields match everything.
+type Selector struct {
+	Kinds     []string
+	Namespace string
+	// Glob matches the object's name, where '*' matches any characters (including '/') and '?' any one character
+	Glob string
+	// Labels must all be present; an empty value only requires the key
+	Labels map[string]string
+
+	glob *regexp.Regexp
+}
+
+// NewSelector validates a selector. Labels are given as 'key' or 'key=value'.
+func NewSelector(kinds []string, namespace, glob string, labels []string) (*Selector, error) {
+	s := &Selector{Namespace: namespace, Glob: glob, Labels: map[string]string{}}
+	for _, k := range kinds {
+		if !slices.Contains(Kinds, k) {
+			return nil, fmt.Errorf("%w: unknown object type '%s'", ErrInvalidSelector, k)
+		}
+		s.Kinds = append(s.Kinds, k)
+	}
+	for _, l := range labels {
+		k, v, _ := strings.Cut(l, "=")
+		if k == "" {
+			return nil, fmt.Errorf("%w: invalid label '%s'", ErrInvalidSelector, l)
+		}
+		s.Labels[k] = v
+	}
+	if glob != "" {
+		pattern := regexp.QuoteMeta(glob)
+		pattern = strings.ReplaceAll(pattern, `\*`, ".*")
+		pattern = strings.ReplaceAll(pattern, `\?`, ".")
+		s.glob = regexp.MustCompile("(?i)^" + pattern + "$")
+	}
+	return s, nil
+}
+
+// IsEmpty reports whether the selector matches every object.
+func (s *Selector) IsEmpty() bool {
+	return len(s.Kinds) == 0 && s.Namespace == "" && s.Glob == "" && len(s.Labels) == 0
+}
+
+func (s *Selector) Matches(o Object) bool {
+	if len(s.Kinds) > 0 && !slices.Contains(s.Kinds, o.Kind) {
+		return false
+	}
+	if s.Namespace != "" && !strings.EqualFold(strings.TrimSuffix(s.Namespace, "/"), o.Namespace) &&
+		!strings.EqualFold("https://"+s.Namespace, o.Namespace) {
+		return false
+	}
+	if s.glob != nil && !s.glob.MatchString(o.Name) {
+		return false
+	}
+	for k, v := range s.Labels {
+		got, ok := o.Labels[k]
+		if !ok || (v != "" && got != v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Operation changes a set of labels.
+type Operation struct {
+	// Set adds or overwrites labels
+	Set map[string]string
+	// Remove deletes label keys
+	Remove []string
+	// Rename moves the value of a label key to a new key
+	Rename map[string]string
+}
+
+// Apply returns the labels after the operation, and whether they differ from the input. Renames run in the sorted
+// order of their keys, and a rename onto a key the labels already have is rejected rather than overwriting it.
+func (op Operation) Apply(labels map[string]string) (map[string]string, bool, error) {
+	out := maps.Clone(labels)
+	if out == nil {
+		out = map[string]string{}
+	}
+	for _, from := range slices.Sorted(maps.Keys(op.Rename)) {
+		to := op.Rename[from]
+		v, ok := out[from]
+		if !ok || from == to {
+			continue
+		}
+		if _, exists := out[to]; exists {
+			return labels, false, fmt.Errorf("%w: cannot rename '%s' to '%s'", ErrLabelExists, from, to)
+		}
+		delete(out, from)
+		out[to] = v
+	}
+	for _, k := range op.Remove {
+		delete(out, k)
+	}
+	for k, v := range op.Set {
+		out[k] = v
+	}
+	return out, !maps.Equal(labels, out), nil
+}
+
+func (op Operation) Validate() error {
+	if len(op.Set)+len(op.Remove)+len(op.Rename) == 0 {
+		return fmt.Errorf("%w: nothing to add, remove or rename", ErrInvalidOperation)
+	}
+	for from, to := range op.Rename {
+		if from == "" || to == "" {
+			return fmt.Errorf("%w: rename needs a key and a new key", ErrInvalidOperation)
+		}
+	}
+	return nil
+}
+
+// Change is the planned update of one object's labels.
+type Change struct {
+	Object
+	After map[string]string `json:"after"`
+	Error string            `json:"error,omitempty"`
+}
+
+// Plan selects the objects whose labels the operation would change. Objects the operation cannot be applied to are
+// planned with their error and their labels unchanged.
+func Plan(objs []Object, sel *Selector, op Operation) []Change {
+	var changes []Change
+	for _, o := range objs {
+		if !sel.Matches(o) {
+			continue
+		}
+		after, changed, err := op.Apply(o.Labels)
+		switch {
+		case err != nil:
+			changes = append(changes, Change{Object: o, After: o.Labels, Error: err.Error()})
+		case changed:
+			changes = append(changes, Change{Object: o, After: after})
+		}
+	}
+	return changes
+}
+
+// Rejected counts the planned changes that already failed when planning.
+func Rejected(changes []Change) int {
+	n := 0
+	for _, c := range changes {
+		if c.Error != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// Apply runs update for each change with at most concurrency updates in flight, recording failures on the changes.
+// Changes rejected when planning are not updated. It returns the number of failed or rejected changes.
+func Apply(ctx context.Context, changes []Change, concurrency int, update func(context.Context, Change) error) int {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed int
+		sem    = make(chan struct{}, concurrency)
+	)
+	for i := range changes {
+		if changes[i].Error != "" {
+			failed++
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(c *Change) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := update(ctx, *c); err != nil {
+				mu.Lock()
+				c.Error = err.Error()
+				failed++
+				mu.Unlock()
+			}
+		}(&changes[i])
+	}
+	wg.Wait()
+	return failed
+}
@@ -0,0 +1,164 @@
+package labels
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var objects = []Object{
+	{Kind: KindNamespace, ID: "ns", Name: "https://example.com", Namespace: "https://example.com"},
+	{Kind: KindAttribute, ID: "a", Name: "https://example.com/attr/level", Namespace: "https://example.com", Labels: map[string]string{"team": "a"}},
+	{Kind: KindValue, ID: "v1", Name: "https://example.com/attr/level/value/high", Namespace: "https://example.com", Labels: map[string]string{"team": "a", "env": "prod"}},
+	{Kind: KindValue, ID: "v2", Name: "https://other.com/attr/level/value/high", Namespace: "https://other.com", Labels: map[string]string{"team": "b"}},
+	{Kind: KindKeyAccessServer, ID: "kas", Name: "https://kas.example.com"},
+}
+
+func selected(t *testing.T, sel *Selector) []string {
+	t.Helper()
+	var ids []string
+	for _, o := range objects {
+		if sel.Matches(o) {
+			ids = append(ids, o.ID)
+		}
+	}
+	return ids
+}
+
+func TestSelector(t *testing.T) {
+	tests := []struct {
+		name      string
+		kinds     []string
+		namespace string
+		glob      string
+		labels    []string
+		want      []string
+	}{
+		{name: "everything", want: []string{"ns", "a", "v1", "v2", "kas"}},
+		{name: "kind", kinds: []string{KindValue, KindKeyAccessServer}, want: []string{"v1", "v2", "kas"}},
+		{name: "namespace fqn", namespace: "https://example.com", want: []string{"ns", "a", "v1"}},
+		{name: "namespace name", namespace: "other.com", want: []string{"v2"}},
+		{name: "glob crosses segments", glob: "https://*/value/high", want: []string{"v1", "v2"}},
+		{name: "glob is case-insensitive", glob: "HTTPS://EXAMPLE.COM/attr/*", want: []string{"a", "v1"}},
+		{name: "label key", labels: []string{"env"}, want: []string{"v1"}},
+		{name: "label value", labels: []string{"team=a"}, want: []string{"a", "v1"}},
+		{name: "combined", kinds: []string{KindValue}, labels: []string{"team"}, glob: "*example*", want: []string{"v1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sel, err := NewSelector(tt.kinds, tt.namespace, tt.glob, tt.labels)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, selected(t, sel))
+		})
+	}
+}
+
+func TestSelectorInvalid(t *testing.T) {
+	_, err := NewSelector([]string{"widget"}, "", "", nil)
+	require.ErrorIs(t, err, ErrInvalidSelector)
+
+	_, err = NewSelector(nil, "", "", []string{"=x"})
+	require.ErrorIs(t, err, ErrInvalidSelector)
+}
+
+func TestOperationApply(t *testing.T) {
+	in := map[string]string{"team": "a", "env": "prod"}
+
+	out, changed, err := Operation{Set: map[string]string{"owner": "x"}}.Apply(in)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"team": "a", "env": "prod", "owner": "x"}, out)
+	assert.Len(t, in, 2, "input labels must not be modified")
+
+	_, changed, _ = Operation{Set: map[string]string{"team": "a"}}.Apply(in)
+	assert.False(t, changed)
+
+	out, changed, _ = Operation{Remove: []string{"env", "missing"}}.Apply(in)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"team": "a"}, out)
+
+	out, changed, _ = Operation{Rename: map[string]string{"team": "owner"}}.Apply(in)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{"owner": "a", "env": "prod"}, out)
+
+	_, changed, _ = Operation{Rename: map[string]string{"missing": "owner"}}.Apply(in)
+	assert.False(t, changed)
+}
+
+func TestOperationApplyRenameCollision(t *testing.T) {
+	in := map[string]string{"team": "a", "owner": "b"}
+
+	out, changed, err := Operation{Rename: map[string]string{"team": "owner"}}.Apply(in)
+	require.ErrorIs(t, err, ErrLabelExists)
+	assert.False(t, changed)
+	assert.Equal(t, in, out)
+
+	// renames run in sorted order, so moving owner away first frees the key for team
+	out, _, err = Operation{Rename: map[string]string{"owner": "lead", "team": "owner"}}.Apply(in)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "a", "lead": "b"}, out)
+
+	_, _, err = Operation{Rename: map[string]string{"env": "team", "owner": "team"}}.Apply(map[string]string{"env": "x", "owner": "y"})
+	require.ErrorIs(t, err, ErrLabelExists, "two keys renamed onto the same key")
+}
+
+func TestOperationValidate(t *testing.T) {
+	require.ErrorIs(t, Operation{}.Validate(), ErrInvalidOperation)
+	require.ErrorIs(t, Operation{Rename: map[string]string{"team": ""}}.Validate(), ErrInvalidOperation)
+	require.NoError(t, Operation{Remove: []string{"team"}}.Validate())
+}
+
+func TestPlan(t *testing.T) {
+	sel, err := NewSelector(nil, "", "", []string{"team"})
+	require.NoError(t, err)
+
+	changes := Plan(objects, sel, Operation{Set: map[string]string{"team": "a"}})
+	require.Len(t, changes, 1, "only objects whose labels change are planned")
+	assert.Equal(t, "v2", changes[0].ID)
+	assert.Equal(t, map[string]string{"team": "b"}, changes[0].Labels)
+	assert.Equal(t, map[string]string{"team": "a"}, changes[0].After)
+
+	changes = Plan(objects, sel, Operation{Rename: map[string]string{"env": "team"}})
+	require.Len(t, changes, 1, "a colliding rename is planned as rejected")
+	assert.Equal(t, "v1", changes[0].ID)
+	assert.Equal(t, changes[0].Labels, changes[0].After)
+	assert.Equal(t, 1, Rejected(changes))
+
+	failed := Apply(context.Background(), changes, 1, func(context.Context, Change) error {
+		t.Fatal("a rejected change must not be updated")
+		return nil
+	})
+	assert.Equal(t, 1, failed)
+}
+
+func TestApply(t *testing.T) {
+	changes := make([]Change, 20)
+	for i := range changes {
+		changes[i].ID = string(rune('a' + i))
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	failed := Apply(context.Background(), changes, 3, func(_ context.Context, c Change) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		if c.ID == "c" {
+			return errors.New("boom")
+		}
+		return nil
+	})
+
+	assert.Equal(t, 1, failed)
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	assert.Equal(t, "boom", changes[2].Error)
+	assert.Empty(t, changes[0].Error)
+}
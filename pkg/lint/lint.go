@@ -0,0 +1,506 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// Rank orders severities from most to least severe, unknown severities rank last
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityError:
+		return 0
+	case SeverityWarning:
+		return 1
+	case SeverityInfo:
+		return 2 //nolint:mnd // severity rank
+	}
+	return 3 //nolint:mnd // severity rank
+}
+
+// AtLeast reports whether s is as severe as or more severe than threshold.
+func (s Severity) AtLeast(threshold Severity) bool {
+	return s.Rank() <= threshold.Rank()
+}
+
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(strings.ToLower(s)); sev {
+	case SeverityError, SeverityWarning, SeverityInfo:
+		return sev, nil
+	}
+	return "", fmt.Errorf("unknown severity %q, expected one of %s, %s, %s", s, SeverityError, SeverityWarning, SeverityInfo)
+}
+
+const (
+	RuleAttributeNoValues         = "attribute-no-values"
+	RuleValueNoSubjectMappings    = "value-no-subject-mappings"
+	RuleHierarchyOrderInverted    = "hierarchy-order-inverted"
+	RuleConditionSetDuplicate     = "condition-set-duplicate"
+	RuleConditionSetContradictory = "condition-set-contradictory"
+	RuleConditionSetAlwaysTrue    = "condition-set-always-true"
+	RuleSubjectMappingInactive    = "subject-mapping-inactive-value"
+	RuleObligationValueNoTriggers = "obligation-value-no-triggers"
+	RuleKasNoKeys                 = "kas-no-keys"
+	RuleNoKeyAssignedWithoutBase  = "no-key-without-base-key"
+)
+
+const (
+	ObjectKindNamespace           = "namespace"
+	ObjectKindAttribute           = "attribute"
+	ObjectKindValue               = "value"
+	ObjectKindSubjectMapping      = "subject-mapping"
+	ObjectKindSubjectConditionSet = "subject-condition-set"
+	ObjectKindObligationValue     = "obligation-value"
+	ObjectKindKeyAccessServer     = "kas"
+)
+
+// Rules lists every rule with its description.
+var Rules = []struct {
+	Name        string
+	Description string
+}{
+	{RuleAttributeNoValues, "attribute has no values"},
+	{RuleValueNoSubjectMappings, "active attribute value has no subject mappings, so no subject can be entitled to it"},
+	{RuleHierarchyOrderInverted, "HIERARCHY attribute values are mapped to broader subjects toward the top, suggesting the value order is inverted"},
+	{RuleConditionSetDuplicate, "subject condition sets have identical conditions"},
+	{RuleConditionSetContradictory, "condition group uses IN and NOT_IN with the same values on a selector and can never match"},
+	{RuleConditionSetAlwaysTrue, "OR condition group uses IN and NOT_IN with the same values on a selector and always matches"},
+	{RuleSubjectMappingInactive, "subject mapping points at a deactivated value, attribute or namespace"},
+	{RuleObligationValueNoTriggers, "obligation value has no triggers"},
+	{RuleKasNoKeys, "registered KAS has no keys"},
+	{RuleNoKeyAssignedWithoutBase, "namespace or attribute has no key assigned while no base key is set"},
+}
+
+// ObjectRef identifies the policy object a finding refers to.
+type ObjectRef struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+func (o ObjectRef) String() string {
+	if o.Name != "" {
+		return fmt.Sprintf("%s %s", o.Kind, o.Name)
+	}
+	return fmt.Sprintf("%s %s", o.Kind, o.ID)
+}
+
+type Finding struct {
+	Rule     string      `json:"rule"`
+	Severity Severity    `json:"severity"`
+	Object   ObjectRef   `json:"object"`
+	Related  []ObjectRef `json:"related,omitempty"`
+	Message  string      `json:"message"`
+}
+
+type Options struct {
+	// Ignore drops findings of the named rules
+	Ignore []string
+}
+
+// Run evaluates every rule against the snapshot and returns the findings ordered by severity, rule and object.
+func Run(s *handlers.PolicySnapshot, opts Options) []Finding {
+	l := newLinter(s)
+	l.attributesWithoutValues()
+	l.valuesWithoutSubjectMappings()
+	l.hierarchyOrder()
+	l.duplicateConditionSets()
+	l.conditionSetContradictions()
+	l.inactiveSubjectMappings()
+	l.obligationValuesWithoutTriggers()
+	l.kasWithoutKeys()
+	l.missingKeys()
+
+	findings := make([]Finding, 0, len(l.findings))
+	for _, f := range l.findings {
+		if !slices.Contains(opts.Ignore, f.Rule) {
+			findings = append(findings, f)
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.Severity.Rank() != b.Severity.Rank() {
+			return a.Severity.Rank() < b.Severity.Rank()
+		}
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		return a.Object.String() < b.Object.String()
+	})
+	return findings
+}
+
+// Summarize counts findings per severity.
+func Summarize(findings []Finding) map[Severity]int {
+	counts := map[Severity]int{SeverityError: 0, SeverityWarning: 0, SeverityInfo: 0}
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
+
+// Exceeds reports whether any finding is at or above the threshold severity.
+func Exceeds(findings []Finding, threshold Severity) bool {
+	for _, f := range findings {
+		if f.Severity.AtLeast(threshold) {
+			return true
+		}
+	}
+	return false
+}
+
+type linter struct {
+	s        *handlers.PolicySnapshot
+	findings []Finding
+
+	namespaces map[string]*policy.Namespace
+	values     map[string]*policy.Value
+	attrOf     map[string]*policy.Attribute
+	mappings   map[string][]*policy.SubjectMapping
+}
+
+func newLinter(s *handlers.PolicySnapshot) *linter {
+	l := &linter{
+		s:          s,
+		namespaces: map[string]*policy.Namespace{},
+		values:     map[string]*policy.Value{},
+		attrOf:     map[string]*policy.Attribute{},
+		mappings:   map[string][]*policy.SubjectMapping{},
+	}
+	for _, ns := range s.Namespaces {
+		l.namespaces[ns.GetId()] = ns
+	}
+	for _, a := range s.Attributes {
+		for _, v := range a.GetValues() {
+			l.values[v.GetId()] = v
+			l.attrOf[v.GetId()] = a
+		}
+	}
+	for _, sm := range s.SubjectMappings {
+		id := sm.GetAttributeValue().GetId()
+		l.mappings[id] = append(l.mappings[id], sm)
+	}
+	return l
+}
+
+func (l *linter) add(rule string, sev Severity, obj ObjectRef, msg string, related ...ObjectRef) {
+	l.findings = append(l.findings, Finding{Rule: rule, Severity: sev, Object: obj, Related: related, Message: msg})
+}
+
+// isActive treats an unset active flag as active, as older platforms omit it
+func isActive(b *wrapperspb.BoolValue) bool {
+	return b == nil || b.GetValue()
+}
+
+func (l *linter) attributeActive(a *policy.Attribute) bool {
+	if !isActive(a.GetActive()) {
+		return false
+	}
+	if ns, ok := l.namespaces[a.GetNamespace().GetId()]; ok {
+		return isActive(ns.GetActive())
+	}
+	return isActive(a.GetNamespace().GetActive())
+}
+
+func attributeRef(a *policy.Attribute) ObjectRef {
+	return ObjectRef{Kind: ObjectKindAttribute, ID: a.GetId(), Name: a.GetFqn()}
+}
+
+func valueRef(v *policy.Value) ObjectRef {
+	return ObjectRef{Kind: ObjectKindValue, ID: v.GetId(), Name: v.GetFqn()}
+}
+
+func scsRef(scs *policy.SubjectConditionSet) ObjectRef {
+	return ObjectRef{Kind: ObjectKindSubjectConditionSet, ID: scs.GetId()}
+}
+
+func (l *linter) attributesWithoutValues() {
+	for _, a := range l.s.Attributes {
+		if len(a.GetValues()) == 0 {
+			l.add(RuleAttributeNoValues, SeverityWarning, attributeRef(a), "attribute has no values and cannot be used in a TDF")
+		}
+	}
+}
+
+func (l *linter) valuesWithoutSubjectMappings() {
+	for _, a := range l.s.Attributes {
+		if !l.attributeActive(a) {
+			continue
+		}
+		for _, v := range a.GetValues() {
+			if isActive(v.GetActive()) && len(l.mappings[v.GetId()]) == 0 {
+				l.add(RuleValueNoSubjectMappings, SeverityWarning, valueRef(v), "no subject mappings entitle any subject to this value")
+			}
+		}
+	}
+}
+
+// hierarchyOrder flags HIERARCHY attributes where mapped subjects broaden toward the top of the hierarchy. Since
+// the top value also grants every lower value, it is usually mapped to the narrowest set of subjects; if every step
+// up broadens the mapped subjects the values were likely created lowest-first.
+func (l *linter) hierarchyOrder() {
+	for _, a := range l.s.Attributes {
+		if a.GetRule() != policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY {
+			continue
+		}
+		var (
+			breadths []int
+			mapped   []string
+		)
+		for _, v := range a.GetValues() {
+			if b := l.mappingBreadth(v.GetId()); b > 0 {
+				breadths = append(breadths, b)
+				mapped = append(mapped, v.GetValue())
+			}
+		}
+		if len(breadths) < 2 || breadths[0] <= breadths[len(breadths)-1] {
+			continue
+		}
+		inverted := true
+		for i := 1; i < len(breadths); i++ {
+			if breadths[i-1] < breadths[i] {
+				inverted = false
+				break
+			}
+		}
+		if inverted {
+			l.add(RuleHierarchyOrderInverted, SeverityWarning, attributeRef(a),
+				fmt.Sprintf("values [%s] are mapped to progressively broader subjects toward the top of the hierarchy; the order may be inverted (highest must be first)", strings.Join(mapped, ", ")))
+		}
+	}
+}
+
+// mappingBreadth counts the distinct selector values that include subjects through the mappings of a value
+func (l *linter) mappingBreadth(valueID string) int {
+	seen := map[string]bool{}
+	for _, sm := range l.mappings[valueID] {
+		forEachCondition(sm.GetSubjectConditionSet(), func(_ *policy.ConditionGroup, c *policy.Condition) {
+			if c.GetOperator() == policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN {
+				return
+			}
+			for _, ev := range c.GetSubjectExternalValues() {
+				seen[c.GetSubjectExternalSelectorValue()+"\x00"+ev] = true
+			}
+		})
+	}
+	return len(seen)
+}
+
+func forEachCondition(scs *policy.SubjectConditionSet, fn func(*policy.ConditionGroup, *policy.Condition)) {
+	for _, ss := range scs.GetSubjectSets() {
+		for _, g := range ss.GetConditionGroups() {
+			for _, c := range g.GetConditions() {
+				fn(g, c)
+			}
+		}
+	}
+}
+
+// CanonicalConditionSet renders subject sets in an order-independent form so equivalent sets compare equal.
+func CanonicalConditionSet(sets []*policy.SubjectSet) string {
+	ssStrs := make([]string, 0, len(sets))
+	for _, ss := range sets {
+		groups := make([]string, 0, len(ss.GetConditionGroups()))
+		for _, g := range ss.GetConditionGroups() {
+			conds := make([]string, 0, len(g.GetConditions()))
+			for _, c := range g.GetConditions() {
+				vals := slices.Clone(c.GetSubjectExternalValues())
+				sort.Strings(vals)
+				conds = append(conds, fmt.Sprintf("%s %s %q", c.GetSubjectExternalSelectorValue(), c.GetOperator(), vals))
+			}
+			sort.Strings(conds)
+			groups = append(groups, fmt.Sprintf("%s(%s)", g.GetBooleanOperator(), strings.Join(conds, ";")))
+		}
+		sort.Strings(groups)
+		ssStrs = append(ssStrs, "["+strings.Join(groups, ",")+"]")
+	}
+	sort.Strings(ssStrs)
+	return strings.Join(ssStrs, "|")
+}
+
+func (l *linter) duplicateConditionSets() {
+	byCanonical := map[string][]*policy.SubjectConditionSet{}
+	var order []string
+	for _, scs := range l.s.SubjectConditionSets {
+		k := CanonicalConditionSet(scs.GetSubjectSets())
+		if _, ok := byCanonical[k]; !ok {
+			order = append(order, k)
+		}
+		byCanonical[k] = append(byCanonical[k], scs)
+	}
+	for _, k := range order {
+		dups := byCanonical[k]
+		if len(dups) < 2 {
+			continue
+		}
+		related := make([]ObjectRef, 0, len(dups)-1)
+		for _, d := range dups[1:] {
+			related = append(related, scsRef(d))
+		}
+		l.add(RuleConditionSetDuplicate, SeverityWarning, scsRef(dups[0]),
+			fmt.Sprintf("%d other subject condition set(s) have identical conditions and could be consolidated", len(related)), related...)
+	}
+}
+
+func (l *linter) conditionSetContradictions() {
+	for _, scs := range l.s.SubjectConditionSets {
+		for _, ss := range scs.GetSubjectSets() {
+			for _, g := range ss.GetConditionGroups() {
+				for _, selector := range contradictorySelectors(g) {
+					if g.GetBooleanOperator() == policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR {
+						l.add(RuleConditionSetAlwaysTrue, SeverityWarning, scsRef(scs),
+							fmt.Sprintf("OR group checks %s both IN and NOT_IN the same values, so the group always matches", selector))
+						continue
+					}
+					l.add(RuleConditionSetContradictory, SeverityError, scsRef(scs),
+						fmt.Sprintf("AND group requires %s to be both IN and NOT_IN the same values, so the group can never match", selector))
+				}
+			}
+		}
+	}
+}
+
+// contradictorySelectors returns selectors with IN and NOT_IN conditions sharing at least one value
+func contradictorySelectors(g *policy.ConditionGroup) []string {
+	in := map[string]map[string]bool{}
+	notIn := map[string]map[string]bool{}
+	for _, c := range g.GetConditions() {
+		var m map[string]map[string]bool
+		switch c.GetOperator() {
+		case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN:
+			m = in
+		case policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN:
+			m = notIn
+		default:
+			continue
+		}
+		sel := c.GetSubjectExternalSelectorValue()
+		if m[sel] == nil {
+			m[sel] = map[string]bool{}
+		}
+		for _, v := range c.GetSubjectExternalValues() {
+			m[sel][v] = true
+		}
+	}
+	var selectors []string
+	for sel, vals := range in {
+		for v := range vals {
+			if notIn[sel][v] {
+				selectors = append(selectors, sel)
+				break
+			}
+		}
+	}
+	sort.Strings(selectors)
+	return selectors
+}
+
+func (l *linter) inactiveSubjectMappings() {
+	for _, sm := range l.s.SubjectMappings {
+		v := sm.GetAttributeValue()
+		if known, ok := l.values[v.GetId()]; ok {
+			v = known
+		}
+		reason := ""
+		switch a, ok := l.attrOf[v.GetId()]; {
+		case !isActive(v.GetActive()):
+			reason = "value"
+		case ok && !isActive(a.GetActive()):
+			reason = "attribute"
+		case ok && !l.attributeActive(a):
+			reason = "namespace"
+		}
+		if reason == "" {
+			continue
+		}
+		l.add(RuleSubjectMappingInactive, SeverityError, ObjectRef{Kind: ObjectKindSubjectMapping, ID: sm.GetId()},
+			fmt.Sprintf("maps to a value whose %s is deactivated", reason), valueRef(v))
+	}
+}
+
+func (l *linter) obligationValuesWithoutTriggers() {
+	triggered := map[string]bool{}
+	for _, t := range l.s.ObligationTriggers {
+		triggered[t.GetObligationValue().GetId()] = true
+	}
+	for _, o := range l.s.Obligations {
+		for _, v := range o.GetValues() {
+			if len(v.GetTriggers()) == 0 && !triggered[v.GetId()] {
+				l.add(RuleObligationValueNoTriggers, SeverityWarning,
+					ObjectRef{Kind: ObjectKindObligationValue, ID: v.GetId(), Name: v.GetFqn()},
+					"obligation value is never triggered by any attribute value and action")
+			}
+		}
+	}
+}
+
+func (l *linter) kasWithoutKeys() {
+	withKeys := map[string]bool{}
+	for _, k := range l.s.KasKeys {
+		withKeys[k.GetKasId()] = true
+	}
+	for _, kas := range l.s.KeyAccessServers {
+		if withKeys[kas.GetId()] || len(kas.GetKasKeys()) > 0 {
+			continue
+		}
+		ref := ObjectRef{Kind: ObjectKindKeyAccessServer, ID: kas.GetId(), Name: kas.GetUri()}
+		if kas.GetPublicKey() != nil {
+			l.add(RuleKasNoKeys, SeverityInfo, ref, "KAS has no keys in the registry and relies on a legacy public key")
+			continue
+		}
+		l.add(RuleKasNoKeys, SeverityWarning, ref, "KAS has no keys in the registry")
+	}
+}
+
+func hasKeys(kasKeys []*policy.SimpleKasKey, grants []*policy.KeyAccessServer) bool {
+	return len(kasKeys) > 0 || len(grants) > 0
+}
+
+func (l *linter) missingKeys() {
+	if l.s.BaseKey.GetPublicKey().GetKid() != "" || l.s.BaseKey.GetKasUri() != "" {
+		return
+	}
+	nsWithKeys := map[string]bool{}
+	for _, ns := range l.s.Namespaces {
+		if !isActive(ns.GetActive()) {
+			continue
+		}
+		if hasKeys(ns.GetKasKeys(), ns.GetGrants()) {
+			nsWithKeys[ns.GetId()] = true
+			continue
+		}
+		l.add(RuleNoKeyAssignedWithoutBase, SeverityWarning, ObjectRef{Kind: ObjectKindNamespace, ID: ns.GetId(), Name: ns.GetFqn()},
+			"namespace has no key assigned and no base key is set")
+	}
+	for _, a := range l.s.Attributes {
+		if !l.attributeActive(a) || nsWithKeys[a.GetNamespace().GetId()] || hasKeys(a.GetKasKeys(), a.GetGrants()) {
+			continue
+		}
+		valuesCovered := len(a.GetValues()) > 0
+		for _, v := range a.GetValues() {
+			if !hasKeys(v.GetKasKeys(), v.GetGrants()) {
+				valuesCovered = false
+				break
+			}
+		}
+		if valuesCovered {
+			continue
+		}
+		l.add(RuleNoKeyAssignedWithoutBase, SeverityError, attributeRef(a),
+			"neither the attribute, its namespace nor all of its values have a key assigned and no base key is set, so encrypting with it will fail")
+	}
+}
@@ -0,0 +1,105 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func condSet(id string, op policy.ConditionBooleanTypeEnum, conds ...*policy.Condition) *policy.SubjectConditionSet {
+	return &policy.SubjectConditionSet{
+		Id: id,
+		SubjectSets: []*policy.SubjectSet{{
+			ConditionGroups: []*policy.ConditionGroup{{BooleanOperator: op, Conditions: conds}},
+		}},
+	}
+}
+
+func cond(selector string, op policy.SubjectMappingOperatorEnum, values ...string) *policy.Condition {
+	return &policy.Condition{SubjectExternalSelectorValue: selector, Operator: op, SubjectExternalValues: values}
+}
+
+const (
+	and   = policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND
+	or    = policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR
+	in    = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN
+	notIn = policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN
+)
+
+func rules(findings []Finding) map[string][]string {
+	m := map[string][]string{}
+	for _, f := range findings {
+		m[f.Rule] = append(m[f.Rule], f.Object.ID)
+	}
+	return m
+}
+
+func TestRun(t *testing.T) {
+	ns := &policy.Namespace{Id: "ns", Fqn: "https://example.com", Active: wrapperspb.Bool(true)}
+	top := &policy.Value{Id: "v-top", Value: "top", Active: wrapperspb.Bool(true)}
+	mid := &policy.Value{Id: "v-mid", Value: "mid", Active: wrapperspb.Bool(true)}
+	unmapped := &policy.Value{Id: "v-unmapped", Value: "unmapped", Active: wrapperspb.Bool(true)}
+	inactive := &policy.Value{Id: "v-inactive", Value: "old", Active: wrapperspb.Bool(false)}
+
+	broad := condSet("scs-broad", and, cond(".team", in, "a", "b", "c"))
+	narrow := condSet("scs-narrow", and, cond(".team", in, "a"))
+	narrowDup := condSet("scs-narrow-dup", and, cond(".team", in, "a"))
+	never := condSet("scs-never", and, cond(".role", in, "x"), cond(".role", notIn, "x"))
+	always := condSet("scs-always", or, cond(".role", in, "x"), cond(".role", notIn, "x", "y"))
+
+	s := &handlers.PolicySnapshot{
+		Namespaces: []*policy.Namespace{ns},
+		Attributes: []*policy.Attribute{
+			{
+				Id: "attr-h", Fqn: "https://example.com/attr/level", Namespace: ns, Active: wrapperspb.Bool(true),
+				Rule:   policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY,
+				Values: []*policy.Value{top, mid, unmapped, inactive},
+			},
+			{Id: "attr-empty", Fqn: "https://example.com/attr/empty", Namespace: ns, Active: wrapperspb.Bool(true)},
+		},
+		SubjectMappings: []*policy.SubjectMapping{
+			{Id: "sm-top", AttributeValue: top, SubjectConditionSet: broad},
+			{Id: "sm-mid", AttributeValue: mid, SubjectConditionSet: narrow},
+			{Id: "sm-inactive", AttributeValue: inactive, SubjectConditionSet: narrow},
+		},
+		SubjectConditionSets: []*policy.SubjectConditionSet{broad, narrow, narrowDup, never, always},
+		Obligations: []*policy.Obligation{{
+			Id:     "obl",
+			Values: []*policy.ObligationValue{{Id: "ov-untriggered"}, {Id: "ov-triggered"}},
+		}},
+		ObligationTriggers: []*policy.ObligationTrigger{{Id: "t", ObligationValue: &policy.ObligationValue{Id: "ov-triggered"}}},
+		KeyAccessServers:   []*policy.KeyAccessServer{{Id: "kas-empty", Uri: "https://kas.example.com"}, {Id: "kas-keys"}},
+		KasKeys:            []*policy.KasKey{{KasId: "kas-keys"}},
+	}
+
+	got := rules(Run(s, Options{}))
+	assert.Equal(t, []string{"attr-empty"}, got[RuleAttributeNoValues])
+	assert.Equal(t, []string{"v-unmapped"}, got[RuleValueNoSubjectMappings])
+	assert.Equal(t, []string{"attr-h"}, got[RuleHierarchyOrderInverted])
+	assert.Equal(t, []string{"scs-narrow"}, got[RuleConditionSetDuplicate])
+	assert.Equal(t, []string{"scs-never"}, got[RuleConditionSetContradictory])
+	assert.Equal(t, []string{"scs-always"}, got[RuleConditionSetAlwaysTrue])
+	assert.Equal(t, []string{"sm-inactive"}, got[RuleSubjectMappingInactive])
+	assert.Equal(t, []string{"ov-untriggered"}, got[RuleObligationValueNoTriggers])
+	assert.Equal(t, []string{"kas-empty"}, got[RuleKasNoKeys])
+	assert.ElementsMatch(t, []string{"ns", "attr-h", "attr-empty"}, got[RuleNoKeyAssignedWithoutBase])
+
+	// a base key covers everything without an explicit assignment
+	s.BaseKey = &policy.SimpleKasKey{KasUri: "https://kas.example.com", PublicKey: &policy.SimpleKasPublicKey{Kid: "k1"}}
+	got = rules(Run(s, Options{Ignore: []string{RuleKasNoKeys}}))
+	assert.Empty(t, got[RuleNoKeyAssignedWithoutBase])
+	assert.Empty(t, got[RuleKasNoKeys])
+}
+
+func TestSeverity(t *testing.T) {
+	findings := []Finding{{Severity: SeverityWarning}}
+	assert.True(t, Exceeds(findings, SeverityInfo))
+	assert.True(t, Exceeds(findings, SeverityWarning))
+	assert.False(t, Exceeds(findings, SeverityError))
+
+	_, err := ParseSeverity("critical")
+	assert.Error(t, err)
+}
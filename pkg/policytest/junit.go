@@ -0,0 +1,67 @@
+package policytest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// JUnit renders the report as JUnit XML for CI systems.
+func (r Report) JUnit() ([]byte, error) {
+	suite := junitTestSuite{
+		Name:     r.File,
+		Tests:    len(r.Cases),
+		Failures: r.Failed,
+		Errors:   r.Errors,
+	}
+	var total float64
+	for _, c := range r.Cases {
+		seconds := c.Duration.Seconds()
+		total += seconds
+		tc := junitTestCase{Name: c.Name, Classname: r.File, Time: fmt.Sprintf("%.3f", seconds)}
+		switch {
+		case c.Error != "":
+			tc.Error = &junitMessage{Message: c.Error, Body: c.Error}
+		case !c.Passed:
+			tc.Failure = &junitMessage{
+				Message: fmt.Sprintf("%d expectations not met", len(c.Failures)),
+				Body:    strings.Join(c.Failures, "\n"),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Time = fmt.Sprintf("%.3f", total)
+
+	b, err := xml.MarshalIndent(junitTestSuites{Suites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
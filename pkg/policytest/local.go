@@ -0,0 +1,73 @@
+package policytest
+
+import (
+	"context"
+
+	"github.com/opentdf/otdfctl/pkg/conditions"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+// LocalEvaluator resolves entitlements from the subject mappings of a policy snapshot rather than through the
+// authorization service. The subject's claims are evaluated as given, without entity resolution, and entitlements
+// to a HIERARCHY value extend to the values below it.
+func LocalEvaluator(s *handlers.PolicySnapshot) Evaluator {
+	valueFQNs := map[string]string{}
+	for _, a := range s.Attributes {
+		for _, v := range a.GetValues() {
+			valueFQNs[v.GetId()] = v.GetFqn()
+		}
+	}
+
+	return func(_ context.Context, subject string) (map[string][]string, error) {
+		values, err := handlers.FlattenSubjectValues(subject)
+		if err != nil {
+			return nil, err
+		}
+
+		entitled := map[string]map[string]bool{}
+		for _, sm := range s.SubjectMappings {
+			if met, _ := conditions.Evaluate(sm.GetSubjectConditionSet().GetSubjectSets(), values); !met {
+				continue
+			}
+			fqn := sm.GetAttributeValue().GetFqn()
+			if fqn == "" {
+				fqn = valueFQNs[sm.GetAttributeValue().GetId()]
+			}
+			for _, a := range sm.GetActions() {
+				addAction(entitled, fqn, a.GetName())
+			}
+		}
+
+		for _, a := range s.Attributes {
+			if a.GetRule() != policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY {
+				continue
+			}
+			// values are ordered from the highest to the lowest
+			above := map[string]bool{}
+			for _, v := range a.GetValues() {
+				for action := range above {
+					addAction(entitled, v.GetFqn(), action)
+				}
+				for action := range entitled[v.GetFqn()] {
+					above[action] = true
+				}
+			}
+		}
+
+		out := make(map[string][]string, len(entitled))
+		for fqn, actions := range entitled {
+			for a := range actions {
+				out[fqn] = append(out[fqn], a)
+			}
+		}
+		return out, nil
+	}
+}
+
+func addAction(entitled map[string]map[string]bool, fqn, action string) {
+	if entitled[fqn] == nil {
+		entitled[fqn] = map[string]bool{}
+	}
+	entitled[fqn][action] = true
+}
@@ -0,0 +1,184 @@
+// Package policytest runs entitlement test cases, asserting which attribute values and actions a subject is and is
+// not entitled to.
+package policytest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrInvalidSuite = errors.New("invalid policy test file")
+
+// Suite is a file of test cases.
+type Suite struct {
+	Tests []Case `yaml:"tests"`
+
+	path string
+}
+
+// Case asserts the entitlements of one subject.
+type Case struct {
+	Name string `yaml:"name"`
+	// Subject is a JWT or JSON claims. SubjectFile reads it from a path relative to the suite file instead.
+	Subject     string        `yaml:"subject,omitempty"`
+	SubjectFile string        `yaml:"subject_file,omitempty"`
+	Entitled    []Expectation `yaml:"entitled,omitempty"`
+	Denied      []Expectation `yaml:"denied,omitempty"`
+}
+
+// Expectation names an attribute value and optionally the actions on it. Without actions, an entitled expectation
+// is met by any action and a denied expectation requires that no action is entitled.
+type Expectation struct {
+	Value   string   `yaml:"value"`
+	Actions []string `yaml:"actions,omitempty"`
+}
+
+// Evaluator resolves a subject's entitled action names per attribute value FQN.
+type Evaluator func(ctx context.Context, subject string) (map[string][]string, error)
+
+type CaseResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Failures []string      `json:"failures,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+type Report struct {
+	File   string       `json:"file"`
+	Cases  []CaseResult `json:"cases"`
+	Passed int          `json:"passed"`
+	Failed int          `json:"failed"`
+	Errors int          `json:"errors"`
+}
+
+// OK reports whether every case passed.
+func (r Report) OK() bool {
+	return r.Failed == 0 && r.Errors == 0
+}
+
+// Load reads and validates a suite file.
+func Load(path string) (*Suite, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &Suite{path: path}
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	d.KnownFields(true)
+	if err := d.Decode(s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidSuite, err)
+	}
+	if len(s.Tests) == 0 {
+		return nil, fmt.Errorf("%w: no tests", ErrInvalidSuite)
+	}
+
+	for i := range s.Tests {
+		tc := &s.Tests[i]
+		if tc.Name == "" {
+			tc.Name = fmt.Sprintf("case %d", i+1)
+		}
+		if (tc.Subject == "") == (tc.SubjectFile == "") {
+			return nil, fmt.Errorf("%w: %s: exactly one of 'subject' or 'subject_file' is required", ErrInvalidSuite, tc.Name)
+		}
+		if tc.SubjectFile != "" {
+			p := tc.SubjectFile
+			if !filepath.IsAbs(p) {
+				p = filepath.Join(filepath.Dir(path), p)
+			}
+			b, err := os.ReadFile(p)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrInvalidSuite, tc.Name, err)
+			}
+			tc.Subject = string(b)
+		}
+		tc.Subject = strings.TrimSpace(tc.Subject)
+		if len(tc.Entitled)+len(tc.Denied) == 0 {
+			return nil, fmt.Errorf("%w: %s: no 'entitled' or 'denied' expectations", ErrInvalidSuite, tc.Name)
+		}
+		for _, e := range append(append([]Expectation{}, tc.Entitled...), tc.Denied...) {
+			if e.Value == "" {
+				return nil, fmt.Errorf("%w: %s: an expectation is missing its 'value'", ErrInvalidSuite, tc.Name)
+			}
+		}
+	}
+	return s, nil
+}
+
+// Run evaluates every case of the suite.
+func Run(ctx context.Context, s *Suite, evaluate Evaluator) Report {
+	r := Report{File: s.path}
+	for _, tc := range s.Tests {
+		start := time.Now()
+		res := CaseResult{Name: tc.Name}
+
+		entitlements, err := evaluate(ctx, tc.Subject)
+		if err != nil {
+			res.Error = err.Error()
+			r.Errors++
+		} else {
+			res.Failures = Check(tc, entitlements)
+			res.Passed = len(res.Failures) == 0
+			if res.Passed {
+				r.Passed++
+			} else {
+				r.Failed++
+			}
+		}
+		res.Duration = time.Since(start)
+		r.Cases = append(r.Cases, res)
+	}
+	return r
+}
+
+// Check compares a case's expectations with the subject's entitled actions per value FQN and describes every
+// expectation which is not met.
+func Check(tc Case, entitlements map[string][]string) []string {
+	entitled := make(map[string]map[string]bool, len(entitlements))
+	for fqn, actions := range entitlements {
+		set := map[string]bool{}
+		for _, a := range actions {
+			set[strings.ToLower(a)] = true
+		}
+		entitled[strings.ToLower(fqn)] = set
+	}
+
+	var failures []string
+	for _, e := range tc.Entitled {
+		actions, ok := entitled[strings.ToLower(e.Value)]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("expected entitlement to %s", e.Value))
+			continue
+		}
+		for _, a := range e.Actions {
+			if !actions[strings.ToLower(a)] {
+				failures = append(failures, fmt.Sprintf("expected entitlement to %s on %s", a, e.Value))
+			}
+		}
+	}
+	for _, e := range tc.Denied {
+		actions, ok := entitled[strings.ToLower(e.Value)]
+		if !ok {
+			continue
+		}
+		if len(e.Actions) == 0 {
+			failures = append(failures, fmt.Sprintf("expected no entitlement to %s", e.Value))
+			continue
+		}
+		for _, a := range e.Actions {
+			if actions[strings.ToLower(a)] {
+				failures = append(failures, fmt.Sprintf("expected no entitlement to %s on %s", a, e.Value))
+			}
+		}
+	}
+	return failures
+}
@@ -0,0 +1,94 @@
+package policytest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const suiteYAML = `
+tests:
+  - name: admin reads secret
+    subject: '{"groups": ["admin"]}'
+    entitled:
+      - value: https://example.com/attr/level/value/secret
+        actions: [read]
+    denied:
+      - value: https://example.com/attr/level/value/topsecret
+  - name: contractor
+    subject_file: contractor.json
+    denied:
+      - value: https://example.com/attr/level/value/secret
+        actions: [update]
+`
+
+func writeSuite(t *testing.T, content string) string {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "tests.yaml")
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "contractor.json"), []byte(`{"groups": ["contractor"]}`+"\n"), 0o600))
+	return p
+}
+
+func TestLoad(t *testing.T) {
+	s, err := Load(writeSuite(t, suiteYAML))
+	require.NoError(t, err)
+	require.Len(t, s.Tests, 2)
+	assert.Equal(t, `{"groups": ["contractor"]}`, s.Tests[1].Subject)
+
+	for _, invalid := range []string{
+		`tests: []`,
+		"tests:\n  - name: no subject\n    entitled:\n      - value: x",
+		"tests:\n  - name: no expectations\n    subject: '{}'",
+		"tests:\n  - name: unknown field\n    subject: '{}'\n    allowed:\n      - value: x",
+	} {
+		_, err := Load(writeSuite(t, invalid))
+		require.ErrorIs(t, err, ErrInvalidSuite, invalid)
+	}
+}
+
+func TestRun(t *testing.T) {
+	s, err := Load(writeSuite(t, suiteYAML))
+	require.NoError(t, err)
+
+	evaluate := func(_ context.Context, subject string) (map[string][]string, error) {
+		if strings.Contains(subject, "admin") {
+			return map[string][]string{
+				"https://example.com/attr/level/value/secret":    {"read"},
+				"https://example.com/attr/level/value/topsecret": {"read"},
+			}, nil
+		}
+		return nil, errors.New("entity resolution failed")
+	}
+
+	r := Run(context.Background(), s, evaluate)
+	assert.False(t, r.OK())
+	assert.Equal(t, 0, r.Passed)
+	assert.Equal(t, 1, r.Failed)
+	assert.Equal(t, 1, r.Errors)
+	assert.Equal(t, []string{"expected no entitlement to https://example.com/attr/level/value/topsecret"}, r.Cases[0].Failures)
+
+	junit, err := r.JUnit()
+	require.NoError(t, err)
+	assert.Contains(t, string(junit), `<testsuite name="`)
+	assert.Contains(t, string(junit), `tests="2" failures="1" errors="1"`)
+	assert.Contains(t, string(junit), `<error message="entity resolution failed">`)
+}
+
+func TestCheck(t *testing.T) {
+	tc := Case{
+		Entitled: []Expectation{{Value: "https://example.com/attr/a/value/x", Actions: []string{"read", "create"}}},
+		Denied:   []Expectation{{Value: "https://example.com/attr/a/value/y", Actions: []string{"update"}}},
+	}
+	failures := Check(tc, map[string][]string{
+		"https://example.com/attr/a/value/x": {"read"},
+		"https://example.com/attr/a/value/y": {"read"},
+	})
+	assert.Equal(t, []string{"expected entitlement to create on https://example.com/attr/a/value/x"}, failures)
+}
@@ -0,0 +1,232 @@
+package profiles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+
+	osprofiles "github.com/jrschumacher/go-osprofiles"
+	"github.com/opentdf/otdfctl/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ProfileBundle is a portable (YAML) document holding one or more profiles. It is produced by
+// `profile export` and consumed by `profile import`, which also makes it suitable as a shared
+// team bundle for onboarding.
+type ProfileBundle struct {
+	Profiles []BundleProfile `yaml:"profiles" json:"profiles"`
+}
+
+type BundleProfile struct {
+	Name            string           `yaml:"name" json:"name"`
+	Endpoint        string           `yaml:"endpoint" json:"endpoint"`
+	TLSNoVerify     bool             `yaml:"tlsNoVerify,omitempty" json:"tlsNoVerify,omitempty"`
+	OutputFormat    string           `yaml:"outputFormat,omitempty" json:"outputFormat,omitempty"`
+	LoginClientID   string           `yaml:"loginClientId,omitempty" json:"loginClientId,omitempty"`
+	Default         bool             `yaml:"default,omitempty" json:"default,omitempty"`
+	AuthCredentials *AuthCredentials `yaml:"authCredentials,omitempty" json:"authCredentials,omitempty"`
+}
+
+type ImportResult struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Skipped []string `json:"skipped"`
+	Default string   `json:"default,omitempty"`
+}
+
+// bundleIndent matches the indentation of hand-written team bundles
+const bundleIndent = 2
+
+// ParseProfileBundle decodes and validates a profile bundle. JSON is accepted as well since it is valid YAML.
+func ParseProfileBundle(data []byte) (*ProfileBundle, error) {
+	b := &ProfileBundle{}
+	d := yaml.NewDecoder(bytes.NewReader(data))
+	d.KnownFields(true)
+	if err := d.Decode(b); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Join(ErrProfileBundleInvalid, err)
+	}
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Validate checks that every profile in the bundle is well-formed and that names and the default are unambiguous.
+func (b *ProfileBundle) Validate() error {
+	if b == nil || len(b.Profiles) == 0 {
+		return ErrProfileBundleEmpty
+	}
+
+	seen := make(map[string]bool, len(b.Profiles))
+	defaults := 0
+	for i, p := range b.Profiles {
+		if p.Name == "" {
+			return fmt.Errorf("%w: profile at index %d is missing a name", ErrProfileBundleInvalid, i)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("%w: profile %s is defined more than once", ErrProfileBundleInvalid, p.Name)
+		}
+		seen[p.Name] = true
+
+		if _, err := utils.NormalizeEndpoint(p.Endpoint); err != nil {
+			return fmt.Errorf("%w: profile %s has an invalid endpoint: %w", ErrProfileBundleInvalid, p.Name, err)
+		}
+		if p.OutputFormat != "" && !IsValidOutputFormat(p.OutputFormat) {
+			return fmt.Errorf("%w: profile %s output format must be either 'styled' or 'json'", ErrProfileBundleInvalid, p.Name)
+		}
+		if p.Default {
+			defaults++
+		}
+	}
+	if defaults > 1 {
+		return fmt.Errorf("%w: only one profile may be marked as default", ErrProfileBundleInvalid)
+	}
+	return nil
+}
+
+// StripCredentials removes all stored credentials from the bundle.
+func (b *ProfileBundle) StripCredentials() {
+	for i := range b.Profiles {
+		b.Profiles[i].AuthCredentials = nil
+	}
+}
+
+// Marshal encodes the bundle as YAML.
+func (b *ProfileBundle) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	e := yaml.NewEncoder(&buf)
+	e.SetIndent(bundleIndent)
+	if err := e.Encode(b); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ExportProfiles builds a bundle from the named profiles in the given store, or from all profiles when no
+// names are provided. Stored credentials are only included when asked for.
+func ExportProfiles(storeType ProfileDriver, names []string, includeCredentials bool) (*ProfileBundle, error) {
+	profiler, err := CreateProfiler(storeType)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(names) == 0 {
+		names = osprofiles.ListProfiles(profiler)
+	}
+	defaultProfile := osprofiles.GetGlobalConfig(profiler).GetDefaultProfile()
+
+	b := &ProfileBundle{}
+	for _, name := range names {
+		store, err := osprofiles.GetProfile[*ProfileConfig](profiler, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load profile %s: %w", name, err)
+		}
+		pc, ok := store.Profile.(*ProfileConfig)
+		if !ok || pc == nil {
+			return nil, ErrProfileIncorrectType
+		}
+
+		bp := BundleProfile{
+			Name:          pc.Name,
+			Endpoint:      pc.Endpoint,
+			TLSNoVerify:   pc.TLSNoVerify,
+			OutputFormat:  NormalizeOutputFormat(pc.OutputFormat),
+			LoginClientID: pc.LoginClientID,
+			Default:       pc.Name == defaultProfile,
+		}
+		if includeCredentials && pc.AuthCredentials.AuthType != "" {
+			ac := pc.AuthCredentials
+			bp.AuthCredentials = &ac
+		}
+		b.Profiles = append(b.Profiles, bp)
+	}
+
+	if len(b.Profiles) == 0 {
+		return nil, ErrProfileBundleEmpty
+	}
+	return b, nil
+}
+
+// ImportProfiles writes every profile in the bundle to the given store. Existing profiles are skipped unless
+// overwrite is set, in which case their settings (and credentials, when present in the bundle) are replaced.
+func ImportProfiles(storeType ProfileDriver, b *ProfileBundle, overwrite bool) (ImportResult, error) {
+	result := ImportResult{}
+	if err := b.Validate(); err != nil {
+		return result, err
+	}
+
+	profiler, err := CreateProfiler(storeType)
+	if err != nil {
+		return result, err
+	}
+	existing := osprofiles.ListProfiles(profiler)
+
+	for _, bp := range b.Profiles {
+		u, err := utils.NormalizeEndpoint(bp.Endpoint)
+		if err != nil {
+			return result, err
+		}
+		cfg := &ProfileConfig{
+			Name:          bp.Name,
+			Endpoint:      u.String(),
+			TLSNoVerify:   bp.TLSNoVerify,
+			OutputFormat:  NormalizeOutputFormat(bp.OutputFormat),
+			LoginClientID: bp.LoginClientID,
+		}
+		if bp.AuthCredentials != nil {
+			cfg.AuthCredentials = *bp.AuthCredentials
+		}
+
+		if !slices.Contains(existing, bp.Name) {
+			if err := profiler.AddProfile(cfg, false); err != nil {
+				return result, fmt.Errorf("failed to import profile %s: %w", bp.Name, err)
+			}
+			slog.Debug("Imported profile", slog.String("profile", bp.Name))
+			result.Created = append(result.Created, bp.Name)
+			continue
+		}
+
+		if !overwrite {
+			slog.Debug("Skipping existing profile", slog.String("profile", bp.Name))
+			result.Skipped = append(result.Skipped, bp.Name)
+			continue
+		}
+
+		store, err := osprofiles.GetProfile[*ProfileConfig](profiler, bp.Name)
+		if err != nil {
+			return result, fmt.Errorf("failed to load profile %s: %w", bp.Name, err)
+		}
+		pc, ok := store.Profile.(*ProfileConfig)
+		if !ok || pc == nil {
+			return result, ErrProfileIncorrectType
+		}
+		// keep existing credentials when the bundle does not carry any
+		if bp.AuthCredentials == nil {
+			cfg.AuthCredentials = pc.AuthCredentials
+		}
+		*pc = *cfg
+		if err := store.Save(); err != nil {
+			return result, fmt.Errorf("failed to update profile %s: %w", bp.Name, err)
+		}
+		slog.Debug("Updated profile", slog.String("profile", bp.Name))
+		result.Updated = append(result.Updated, bp.Name)
+	}
+
+	for _, bp := range b.Profiles {
+		if !bp.Default || slices.Contains(result.Skipped, bp.Name) {
+			continue
+		}
+		if err := osprofiles.SetDefaultProfile(profiler, bp.Name); err != nil {
+			return result, fmt.Errorf("failed to set default profile %s: %w", bp.Name, err)
+		}
+		result.Default = bp.Name
+	}
+
+	return result, nil
+}
@@ -11,4 +11,7 @@ var (
 	ErrUnknownProfileDriverType = errors.New("error unknown profile driver type")
 	ErrCleaningUpProfiles       = errors.New("error occurred when cleaning up profiles")
 	ErrProfileConfigEmpty       = errors.New("error profile configuration cannot be empty")
+	ErrProfileBundleEmpty       = errors.New("error profile bundle contains no profiles")
+	ErrProfileBundleInvalid     = errors.New("error profile bundle is invalid")
+	ErrProjectConfigInvalid     = errors.New("error project config is invalid")
 )
@@ -22,12 +22,22 @@ const (
 	ProfileDriverDefault                  = ProfileDriverFileSystem
 )
 
-func newFileStoreProfiler() (*osprofiles.Profiler, error) {
+// UserConfigDirectory is the directory of the filesystem profile store, which also holds other local state such as
+// the policy change journal
+func UserConfigDirectory() (string, error) {
 	platform, err := osplatform.NewPlatform(config.ServicePublisher, config.AppName, runtime.GOOS)
 	if err != nil {
-		return nil, errors.Join(ErrCreatingPlatform, err)
+		return "", errors.Join(ErrCreatingPlatform, err)
+	}
+	return platform.UserAppConfigDirectory(), nil
+}
+
+func newFileStoreProfiler() (*osprofiles.Profiler, error) {
+	dir, err := UserConfigDirectory()
+	if err != nil {
+		return nil, err
 	}
-	profiler, err := osprofiles.New(config.AppName, osprofiles.WithFileStore(platform.UserAppConfigDirectory()))
+	profiler, err := osprofiles.New(config.AppName, osprofiles.WithFileStore(dir))
 	if err != nil {
 		return nil, errors.Join(ErrCreatingNewProfile, err)
 	}
@@ -3,22 +3,43 @@ package profiles
 const (
 	AuthTypeClientCredentials = "client-credentials"
 	AuthTypeAccessToken       = "access-token"
+	AuthTypeWorkloadIdentity  = "workload-identity"
+)
+
+// Grants used to trade a workload identity token for a platform access token
+const (
+	// RFC 8693 token exchange
+	WorkloadIdentityGrantTokenExchange = "token-exchange"
+	// RFC 7523 client credentials with a JWT-bearer client assertion
+	WorkloadIdentityGrantJWTBearer = "jwt-bearer"
 )
 
 type AuthCredentials struct {
-	AuthType string `json:"authType"`
-	ClientID string `json:"clientId"`
+	AuthType string `json:"authType" yaml:"authType"`
+	ClientID string `json:"clientId" yaml:"clientId,omitempty"`
 	// Used for client credentials
-	ClientSecret string                     `json:"clientSecret,omitempty"`
-	Scopes       []string                   `json:"scopes,omitempty"`
-	AccessToken  AuthCredentialsAccessToken `json:"accessToken,omitempty"`
+	ClientSecret string                     `json:"clientSecret,omitempty" yaml:"clientSecret,omitempty"`
+	Scopes       []string                   `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	AccessToken  AuthCredentialsAccessToken `json:"accessToken,omitempty" yaml:"accessToken,omitempty"`
+	// Used for workload identity federation
+	WorkloadIdentity *AuthCredentialsWorkloadIdentity `json:"workloadIdentity,omitempty" yaml:"workloadIdentity,omitempty"`
 }
 
 type AuthCredentialsAccessToken struct {
-	ClientID     string `json:"clientId"`
-	AccessToken  string `json:"accessToken"`
-	RefreshToken string `json:"refreshToken"`
-	Expiration   int64  `json:"expiration"`
+	ClientID     string `json:"clientId" yaml:"clientId,omitempty"`
+	AccessToken  string `json:"accessToken" yaml:"accessToken,omitempty"`
+	RefreshToken string `json:"refreshToken" yaml:"refreshToken,omitempty"`
+	Expiration   int64  `json:"expiration" yaml:"expiration,omitempty"`
+}
+
+// AuthCredentialsWorkloadIdentity locates an externally issued identity token (e.g. a projected Kubernetes service
+// account token or a CI OIDC token) which is exchanged for a platform access token whenever one is needed.
+type AuthCredentialsWorkloadIdentity struct {
+	Grant            string `json:"grant" yaml:"grant"`
+	TokenFile        string `json:"tokenFile,omitempty" yaml:"tokenFile,omitempty"`
+	TokenEnv         string `json:"tokenEnv,omitempty" yaml:"tokenEnv,omitempty"`
+	SubjectTokenType string `json:"subjectTokenType,omitempty" yaml:"subjectTokenType,omitempty"`
+	Audience         string `json:"audience,omitempty" yaml:"audience,omitempty"`
 }
 
 func (p *OtdfctlProfileStore) GetAuthCredentials() AuthCredentials {
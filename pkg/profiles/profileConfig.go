@@ -17,6 +17,8 @@ type OtdfctlProfileStore struct {
 	store    osprofiles.ProfileStore
 	config   *ProfileConfig // Pointer to the store.Profile field
 	profiler *osprofiles.Profiler
+	// path of the project config defining the endpoint, for a profile which is not stored
+	projectConfig string
 }
 
 type ProfileConfig struct {
@@ -24,6 +26,7 @@ type ProfileConfig struct {
 	Endpoint        string          `json:"endpoint"`
 	TLSNoVerify     bool            `json:"tlsNoVerify"`
 	OutputFormat    string          `json:"outputFormat,omitempty"`
+	LoginClientID   string          `json:"loginClientId,omitempty"`
 	AuthCredentials AuthCredentials `json:"authCredentials"`
 }
 
@@ -47,10 +50,11 @@ func NewOtdfctlProfileStore(storeType ProfileDriver, cfg *ProfileConfig, setDefa
 	}
 
 	p := &ProfileConfig{
-		Name:         cfg.Name,
-		Endpoint:     u.String(),
-		TLSNoVerify:  cfg.TLSNoVerify,
-		OutputFormat: NormalizeOutputFormat(cfg.OutputFormat),
+		Name:          cfg.Name,
+		Endpoint:      u.String(),
+		TLSNoVerify:   cfg.TLSNoVerify,
+		OutputFormat:  NormalizeOutputFormat(cfg.OutputFormat),
+		LoginClientID: cfg.LoginClientID,
 	}
 	err = profiler.AddProfile(p, setDefault)
 	if err != nil {
@@ -138,6 +142,16 @@ func (p *OtdfctlProfileStore) SetOutputFormat(format string) error {
 	return p.store.Save()
 }
 
+// GetLoginClientID returns the public IdP client ID used by `auth login` when none is provided.
+func (p *OtdfctlProfileStore) GetLoginClientID() string {
+	return p.config.LoginClientID
+}
+
+// ProjectConfigPath returns the project config an in-memory profile was built from, or "" for a stored profile.
+func (p *OtdfctlProfileStore) ProjectConfigPath() string {
+	return p.projectConfig
+}
+
 func (p *OtdfctlProfileStore) Name() string {
 	return p.config.Name
 }
@@ -0,0 +1,153 @@
+package profiles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/opentdf/otdfctl/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectConfigFileName is the name of the directory-scoped config file searched for from the working directory upward.
+const ProjectConfigFileName = ".otdfctl.yaml"
+
+// Only flags that scope or format output can be defaulted by a project config, since the file comes with whatever
+// repository is checked out. Flags that select a profile, authenticate, skip confirmation or relax TLS cannot be.
+var projectConfigAllowedFlags = map[string]bool{
+	"namespace":    true,
+	"kas-url-path": true,
+	"state":        true,
+	"limit":        true,
+	"json":         true,
+}
+
+// IsProjectConfigFlagAllowed reports whether a project config may set a default value for the flag.
+func IsProjectConfigFlagAllowed(name string) bool {
+	return projectConfigAllowedFlags[name]
+}
+
+// ProjectConfig pins a profile (or inline endpoint settings) and default flag values for a directory tree.
+type ProjectConfig struct {
+	Profile       string            `yaml:"profile,omitempty"`
+	Endpoint      string            `yaml:"endpoint,omitempty"`
+	OutputFormat  string            `yaml:"outputFormat,omitempty"`
+	LoginClientID string            `yaml:"loginClientId,omitempty"`
+	Flags         map[string]string `yaml:"flags,omitempty"`
+
+	path string
+}
+
+// Path returns the location of the file the config was loaded from.
+func (pc *ProjectConfig) Path() string {
+	if pc == nil {
+		return ""
+	}
+	return pc.path
+}
+
+// HasInlineEndpoint reports whether the config defines its own endpoint rather than only pinning a profile.
+func (pc *ProjectConfig) HasInlineEndpoint() bool {
+	return pc != nil && pc.Endpoint != ""
+}
+
+func (pc *ProjectConfig) Validate() error {
+	if pc.Endpoint != "" {
+		if _, err := utils.NormalizeEndpoint(pc.Endpoint); err != nil {
+			return fmt.Errorf("%w: invalid endpoint: %w", ErrProjectConfigInvalid, err)
+		}
+	}
+	if pc.OutputFormat != "" && !IsValidOutputFormat(pc.OutputFormat) {
+		return fmt.Errorf("%w: output format must be either 'styled' or 'json'", ErrProjectConfigInvalid)
+	}
+	names := make([]string, 0, len(pc.Flags))
+	for f := range pc.Flags {
+		names = append(names, f)
+	}
+	sort.Strings(names)
+	for _, f := range names {
+		if !IsProjectConfigFlagAllowed(f) {
+			return fmt.Errorf("%w: flag '--%s' cannot be set in a project config", ErrProjectConfigInvalid, f)
+		}
+	}
+	return nil
+}
+
+// FindProjectConfig searches dir and each of its parents for a project config file. It returns nil without an
+// error when none is found.
+func FindProjectConfig(dir string) (*ProjectConfig, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p := filepath.Join(dir, ProjectConfigFileName)
+		if _, err := os.Stat(p); err == nil {
+			return LoadProjectConfig(p)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig reads and validates the project config file at path.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := &ProjectConfig{}
+	d := yaml.NewDecoder(bytes.NewReader(data))
+	d.KnownFields(true)
+	if err := d.Decode(pc); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %s: %w", ErrProjectConfigInvalid, path, err)
+	}
+	if err := pc.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	pc.path = path
+	return pc, nil
+}
+
+// NewProjectProfileStore returns an in-memory profile applying the inline endpoint settings of a project config.
+// The profile starts unauthenticated, since credentials of the base profile are never sent to an endpoint named by a
+// project config, and it is not stored, so credentials cannot be saved to it either. TLS verification is always on.
+func NewProjectProfileStore(base *OtdfctlProfileStore, pc *ProjectConfig) (*OtdfctlProfileStore, error) {
+	if !pc.HasInlineEndpoint() {
+		return base, nil
+	}
+
+	cfg := &ProfileConfig{
+		Name:          "project",
+		Endpoint:      pc.Endpoint,
+		OutputFormat:  pc.OutputFormat,
+		LoginClientID: pc.LoginClientID,
+	}
+	if base != nil {
+		if cfg.OutputFormat == "" {
+			cfg.OutputFormat = base.GetOutputFormat()
+		}
+		if cfg.LoginClientID == "" {
+			cfg.LoginClientID = base.GetLoginClientID()
+		}
+	}
+
+	store, err := NewOtdfctlProfileStore(ProfileDriverMemory, cfg, true)
+	if err != nil {
+		return nil, err
+	}
+	store.projectConfig = pc.Path()
+	return store, nil
+}
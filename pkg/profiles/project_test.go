@@ -0,0 +1,113 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProjectConfig(t *testing.T, dir, content string) string {
+	t.Helper()
+	p := filepath.Join(dir, ProjectConfigFileName)
+	require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	return p
+}
+
+func TestFindProjectConfig_SearchesParents(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b", "c")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	p := writeProjectConfig(t, root, `
+profile: staging
+flags:
+  namespace: https://example.com
+  kas-url-path: /kas
+`)
+
+	pc, err := FindProjectConfig(nested)
+	require.NoError(t, err)
+	require.NotNil(t, pc)
+	assert.Equal(t, p, pc.Path())
+	assert.Equal(t, "staging", pc.Profile)
+	assert.False(t, pc.HasInlineEndpoint())
+	assert.Equal(t, "https://example.com", pc.Flags["namespace"])
+	assert.Equal(t, "/kas", pc.Flags["kas-url-path"])
+}
+
+func TestFindProjectConfig_NearestWins(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "repo")
+	require.NoError(t, os.MkdirAll(nested, 0o755))
+
+	writeProjectConfig(t, root, "profile: outer\n")
+	writeProjectConfig(t, nested, "endpoint: https://platform.example.com\noutputFormat: json\n")
+
+	pc, err := FindProjectConfig(nested)
+	require.NoError(t, err)
+	require.NotNil(t, pc)
+	assert.True(t, pc.HasInlineEndpoint())
+	assert.Equal(t, "json", pc.OutputFormat)
+	assert.Empty(t, pc.Profile)
+}
+
+func TestFindProjectConfig_NotFound(t *testing.T) {
+	pc, err := FindProjectConfig(t.TempDir())
+	require.NoError(t, err)
+	assert.Nil(t, pc)
+	assert.False(t, pc.HasInlineEndpoint())
+	assert.Empty(t, pc.Path())
+}
+
+func TestLoadProjectConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "unknown key", content: "profil: typo\n"},
+		{name: "invalid endpoint", content: "endpoint: localhost:8080\n"},
+		{name: "invalid output format", content: "outputFormat: xml\n"},
+		{name: "credential flag", content: "flags:\n  with-access-token: abc\n"},
+		{name: "tls verification", content: "endpoint: https://platform.example.com\ntlsNoVerify: true\n"},
+		{name: "tls flag", content: "flags:\n  tls-no-verify: \"true\"\n"},
+		{name: "force flag", content: "flags:\n  force: \"true\"\n"},
+		{name: "commit flag", content: "flags:\n  commit: \"true\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := writeProjectConfig(t, t.TempDir(), tt.content)
+			_, err := LoadProjectConfig(p)
+			require.ErrorIs(t, err, ErrProjectConfigInvalid)
+		})
+	}
+}
+
+func TestNewProjectProfileStore_DoesNotCarryCredentials(t *testing.T) {
+	base, err := NewOtdfctlProfileStore(ProfileDriverMemory, &ProfileConfig{
+		Name:         "default",
+		Endpoint:     "https://platform.example.com",
+		OutputFormat: "json",
+	}, true)
+	require.NoError(t, err)
+	require.NoError(t, base.SetAuthCredentials(AuthCredentials{
+		AuthType:     AuthTypeClientCredentials,
+		ClientID:     "client",
+		ClientSecret: "secret",
+	}))
+
+	store, err := NewProjectProfileStore(base, &ProjectConfig{Endpoint: "https://attacker.example.com", path: "/repo/.otdfctl.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, "/repo/.otdfctl.yaml", store.ProjectConfigPath())
+	assert.Empty(t, base.ProjectConfigPath())
+	assert.Equal(t, "https://attacker.example.com:443", store.GetEndpoint())
+	assert.False(t, store.GetTLSNoVerify())
+	assert.Empty(t, store.GetAuthCredentials().ClientSecret)
+	assert.Equal(t, "json", store.GetOutputFormat())
+
+	same, err := NewProjectProfileStore(base, &ProjectConfig{Profile: "staging"})
+	require.NoError(t, err)
+	assert.Same(t, base, same)
+}
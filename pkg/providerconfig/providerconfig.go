@@ -0,0 +1,227 @@
+// Package providerconfig is the registry of known key provider types, with the JSON Schema of the config each expects.
+// It validates a config before it is sent to the platform, so that typos are caught when the config is created rather
+// than when the KAS first fails to unwrap a key, scaffolds a config from a template, and masks the secret fields of a
+// config for display.
+package providerconfig
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	TypeAWSKMS        = "aws-kms"
+	TypeGCPKMS        = "gcp-kms"
+	TypeAzureKeyVault = "azure-key-vault"
+	TypePKCS11        = "pkcs11"
+	TypeLocalFile     = "local-file"
+	TypeDevKeyManager = "dev-key-manager"
+
+	// Masked replaces the value of a secret field
+	Masked = "********"
+)
+
+var (
+	ErrUnknownType   = errors.New("unknown provider type")
+	ErrInvalidConfig = errors.New("invalid provider config")
+
+	// secretName matches the fields treated as secrets in the config of a provider type without a schema
+	secretName = regexp.MustCompile(`(?i)(secret|password|passphrase|pin$|token$|private|credentials_json|api_?key)`)
+
+	//go:embed schemas/*.json
+	schemaFiles embed.FS
+
+	registry = mustLoadRegistry()
+)
+
+// Type is a known provider type, by the key manager name it is registered with.
+type Type struct {
+	Name   string
+	Schema *Schema
+}
+
+func mustLoadRegistry() map[string]*Type {
+	types := map[string]*Type{}
+	for _, name := range []string{TypeAWSKMS, TypeGCPKMS, TypeAzureKeyVault, TypePKCS11, TypeLocalFile, TypeDevKeyManager} {
+		b, err := schemaFiles.ReadFile("schemas/" + name + ".json")
+		if err != nil {
+			panic(err)
+		}
+		var s Schema
+		if err := json.Unmarshal(b, &s); err != nil {
+			panic(fmt.Sprintf("invalid schema of provider type %s: %s", name, err))
+		}
+		if err := s.compile(); err != nil {
+			panic(fmt.Sprintf("invalid schema of provider type %s: %s", name, err))
+		}
+		types[name] = &Type{Name: name, Schema: &s}
+	}
+	return types
+}
+
+// Lookup finds the provider type of a key manager. Key managers are matched case-insensitively.
+func Lookup(manager string) (*Type, bool) {
+	t, ok := registry[strings.ToLower(strings.TrimSpace(manager))]
+	return t, ok
+}
+
+// Get is the provider type with the given name.
+func Get(name string) (*Type, error) {
+	t, ok := Lookup(name)
+	if !ok {
+		return nil, fmt.Errorf("%w '%s', expected one of: %s", ErrUnknownType, name, strings.Join(Names(), ", "))
+	}
+	return t, nil
+}
+
+// Types lists the known provider types by name.
+func Types() []*Type {
+	types := make([]*Type, 0, len(registry))
+	for _, t := range registry {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	return types
+}
+
+// Names lists the names of the known provider types.
+func Names() []string {
+	types := Types()
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// ValidationError lists every violation of the schema by a config.
+type ValidationError struct {
+	Type       string
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.String())
+	}
+	return fmt.Sprintf("%s config: %s", e.Type, strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// Validate checks a JSON config against the schema of the type.
+func (t *Type) Validate(config []byte) error {
+	var v any
+	if err := json.Unmarshal(config, &v); err != nil {
+		return fmt.Errorf("%w: %s config is not valid JSON: %w", ErrInvalidConfig, t.Name, err)
+	}
+	if violations := t.Schema.validate("", v); len(violations) > 0 {
+		return &ValidationError{Type: t.Name, Violations: violations}
+	}
+	return nil
+}
+
+// Template scaffolds a config with the required fields and the fields with an example or a default value. Fields
+// without one hold a placeholder to replace.
+func (t *Type) Template() ([]byte, error) {
+	required := map[string]bool{}
+	for _, name := range t.Schema.Required {
+		required[name] = true
+	}
+	tmpl := map[string]any{}
+	for name, p := range t.Schema.Properties {
+		switch {
+		case len(p.Examples) > 0:
+			tmpl[name] = p.Examples[0]
+		case p.Default != nil:
+			tmpl[name] = p.Default
+		case required[name]:
+			tmpl[name] = placeholder(name, p)
+		}
+	}
+	return marshal(tmpl, "  ")
+}
+
+// marshal encodes JSON without escaping HTML characters, such as the angle brackets of placeholders.
+func marshal(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+func placeholder(name string, p *Schema) any {
+	switch p.Type {
+	case "integer", "number":
+		if p.Minimum != nil {
+			return *p.Minimum
+		}
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		return []any{}
+	case "object":
+		return map[string]any{}
+	default:
+		return "<" + name + ">"
+	}
+}
+
+// Mask replaces the values of the secret fields of a config, the fields marked writeOnly in the schema of its key
+// manager or, for a key manager without a schema, the fields named like a secret. The config is returned unchanged,
+// byte for byte, when it has no secret to mask or is not a JSON object.
+func Mask(manager string, config []byte) []byte {
+	var obj map[string]any
+	if err := json.Unmarshal(config, &obj); err != nil {
+		return config
+	}
+	var schema *Schema
+	if t, ok := Lookup(manager); ok {
+		schema = t.Schema
+	}
+	if !maskObject(schema, obj) {
+		return config
+	}
+	masked, err := marshal(obj, "")
+	if err != nil {
+		return config
+	}
+	return masked
+}
+
+func maskObject(schema *Schema, obj map[string]any) bool {
+	masked := false
+	for name, v := range obj {
+		var prop *Schema
+		if schema != nil {
+			prop = schema.Properties[name]
+		}
+		secret := secretName.MatchString(name)
+		if prop != nil {
+			secret = prop.WriteOnly
+		}
+		if nested, ok := v.(map[string]any); ok && !secret {
+			masked = maskObject(prop, nested) || masked
+			continue
+		}
+		if secret && v != nil && v != "" {
+			obj[name] = Masked
+			masked = true
+		}
+	}
+	return masked
+}
@@ -0,0 +1,273 @@
+package providerconfig
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Schema is the subset of JSON Schema used to describe provider configs: types, properties, required and dependent
+// required properties, enums, patterns, length and range bounds. Secret properties are marked writeOnly.
+type Schema struct {
+	Title                string              `json:"title,omitempty"`
+	Description          string              `json:"description,omitempty"`
+	Type                 string              `json:"type,omitempty"`
+	Properties           map[string]*Schema  `json:"properties,omitempty"`
+	Required             []string            `json:"required,omitempty"`
+	DependentRequired    map[string][]string `json:"dependentRequired,omitempty"`
+	AdditionalProperties *bool               `json:"additionalProperties,omitempty"`
+	Items                *Schema             `json:"items,omitempty"`
+	Enum                 []any               `json:"enum,omitempty"`
+	Pattern              string              `json:"pattern,omitempty"`
+	MinLength            *int                `json:"minLength,omitempty"`
+	MaxLength            *int                `json:"maxLength,omitempty"`
+	Minimum              *float64            `json:"minimum,omitempty"`
+	Maximum              *float64            `json:"maximum,omitempty"`
+	WriteOnly            bool                `json:"writeOnly,omitempty"`
+	Default              any                 `json:"default,omitempty"`
+	Examples             []any               `json:"examples,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// compile compiles the patterns of the schema and its subschemas.
+func (s *Schema) compile() error {
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	for name, p := range s.Properties {
+		if err := p.compile(); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if s.Items != nil {
+		return s.Items.compile()
+	}
+	return nil
+}
+
+// Violation is a value which does not match the schema, by its path within the config.
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+func (s *Schema) validate(path string, v any) []Violation {
+	violation := func(format string, a ...any) []Violation {
+		return []Violation{{Path: path, Message: fmt.Sprintf(format, a...)}}
+	}
+
+	if !s.hasType(v) {
+		return violation("must be %s, got %s", withArticle(s.Type), withArticle(typeOf(v)))
+	}
+	if len(s.Enum) > 0 && !s.inEnum(v) {
+		return violation("must be one of %s", formatEnum(s.Enum))
+	}
+
+	switch val := v.(type) {
+	case string:
+		n := utf8.RuneCountInString(val)
+		if s.MinLength != nil && n < *s.MinLength {
+			if *s.MinLength == 1 {
+				return violation("must not be empty")
+			}
+			return violation("must be at least %d characters", *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return violation("must be at most %d characters", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(val) {
+			if s.WriteOnly {
+				return violation("does not match the expected format")
+			}
+			return violation("%q does not match the expected format %s", val, s.Pattern)
+		}
+	case float64:
+		if s.Minimum != nil && val < *s.Minimum {
+			return violation("must be at least %v", *s.Minimum)
+		}
+		if s.Maximum != nil && val > *s.Maximum {
+			return violation("must be at most %v", *s.Maximum)
+		}
+	case []any:
+		var violations []Violation
+		if s.Items != nil {
+			for i, item := range val {
+				violations = append(violations, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item)...)
+			}
+		}
+		return violations
+	case map[string]any:
+		return s.validateObject(path, val)
+	}
+	return nil
+}
+
+func (s *Schema) validateObject(path string, obj map[string]any) []Violation {
+	var violations []Violation
+	add := func(p, format string, a ...any) {
+		violations = append(violations, Violation{Path: p, Message: fmt.Sprintf(format, a...)})
+	}
+
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			add(path, "missing required field '%s'", name)
+		}
+	}
+
+	dependents := make([]string, 0, len(s.DependentRequired))
+	for name := range s.DependentRequired {
+		dependents = append(dependents, name)
+	}
+	sort.Strings(dependents)
+	for _, name := range dependents {
+		if _, ok := obj[name]; !ok {
+			continue
+		}
+		for _, required := range s.DependentRequired[name] {
+			if _, ok := obj[required]; !ok {
+				add(path, "field '%s' requires field '%s'", name, required)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := joinPath(path, name)
+		prop, ok := s.Properties[name]
+		if !ok {
+			if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+				if suggestion := s.closestProperty(name); suggestion != "" {
+					add(p, "unknown field, did you mean '%s'?", suggestion)
+				} else {
+					add(p, "unknown field")
+				}
+			}
+			continue
+		}
+		violations = append(violations, prop.validate(p, obj[name])...)
+	}
+	return violations
+}
+
+// closestProperty is the known property nearest to a misspelled name, if any is close enough to be a typo.
+func (s *Schema) closestProperty(name string) string {
+	const maxDistance = 3
+	best, bestDistance := "", maxDistance+1
+	for p := range s.Properties {
+		d := levenshtein(strings.ToLower(name), p)
+		if d < bestDistance || (d == bestDistance && p < best) {
+			best, bestDistance = p, d
+		}
+	}
+	if bestDistance > maxDistance || bestDistance >= len(name) {
+		return ""
+	}
+	return best
+}
+
+func (s *Schema) hasType(v any) bool {
+	switch s.Type {
+	case "":
+		return true
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	default:
+		return typeOf(v) == s.Type
+	}
+}
+
+func (s *Schema) inEnum(v any) bool {
+	for _, e := range s.Enum {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf is the JSON Schema type of a value decoded by encoding/json.
+func typeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func withArticle(t string) string {
+	switch t {
+	case "integer", "object", "array":
+		return "an " + t
+	case "null":
+		return t
+	default:
+		return "a " + t
+	}
+}
+
+func formatEnum(enum []any) string {
+	values := make([]string, 0, len(enum))
+	for _, e := range enum {
+		values = append(values, fmt.Sprintf("%v", e))
+	}
+	return strings.Join(values, ", ")
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,262 @@
+// Package rotation plans the rotation of a KAS key and tracks a staged rollout of it, in which the namespaces,
+// attributes and values mapped to the old key move to the new key in batches. The progress of a rollout is saved
+// after every step, so an interrupted rollout can be resumed.
+package rotation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// kinds of policy objects a key can be mapped to
+const (
+	KindNamespace = "namespace"
+	KindAttribute = "attribute"
+	KindValue     = "value"
+)
+
+// steps of moving one object from the old key to the new key
+const (
+	StepPending = ""
+	// StepAssigned objects are mapped to both keys
+	StepAssigned = "assigned"
+	StepMoved    = "moved"
+	// StepSkipped objects were unmapped from the old key by someone else during the rollout, so had nothing to move
+	StepSkipped = "skipped"
+)
+
+const (
+	dirName = "rotations"
+
+	dirMode  = 0o700
+	fileMode = 0o600
+)
+
+var (
+	ErrNotFound = errors.New("no staged rotation in progress")
+
+	unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+)
+
+// kindOrder moves the most specific objects first, so the new key is first used for the narrowest slice of data
+var kindOrder = map[string]int{KindValue: 0, KindAttribute: 1, KindNamespace: 2}
+
+// Key identifies a KAS key by its system ID, its key ID and its KAS.
+type Key struct {
+	ID     string `json:"id,omitempty"`
+	KeyID  string `json:"key_id"`
+	KasID  string `json:"kas_id,omitempty"`
+	KasURI string `json:"kas_uri,omitempty"`
+}
+
+// Object is a namespace, attribute or value mapped to the old key.
+type Object struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	FQN  string `json:"fqn"`
+	Step string `json:"step,omitempty"`
+}
+
+func (o Object) Done() bool {
+	return o.Step == StepMoved || o.Step == StepSkipped
+}
+
+// Plan is the rotation of the old key to the new key and, once a staged rollout has started, its progress.
+type Plan struct {
+	Endpoint  string    `json:"endpoint"`
+	OldKey    Key       `json:"old_key"`
+	NewKey    Key       `json:"new_key"`
+	Objects   []Object  `json:"objects"`
+	StartedAt time.Time `json:"started_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// NewPlan plans to move the objects from the old key to the new key, values first, then attributes, then namespaces.
+// An object listed more than once is moved once.
+func NewPlan(endpoint string, oldKey, newKey Key, objects []Object) *Plan {
+	p := &Plan{Endpoint: endpoint, OldKey: oldKey, NewKey: newKey}
+	seen := map[string]bool{}
+	for _, o := range objects {
+		if seen[o.Kind+"/"+o.ID] {
+			continue
+		}
+		seen[o.Kind+"/"+o.ID] = true
+		o.Step = StepPending
+		p.Objects = append(p.Objects, o)
+	}
+	sort.SliceStable(p.Objects, func(i, j int) bool {
+		a, b := p.Objects[i], p.Objects[j]
+		if kindOrder[a.Kind] != kindOrder[b.Kind] {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		return a.FQN < b.FQN
+	})
+	return p
+}
+
+// NewKeyCreated reports whether the new key exists. A plan is saved before its new key is created, so a rollout
+// interrupted in between is resumed by looking the key up by its key ID rather than creating it again.
+func (p *Plan) NewKeyCreated() bool {
+	return p.NewKey.ID != ""
+}
+
+// Batches groups the indexes of the objects still to move into batches of at most size objects. A size of zero or
+// less puts them all in one batch.
+func (p *Plan) Batches(size int) [][]int {
+	var pending []int
+	for i, o := range p.Objects {
+		if !o.Done() {
+			pending = append(pending, i)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	if size <= 0 {
+		size = len(pending)
+	}
+	var batches [][]int
+	for len(pending) > 0 {
+		n := min(size, len(pending))
+		batches = append(batches, pending[:n])
+		pending = pending[n:]
+	}
+	return batches
+}
+
+// Progress counts the objects that have moved, or had nothing to move, out of all objects.
+func (p *Plan) Progress() (done, total int) {
+	for _, o := range p.Objects {
+		if o.Done() {
+			done++
+		}
+	}
+	return done, len(p.Objects)
+}
+
+func (p *Plan) Done() bool {
+	done, total := p.Progress()
+	return done == total
+}
+
+// Reconcile sets the step of every object from the objects currently mapped to the old and the new key. A rollout
+// interrupted between a change and saving its progress then resumes from what the platform holds, rather than
+// repeating a change that was already made. Objects mapped to the old key since the plan was made are added to it.
+func (p *Plan) Reconcile(onOld, onNew []Object) {
+	old, cur := objectSet(onOld), objectSet(onNew)
+	planned := objectSet(p.Objects)
+	for _, o := range onOld {
+		if !planned[o.Kind+"/"+o.ID] {
+			planned[o.Kind+"/"+o.ID] = true
+			p.Objects = append(p.Objects, o)
+		}
+	}
+	for i := range p.Objects {
+		k := p.Objects[i].Kind + "/" + p.Objects[i].ID
+		switch {
+		case old[k] && cur[k]:
+			p.Objects[i].Step = StepAssigned
+		case old[k]:
+			p.Objects[i].Step = StepPending
+		case cur[k]:
+			p.Objects[i].Step = StepMoved
+		default:
+			p.Objects[i].Step = StepSkipped
+		}
+	}
+}
+
+func objectSet(objects []Object) map[string]bool {
+	set := make(map[string]bool, len(objects))
+	for _, o := range objects {
+		set[o.Kind+"/"+o.ID] = true
+	}
+	return set
+}
+
+// Store keeps the staged rotations in progress, one file per old key.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// ForProfile opens the rotations of a profile within the config directory.
+func ForProfile(configDir, profile string) *Store {
+	if profile == "" {
+		profile = "default"
+	}
+	return NewStore(filepath.Join(configDir, dirName, unsafeFileChars.ReplaceAllString(profile, "_")))
+}
+
+// Path is the file the rotation of the old key is saved in.
+func (s *Store) Path(oldKeyID string) string {
+	return filepath.Join(s.dir, unsafeFileChars.ReplaceAllString(oldKeyID, "_")+".json")
+}
+
+// Load reads the rotation of the old key, or ErrNotFound when none is in progress.
+func (s *Store) Load(oldKeyID string) (*Plan, error) {
+	b, err := os.ReadFile(s.Path(oldKeyID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w for key %s", ErrNotFound, oldKeyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var p Plan
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("invalid rotation file %s: %w", s.Path(oldKeyID), err)
+	}
+	return &p, nil
+}
+
+// Save writes the rotation through a temporary file, so an interruption never leaves a partly written file.
+func (s *Store) Save(p *Plan) error {
+	now := time.Now().UTC()
+	if p.StartedAt.IsZero() {
+		p.StartedAt = now
+	}
+	p.UpdatedAt = now
+
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, dirMode); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(s.dir, ".rotation-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(fileMode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path(p.OldKey.ID))
+}
+
+// Remove forgets the rotation of the old key once it has finished.
+func (s *Store) Remove(oldKeyID string) error {
+	err := os.Remove(s.Path(oldKeyID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package rotation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey = Key{ID: "8f6a2b3c-0000-4000-8000-000000000001", KeyID: "key-v1", KasURI: "https://kas.example.com"}
+	newKey = Key{KeyID: "key-v2", KasURI: "https://kas.example.com"}
+
+	ns    = Object{Kind: KindNamespace, ID: "ns", FQN: "https://example.com"}
+	attrA = Object{Kind: KindAttribute, ID: "a", FQN: "https://example.com/attr/a"}
+	valA1 = Object{Kind: KindValue, ID: "a1", FQN: "https://example.com/attr/a/value/1"}
+	valA2 = Object{Kind: KindValue, ID: "a2", FQN: "https://example.com/attr/a/value/2"}
+)
+
+func fqns(p *Plan, idx []int) []string {
+	out := make([]string, 0, len(idx))
+	for _, i := range idx {
+		out = append(out, p.Objects[i].FQN)
+	}
+	return out
+}
+
+func TestNewPlan(t *testing.T) {
+	p := NewPlan("http://localhost:8080", oldKey, newKey, []Object{ns, attrA, valA2, valA1, valA1})
+
+	require.Len(t, p.Objects, 4, "duplicates are moved once")
+	assert.Equal(t, []string{valA1.FQN, valA2.FQN, attrA.FQN, ns.FQN}, fqns(p, []int{0, 1, 2, 3}),
+		"values move first, then attributes, then namespaces")
+}
+
+func TestBatches(t *testing.T) {
+	p := NewPlan("", oldKey, newKey, []Object{ns, attrA, valA1, valA2})
+
+	batches := p.Batches(3)
+	require.Len(t, batches, 2)
+	assert.Equal(t, []string{valA1.FQN, valA2.FQN, attrA.FQN}, fqns(p, batches[0]))
+	assert.Equal(t, []string{ns.FQN}, fqns(p, batches[1]))
+
+	assert.Len(t, p.Batches(0), 1, "no size is one batch")
+
+	p.Objects[0].Step = StepMoved
+	p.Objects[1].Step = StepAssigned
+	batches = p.Batches(2)
+	require.Len(t, batches, 2)
+	assert.Equal(t, []string{valA2.FQN, attrA.FQN}, fqns(p, batches[0]), "assigned objects still have to move")
+
+	done, total := p.Progress()
+	assert.Equal(t, 1, done)
+	assert.Equal(t, 4, total)
+	assert.False(t, p.Done())
+
+	for i := range p.Objects {
+		p.Objects[i].Step = StepMoved
+	}
+	assert.Empty(t, p.Batches(2))
+	assert.True(t, p.Done())
+}
+
+func TestReconcile(t *testing.T) {
+	p := NewPlan("", oldKey, newKey, []Object{ns, attrA, valA1, valA2})
+
+	// a1 moved, a2 was assigned before the interruption, the namespace was unmapped by someone else and a3 was
+	// mapped to the old key since the plan was made
+	valA3 := Object{Kind: KindValue, ID: "a3", FQN: "https://example.com/attr/a/value/3"}
+	p.Reconcile([]Object{valA2, attrA, valA3}, []Object{valA1, valA2})
+
+	steps := map[string]string{}
+	for _, o := range p.Objects {
+		steps[o.ID] = o.Step
+	}
+	assert.Equal(t, map[string]string{
+		"a1": StepMoved,
+		"a2": StepAssigned,
+		"a":  StepPending,
+		"ns": StepSkipped,
+		"a3": StepPending,
+	}, steps)
+
+	done, _ := p.Progress()
+	assert.Equal(t, 2, done)
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	s := ForProfile(dir, "my profile")
+	assert.Equal(t, filepath.Join(dir, "rotations", "my_profile", oldKey.ID+".json"), s.Path(oldKey.ID))
+
+	_, err := s.Load(oldKey.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	p := NewPlan("http://localhost:8080", oldKey, newKey, []Object{ns, valA1})
+	p.Objects[0].Step = StepAssigned
+	require.NoError(t, s.Save(p))
+	assert.False(t, p.StartedAt.IsZero())
+
+	info, err := os.Stat(s.Path(oldKey.ID))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(fileMode), info.Mode().Perm())
+
+	got, err := s.Load(oldKey.ID)
+	require.NoError(t, err)
+	assert.Equal(t, p.Objects, got.Objects)
+	assert.Equal(t, p.NewKey, got.NewKey)
+	assert.True(t, p.StartedAt.Equal(got.StartedAt))
+	// saved before the new key was created
+	assert.False(t, got.NewKeyCreated())
+
+	got.NewKey.ID = "8f6a2b3c-0000-4000-8000-000000000002"
+	require.NoError(t, s.Save(got))
+	got, err = s.Load(oldKey.ID)
+	require.NoError(t, err)
+	assert.True(t, got.NewKeyCreated())
+	assert.True(t, p.StartedAt.Equal(got.StartedAt), "saving progress keeps the start")
+
+	entries, err := os.ReadDir(filepath.Dir(s.Path(oldKey.ID)))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+
+	require.NoError(t, s.Remove(oldKey.ID))
+	require.NoError(t, s.Remove(oldKey.ID), "removing a finished rotation twice is fine")
+	_, err = s.Load(oldKey.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
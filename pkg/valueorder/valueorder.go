@@ -0,0 +1,93 @@
+// Package valueorder works out a new order for the values of an attribute from value names, so that a HIERARCHY can be
+// reordered without listing every value ID.
+package valueorder
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+var ErrInvalidOrder = errors.New("invalid value order")
+
+// Names lists the values of an order.
+func Names(values []*policy.Value) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, v.GetValue())
+	}
+	return out
+}
+
+// IDs lists the value IDs of an order, as the platform expects them.
+func IDs(values []*policy.Value) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, v.GetId())
+	}
+	return out
+}
+
+// FromNames orders the values as named, first to last. Every value must be named exactly once.
+func FromNames(values []*policy.Value, names []string) ([]*policy.Value, error) {
+	out := make([]*policy.Value, 0, len(values))
+	seen := map[int]bool{}
+	for _, n := range names {
+		i := index(values, n)
+		switch {
+		case i < 0:
+			return nil, fmt.Errorf("%w: unknown value '%s'", ErrInvalidOrder, n)
+		case seen[i]:
+			return nil, fmt.Errorf("%w: value '%s' is named more than once", ErrInvalidOrder, n)
+		}
+		seen[i] = true
+		out = append(out, values[i])
+	}
+	if len(out) != len(values) {
+		var missing []string
+		for i, v := range values {
+			if !seen[i] {
+				missing = append(missing, v.GetValue())
+			}
+		}
+		return nil, fmt.Errorf("%w: missing values %s", ErrInvalidOrder, strings.Join(missing, ", "))
+	}
+	return out, nil
+}
+
+// Move places a value directly above or below an anchor value. Above is towards the first value, which is the
+// highest in a HIERARCHY.
+func Move(values []*policy.Value, name, anchor string, above bool) ([]*policy.Value, error) {
+	from := index(values, name)
+	if from < 0 {
+		return nil, fmt.Errorf("%w: unknown value '%s'", ErrInvalidOrder, name)
+	}
+	if index(values, anchor) < 0 {
+		return nil, fmt.Errorf("%w: unknown value '%s'", ErrInvalidOrder, anchor)
+	}
+	if from == index(values, anchor) {
+		return nil, fmt.Errorf("%w: cannot move '%s' relative to itself", ErrInvalidOrder, name)
+	}
+
+	moved := values[from]
+	out := slices.Delete(slices.Clone(values), from, from+1)
+	to := index(out, anchor)
+	if !above {
+		to++
+	}
+	return slices.Insert(out, to, moved), nil
+}
+
+// Changed reports whether two orders differ.
+func Changed(before, after []*policy.Value) bool {
+	return !slices.Equal(IDs(before), IDs(after))
+}
+
+func index(values []*policy.Value, name string) int {
+	return slices.IndexFunc(values, func(v *policy.Value) bool {
+		return strings.EqualFold(v.GetValue(), name)
+	})
+}
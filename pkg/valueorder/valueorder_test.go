@@ -0,0 +1,71 @@
+package valueorder
+
+import (
+	"testing"
+
+	"github.com/opentdf/platform/protocol/go/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testValues() []*policy.Value {
+	return []*policy.Value{
+		{Id: "1", Value: "topsecret"},
+		{Id: "2", Value: "confidential"},
+		{Id: "3", Value: "secret"},
+		{Id: "4", Value: "public"},
+	}
+}
+
+func TestMove(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		anchor string
+		above  bool
+		want   []string
+	}{
+		{"up above", "secret", "confidential", true, []string{"topsecret", "secret", "confidential", "public"}},
+		{"down below", "confidential", "secret", false, []string{"topsecret", "secret", "confidential", "public"}},
+		{"to the top", "public", "topsecret", true, []string{"public", "topsecret", "confidential", "secret"}},
+		{"to the bottom", "topsecret", "public", false, []string{"confidential", "secret", "public", "topsecret"}},
+		{"already in place", "confidential", "secret", true, []string{"topsecret", "confidential", "secret", "public"}},
+		{"case insensitive", "SECRET", "Confidential", true, []string{"topsecret", "secret", "confidential", "public"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := testValues()
+			got, err := Move(values, tt.value, tt.anchor, tt.above)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, Names(got))
+			// the input order is left alone
+			assert.Equal(t, []string{"topsecret", "confidential", "secret", "public"}, Names(values))
+		})
+	}
+}
+
+func TestMove_Invalid(t *testing.T) {
+	_, err := Move(testValues(), "missing", "secret", true)
+	require.ErrorIs(t, err, ErrInvalidOrder)
+	_, err = Move(testValues(), "secret", "missing", true)
+	require.ErrorIs(t, err, ErrInvalidOrder)
+	_, err = Move(testValues(), "secret", "secret", true)
+	require.ErrorIs(t, err, ErrInvalidOrder)
+}
+
+func TestFromNames(t *testing.T) {
+	got, err := FromNames(testValues(), []string{"topsecret", "secret", "confidential", "public"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "3", "2", "4"}, IDs(got))
+	assert.True(t, Changed(testValues(), got))
+
+	_, err = FromNames(testValues(), []string{"topsecret", "secret", "public"})
+	require.ErrorIs(t, err, ErrInvalidOrder)
+	assert.Contains(t, err.Error(), "missing values confidential")
+
+	_, err = FromNames(testValues(), []string{"topsecret", "secret", "secret", "confidential", "public"})
+	require.ErrorIs(t, err, ErrInvalidOrder)
+
+	_, err = FromNames(testValues(), []string{"topsecret", "secret", "confidential", "public", "other"})
+	require.ErrorIs(t, err, ErrInvalidOrder)
+}
@@ -79,8 +79,11 @@ func (m AttributeView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+d":
 			return m, nil
 		case "enter":
-			if m.read.list.SelectedItem().(AttributeSubItem).title == "Labels" {
+			switch m.read.list.SelectedItem().(AttributeSubItem).title {
+			case "Labels":
 				return InitLabelList(m.attr, m.sdk)
+			case "Values":
+				return InitValueReorder(m.attr, m.sdk)
 			}
 			// case "enter":
 			// 	switch m.list.SelectedItem().(AttributeItem).id {
@@ -0,0 +1,341 @@
+package forms
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/opentdf/otdfctl/pkg/conditions"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	scsActionTest   = "test"
+	scsActionEdit   = "edit"
+	scsActionSubmit = "submit"
+	scsActionRedo   = "redo"
+	scsActionCancel = "cancel"
+)
+
+var ErrSubjectConditionSetCanceled = errors.New("subject condition set builder canceled")
+
+var subjectMappingOperators = []policy.SubjectMappingOperatorEnum{
+	policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN,
+	policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_NOT_IN,
+	policy.SubjectMappingOperatorEnum_SUBJECT_MAPPING_OPERATOR_ENUM_IN_CONTAINS,
+}
+
+// BuildSubjectConditionSet walks the user through subject sets, condition groups and conditions, lets them test the
+// draft against a subject token or JSON, and returns the subject sets once the user confirms the resulting JSON.
+// When replacing an existing condition set, the current subject sets are the draft to edit rather than starting over.
+func BuildSubjectConditionSet(current []*policy.SubjectSet) ([]*policy.SubjectSet, error) {
+	sets := make([]*policy.SubjectSet, 0, len(current))
+	for _, ss := range current {
+		//nolint:forcetypeassert // cloning a subject set returns a subject set
+		sets = append(sets, proto.Clone(ss).(*policy.SubjectSet))
+	}
+	if len(sets) == 0 {
+		var err error
+		if sets, err = buildSubjectSets(nil); err != nil {
+			return nil, err
+		}
+	}
+
+	for {
+		var action string
+		if err := huh.NewForm(huh.NewGroup(
+			huh.NewNote().
+				Title("Conditions").
+				Description(describeSubjectSets(sets)),
+			huh.NewSelect[string]().
+				Title("What next?").
+				Options(
+					huh.NewOption("Test against a subject (JWT or JSON)", scsActionTest),
+					huh.NewOption("Edit the conditions", scsActionEdit),
+					huh.NewOption("Review JSON and submit", scsActionSubmit),
+					huh.NewOption("Start over", scsActionRedo),
+					huh.NewOption("Cancel", scsActionCancel),
+				).
+				Value(&action),
+		)).Run(); err != nil {
+			return nil, err
+		}
+
+		var err error
+		switch action {
+		case scsActionTest:
+			err = testSubjectSets(sets)
+		case scsActionEdit:
+			sets, err = buildSubjectSets(sets)
+		case scsActionRedo:
+			sets, err = buildSubjectSets(nil)
+		case scsActionSubmit:
+			var submit bool
+			if submit, err = confirmSubjectSets(sets); err == nil && submit {
+				return sets, nil
+			}
+		case scsActionCancel:
+			return nil, ErrSubjectConditionSetCanceled
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func describeSubjectSets(sets []*policy.SubjectSet) string {
+	if len(sets) == 0 {
+		return "(none, edit or start over to add conditions)"
+	}
+	return conditions.Format(sets)
+}
+
+// buildSubjectSets edits the seeded subject sets in turn, and then offers to add more. Conditions the user does not
+// keep are dropped, along with groups and sets left empty.
+func buildSubjectSets(seed []*policy.SubjectSet) ([]*policy.SubjectSet, error) {
+	var sets []*policy.SubjectSet
+	for i := 0; ; i++ {
+		var seedSet *policy.SubjectSet
+		if i < len(seed) {
+			seedSet = seed[i]
+		}
+		ss, err := buildSubjectSet(i+1, seedSet)
+		if err != nil {
+			return nil, err
+		}
+		if len(ss.GetConditionGroups()) > 0 {
+			sets = append(sets, ss)
+		}
+		if i+1 < len(seed) {
+			continue
+		}
+
+		if another, err := confirm("Add another subject set? (all subject sets must be met)"); err != nil {
+			return nil, err
+		} else if !another {
+			return sets, nil
+		}
+	}
+}
+
+func buildSubjectSet(n int, seed *policy.SubjectSet) (*policy.SubjectSet, error) {
+	ss := &policy.SubjectSet{}
+	seedGroups := seed.GetConditionGroups()
+	for j := 0; ; j++ {
+		var seedGroup *policy.ConditionGroup
+		if j < len(seedGroups) {
+			seedGroup = seedGroups[j]
+		}
+		g, err := buildConditionGroup(fmt.Sprintf("Subject set %d, condition group %d", n, j+1), seedGroup)
+		if err != nil {
+			return nil, err
+		}
+		if len(g.GetConditions()) > 0 {
+			ss.ConditionGroups = append(ss.ConditionGroups, g)
+		}
+		if j+1 < len(seedGroups) {
+			continue
+		}
+
+		if another, err := confirm("Add another condition group to this subject set?"); err != nil {
+			return nil, err
+		} else if !another {
+			return ss, nil
+		}
+	}
+}
+
+func buildConditionGroup(title string, seed *policy.ConditionGroup) (*policy.ConditionGroup, error) {
+	g := &policy.ConditionGroup{BooleanOperator: seed.GetBooleanOperator()}
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewSelect[policy.ConditionBooleanTypeEnum]().
+			Title(title+": how are its conditions combined?").
+			Options(
+				huh.NewOption("AND (all conditions met)", policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND),
+				huh.NewOption("OR (any one condition met)", policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_OR),
+			).
+			Value(&g.BooleanOperator),
+	)).Run(); err != nil {
+		return nil, err
+	}
+
+	seedConditions := seed.GetConditions()
+	for k := 0; ; k++ {
+		var seedCondition *policy.Condition
+		if k < len(seedConditions) {
+			seedCondition = seedConditions[k]
+		}
+		moreSeeded := k+1 < len(seedConditions)
+		c, another, err := buildCondition(seedCondition, !moreSeeded)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			g.Conditions = append(g.Conditions, c)
+		}
+		if !moreSeeded && !another {
+			return g, nil
+		}
+	}
+}
+
+// buildCondition asks for one condition, prefilled from the seed when editing. It returns nil when the user does not
+// keep a seeded condition, and only asks whether another condition follows when askAnother is set.
+func buildCondition(seed *policy.Condition, askAnother bool) (*policy.Condition, bool, error) {
+	var (
+		c = &policy.Condition{
+			SubjectExternalSelectorValue: seed.GetSubjectExternalSelectorValue(),
+			Operator:                     seed.GetOperator(),
+		}
+		values  = strings.Join(seed.GetSubjectExternalValues(), "\n")
+		keep    = true
+		another bool
+	)
+
+	operators := make([]huh.Option[policy.SubjectMappingOperatorEnum], 0, len(subjectMappingOperators))
+	for _, op := range subjectMappingOperators {
+		operators = append(operators, huh.NewOption(handlers.GetSubjectMappingOperatorChoiceFromEnum(op), op))
+	}
+
+	var fields []huh.Field
+	if seed != nil {
+		fields = append(fields, huh.NewConfirm().
+			Title("Keep this condition?").
+			Description(conditions.Format([]*policy.SubjectSet{{ConditionGroups: []*policy.ConditionGroup{{
+				BooleanOperator: policy.ConditionBooleanTypeEnum_CONDITION_BOOLEAN_TYPE_ENUM_AND,
+				Conditions:      []*policy.Condition{seed},
+			}}}})).
+			Value(&keep))
+	}
+	fields = append(fields,
+		huh.NewInput().
+			Title("Selector").
+			Description("Path to a field of the subject's token or entity, e.g. .team.name or .groups[]").
+			Validate(func(s string) error {
+				if keep && !strings.HasPrefix(strings.TrimSpace(s), ".") {
+					return errors.New("selector must start with '.'")
+				}
+				return nil
+			}).
+			Value(&c.SubjectExternalSelectorValue),
+		huh.NewSelect[policy.SubjectMappingOperatorEnum]().
+			Title("Operator").
+			Options(operators...).
+			Value(&c.Operator),
+		huh.NewText().
+			Title("Values").
+			Description("One value per line").
+			Validate(func(s string) error {
+				if keep && len(splitValues(s)) == 0 {
+					return errors.New("at least one value is required")
+				}
+				return nil
+			}).
+			Value(&values),
+	)
+	if askAnother {
+		fields = append(fields, huh.NewConfirm().
+			Title("Add another condition to this group?").
+			Value(&another))
+	}
+
+	if err := huh.NewForm(huh.NewGroup(fields...)).Run(); err != nil {
+		return nil, false, err
+	}
+	if !keep {
+		return nil, another, nil
+	}
+
+	c.SubjectExternalSelectorValue = strings.TrimSpace(c.GetSubjectExternalSelectorValue())
+	c.SubjectExternalValues = splitValues(values)
+	return c, another, nil
+}
+
+func splitValues(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, "\n") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func confirm(title string) (bool, error) {
+	var yes bool
+	err := huh.NewForm(huh.NewGroup(huh.NewConfirm().Title(title).Value(&yes))).Run()
+	return yes, err
+}
+
+// showNote renders a read-only result in the form until the user moves on.
+func showNote(title, description string) error {
+	return huh.NewForm(huh.NewGroup(
+		huh.NewNote().Title(title).Description(description).Next(true).NextLabel("Back"),
+	)).Run()
+}
+
+func testSubjectSets(sets []*policy.SubjectSet) error {
+	var subject string
+	if err := huh.NewForm(huh.NewGroup(
+		huh.NewText().
+			Title("Subject").
+			Description("Paste a JWT or a JSON entity representation. It is only decoded locally and is not verified.").
+			Value(&subject),
+	)).Run(); err != nil {
+		return err
+	}
+
+	values, err := handlers.FlattenSubjectValues(strings.TrimSpace(subject))
+	if err != nil {
+		return showNote("Could not read the subject", err.Error())
+	}
+
+	met, results := conditions.Evaluate(sets, values)
+	var b strings.Builder
+	for _, r := range results {
+		mark := "✗"
+		if r.Matched {
+			mark = "✓"
+		}
+		found := "not present"
+		if len(r.Found) > 0 {
+			found = strings.Join(r.Found, ", ")
+		}
+		fmt.Fprintf(&b, "%s %s (subject: %s)\n", mark, r.Condition, found)
+	}
+	title := "The subject meets this subject condition set"
+	if !met {
+		title = "The subject does NOT meet this subject condition set"
+	}
+	return showNote(title, b.String())
+}
+
+func confirmSubjectSets(sets []*policy.SubjectSet) (bool, error) {
+	if len(sets) == 0 {
+		return false, showNote("Nothing to submit", "Add at least one condition before submitting.")
+	}
+	raw := make([]json.RawMessage, 0, len(sets))
+	for _, ss := range sets {
+		b, err := protojson.Marshal(ss)
+		if err != nil {
+			return false, err
+		}
+		raw = append(raw, b)
+	}
+	b, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return false, err
+	}
+
+	var submit bool
+	err = huh.NewForm(huh.NewGroup(
+		huh.NewNote().Title("Subject sets").Description(string(b)),
+		huh.NewConfirm().Title("Submit this subject condition set?").Value(&submit),
+	)).Run()
+	return submit, err
+}
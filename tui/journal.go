@@ -0,0 +1,16 @@
+package tui
+
+import (
+	"log/slog"
+
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"google.golang.org/protobuf/proto"
+)
+
+// journalChange records a change made in the TUI in the journal of the current profile. The change has already been
+// made, so failing to record it is only logged.
+func journalChange(h handlers.Handler, operation, kind string, before, after proto.Message) {
+	if err := h.RecordChange(operation, kind, before, after, 0); err != nil {
+		slog.Warn("Failed to record the change in the policy journal", "operation", operation, "kind", kind, "error", err)
+	}
+}
@@ -5,8 +5,11 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
 	"github.com/opentdf/platform/protocol/go/common"
 	"github.com/opentdf/platform/protocol/go/policy"
+	"google.golang.org/protobuf/proto"
 )
 
 type LabelUpdate struct {
@@ -37,6 +40,7 @@ func (m LabelUpdate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "enter":
 			if m.update.focusIndex == len(m.update.inputs) {
 				// update the label
+				before := proto.Clone(m.attr)
 				metadata := &common.MetadataMutable{Labels: m.attr.GetMetadata().GetLabels()}
 				oldKey := m.label.title
 				newKey := m.update.inputs[0].Value()
@@ -47,7 +51,10 @@ func (m LabelUpdate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				metadata.Labels[newKey] = newVal
 				behavior := common.MetadataUpdateEnum_METADATA_UPDATE_ENUM_REPLACE
 				// TODO: handle and return error view
-				attr, _ := m.sdk.UpdateAttribute(ctx, m.attr.GetId(), metadata, behavior)
+				attr, err := m.sdk.UpdateAttribute(ctx, m.attr.GetId(), metadata, behavior)
+				if err == nil {
+					journalChange(m.sdk, journal.OperationUpdate, labels.KindAttribute, before, attr)
+				}
 				return InitLabelList(attr, m.sdk)
 			}
 		}
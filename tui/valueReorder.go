@@ -0,0 +1,201 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/opentdf/otdfctl/pkg/handlers"
+	"github.com/opentdf/otdfctl/pkg/journal"
+	"github.com/opentdf/otdfctl/pkg/labels"
+	"github.com/opentdf/otdfctl/pkg/valueorder"
+	"github.com/opentdf/otdfctl/tui/constants"
+	"github.com/opentdf/platform/protocol/go/policy"
+)
+
+type ValueItem struct {
+	value  *policy.Value
+	rank   int
+	moving bool
+}
+
+func (m ValueItem) FilterValue() string {
+	return m.value.GetValue()
+}
+
+func (m ValueItem) Title() string {
+	if m.moving {
+		return fmt.Sprintf("%d. %s (moving)", m.rank, m.value.GetValue())
+	}
+	return fmt.Sprintf("%d. %s", m.rank, m.value.GetValue())
+}
+
+func (m ValueItem) Description() string {
+	return m.value.GetFqn()
+}
+
+// ValueReorder drags the values of an attribute into a new order: enter picks up the selected value, up and down
+// move it, and enter drops it again. The order is only saved with 's', once the attribute FQN is typed to confirm the
+// unsafe update, as the CLI asks for it too.
+type ValueReorder struct {
+	attr   *policy.Attribute
+	sdk    handlers.Handler
+	read   Read
+	values []*policy.Value
+	moving bool
+	err    error
+
+	confirming bool
+	confirm    textinput.Model
+}
+
+func InitValueReorder(attr *policy.Attribute, sdk handlers.Handler) (tea.Model, tea.Cmd) {
+	model, _ := InitRead("", nil)
+	// TODO: handle and return error view
+	mod, _ := model.(Read)
+	m := ValueReorder{attr: attr, sdk: sdk, read: mod, values: attr.GetValues()}
+	cmd := m.refresh(0)
+	return m, cmd
+}
+
+func (m ValueReorder) Init() tea.Cmd {
+	return nil
+}
+
+// refresh redraws the values with the one at index selected
+func (m *ValueReorder) refresh(index int) tea.Cmd {
+	items := make([]list.Item, 0, len(m.values))
+	for i, v := range m.values {
+		items = append(items, ValueItem{value: v, rank: i + 1, moving: m.moving && i == index})
+	}
+	cmd := m.read.list.SetItems(items)
+	m.read.list.Select(index)
+
+	title := "Reorder Values"
+	if m.attr.GetRule() == policy.AttributeRuleTypeEnum_ATTRIBUTE_RULE_TYPE_ENUM_HIERARCHY {
+		title += " (highest first)"
+	}
+	switch {
+	case m.err != nil && m.confirming:
+		title += " - " + m.err.Error()
+	case m.err != nil:
+		title += " - failed to save: " + m.err.Error()
+	case valueorder.Changed(m.attr.GetValues(), m.values):
+		title += " - unsaved, 's' to save"
+	}
+	m.read.list.Title = title
+	return cmd
+}
+
+// drag moves the value being moved one place up or down
+func (m *ValueReorder) drag(up bool) tea.Cmd {
+	i := m.read.list.Index()
+	to := i + 1
+	if up {
+		to = i - 1
+	}
+	if to < 0 || to >= len(m.values) {
+		return nil
+	}
+	moved, err := valueorder.Move(m.values, m.values[i].GetValue(), m.values[to].GetValue(), up)
+	if err != nil {
+		return nil
+	}
+	m.values = moved
+	return m.refresh(to)
+}
+
+func (m ValueReorder) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	ctx := context.Background()
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		constants.WindowSize = msg
+		m.read.list.SetSize(msg.Width, msg.Height)
+		return m, nil
+	case tea.KeyMsg:
+		if m.confirming {
+			return m.updateConfirm(ctx, msg)
+		}
+		switch msg.String() {
+		case "backspace":
+			return InitAttributeView(ctx, m.attr.GetId(), m.sdk)
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "enter", " ":
+			m.moving = !m.moving
+			cmd := m.refresh(m.read.list.Index())
+			return m, cmd
+		case "up", "k":
+			if m.moving {
+				cmd := m.drag(true)
+				return m, cmd
+			}
+		case "down", "j":
+			if m.moving {
+				cmd := m.drag(false)
+				return m, cmd
+			}
+		case "s":
+			if !valueorder.Changed(m.attr.GetValues(), m.values) {
+				return InitAttributeView(ctx, m.attr.GetId(), m.sdk)
+			}
+			m.moving = false
+			m.err = nil
+			m.confirming = true
+			m.confirm = textinput.New()
+			m.confirm.Placeholder = m.attr.GetFqn()
+			m.confirm.Prompt = "FQN: "
+			cmd := tea.Batch(m.confirm.Focus(), m.refresh(m.read.list.Index()))
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	m.read.list, cmd = m.read.list.Update(msg)
+	return m, cmd
+}
+
+// updateConfirm takes the typed attribute FQN, and saves the order once it matches
+func (m ValueReorder) updateConfirm(ctx context.Context, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.confirming = false
+		m.err = nil
+		cmd := m.refresh(m.read.list.Index())
+		return m, cmd
+	case "enter":
+		if m.confirm.Value() != m.attr.GetFqn() {
+			m.err = fmt.Errorf("the FQN does not match %s", m.attr.GetFqn())
+			cmd := m.refresh(m.read.list.Index())
+			return m, cmd
+		}
+		updated, err := m.sdk.UnsafeUpdateAttribute(ctx, m.attr.GetId(), "", "", valueorder.IDs(m.values), nil)
+		if err != nil {
+			m.confirming = false
+			m.err = err
+			cmd := m.refresh(m.read.list.Index())
+			return m, cmd
+		}
+		journalChange(m.sdk, journal.OperationUnsafeUpdate, labels.KindAttribute, m.attr, updated)
+		return InitAttributeView(ctx, m.attr.GetId(), m.sdk)
+	}
+
+	var cmd tea.Cmd
+	m.confirm, cmd = m.confirm.Update(msg)
+	return m, cmd
+}
+
+func (m ValueReorder) View() string {
+	if !m.confirming {
+		return ViewList(m.read.list)
+	}
+	prompt := fmt.Sprintf("Reordering the values of %s is an unsafe update: type its FQN to confirm, or esc to cancel",
+		m.attr.GetFqn())
+	return lipgloss.JoinVertical(lipgloss.Left, ViewList(m.read.list), prompt, m.confirm.View())
+}